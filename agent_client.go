@@ -1,21 +1,26 @@
-package blackfire
+//go:build !blackfire_noop
+// +build !blackfire_noop
 
-// TODO: AgentTimeout
+package blackfire
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path"
-	"regexp"
+	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/blackfireio/go-blackfire/bf_format"
 	"github.com/blackfireio/go-blackfire/pprof_reader"
@@ -24,20 +29,28 @@ import (
 )
 
 type agentClient struct {
-	agentNetwork              string
-	agentAddress              string
-	signingEndpoint           *url.URL
-	signingAuth               string
-	serverID                  string
-	serverToken               string
-	links                     []*linksMap
-	profiles                  []*Profile
-	logger                    *zerolog.Logger
+	agentNetwork    string
+	agentAddress    string
+	signingEndpoint *url.URL
+	signingAuth     string
+	serverID        string
+	serverToken     string
+	history         *profileHistory
+	logger          *zerolog.Logger
+	// signingMutex guards the signing response, so that concurrent callers
+	// share a single signing request.
+	signingMutex              sync.Mutex
 	signingResponse           *signingResponseData
 	signingResponseIsConsumed bool
+	blackfireYamlPath         string
+	timeout                   time.Duration
+	encoder                   ProfileEncoder
+	onUploadProgress          func(UploadProgress)
 }
 
-type linksMap map[string]map[string]string
+// uploadChunkSize is the size of the chunks the profile is written in, so
+// that the upload progress can be reported.
+const uploadChunkSize = 32 * 1024
 
 func NewAgentClient(configuration *Configuration) (*agentClient, error) {
 	agentNetwork, agentAddress, err := parseNetworkAddressString(configuration.AgentSocket)
@@ -45,8 +58,7 @@ func NewAgentClient(configuration *Configuration) (*agentClient, error) {
 		return nil, err
 	}
 
-	signingEndpoint := configuration.HTTPEndpoint
-	signingEndpoint.Path = path.Join(signingEndpoint.Path, "/api/v1/signing")
+	signingEndpoint := signingEndpointOf(configuration.HTTPEndpoint)
 
 	signingResponse, err := signingResponseFromBFQuery(configuration.BlackfireQuery)
 	if err != nil {
@@ -57,95 +69,235 @@ func NewAgentClient(configuration *Configuration) (*agentClient, error) {
 		agentNetwork:              agentNetwork,
 		agentAddress:              agentAddress,
 		signingEndpoint:           signingEndpoint,
-		signingAuth:               fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(configuration.ClientID+":"+configuration.ClientToken))),
-		links:                     make([]*linksMap, 10),
-		profiles:                  make([]*Profile, 10),
+		signingAuth:               apiAuthorization(configuration),
+		history:                   newProfileHistory(configuration.ProfileHistorySize, configuration.ProfileHistoryFile),
 		logger:                    configuration.Logger,
 		serverID:                  configuration.ServerID,
 		serverToken:               configuration.ServerToken,
 		signingResponse:           signingResponse,
 		signingResponseIsConsumed: signingResponse == nil,
+		blackfireYamlPath:         configuration.BlackfireYamlPath,
+		timeout:                   configuration.AgentTimeout,
+		encoder:                   configuration.Encoder,
+		onUploadProgress:          configuration.OnUploadProgress,
+	}
+	if err := a.history.load(); err != nil {
+		a.logger.Warn().Err(err).Msgf("Blackfire: Unable to load the profile history from %s", configuration.ProfileHistoryFile)
 	}
 	return a, nil
 }
 
-func (c *agentClient) CurrentBlackfireQuery() (string, error) {
-	if err := c.updateSigningRequest(); err != nil {
+// signingEndpointOf returns the signing endpoint of the Blackfire API at the
+// specified URL.
+func signingEndpointOf(endpoint *url.URL) *url.URL {
+	// Copy the endpoint so that the configuration is left untouched.
+	signingEndpoint := *endpoint
+	signingEndpoint.Path = path.Join(signingEndpoint.Path, "/api/v1/signing")
+	return &signingEndpoint
+}
+
+// apiAuthorization returns the Authorization header of the requests to the
+// Blackfire API.
+func apiAuthorization(configuration *Configuration) string {
+	return basicAuthorization(configuration.ClientID, configuration.ClientToken)
+}
+
+func basicAuthorization(clientID, clientToken string) string {
+	return fmt.Sprintf("Basic %s", base64.StdEncoding.EncodeToString([]byte(clientID+":"+clientToken)))
+}
+
+func (c *agentClient) CurrentBlackfireQuery(ctx context.Context) (string, error) {
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	if err := c.updateSigningRequest(ctx); err != nil {
 		return "", err
 	}
 	return c.signingResponse.QueryString, nil
 }
 
-func (c *agentClient) LastProfiles() []*Profile {
+// consumeSigningResponse returns the signing response of the profile about to
+// be sent, signing a new one if needed, so that the next profile gets a new
+// one.
+func (c *agentClient) consumeSigningResponse(ctx context.Context) (*signingResponseData, error) {
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	if err := c.updateSigningRequest(ctx); err != nil {
+		return nil, err
+	}
+	c.signingResponseIsConsumed = true
+	return c.signingResponse, nil
+}
+
+// currentProfile returns the profile the next upload will be attached to. Its
+// UUID and URL are only known in advance when the profile was signed via the
+// signing endpoint, not when it comes from a Blackfire query.
+func (c *agentClient) currentProfile(ctx context.Context) (*Profile, error) {
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	if err := c.updateSigningRequest(ctx); err != nil {
+		return nil, err
+	}
+	return c.signedProfile(), nil
+}
+
+// lastSentProfile returns the profile the last upload was attached to, or nil
+// if there was none.
+func (c *agentClient) lastSentProfile() *Profile {
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	if !c.signingResponseIsConsumed || c.signingResponse == nil {
+		return nil
+	}
+	return c.signedProfile()
+}
+
+// signedProfile returns the profile of the current signing response. It must
+// be called with the signing mutex held.
+func (c *agentClient) signedProfile() *Profile {
+	return &Profile{
+		UUID:   c.signingResponse.UUID,
+		URL:    c.signingResponse.Links["graph_url"]["href"],
+		APIURL: c.signingResponse.Links["profile"]["href"],
+		auth:   c.signingAuth,
+	}
+}
+
+// setBlackfireQuery makes the next profile use the specified query instead of
+// requesting a new one from the signing endpoint.
+func (c *agentClient) setBlackfireQuery(query string) error {
+	signingResponse, err := signingResponseFromBFQuery(query)
+	if err != nil {
+		return err
+	}
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	c.signingResponse = signingResponse
+	c.signingResponseIsConsumed = false
+	return nil
+}
+
+// LastProfiles returns the most recent profiles, the most recent first, with
+// their details fetched from the API until the context is done. They are
+// copies, which the caller may keep.
+func (c *agentClient) LastProfiles(ctx context.Context) []*Profile {
 	profiles := []*Profile{}
-	for _, profile := range c.profiles {
-		if profile == nil {
-			continue
-		}
+	for _, profile := range c.history.list() {
 		c.logger.Debug().Msgf("Blackfire: Get profile data for %s", profile.UUID)
-		if err := profile.load(c.signingAuth); err != nil {
+		if err := profile.load(ctx, c.signingAuth); err != nil {
 			c.logger.Debug().Msgf("Blackfire: Unable to get profile data for %s: %s", profile.UUID, err)
 			continue
 		}
+		c.history.update(profile)
 		profiles = append(profiles, profile)
 	}
 	return profiles
 }
 
-func (c *agentClient) ProbeOptions() bf_format.ProbeOptions {
-	return c.signingResponse.Options
+// ProbeOptions returns the options of the current signing response, and
+// whether it was already used for a profile. The options are nil if there is
+// no signing response.
+func (c *agentClient) ProbeOptions() (options bf_format.ProbeOptions, consumed bool) {
+	c.signingMutex.Lock()
+	defer c.signingMutex.Unlock()
+	if c.signingResponse == nil {
+		return nil, c.signingResponseIsConsumed
+	}
+	return c.signingResponse.Options, c.signingResponseIsConsumed
 }
 
 func (c *agentClient) getGoVersion() string {
 	return fmt.Sprintf("go-%s", runtime.Version()[2:])
 }
 
-func (c *agentClient) getBlackfireProbeHeader(hasBlackfireYaml bool) string {
+func (c *agentClient) getBlackfireProbeHeader(hasBlackfireYaml bool, options bf_format.ProbeOptions) string {
 	builder := strings.Builder{}
 	builder.WriteString(c.getGoVersion())
 	if hasBlackfireYaml {
 		builder.WriteString(", blackfire_yml")
 	}
-	if c.signingResponse.Options.IsTimespanFlagSet() {
+	if options.IsTimespanFlagSet() {
 		builder.WriteString(", timespan")
 	}
 	return builder.String()
 }
 
 func (c *agentClient) loadBlackfireYaml() (data []byte, err error) {
-	filenames := []string{".blackfire.yml", ".blackfire.yaml"}
+	filename, data, err := readBlackfireYaml(c.blackfireYamlPath)
+	if err != nil || filename == "" {
+		c.logger.Debug().Msgf("No .blackfire.yml found")
+		return nil, err
+	}
+	c.logger.Debug().Msgf("Blackfire: Using %s", filename)
+	return
+}
 
-	var filename string
-	for _, filename = range filenames {
-		if data, err = ioutil.ReadFile(filename); err == nil {
-			c.logger.Debug().Msgf("Loaded %s", filename)
-			break
-		} else if os.IsNotExist(err) {
-			c.logger.Debug().Msgf("%s does not exist", filename)
-		} else {
-			return nil, err
+// readBlackfireYaml reads the .blackfire.yml file at the specified path, or
+// found by findBlackfireYaml from the working directory if path is empty. It
+// returns an empty filename if there is no such file.
+func readBlackfireYaml(path string) (filename string, data []byte, err error) {
+	filename = path
+	if filename == "" {
+		var dir string
+		if dir, err = os.Getwd(); err != nil {
+			return
+		}
+		if filename, err = findBlackfireYaml(dir); err != nil || filename == "" {
+			return
 		}
 	}
-	if os.IsNotExist(err) {
-		err = nil
-	}
+	data, err = ioutil.ReadFile(filename)
 	return
 }
 
-func (c *agentClient) sendBlackfireYaml(conn *agentConnection, contents []byte) (err error) {
-	if err = conn.WriteStringHeader("Blackfire-Yaml-Size", strconv.Itoa(len(contents))); err != nil {
-		return
+var blackfireYamlFilenames = []string{".blackfire.yml", ".blackfire.yaml"}
+
+// findBlackfireYaml looks for a .blackfire.yml file in dir, then in its
+// parent directories up to the root of the Go module (the directory
+// containing go.mod). Only dir is searched when it is not inside a module.
+// It returns an empty path if there is no such file.
+func findBlackfireYaml(dir string) (string, error) {
+	dirs := []string{dir}
+	for current := dir; ; {
+		if _, err := os.Stat(filepath.Join(current, "go.mod")); err == nil {
+			break
+		}
+		parent := filepath.Dir(current)
+		if parent == current {
+			// Not in a module.
+			dirs = dirs[:1]
+			break
+		}
+		current = parent
+		dirs = append(dirs, current)
 	}
 
+	for _, dir := range dirs {
+		for _, name := range blackfireYamlFilenames {
+			filename := filepath.Join(dir, name)
+			info, err := os.Stat(filename)
+			if err == nil && !info.IsDir() {
+				return filename, nil
+			}
+			if err != nil && !os.IsNotExist(err) {
+				return "", err
+			}
+		}
+	}
+	return "", nil
+}
+
+func (c *agentClient) sendBlackfireYaml(conn *agentConnection, contents []byte) error {
+	if err := lintBlackfireYaml(contents); err != nil {
+		c.logger.Warn().Err(err).Msg("Blackfire: Sending a malformed .blackfire.yml")
+	}
 	c.logger.Debug().Str("blackfire.yml", string(contents)).Msgf("Send blackfire.yml, size %d", len(contents))
-	err = conn.WriteRawData(contents)
-	return
+	return writeBlackfireYaml(conn, contents)
 }
 
-func (c *agentClient) sendProfilePrologue(conn *agentConnection) (err error) {
-	// https://private.blackfire.io/knowledge-base/protocol/profiler/04-sending.html
-	bfQuery, err := c.CurrentBlackfireQuery()
-	if err != nil {
+// sendProfilePrologue sends the prologue of a profile, and returns the signing
+// response it consumed.
+func (c *agentClient) sendProfilePrologue(ctx context.Context, conn *agentConnection) (signing *signingResponseData, err error) {
+	if signing, err = c.consumeSigningResponse(ctx); err != nil {
 		return
 	}
 
@@ -160,24 +312,24 @@ func (c *agentClient) sendProfilePrologue(conn *agentConnection) (err error) {
 	}
 	hasBlackfireYaml := blackfireYaml != nil
 
-	// These must be done separately from the rest of the headers because they
-	// either must be sent in a specific order, or use nonstandard encoding.
-	var orderedHeaders []string
+	message := &prologue{
+		query:     signing.QueryString,
+		probe:     c.getBlackfireProbeHeader(hasBlackfireYaml, signing.Options),
+		osVersion: osVersion,
+	}
 	if c.serverID != "" && c.serverToken != "" {
-		orderedHeaders = append(orderedHeaders, fmt.Sprintf("Blackfire-Auth: %v:%v", c.serverID, c.serverToken))
+		message.auth = fmt.Sprintf("%v:%v", c.serverID, c.serverToken)
 	}
-	orderedHeaders = append(orderedHeaders, fmt.Sprintf("Blackfire-Query: %s", bfQuery))
-	orderedHeaders = append(orderedHeaders, fmt.Sprintf("Blackfire-Probe: %s", c.getBlackfireProbeHeader(hasBlackfireYaml)))
-
-	unorderedHeaders := make(map[string]interface{})
-	unorderedHeaders["os-version"] = osVersion
 
-	// We've now consumed the current Blackfire query, and must fetch a new one next time.
-	c.signingResponseIsConsumed = true
+	// The whole handshake, up to the agent response, must fit in the agent
+	// timeout.
+	if c.timeout > 0 {
+		if err = conn.SetDeadline(time.Now().Add(c.timeout)); err != nil {
+			return
+		}
+	}
 
-	// Send the ordered headers first, then wait for the Blackfire-Response,
-	// then send the unordered headers.
-	if err = conn.WriteOrderedHeaders(orderedHeaders); err != nil {
+	if err = message.writeHeaders(conn); err != nil {
 		return
 	}
 
@@ -185,40 +337,28 @@ func (c *agentClient) sendProfilePrologue(conn *agentConnection) (err error) {
 		if err = conn.WriteEndOfHeaders(); err != nil {
 			return
 		}
-
-		var responseName string
-		var responseValue string
-		if responseName, responseValue, err = conn.ReadEncodedHeader(); err != nil {
+		var request *yamlRequest
+		if request, err = readYamlRequest(conn); err != nil {
 			return
 		}
-		switch responseName {
-		case "Blackfire-Response":
-			var values url.Values
-			if values, err = url.ParseQuery(responseValue); err != nil {
+		if request.wanted() {
+			if err = c.sendBlackfireYaml(conn, blackfireYaml); err != nil {
 				return
 			}
-			if result := values.Get("blackfire_yml"); result == "true" {
-				if err = c.sendBlackfireYaml(conn, blackfireYaml); err != nil {
-					return
-				}
-			}
-		case "Blackfire-Error":
-			return fmt.Errorf(strings.TrimSpace(responseValue))
-		default:
-			return fmt.Errorf("Unexpected agent response: %s", responseValue)
 		}
 	}
 
-	if err = conn.WriteHeaders(unorderedHeaders); err != nil {
-		return
-	}
-	err = conn.WriteEndOfHeaders()
+	err = message.writeTrailer(conn)
 	return
 }
 
-func (c *agentClient) SendProfile(profile *pprof_reader.Profile, title string) (err error) {
+// SendProfile uploads the profile to the agent, and returns the size of the
+// payload that was sent. dimensions, if any, replace the cost dimensions
+// requested by the server. The upload is aborted, and the connection closed,
+// when the context is done.
+func (c *agentClient) SendProfile(ctx context.Context, profile *pprof_reader.Profile, title string, dimensions []string) (size int, err error) {
 	var conn *agentConnection
-	if conn, err = newAgentConnection(c.agentNetwork, c.agentAddress, c.logger); err != nil {
+	if conn, err = newAgentConnection(ctx, c.agentNetwork, c.agentAddress, c.timeout, c.logger); err != nil {
 		return
 	}
 	defer func() {
@@ -230,40 +370,121 @@ func (c *agentClient) SendProfile(profile *pprof_reader.Profile, title string) (
 			conn.Close()
 		}
 	}()
+	defer func() {
+		if ctxErr := ctx.Err(); err != nil && ctxErr != nil {
+			err = ctxErr
+		}
+	}()
+	stop := conn.closeOnDone(ctx)
+	defer stop()
 
-	if err = c.sendProfilePrologue(conn); err != nil {
+	return c.uploadProfile(ctx, conn, profile, title, dimensions)
+}
+
+// uploadProfile runs the whole agent protocol on the connection.
+func (c *agentClient) uploadProfile(ctx context.Context, conn *agentConnection, profile *pprof_reader.Profile, title string, dimensions []string) (size int, err error) {
+	start := time.Now()
+	var signing *signingResponseData
+	if signing, err = c.sendProfilePrologue(ctx, conn); err != nil {
 		return
 	}
 
-	var response http.Header
-	if response, err = conn.ReadResponse(); err != nil {
-		return err
+	if err = readAgentResponse(conn); err != nil {
+		return
 	}
-	if response.Get("Blackfire-Error") != "" {
-		return fmt.Errorf("Blackfire-Error: %s", response.Get("Blackfire-Error"))
+	if err = conn.SetDeadline(time.Time{}); err != nil {
+		return
 	}
 
 	profileBuffer := new(bytes.Buffer)
-	if err := bf_format.WriteBFFormat(profile, profileBuffer, c.ProbeOptions(), title); err != nil {
-		return err
+	if err = c.encoder.Encode(profileBuffer, profile, withDimensions(signing.Options, dimensions), ProfileMetadata{Title: title}); err != nil {
+		return
 	}
 	encodedProfile := profileBuffer.Bytes()
 
-	c.logger.Debug().Str("contents", string(encodedProfile)).Msg("Blackfire: Send profile")
-	if err = conn.WriteRawData(encodedProfile); err != nil {
+	if e := c.logger.Debug(); e.Enabled() {
+		e.Str("contents", string(encodedProfile)).Msg("Blackfire: Send profile")
+	}
+	if err = c.writeProfileData(conn, encodedProfile, title, start); err != nil {
 		return
 	}
+	size = len(encodedProfile)
 
+	c.logger.Info().
+		Int("size", size).
+		Dur("duration", time.Since(start)).
+		Msgf("Blackfire: Uploaded %d bytes in %v", size, time.Since(start))
 	return
 }
 
-func (c *agentClient) updateSigningRequest() (err error) {
+// writeProfileData writes the profile to the agent in chunks, reporting the
+// progress to the OnUploadProgress hook after each of them.
+func (c *agentClient) writeProfileData(conn *agentConnection, data []byte, title string, start time.Time) error {
+	if c.onUploadProgress == nil {
+		return conn.WriteRawData(data)
+	}
+	for written := 0; written < len(data); {
+		end := written + uploadChunkSize
+		if end > len(data) {
+			end = len(data)
+		}
+		if err := conn.WriteRawData(data[written:end]); err != nil {
+			return err
+		}
+		written = end
+		c.onUploadProgress(UploadProgress{
+			Title:   title,
+			Written: written,
+			Total:   len(data),
+			Elapsed: time.Since(start),
+		})
+	}
+	return nil
+}
+
+// checkAgentSocket dials the agent socket, and closes the connection right
+// away.
+func checkAgentSocket(ctx context.Context, agentSocket string) error {
+	network, address, err := parseNetworkAddressString(agentSocket)
+	if err != nil {
+		return err
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, network, address)
+	if err != nil {
+		return err
+	}
+	return conn.Close()
+}
+
+// checkSigning sends a signing request to check that the endpoint is
+// reachable and accepts the credentials. The signed query is not used.
+func (c *agentClient) checkSigning(ctx context.Context) (reachable bool, err error) {
+	request, err := http.NewRequest("POST", c.signingEndpoint.String(), nil)
+	if err != nil {
+		return false, err
+	}
+	request.Header.Add("Authorization", c.signingAuth)
+	response, err := http.DefaultClient.Do(request.WithContext(ctx))
+	if err != nil {
+		return false, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode != 201 {
+		return true, fmt.Errorf("Signing request to %s failed: %s", redactedURL(c.signingEndpoint), response.Status)
+	}
+	return true, nil
+}
+
+// updateSigningRequest sends a signing request if the current signing
+// response was used already. It must be called with the signing mutex held.
+func (c *agentClient) updateSigningRequest(ctx context.Context) (err error) {
 	if !c.signingResponseIsConsumed {
 		return
 	}
 
 	var response *http.Response
-	c.logger.Debug().Msgf("Blackfire: Get authorization from %s", c.signingEndpoint)
+	c.logger.Debug().Msgf("Blackfire: Get authorization from %s", redactedURL(c.signingEndpoint))
 	request, err := http.NewRequest("POST", c.signingEndpoint.String(), nil)
 	if err != nil {
 		return
@@ -271,12 +492,13 @@ func (c *agentClient) updateSigningRequest() (err error) {
 	request.Header.Add("Authorization", c.signingAuth)
 	c.logger.Debug().Msg("Blackfire: Send signing request")
 	client := http.DefaultClient
-	response, err = client.Do(request)
+	response, err = client.Do(request.WithContext(ctx))
 	if err != nil {
 		return
 	}
+	defer response.Body.Close()
 	if response.StatusCode != 201 {
-		return fmt.Errorf("Signing request to %s failed: %s", c.signingEndpoint, response.Status)
+		return fmt.Errorf("Signing request to %s failed: %s", redactedURL(c.signingEndpoint), response.Status)
 	}
 	var responseData []byte
 	responseData, err = ioutil.ReadAll(response.Body)
@@ -284,23 +506,24 @@ func (c *agentClient) updateSigningRequest() (err error) {
 		return
 	}
 	c.logger.Debug().Interface("response", string(responseData)).Msg("Blackfire: Receive signing response")
-	err = json.Unmarshal(responseData, &c.signingResponse)
+	// The response is only replaced once complete, as the previous one may
+	// still be in use for a profile being sent.
+	signingResponse := newSigningResponseData()
+	err = json.Unmarshal(responseData, signingResponse)
 	if err != nil {
 		return fmt.Errorf("JSON error: %v", err)
 	}
-	if c.signingResponse.QueryString == "" {
+	if signingResponse.QueryString == "" {
 		return fmt.Errorf("Signing response blackfire query was empty")
 	}
-	profileURL, ok := c.signingResponse.Links["profile"]
-	if !ok {
+	if _, ok := signingResponse.Links["profile"]; !ok {
 		return fmt.Errorf("Signing response blackfire profile URL was empty")
 	}
-	c.links = append([]*linksMap{&c.signingResponse.Links}, c.links[:9]...)
-	c.profiles = append([]*Profile{{
-		UUID:   c.signingResponse.UUID,
-		URL:    c.signingResponse.Links["graph_url"]["href"],
-		APIURL: profileURL["href"],
-	}}, c.profiles[:9]...)
+	c.signingResponse = signingResponse
+	c.history.add(c.signedProfile())
+	if err := c.history.save(); err != nil {
+		c.logger.Warn().Err(err).Msgf("Blackfire: Unable to save the profile history")
+	}
 
 	c.signingResponseIsConsumed = false
 
@@ -360,8 +583,7 @@ func signingResponseFromBFQuery(query string) (response *signingResponseData, er
 }
 
 func parseNetworkAddressString(agentSocket string) (network string, address string, err error) {
-	re := regexp.MustCompile(`^([^:]+)://(.*)`)
-	matches := re.FindAllStringSubmatch(agentSocket, -1)
+	matches := agentSocketPattern.FindAllStringSubmatch(agentSocket, -1)
 	if matches == nil {
 		err = fmt.Errorf("Could not parse agent socket value: [%v]", agentSocket)
 		return
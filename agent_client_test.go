@@ -0,0 +1,196 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestFindBlackfireYaml(c *C) {
+	root, err := ioutil.TempDir("", "blackfire-yaml")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(root)
+	module := filepath.Join(root, "module")
+	dir := filepath.Join(module, "cmd", "server")
+	c.Assert(os.MkdirAll(dir, 0755), IsNil)
+
+	// Not in a module: only the directory itself is searched.
+	c.Assert(ioutil.WriteFile(filepath.Join(root, ".blackfire.yml"), nil, 0644), IsNil)
+	found, err := findBlackfireYaml(dir)
+	c.Assert(err, IsNil)
+	c.Assert(found, Equals, "")
+
+	// The search stops at the module root.
+	c.Assert(ioutil.WriteFile(filepath.Join(module, "go.mod"), nil, 0644), IsNil)
+	found, err = findBlackfireYaml(dir)
+	c.Assert(err, IsNil)
+	c.Assert(found, Equals, "")
+
+	c.Assert(ioutil.WriteFile(filepath.Join(module, ".blackfire.yaml"), nil, 0644), IsNil)
+	found, err = findBlackfireYaml(dir)
+	c.Assert(err, IsNil)
+	c.Assert(found, Equals, filepath.Join(module, ".blackfire.yaml"))
+
+	// The closest file wins.
+	c.Assert(ioutil.WriteFile(filepath.Join(dir, ".blackfire.yml"), nil, 0644), IsNil)
+	found, err = findBlackfireYaml(dir)
+	c.Assert(err, IsNil)
+	c.Assert(found, Equals, filepath.Join(dir, ".blackfire.yml"))
+}
+
+// serveFakeAgent accepts profiles on the listener, answering with the
+// specified response headers, and sends the index of the connection each
+// profile was received on to the channel, once the probe closed it to end the
+// profile. Connections are also sent to the conns channel as they are
+// accepted.
+func serveFakeAgent(listener net.Listener, response string, conns chan<- net.Conn, profiles chan<- int) {
+	for index := 0; ; index++ {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		conns <- conn
+		go func(index int, conn net.Conn) {
+			defer conn.Close()
+			reader := bufio.NewReader(conn)
+			// Headers, up to an empty line.
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == "\n" {
+					break
+				}
+			}
+			if _, err := conn.Write([]byte(response + "\n")); err != nil {
+				return
+			}
+			// The profile, up to the end of the connection. Nothing is sent
+			// when the probe aborts the upload.
+			if n, err := io.Copy(ioutil.Discard, reader); err != nil || n == 0 {
+				return
+			}
+			profiles <- index
+		}(index, conn)
+	}
+}
+
+func (s *BlackfireSuite) TestAgentClientConcurrentSigning(c *C) {
+	var requests int32
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&requests, 1)
+		// Let the other callers pile up.
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"query_string":"expires=1&signature=%d","_links":{"profile":{"href":"/profile"}}}`, n)
+	}))
+	defer api.Close()
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-agent-test.log"), 4)
+	endpoint, err := url.Parse(api.URL)
+	c.Assert(err, IsNil)
+	client, err := NewAgentClient(newConfiguration(&Configuration{
+		HTTPEndpoint: endpoint,
+		Logger:       &logger,
+	}))
+	c.Assert(err, IsNil)
+
+	queries := make(chan string, 10)
+	var wg sync.WaitGroup
+	for i := 0; i < cap(queries); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			query, err := client.CurrentBlackfireQuery(context.Background())
+			c.Check(err, IsNil)
+			queries <- query
+		}()
+	}
+	wg.Wait()
+	close(queries)
+	for query := range queries {
+		c.Assert(query, Equals, "expires=1&signature=1")
+	}
+	c.Assert(atomic.LoadInt32(&requests), Equals, int32(1))
+
+	// A new query is signed once the current one is consumed.
+	signing, err := client.consumeSigningResponse(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(signing.QueryString, Equals, "expires=1&signature=1")
+	query, err := client.CurrentBlackfireQuery(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(query, Equals, "expires=1&signature=2")
+}
+
+func (s *BlackfireSuite) TestAgentClientCancellation(c *C) {
+	// An agent which never answers.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+		}
+	}()
+	// An API which never answers either.
+	unblock := make(chan struct{})
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer api.Close()
+	defer close(unblock)
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-agent-test.log"), 4)
+	endpoint, err := url.Parse(api.URL)
+	c.Assert(err, IsNil)
+	client, err := NewAgentClient(newConfiguration(&Configuration{
+		AgentSocket:  "tcp://" + listener.Addr().String(),
+		AgentTimeout: time.Minute,
+		HTTPEndpoint: endpoint,
+		Logger:       &logger,
+	}))
+	c.Assert(err, IsNil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.CurrentBlackfireQuery(ctx)
+	c.Assert(err, NotNil)
+	c.Assert(ctx.Err(), Equals, context.DeadlineExceeded)
+
+	c.Assert(client.setBlackfireQuery("signature=abc&expires=9999999999"), IsNil)
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.SendProfile(ctx, pprof_reader.NewProfile(), "", nil)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+
+	// A done context doesn't even connect.
+	c.Assert(client.setBlackfireQuery("signature=abc&expires=9999999999"), IsNil)
+	_, err = client.SendProfile(ctx, pprof_reader.NewProfile(), "", nil)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+}
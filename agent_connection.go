@@ -1,7 +1,11 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
 package blackfire
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"net"
@@ -9,12 +13,22 @@ import (
 	"net/textproto"
 	"net/url"
 	"regexp"
+	"strings"
+	"time"
 
 	"github.com/rs/zerolog"
 )
 
 var headerRegex *regexp.Regexp = regexp.MustCompile(`^([^:]+):(.*)`)
 
+const (
+	// maxAgentHeaderSize is the maximum size of a header line sent by the
+	// agent.
+	maxAgentHeaderSize = 64 * 1024
+	// maxAgentHeaders is the maximum number of headers in an agent response.
+	maxAgentHeaders = 100
+)
+
 type agentConnection struct {
 	conn   net.Conn
 	reader *bufio.Reader
@@ -22,17 +36,23 @@ type agentConnection struct {
 	logger *zerolog.Logger
 }
 
-func newAgentConnection(network, address string, logger *zerolog.Logger) (*agentConnection, error) {
+// newAgentConnection connects to the agent, giving up after the specified
+// timeout if it is not zero, or when the context is done.
+func newAgentConnection(ctx context.Context, network, address string, timeout time.Duration, logger *zerolog.Logger) (*agentConnection, error) {
 	c := &agentConnection{
 		logger: logger,
 	}
-	err := c.Init(network, address)
+	err := c.Init(ctx, network, address, timeout)
 	return c, err
 }
 
-func (c *agentConnection) Init(network, address string) (err error) {
-	if c.conn, err = net.Dial(network, address); err != nil {
-		return
+func (c *agentConnection) Init(ctx context.Context, network, address string, timeout time.Duration) (err error) {
+	dialer := net.Dialer{Timeout: timeout}
+	if c.conn, err = dialer.DialContext(ctx, network, address); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return agentError(err)
 	}
 
 	c.reader = bufio.NewReader(c.conn)
@@ -40,8 +60,62 @@ func (c *agentConnection) Init(network, address string) (err error) {
 	return
 }
 
+// SetDeadline bounds the time the following reads and writes may take, so
+// that an unresponsive agent can't block the probe. The zero time removes the
+// deadline.
+func (c *agentConnection) SetDeadline(deadline time.Time) error {
+	return c.conn.SetDeadline(deadline)
+}
+
+// closeOnDone closes the connection when the context is done, which aborts the
+// reads and writes in progress, until the returned function is called.
+func (c *agentConnection) closeOnDone(ctx context.Context) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {}
+	}
+	stopped := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.conn.Close()
+		case <-stopped:
+		}
+	}()
+	return func() { close(stopped) }
+}
+
+// agentError translates the errors of the connection to the agent.
+func agentError(err error) error {
+	if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+		return ErrAgentTimeout
+	}
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// readLine reads a header line sent by the agent, including the line feed.
+func (c *agentConnection) readLine() (string, error) {
+	var line []byte
+	for {
+		chunk, err := c.reader.ReadSlice('\n')
+		if len(line)+len(chunk) > maxAgentHeaderSize {
+			return "", ErrAgentHeaderTooLarge
+		}
+		line = append(line, chunk...)
+		if err == bufio.ErrBufferFull {
+			continue
+		}
+		if err != nil {
+			return "", agentError(err)
+		}
+		return string(line), nil
+	}
+}
+
 func (c *agentConnection) ReadEncodedHeader() (name string, urlEncodedValue string, err error) {
-	line, err := c.reader.ReadString('\n')
+	line, err := c.readLine()
 	if err != nil {
 		return
 	}
@@ -51,7 +125,7 @@ func (c *agentConnection) ReadEncodedHeader() (name string, urlEncodedValue stri
 	c.logger.Debug().Str("read header", line).Msgf("Recv header")
 	matches := headerRegex.FindAllStringSubmatch(line, -1)
 	if matches == nil {
-		err = fmt.Errorf("Could not parse header: [%s]", line)
+		err = &AgentProtocolError{Line: strings.TrimRight(line, "\r\n")}
 		return
 	}
 	name = matches[0][1]
@@ -59,16 +133,28 @@ func (c *agentConnection) ReadEncodedHeader() (name string, urlEncodedValue stri
 	return
 }
 
+// ReadResponse reads the headers of an agent response, up to an empty line.
 func (c *agentConnection) ReadResponse() (http.Header, error) {
-	tp := textproto.NewReader(c.reader)
-	mimeHeader, err := tp.ReadMIMEHeader()
-	if err != nil {
-		if err == io.EOF {
-			err = io.ErrUnexpectedEOF
+	header := make(http.Header)
+	for count := 0; ; count++ {
+		line, err := c.readLine()
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			return header, nil
 		}
-		return nil, err
+		if count == maxAgentHeaders {
+			return nil, ErrAgentTooManyHeaders
+		}
+		c.logger.Debug().Str("read header", line).Msgf("Recv header")
+		matches := headerRegex.FindStringSubmatch(line)
+		if matches == nil {
+			return nil, &AgentProtocolError{Line: line}
+		}
+		header.Add(textproto.CanonicalMIMEHeaderKey(matches[1]), strings.TrimSpace(matches[2]))
 	}
-	return http.Header(mimeHeader), nil
 }
 
 func (c *agentConnection) WriteEncodedHeader(name string, urlEncodedValue string) error {
@@ -101,21 +187,6 @@ func (c *agentConnection) WriteOrderedHeaders(encodedHeaders []string) error {
 	return nil
 }
 
-func (c *agentConnection) WriteHeaders(nonEncodedHeaders map[string]interface{}) error {
-	for k, v := range nonEncodedHeaders {
-		if asString, ok := v.(string); ok {
-			if err := c.WriteStringHeader(k, asString); err != nil {
-				return err
-			}
-		} else {
-			if err := c.WriteMapHeader(k, v.(url.Values)); err != nil {
-				return err
-			}
-		}
-	}
-	return nil
-}
-
 func (c *agentConnection) WriteEndOfHeaders() (err error) {
 	c.logger.Debug().Msgf("Send end-of-headers")
 	if _, err = c.writer.WriteString("\n"); err != nil {
@@ -130,7 +201,7 @@ func (c *agentConnection) WriteRawData(data []byte) error {
 }
 
 func (c *agentConnection) Flush() error {
-	return c.writer.Flush()
+	return agentError(c.writer.Flush())
 }
 
 func (c *agentConnection) Close() error {
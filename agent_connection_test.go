@@ -0,0 +1,68 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	. "gopkg.in/check.v1"
+)
+
+// newPipeAgentConnection returns a connection to a fake agent sending the
+// specified data.
+func newPipeAgentConnection(data string) *agentConnection {
+	client, server := net.Pipe()
+	go func() {
+		server.Write([]byte(data))
+	}()
+	logger := zerolog.Nop()
+	return &agentConnection{
+		conn:   client,
+		reader: bufio.NewReader(client),
+		writer: bufio.NewWriter(client),
+		logger: &logger,
+	}
+}
+
+func (s *BlackfireSuite) TestAgentConnectionReadResponse(c *C) {
+	conn := newPipeAgentConnection("Blackfire-Response: continue=true\r\nblackfire-error:  abc \n\n")
+	defer conn.Close()
+	response, err := conn.ReadResponse()
+	c.Assert(err, IsNil)
+	c.Assert(response.Get("Blackfire-Response"), Equals, "continue=true")
+	c.Assert(response.Get("Blackfire-Error"), Equals, "abc")
+}
+
+func (s *BlackfireSuite) TestAgentConnectionLimits(c *C) {
+	conn := newPipeAgentConnection("Blackfire-Response: " + strings.Repeat("a", maxAgentHeaderSize) + "\n\n")
+	_, err := conn.ReadResponse()
+	c.Assert(err, Equals, ErrAgentHeaderTooLarge)
+	conn.Close()
+
+	conn = newPipeAgentConnection(strings.Repeat("Blackfire-Response: a\n", maxAgentHeaders+1) + "\n")
+	_, err = conn.ReadResponse()
+	c.Assert(err, Equals, ErrAgentTooManyHeaders)
+	conn.Close()
+
+	conn = newPipeAgentConnection("garbage\n\n")
+	_, err = conn.ReadResponse()
+	c.Assert(err, DeepEquals, &AgentProtocolError{Line: "garbage"})
+	conn.Close()
+
+	conn = newPipeAgentConnection("garbage\n")
+	_, _, err = conn.ReadEncodedHeader()
+	c.Assert(err, ErrorMatches, `Could not parse header: \[garbage\]`)
+	conn.Close()
+
+	// A wedged agent.
+	conn = newPipeAgentConnection("")
+	c.Assert(conn.SetDeadline(time.Now().Add(10*time.Millisecond)), IsNil)
+	_, err = conn.ReadResponse()
+	c.Assert(err, Equals, ErrAgentTimeout)
+	conn.Close()
+}
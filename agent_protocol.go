@@ -0,0 +1,111 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// The messages exchanged with the agent before sending a profile:
+//
+//   probe:  prologue headers (Blackfire-Auth, Blackfire-Query, Blackfire-Probe)
+//   probe:  end of headers, only when offering a .blackfire.yml
+//   agent:  yamlRequest (Blackfire-Response) or errorResponse (Blackfire-Error)
+//   probe:  Blackfire-Yaml-Size and the .blackfire.yml, if requested
+//   probe:  prologue trailer (os-version), end of headers
+//   agent:  Blackfire-Response, or errorResponse
+//   probe:  the profile
+//
+// https://private.blackfire.io/knowledge-base/protocol/profiler/04-sending.html
+
+// prologue is the first message sent to the agent.
+type prologue struct {
+	// auth is "id:token", or empty when the server credentials are unknown.
+	auth      string
+	query     string
+	probe     string
+	osVersion url.Values
+}
+
+// writeHeaders writes the headers identifying the probe and the profile. They
+// must be sent first, in this order.
+func (m *prologue) writeHeaders(conn *agentConnection) error {
+	var headers []string
+	if m.auth != "" {
+		headers = append(headers, fmt.Sprintf("Blackfire-Auth: %s", m.auth))
+	}
+	headers = append(headers, fmt.Sprintf("Blackfire-Query: %s", m.query))
+	headers = append(headers, fmt.Sprintf("Blackfire-Probe: %s", m.probe))
+	return conn.WriteOrderedHeaders(headers)
+}
+
+// writeTrailer writes the remaining headers, and ends the prologue.
+func (m *prologue) writeTrailer(conn *agentConnection) error {
+	if err := conn.WriteMapHeader("os-version", m.osVersion); err != nil {
+		return err
+	}
+	return conn.WriteEndOfHeaders()
+}
+
+// yamlRequest is the agent answer to a prologue offering a .blackfire.yml.
+type yamlRequest struct {
+	response url.Values
+}
+
+// wanted tells whether the agent wants the .blackfire.yml to be sent.
+func (m *yamlRequest) wanted() bool {
+	return m.response.Get("blackfire_yml") == "true"
+}
+
+func readYamlRequest(conn *agentConnection) (*yamlRequest, error) {
+	name, value, err := conn.ReadEncodedHeader()
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case "Blackfire-Response":
+		response, err := url.ParseQuery(strings.TrimSpace(value))
+		if err != nil {
+			return nil, err
+		}
+		return &yamlRequest{response: response}, nil
+	case "Blackfire-Error":
+		return nil, (&errorResponse{message: strings.TrimSpace(value)}).err()
+	default:
+		return nil, fmt.Errorf("Unexpected agent response: %s", strings.TrimSpace(value))
+	}
+}
+
+func writeBlackfireYaml(conn *agentConnection, contents []byte) error {
+	if err := conn.WriteStringHeader("Blackfire-Yaml-Size", strconv.Itoa(len(contents))); err != nil {
+		return err
+	}
+	return conn.WriteRawData(contents)
+}
+
+// readAgentResponse reads the agent answer to the prologue, after which the
+// profile is sent.
+func readAgentResponse(conn *agentConnection) error {
+	header, err := conn.ReadResponse()
+	if err != nil {
+		return err
+	}
+	if message := header.Get("Blackfire-Error"); message != "" {
+		return (&errorResponse{message: message}).err()
+	}
+	return nil
+}
+
+// errorResponse is sent by the agent instead of a response when it refuses
+// the profile.
+type errorResponse struct {
+	message string
+}
+
+func (m *errorResponse) err() error {
+	return newAgentError(m.message)
+}
@@ -0,0 +1,135 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	"github.com/rs/zerolog"
+	. "gopkg.in/check.v1"
+)
+
+// The agent transcripts in fixtures/agent_transcripts record the exchanges
+// with the agent, one line per line on the wire:
+//
+//   # a comment
+//   @ blackfire_yml   the probe offers fixtures/agent_transcripts/blackfire.yml
+//   @ auth id:token   the probe knows the server credentials
+//   > line            sent by the probe
+//   < line            sent by the agent
+//   [profile]         the probe sends the profile
+//   [error] message   the upload fails with this error
+//
+// They are loaded with loadAgentTranscript, and played by the fake agent of
+// fakeBlackfire as well.
+//
+// {go_version} and {os_version} are replaced by the values for the current
+// platform.
+
+// playAgentTranscript plays the agent side of the transcript on the
+// connection.
+func playAgentTranscript(conn net.Conn, lines []string) error {
+	reader := bufio.NewReader(conn)
+	var pending bytes.Buffer
+	for _, line := range lines {
+		if strings.HasPrefix(line, "<") {
+			pending.WriteString(strings.TrimPrefix(line[1:], " ") + "\n")
+			continue
+		}
+		if pending.Len() > 0 {
+			if _, err := conn.Write(pending.Bytes()); err != nil {
+				return err
+			}
+			pending.Reset()
+		}
+		switch {
+		case strings.HasPrefix(line, ">"):
+			expected := strings.TrimPrefix(line[1:], " ") + "\n"
+			actual, err := reader.ReadString('\n')
+			if err != nil {
+				return err
+			}
+			if actual != expected {
+				return fmt.Errorf("expected %q from the probe, got %q", expected, actual)
+			}
+		case line == "[profile]":
+			profile, err := ioutil.ReadAll(reader)
+			if err != nil {
+				return err
+			}
+			if !bytes.HasPrefix(profile, []byte("file-format: BlackfireProbe\n")) {
+				return fmt.Errorf("expected a profile, got %q", profile)
+			}
+		}
+	}
+	if pending.Len() > 0 {
+		_, err := conn.Write(pending.Bytes())
+		return err
+	}
+	return nil
+}
+
+func (s *BlackfireSuite) TestAgentProtocolTranscripts(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	filenames, err := filepath.Glob("fixtures/agent_transcripts/*.txt")
+	c.Assert(err, IsNil)
+	c.Assert(len(filenames) > 0, Equals, true)
+
+	for _, filename := range filenames {
+		transcript, err := loadAgentTranscript(filepath.Base(filename))
+		c.Assert(err, IsNil)
+
+		logger := zerolog.Nop()
+		client, err := NewAgentClient(newConfiguration(&Configuration{
+			AgentTimeout: time.Second,
+			Logger:       &logger,
+		}))
+		c.Assert(err, IsNil)
+		c.Assert(client.setBlackfireQuery(fakeQuery), IsNil)
+		if transcript.blackfireYaml {
+			client.blackfireYamlPath = "fixtures/agent_transcripts/blackfire.yml"
+		}
+		client.serverID, client.serverToken = transcript.serverID, transcript.serverToken
+
+		probeConn, agentConn := net.Pipe()
+		played := make(chan error, 1)
+		go func() {
+			defer agentConn.Close()
+			played <- playAgentTranscript(agentConn, transcript.lines)
+		}()
+		conn := &agentConnection{
+			conn:   probeConn,
+			reader: bufio.NewReader(probeConn),
+			writer: bufio.NewWriter(probeConn),
+			logger: &logger,
+		}
+		_, err = client.uploadProfile(context.Background(), conn, pprof_reader.NewProfile(), "", nil)
+		conn.Close()
+		c.Assert(<-played, IsNil, Commentf("%s", filename))
+		if transcript.expectedError == "" {
+			c.Assert(err, IsNil, Commentf("%s", filename))
+		} else {
+			c.Assert(err, ErrorMatches, transcript.expectedError, Commentf("%s", filename))
+		}
+	}
+}
+
+func (s *BlackfireSuite) TestAgentErrors(c *C) {
+	c.Assert(newAgentError("Invalid signature").Err, Equals, ErrAgentInvalidSignature)
+	c.Assert(newAgentError("The signature has expired").Err, Equals, ErrAgentQueryExpired)
+	c.Assert(newAgentError("Unknown server ID").Err, Equals, ErrAgentInvalidCredentials)
+	c.Assert(newAgentError("Quota exceeded").Err, Equals, ErrAgentQuotaExceeded)
+	c.Assert(newAgentError("Something else").Err, IsNil)
+	c.Assert(newAgentError("Something else"), ErrorMatches, "Blackfire-Error: Something else")
+}
@@ -1,11 +1,14 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
 package blackfire
 
 import (
-	"errors"
+	"context"
 	"time"
-)
 
-var ProfilerErrorAlreadyProfiling = errors.New("A Blackfire profile is currently in progress. Please wait for it to finish.")
+	"github.com/blackfireio/go-blackfire/bf_format"
+)
 
 // Configure explicitely configures the probe. This should be done before any other API calls.
 //
@@ -34,6 +37,45 @@ func EnableNowFor(duration time.Duration) Ender {
 	return globalProbe.ender
 }
 
+// EnableNowForWithOptions profiles the current process for the specified
+// duration with options specific to this profile, then connects to the agent
+// and uploads the generated profile.
+func EnableNowForWithOptions(duration time.Duration, options ProfileOptions) Ender {
+	globalProbe.EnableNowForWithOptions(duration, options)
+	return globalProbe.ender
+}
+
+// EnableWithOptions profiles the current process with options specific to
+// this profile, such as its title, duration or CPU sample rate, then connects
+// to the agent and uploads the generated profile.
+func EnableWithOptions(options ProfileOptions) Ender {
+	globalProbe.EnableWithOptions(options)
+	return globalProbe.ender
+}
+
+// WaitAndEnable profiles the current process for the specified duration like
+// EnableNowFor, but waits for the profile in progress, if any, to end first.
+// It gives up when the context is done.
+func WaitAndEnable(ctx context.Context, duration time.Duration) (Ender, error) {
+	err := globalProbe.WaitAndEnable(ctx, duration)
+	return globalProbe.ender, err
+}
+
+// ProfileStartup profiles the startup of the process for the specified
+// duration, then uploads the profile in the background (see
+// Probe.ProfileStartup).
+func ProfileStartup(duration time.Duration) Ender {
+	globalProbe.ProfileStartup(duration)
+	return globalProbe.ender
+}
+
+// ProfileWithCallback profiles for the specified duration, then uploads the
+// profile in the background and calls back with the outcome of the upload
+// (see Probe.ProfileWithCallback).
+func ProfileWithCallback(duration time.Duration, callback func(ProfileResult)) error {
+	return globalProbe.ProfileWithCallback(duration, callback)
+}
+
 // EnableNow starts profiling. Profiling will continue until you call StopProfiling().
 // If you forget to stop profiling, it will automatically stop after the maximum
 // allowed duration (DefaultMaxProfileDuration or whatever you set via SetMaxProfileDuration()).
@@ -53,6 +95,20 @@ func Disable() {
 	globalProbe.Disable()
 }
 
+// Pause stops profiling without ending the current profile, so that only the
+// relevant phases of a task are profiled. It fails if the probe is not
+// profiling.
+func Pause() error {
+	return globalProbe.Pause()
+}
+
+// Resume resumes profiling after Pause, for the rest of the duration the
+// profile was enabled for. When the profile ends, the timeline shows a gap
+// marker between the profiled segments. It fails if the probe is not paused.
+func Resume() error {
+	return globalProbe.Resume()
+}
+
 // End ends the current profile, then blocks until the result is uploaded
 // to the agent.
 func End() {
@@ -66,19 +122,83 @@ func EndNoWait() {
 	globalProbe.EndNoWait()
 }
 
+// EndToFile ends the current profile, then writes it in Blackfire format to
+// the specified file instead of uploading it to the agent. It blocks until
+// the file is written.
+func EndToFile(path string) error {
+	return globalProbe.EndToFile(path)
+}
+
+// GetProfile fetches a profile from the Blackfire API, with the configured
+// client credentials. Profiles that are not finalized yet can be waited for
+// with Profile.Wait.
+func GetProfile(ctx context.Context, uuid string) (*Profile, error) {
+	return globalProbe.GetProfile(ctx, uuid)
+}
+
+// AssertProfile waits for a profile to be finalized, then checks its
+// envelope, to fail tests when the costs of the profiled code exceed a
+// budget:
+//
+//	err := blackfire.AssertProfile(ctx, uuid, blackfire.EnvelopeBudget{
+//		CPU: 50 * time.Millisecond,
+//	}.Check)
+func AssertProfile(ctx context.Context, uuid string, check func(Envelope) error) error {
+	return globalProbe.AssertProfile(ctx, uuid, check)
+}
+
+// RetryUpload uploads again the last profile the agent failed to receive, with
+// a new Blackfire query. It does nothing if there is no such profile.
+func RetryUpload() error {
+	return globalProbe.RetryUpload()
+}
+
 // GenerateSubProfileQuery generates a Blackfire query
 // to attach a subprofile with the current one as a parent
 func GenerateSubProfileQuery() (string, error) {
 	return globalProbe.GenerateSubProfileQuery()
 }
 
-// SetCurrentTitle Sets the title to use for following profiles
+// SetCurrentTitle Sets the title to use for following profiles, unless they
+// are given their own title (see EnableNowForWithOptions).
 func SetCurrentTitle(title string) {
 	globalProbe.SetCurrentTitle(title)
 }
 
+// CurrentProbeOptions returns the options requested by the server for the
+// current profile of the global probe (see Probe.CurrentProbeOptions).
+func CurrentProbeOptions() bf_format.ProbeOptions {
+	return globalProbe.CurrentProbeOptions()
+}
+
+// CurrentProfile returns the profile being recorded by the global probe (see
+// Probe.CurrentProfile).
+func CurrentProfile() (*Profile, error) {
+	return globalProbe.CurrentProfile()
+}
+
+// CachedProfile returns the profile being recorded by the global probe,
+// without blocking (see Probe.CachedProfile).
+func CachedProfile() *Profile {
+	return globalProbe.CachedProfile()
+}
+
+// GlobalProbe returns the probe used by the package-level API, for the
+// integrations expecting a *Probe.
+func GlobalProbe() *Probe {
+	return globalProbe
+}
+
 // globalProbe is the access point for all probe functionality. The API, signal,
 // and HTTP interfaces perform all operations by proxying to globalProbe. This
 // ensures that mutexes and other guards are respected, and no interface can
 // trigger functionality that others can't, or in a way that others can't.
 var globalProbe = newProbe()
+
+// AutoEnable starts profiling for Configuration.AutoEnableDuration if
+// auto-enabling is requested, by BLACKFIRE_AUTO_ENABLE or the auto_enable
+// option of the Blackfire query. It only has effect once. Import the
+// autoenable package to call it at startup.
+func AutoEnable() {
+	globalProbe.autoEnable()
+}
@@ -0,0 +1,376 @@
+package blackfire
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// The declarations shared by the probe and its no-op implementation (see
+// noop.go).
+
+// ProfilerErrorAlreadyProfiling is returned when trying to enable profiling
+// while a profile is already in progress.
+var ProfilerErrorAlreadyProfiling = errors.New("A Blackfire profile is currently in progress. Please wait for it to finish.")
+
+// ProfilerErrorCPUProfilerInUse is returned when trying to enable profiling
+// while another probe of the process is profiling the CPU: the Go runtime
+// only has one CPU profiler.
+var ProfilerErrorCPUProfilerInUse = errors.New("The CPU profiler is in use by another Blackfire probe. Please wait for its profile to finish.")
+
+// Errors returned when the agent misbehaves while a profile is uploaded.
+var (
+	ErrAgentTimeout        = errors.New("The Blackfire agent did not answer in time")
+	ErrAgentHeaderTooLarge = errors.New("The Blackfire agent sent a header that is too large")
+	ErrAgentTooManyHeaders = errors.New("The Blackfire agent sent too many headers")
+)
+
+// AgentProtocolError is returned when the agent sends a malformed header.
+type AgentProtocolError struct {
+	Line string
+}
+
+func (e *AgentProtocolError) Error() string {
+	return fmt.Sprintf("Could not parse header: [%s]", e.Line)
+}
+
+// Errors reported by the agent when it refuses a profile (see AgentError).
+var (
+	ErrAgentInvalidSignature   = errors.New("The Blackfire query signature is invalid")
+	ErrAgentQueryExpired       = errors.New("The Blackfire query has expired")
+	ErrAgentInvalidCredentials = errors.New("The Blackfire server credentials are invalid")
+	ErrAgentQuotaExceeded      = errors.New("The Blackfire profile quota is exceeded")
+)
+
+// AgentError is returned when the agent refuses a profile with a
+// Blackfire-Error response. Err is the matching ErrAgent* error, or nil when
+// the message is not a known one.
+type AgentError struct {
+	Message string
+	Err     error
+}
+
+var agentErrorMessages = []struct {
+	substring string
+	err       error
+}{
+	{"expired", ErrAgentQueryExpired},
+	{"signature", ErrAgentInvalidSignature},
+	{"server id", ErrAgentInvalidCredentials},
+	{"server token", ErrAgentInvalidCredentials},
+	{"credentials", ErrAgentInvalidCredentials},
+	{"quota", ErrAgentQuotaExceeded},
+}
+
+func newAgentError(message string) *AgentError {
+	e := &AgentError{Message: message}
+	lowered := strings.ToLower(message)
+	for _, known := range agentErrorMessages {
+		if strings.Contains(lowered, known.substring) {
+			e.Err = known.err
+			break
+		}
+	}
+	return e
+}
+
+func (e *AgentError) Error() string {
+	return fmt.Sprintf("Blackfire-Error: %s", e.Message)
+}
+
+// Unwrap returns the matching ErrAgent* error, for errors.Is.
+func (e *AgentError) Unwrap() error {
+	return e.Err
+}
+
+type Ender interface {
+	End()
+	EndNoWait()
+}
+
+// ErrRateLimited is wrapped by the RateLimitError returned when a profile is
+// not started because too many profiles were started in the last hour.
+var ErrRateLimited = errors.New("Too many Blackfire profiles were started in the last hour")
+
+// RateLimitError is returned when a profile is not started because a rate
+// limit is reached (see Configuration.MaxProfilesPerHour).
+type RateLimitError struct {
+	// The trigger whose limit is reached ("signal", "http" or "auto"), or
+	// empty for the overall limit.
+	Trigger string
+	// How long to wait until a profile can be started again.
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Trigger == "" {
+		return fmt.Sprintf("%s, retry in %v", ErrRateLimited, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s by the %s trigger, retry in %v", ErrRateLimited, e.Trigger, e.RetryAfter)
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return ErrRateLimited
+}
+
+// ProfileResult is the outcome of a profile, as reported to the callback of
+// ProfileWithCallback.
+type ProfileResult struct {
+	Title string
+	// The UUID and URL of the profile, when it was sent to the agent.
+	UUID string
+	URL  string
+	// The error which prevented the profile from being uploaded, if any.
+	Err error
+}
+
+// TitleVariables are the variables of the title templates (see
+// Configuration.TitleTemplate), resolved when the profile ends.
+type TitleVariables struct {
+	// The title the profile has without a template (see SetCurrentTitle and
+	// ProfileOptions.Title).
+	Title    string
+	Hostname string
+	// The name of the executable, without its directory nor extension.
+	Executable string
+	// The method and path of the request, for the profiles of the middleware.
+	Method string
+	Route  string
+	// What started the profile: "http", "signal", "auto", or "" for the API.
+	Trigger string
+	// When the profile ended (or started, for the default title).
+	Time time.Time
+}
+
+// ProfileOptions are options applying to a single profile, which take
+// precedence over the probe-wide settings.
+type ProfileOptions struct {
+	// Title of the profile. Defaults to the current title (see SetCurrentTitle).
+	Title string
+	// How long to profile for. Defaults to the maximum profile duration.
+	Duration time.Duration
+	// CPU sampling rate of the profile. Defaults to
+	// Configuration.DefaultCPUSampleRateHz.
+	CPUSampleRateHz int
+	// Tags attached to the profile, recorded in its Context header.
+	Tags map[string]string
+	// Cost dimensions to collect (DimensionCPU, DimensionMemory,
+	// DimensionNetwork), replacing the ones requested by the server.
+	// Defaults to the ones requested by the server.
+	Dimensions []string
+}
+
+// The cost dimensions that can be collected for a profile (see
+// ProfileOptions.Dimensions).
+const (
+	DimensionCPU     = "cpu"
+	DimensionMemory  = "memory"
+	DimensionNetwork = "network"
+)
+
+func (o ProfileOptions) validate() error {
+	if o.Duration < 0 {
+		return fmt.Errorf("invalid profile duration %v", o.Duration)
+	}
+	if o.CPUSampleRateHz < 0 {
+		return fmt.Errorf("invalid CPU sample rate %d", o.CPUSampleRateHz)
+	}
+	for _, dimension := range o.Dimensions {
+		switch dimension {
+		case DimensionCPU, DimensionMemory, DimensionNetwork:
+		default:
+			return fmt.Errorf("unknown cost dimension %q", dimension)
+		}
+	}
+	return nil
+}
+
+// tagsContext returns the tags of the profile, URL-encoded.
+func (o ProfileOptions) tagsContext() string {
+	values := url.Values{}
+	for name, value := range o.Tags {
+		values.Set(name, value)
+	}
+	return values.Encode()
+}
+
+// RequestCapture selects the attributes of the requests profiled by the
+// middleware which are recorded in the Context header of their profiles.
+type RequestCapture struct {
+	Method     bool
+	Path       bool
+	StatusCode bool
+	Duration   bool
+	// Redact, if set, is called with the name ("method", "path",
+	// "status_code" or "duration") and the value of each attribute, and
+	// returns the value to record. An empty value drops the attribute.
+	Redact func(name, value string) string
+	// Maximum size of the recorded attributes, URL-encoded (default 1024
+	// bytes). The attributes that don't fit are dropped.
+	MaxSize int
+}
+
+// ProbeStats reports the overhead of a probe, accumulated over all the
+// profiles it recorded.
+type ProbeStats struct {
+	// Number of profiles started and ended.
+	ProfilesStarted int
+	Profiles        int
+	// Time spent writing heap snapshots when profiling is disabled.
+	HeapSnapshotTime time.Duration
+	// Time spent converting the pprof profiles to Blackfire profiles.
+	ConversionTime time.Duration
+	// Time spent encoding the profiles in Blackfire format and uploading
+	// them to the agent (or writing them to the output file).
+	UploadTime time.Duration
+	// Number of profiles successfully uploaded (or written to the output
+	// file) or not, and the total size of the uploaded payloads.
+	UploadsSucceeded int
+	UploadsFailed    int
+	PayloadBytes     uint64
+	// Samples left out of the profiles, because they had no call stack or
+	// were taken in goroutines not being profiled.
+	DroppedSamples uint64
+	// Stacks truncated to Configuration.MaxStackDepth.
+	TruncatedStacks uint64
+	// Number of profiles not started because of a rate limit.
+	ProfilesRateLimited int
+}
+
+// UploadProgress reports the progress of the upload of a profile to the agent
+// (see Configuration.OnUploadProgress).
+type UploadProgress struct {
+	Title string
+	// Number of bytes of the payload written so far, and its total size.
+	Written int
+	Total   int
+	// Time elapsed since the upload started, including the exchange of the
+	// headers with the agent.
+	Elapsed time.Duration
+}
+
+// SinkStats reports the deliveries of profiles to an additional sink (see
+// Configuration.Sinks).
+type SinkStats struct {
+	Name             string
+	UploadsSucceeded int
+	UploadsFailed    int
+	PayloadBytes     uint64
+	// Error of the last failed delivery, if the last delivery failed.
+	LastError error
+}
+
+// overheadHeader formats the stats for the probe-overhead profile header.
+func (s ProbeStats) overheadHeader() string {
+	values := url.Values{}
+	values.Set("profiles", strconv.Itoa(s.Profiles))
+	values.Set("heap_snapshot_us", strconv.FormatInt(int64(s.HeapSnapshotTime/time.Microsecond), 10))
+	values.Set("conversion_us", strconv.FormatInt(int64(s.ConversionTime/time.Microsecond), 10))
+	values.Set("upload_us", strconv.FormatInt(int64(s.UploadTime/time.Microsecond), 10))
+	values.Set("dropped_samples", strconv.FormatUint(s.DroppedSamples, 10))
+	return values.Encode()
+}
+
+// AgentDiagnosis reports whether the probe is able to upload profiles.
+type AgentDiagnosis struct {
+	// The agent socket, and whether it accepts connections.
+	AgentSocket     string
+	SocketReachable bool
+	SocketError     error
+
+	// The Blackfire API endpoint, whether it answered the signing request
+	// and whether it accepted the credentials. The signing request is
+	// skipped when no credentials are configured (profiling is then only
+	// possible with a Blackfire query).
+	Endpoint          string
+	EndpointReachable bool
+	SigningSkipped    bool
+	AuthOK            bool
+	AuthError         error
+}
+
+// Err returns the first problem found, or nil if profiles can be uploaded.
+func (d *AgentDiagnosis) Err() error {
+	if !d.SocketReachable {
+		return fmt.Errorf("Blackfire agent unreachable at %s: %v", d.AgentSocket, d.SocketError)
+	}
+	if d.SigningSkipped {
+		return nil
+	}
+	if !d.EndpointReachable {
+		return fmt.Errorf("Blackfire API unreachable at %s: %v", d.Endpoint, d.AuthError)
+	}
+	if !d.AuthOK {
+		return fmt.Errorf("Blackfire credentials rejected: %v", d.AuthError)
+	}
+	return nil
+}
+
+// GoroutineLabel is the pprof label key used by ProfileGoroutine to tag
+// goroutines.
+const GoroutineLabel = "blackfire"
+
+// MuxOption customizes the ServeMux returned by NewServeMuxFor.
+type MuxOption func(*muxOptions)
+
+type muxOptions struct {
+	prefix        string
+	methods       []string
+	maxDuration   time.Duration
+	validateTitle func(title string) error
+	logger        *zerolog.Logger
+	statusPath    string
+}
+
+// MuxPrefix sets the path under which the endpoints are served.
+func MuxPrefix(prefix string) MuxOption {
+	return func(o *muxOptions) {
+		o.prefix = prefix
+	}
+}
+
+// MuxMethods restricts the HTTP methods accepted by the endpoints changing
+// the profiler state (enable, disable, end), typically to POST only.
+func MuxMethods(methods ...string) MuxOption {
+	return func(o *muxOptions) {
+		o.methods = methods
+	}
+}
+
+// MuxMaxDuration rejects profiling requests asking for a longer duration, and
+// caps the default duration applied to the requests not specifying one (see
+// Configuration.DefaultProfileDuration).
+func MuxMaxDuration(duration time.Duration) MuxOption {
+	return func(o *muxOptions) {
+		o.maxDuration = duration
+	}
+}
+
+// MuxTitleValidator rejects profiling requests whose title is refused by the
+// validate function.
+func MuxTitleValidator(validate func(title string) error) MuxOption {
+	return func(o *muxOptions) {
+		o.validateTitle = validate
+	}
+}
+
+// MuxLogger sets the logger used by the endpoints, instead of the probe's.
+func MuxLogger(logger *zerolog.Logger) MuxOption {
+	return func(o *muxOptions) {
+		o.logger = logger
+	}
+}
+
+// Reasons a profile was stopped without being asked to, as reported by
+// StopReason.
+const (
+	// The duration of the profile elapsed.
+	StopReasonDuration = "duration"
+	// The profile reached the maximum duration (MaxProfileDuration).
+	StopReasonMaxDuration = "max_duration"
+)
@@ -0,0 +1,15 @@
+// Package autoenable starts profiling the program at startup when requested
+// by BLACKFIRE_AUTO_ENABLE or the auto_enable option of BLACKFIRE_QUERY (see
+// blackfire.AutoEnable). Import it for its side effect from the main package:
+//
+//	import _ "github.com/blackfireio/go-blackfire/autoenable"
+//
+// The profile is uploaded when the auto-enable duration expires, which spares
+// short-lived workers the boilerplate in their main function.
+package autoenable
+
+import "github.com/blackfireio/go-blackfire"
+
+func init() {
+	blackfire.AutoEnable()
+}
@@ -2,42 +2,87 @@ package bf_format
 
 import (
 	"bufio"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
 	"os"
+	"regexp"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/blackfireio/go-blackfire/pprof_reader"
 	"github.com/blackfireio/osinfo"
 )
 
-// Write a parsed profile out as a Blackfire profile.
+// Headers are the headers of a Blackfire profile, by name.
+type Headers map[string]string
+
+// headerOrder is the order in which the headers are written, after
+// file-format which always comes first. The other headers follow, sorted by
+// name, then the timeline headers.
+var headerOrder = []string{
+	"Cost-Dimensions",
+	"graph-root-id",
+	"probed-os",
+	"profiler-type",
+	"probed-language",
+	"probed-runtime",
+	"probed-cpu-sample-rate",
+	"probed-features",
+	"Context",
+	"Profile-Title",
+}
+
+// names returns the names of the headers, in the order they are written.
+func (h Headers) names() []string {
+	names := make([]string, 0, len(h))
+	known := make(map[string]bool, len(headerOrder))
+	for _, name := range headerOrder {
+		known[name] = true
+		if _, ok := h[name]; ok {
+			names = append(names, name)
+		}
+	}
+	var others []string
+	for name := range h {
+		if !known[name] {
+			others = append(others, name)
+		}
+	}
+	sort.Strings(others)
+	return append(names, others...)
+}
+
+// Writer writes profiles in the Blackfire format. The zero value writes them
+// like WriteBFFormat.
+type Writer struct {
+	// CustomizeHeaders, if not nil, is called with the headers of each
+	// profile before they are written. It may add, change or remove headers;
+	// file-format is not part of them, as it always comes first.
+	CustomizeHeaders func(headers Headers, profile *pprof_reader.Profile)
+}
+
+// WriteBFFormat writes a parsed profile out as a Blackfire profile, with the
+// options requested by the server and the specified title, if any.
 func WriteBFFormat(profile *pprof_reader.Profile, w io.Writer, options ProbeOptions, title string) (err error) {
-	const headerCostDimensions = "cpu pmu"
-	const headerProfiledLanguage = "go"
-	const headerProfilerType = "statistical"
+	return (&Writer{}).Write(w, profile, options, title)
+}
 
-	osInfo, err := osinfo.GetOSInfo()
+// Write writes a parsed profile out as a Blackfire profile, like
+// WriteBFFormat. The output only depends on the profile, the options, the
+// title and the headers: they are written in a stable order, so that the
+// same profile is always written the same way.
+func (bw *Writer) Write(w io.Writer, profile *pprof_reader.Profile, options ProbeOptions, title string) (err error) {
+	headers, err := ProfileHeaders(profile, options, title)
 	if err != nil {
 		return
 	}
-
-	headers := make(map[string]string)
-	headers["Cost-Dimensions"] = headerCostDimensions
-	headers["graph-root-id"] = "go"
-	headers["probed-os"] = osInfo.Name
-	headers["profiler-type"] = headerProfilerType
-	headers["probed-language"] = headerProfiledLanguage
-	headers["probed-runtime"] = runtime.Version()
-	headers["probed-cpu-sample-rate"] = strconv.Itoa(profile.CpuSampleRateHz)
-	headers["probed-features"] = generateProbedFeaturesHeader(options)
-	headers["Context"] = generateContextHeader()
-
-	if title != "" {
-		headers["Profile-Title"] = fmt.Sprintf(`{"blackfire-metadata":{"title":"%s"}}`, title)
+	if bw.CustomizeHeaders != nil {
+		bw.CustomizeHeaders(headers, profile)
 	}
 
 	bufW := bufio.NewWriter(w)
@@ -53,8 +98,8 @@ func WriteBFFormat(profile *pprof_reader.Profile, w io.Writer, options ProbeOpti
 	}
 
 	// Begin headers
-	for k, v := range headers {
-		if _, err = bufW.WriteString(fmt.Sprintf("%s: %s\n", k, v)); err != nil {
+	for _, name := range headers.names() {
+		if _, err = bufW.WriteString(fmt.Sprintf("%s: %s\n", name, headers[name])); err != nil {
 			return
 		}
 	}
@@ -71,12 +116,216 @@ func WriteBFFormat(profile *pprof_reader.Profile, w io.Writer, options ProbeOpti
 	}
 
 	// Profile data
-	err = writeSamples(profile, bufW)
+	if iterations := options.AggregSamples(); iterations > 1 {
+		profile = profile.AggregateSamples(iterations)
+	}
+	dimensions := costDimensionsFromOptions(options)
+	dimensions.metrics = profile.MetricNames()
+	err = writeSamples(profile, bufW, dimensions)
 
 	return
 }
 
-func generateContextHeaderFromArgs(args []string) string {
+// ProfileHeaders returns the headers describing the profile, as written by
+// WriteBFFormat before the timeline headers requested with the
+// flag_timespan option.
+func ProfileHeaders(profile *pprof_reader.Profile, options ProbeOptions, title string) (Headers, error) {
+	const headerProfilerType = "statistical"
+
+	osInfo, err := osinfo.GetOSInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(Headers)
+	headers["Cost-Dimensions"] = CostDimensionsHeader(options, profile.MetricNames())
+	headers["graph-root-id"] = graphRoot(profile)
+	headers["probed-os"] = osInfo.Name
+	headers["profiler-type"] = headerProfilerType
+	headers["probed-language"] = "go"
+	if profile.Language != "" {
+		headers["probed-language"] = profile.Language
+	}
+	headers["probed-runtime"] = runtime.Version()
+	if profile.Runtime != "" {
+		headers["probed-runtime"] = profile.Runtime
+	}
+	headers["probed-cpu-sample-rate"] = strconv.Itoa(profile.CpuSampleRateHz)
+	headers["probed-features"] = ProbedFeaturesHeader(options)
+	headers["Context"] = generateContextHeader(profile.SensitiveArgs)
+	if profile.Context != nil {
+		headers["Context"] = profile.Context.Encode()
+	}
+	if context := profile.Headers["Context"]; context != "" {
+		// Context added by the probe, such as the profiled request.
+		if headers["Context"] != "" {
+			context = headers["Context"] + "&" + context
+		}
+		headers["Context"] = context
+	}
+
+	for k, v := range profile.Headers {
+		if _, ok := headers[k]; !ok {
+			headers[k] = v
+		}
+	}
+
+	if title != "" {
+		if headers["Profile-Title"], err = titleHeader(title); err != nil {
+			return nil, err
+		}
+	}
+
+	// Values such as the title or the context may come from the profiled
+	// requests, and must not break the header lines.
+	for k, v := range headers {
+		headers[k] = headerValueReplacer.Replace(v)
+	}
+	return headers, nil
+}
+
+var headerValueReplacer = strings.NewReplacer("\r", "", "\n", "")
+
+// titleHeader returns the Profile-Title header setting the title of the
+// profile.
+func titleHeader(title string) (string, error) {
+	var metadata struct {
+		Metadata struct {
+			Title string `json:"title"`
+		} `json:"blackfire-metadata"`
+	}
+	metadata.Metadata.Title = title
+	data, err := json.Marshal(metadata)
+	return string(data), err
+}
+
+// graphRoot returns the name of the root node of the graph.
+func graphRoot(profile *pprof_reader.Profile) string {
+	if profile.GraphRoot != "" {
+		return profile.GraphRoot
+	}
+	return "go"
+}
+
+// CostDimensionsHeader returns the Cost-Dimensions header listing the costs
+// written for each edge: the ones enabled by the options, followed by the
+// custom metrics.
+func CostDimensionsHeader(options ProbeOptions, metrics []string) string {
+	dimensions := costDimensionsFromOptions(options)
+	dimensions.metrics = metrics
+	return dimensions.header()
+}
+
+// costDimensions are the costs written for each edge: the CPU time and the
+// memory, unless the server disabled them with the flag_cpu and flag_memory
+// options, the network traffic if it enabled it with flag_nw, and the custom
+// metrics recorded in the profile.
+type costDimensions struct {
+	cpu     bool
+	memory  bool
+	network bool
+	metrics []string
+}
+
+func costDimensionsFromOptions(options ProbeOptions) costDimensions {
+	return costDimensions{
+		cpu:     options.GetBool(OptionFlagCPU, true),
+		memory:  options.GetBool(OptionFlagMemory, true),
+		network: options.GetBool(OptionFlagNW, false),
+	}
+}
+
+func (d costDimensions) header() string {
+	var names []string
+	if d.cpu {
+		names = append(names, "cpu")
+	}
+	if d.memory {
+		names = append(names, "pmu")
+	}
+	if d.network {
+		names = append(names, "nw_in", "nw_out")
+	}
+	for _, metric := range d.metrics {
+		names = append(names, "metric_"+metric)
+	}
+	return strings.Join(names, " ")
+}
+
+// edgeCosts are the costs of an edge of the graph.
+type edgeCosts struct {
+	count      int
+	cpuTime    uint64
+	memUsage   uint64
+	networkIn  uint64
+	networkOut uint64
+	metrics    map[string]uint64
+}
+
+// costs formats the costs of an edge, starting with its call count.
+func (d costDimensions) costs(c edgeCosts) string {
+	costs := strconv.Itoa(c.count)
+	if d.cpu {
+		costs += " " + strconv.FormatUint(c.cpuTime, 10)
+	}
+	if d.memory {
+		costs += " " + strconv.FormatUint(c.memUsage, 10)
+	}
+	if d.network {
+		costs += " " + strconv.FormatUint(c.networkIn, 10) + " " + strconv.FormatUint(c.networkOut, 10)
+	}
+	for _, metric := range d.metrics {
+		costs += " " + strconv.FormatUint(c.metrics[metric], 10)
+	}
+	return costs
+}
+
+// maskedValue replaces the values of the sensitive arguments.
+const maskedValue = "***"
+
+// MaskArgs masks the sensitive command line arguments: the values of the
+// flags whose name matches one of the patterns (--password=value or
+// --password value), and the other arguments matching one of them.
+func MaskArgs(args []string, patterns []*regexp.Regexp) []string {
+	matches := func(s string) bool {
+		for _, pattern := range patterns {
+			if pattern.MatchString(s) {
+				return true
+			}
+		}
+		return false
+	}
+
+	masked := make([]string, len(args))
+	copy(masked, args)
+	// The program name is never masked.
+	for i := 1; i < len(masked); i++ {
+		arg := masked[i]
+		if !strings.HasPrefix(arg, "-") {
+			if matches(arg) {
+				masked[i] = maskedValue
+			}
+			continue
+		}
+		if eq := strings.Index(arg, "="); eq >= 0 {
+			if matches(arg[:eq]) {
+				masked[i] = arg[:eq+1] + maskedValue
+			}
+			continue
+		}
+		if matches(arg) && i+1 < len(masked) && !strings.HasPrefix(masked[i+1], "-") {
+			i++
+			masked[i] = maskedValue
+		}
+	}
+	return masked
+}
+
+// ContextHeader returns the Context header describing the command line of the
+// profiled program, the values of the arguments matching sensitiveArgs being
+// masked (see Configuration.SensitiveArgs in the blackfire package).
+func ContextHeader(args []string, sensitiveArgs []*regexp.Regexp) string {
+	args = MaskArgs(args, sensitiveArgs)
 	s := strings.Builder{}
 	s.WriteString("script=")
 	s.WriteString(url.QueryEscape(args[0]))
@@ -89,25 +338,34 @@ func generateContextHeaderFromArgs(args []string) string {
 	return s.String()
 }
 
-func generateContextHeader() string {
-	return generateContextHeaderFromArgs(os.Args)
+func generateContextHeader(sensitiveArgs []*regexp.Regexp) string {
+	return ContextHeader(os.Args, sensitiveArgs)
 }
 
-func writeSamples(profile *pprof_reader.Profile, bufW *bufio.Writer) (err error) {
+func writeSamples(profile *pprof_reader.Profile, bufW *bufio.Writer, dimensions costDimensions) (err error) {
 	totalCPUTime := uint64(0)
 	totalMemUsage := uint64(0)
+	totalNetworkIn := uint64(0)
+	totalNetworkOut := uint64(0)
+	totalMetrics := make(map[string]uint64)
+	root := graphRoot(profile)
 
 	for _, sample := range profile.Samples {
 		totalCPUTime += sample.CPUTime
+		totalNetworkIn += sample.NetworkIn
+		totalNetworkOut += sample.NetworkOut
+		for name, value := range sample.Metrics {
+			totalMetrics[name] += value
+		}
 
 		if len(sample.Stack) == 0 {
 			continue
 		}
 
-		// Fake "go" top-of-stack
-		if _, err = bufW.WriteString(fmt.Sprintf("go==>%s//%d %d %d\n",
-			sample.Stack[0].Name,
-			sample.Count, sample.CPUTime, sample.MemUsage)); err != nil {
+		// Fake root top-of-stack
+		if _, err = bufW.WriteString(fmt.Sprintf("%s==>%s//%s\n",
+			root, sample.Stack[0].Name,
+			dimensions.costs(edgeCosts{sample.Count, sample.CPUTime, sample.MemUsage, sample.NetworkIn, sample.NetworkOut, sample.Metrics}))); err != nil {
 			return
 		}
 
@@ -115,20 +373,28 @@ func writeSamples(profile *pprof_reader.Profile, bufW *bufio.Writer) (err error)
 		// Skip index 0 because every edge needs a begin and end node
 		for iStack := len(sample.Stack) - 1; iStack > 0; iStack-- {
 			f := sample.Stack[iStack]
-			edgeMemCost := f.DistributedMemoryCost * uint64(sample.Count)
+			edgeMemCost := sample.FrameMemoryCost(iStack)
+			if profile.MemoryAttribution == pprof_reader.MemoryLeafOnly {
+				// Only the leaf is accounted for memory, not its callers.
+				if iStack != len(sample.Stack)-1 {
+					edgeMemCost = 0
+				}
+				stackMemUsage = edgeMemCost
+			} else {
+				stackMemUsage += edgeMemCost
+			}
 			totalMemUsage += edgeMemCost
-			stackMemUsage += edgeMemCost
 
 			fPrev := sample.Stack[iStack-1]
-			if _, err = bufW.WriteString(fmt.Sprintf("%s==>%s//%d %d %d\n",
+			if _, err = bufW.WriteString(fmt.Sprintf("%s==>%s//%s\n",
 				fPrev.Name, f.Name,
-				sample.Count, sample.CPUTime, stackMemUsage)); err != nil {
+				dimensions.costs(edgeCosts{sample.Count, sample.CPUTime, stackMemUsage, sample.NetworkIn, sample.NetworkOut, sample.Metrics}))); err != nil {
 				return
 			}
 		}
 	}
 
-	if _, err = bufW.WriteString(fmt.Sprintf("==>go//%d %d %d\n", 1, totalCPUTime, totalMemUsage)); err != nil {
+	if _, err = bufW.WriteString(fmt.Sprintf("==>%s//%s\n", root, dimensions.costs(edgeCosts{1, totalCPUTime, totalMemUsage, totalNetworkIn, totalNetworkOut, totalMetrics}))); err != nil {
 		return
 	}
 
@@ -151,15 +417,15 @@ func (t *timelineEntry) String() string {
 func writeTimelineData(profile *pprof_reader.Profile, bufW *bufio.Writer) (err error) {
 	tlEntriesByEndTime := make([]*timelineEntry, 0, 10)
 
-	// Insert 2-level fake root so that the timeline visualizer has "go" as the
-	// top of the stack.
+	// Insert 2-level fake root so that the timeline visualizer has the root of
+	// the graph ("go" by default) as the top of the stack.
 	fakeStackTop := []*pprof_reader.Function{
 		&pprof_reader.Function{
 			Name:           "golang",
 			ReferenceCount: 1,
 		},
 		&pprof_reader.Function{
-			Name:           "go",
+			Name:           graphRoot(profile),
 			ReferenceCount: 1,
 		},
 	}
@@ -240,6 +506,9 @@ func writeTimelineData(profile *pprof_reader.Profile, bufW *bufio.Writer) (err e
 		tlEntriesByEndTime = append(tlEntriesByEndTime, tlEntry)
 	}
 
+	tlEntriesByEndTime = insertGCPauses(tlEntriesByEndTime, profile, currentCPUTime)
+	tlEntriesByEndTime = insertGaps(tlEntriesByEndTime, profile, currentCPUTime)
+
 	for i, entry := range tlEntriesByEndTime {
 		name := entry.Function.Name
 
@@ -265,25 +534,122 @@ func writeTimelineData(profile *pprof_reader.Profile, bufW *bufio.Writer) (err e
 	return
 }
 
+// insertGCPauses adds the GC pauses of the profile to the timeline entries, as
+// spans under a synthetic "runtime.GC" parent. Since the timeline is based on
+// CPU time, pauses are placed proportionally to their wall clock position.
+func insertGCPauses(entries []*timelineEntry, profile *pprof_reader.Profile, totalCPUTime uint64) []*timelineEntry {
+	if len(profile.GCPauses) == 0 || profile.Duration <= 0 {
+		return entries
+	}
+
+	gcParent := &pprof_reader.Function{
+		Name:           "runtime.GC",
+		ReferenceCount: 1,
+	}
+	gcPause := &pprof_reader.Function{
+		Name:           "runtime.GC.pause",
+		ReferenceCount: 1,
+	}
+
+	gcEntries := make([]*timelineEntry, 0, len(profile.GCPauses))
+	for _, pause := range profile.GCPauses {
+		gcEntries = append(gcEntries, &timelineEntry{
+			Parent:   gcParent,
+			Function: gcPause,
+			CPUStart: wallToCPUTime(pause.Start, profile, totalCPUTime),
+			CPUEnd:   wallToCPUTime(pause.Start+pause.Duration, profile, totalCPUTime),
+		})
+	}
+	return mergeTimelineEntries(entries, gcEntries)
+}
+
+// insertGaps adds markers to the timeline entries where profiling was paused,
+// under a synthetic "blackfire.paused" parent. Since paused time isn't
+// profiled, the markers have no width.
+func insertGaps(entries []*timelineEntry, profile *pprof_reader.Profile, totalCPUTime uint64) []*timelineEntry {
+	if len(profile.Gaps) == 0 || profile.Duration <= 0 {
+		return entries
+	}
+
+	pausedParent := &pprof_reader.Function{
+		Name:           "blackfire.paused",
+		ReferenceCount: 1,
+	}
+
+	gapEntries := make([]*timelineEntry, 0, len(profile.Gaps))
+	for _, gap := range profile.Gaps {
+		at := wallToCPUTime(gap.Start, profile, totalCPUTime)
+		gapEntries = append(gapEntries, &timelineEntry{
+			Parent: pausedParent,
+			Function: &pprof_reader.Function{
+				Name:           fmt.Sprintf("blackfire.gap(%v)", gap.Duration.Round(time.Millisecond)),
+				ReferenceCount: 1,
+			},
+			CPUStart: at,
+			CPUEnd:   at,
+		})
+	}
+	return mergeTimelineEntries(entries, gapEntries)
+}
+
+// wallToCPUTime converts a wall clock position in the profile to a position in
+// the CPU time based timeline.
+func wallToCPUTime(d time.Duration, profile *pprof_reader.Profile, totalCPUTime uint64) uint64 {
+	if d > profile.Duration {
+		d = profile.Duration
+	}
+	return uint64(float64(totalCPUTime) * float64(d) / float64(profile.Duration))
+}
+
+// mergeTimelineEntries merges two lists of timeline entries sorted by end time.
+func mergeTimelineEntries(entries, others []*timelineEntry) []*timelineEntry {
+	merged := make([]*timelineEntry, 0, len(entries)+len(others))
+	for _, other := range others {
+		for len(entries) > 0 && entries[0].CPUEnd <= other.CPUEnd {
+			merged = append(merged, entries[0])
+			entries = entries[1:]
+		}
+		merged = append(merged, other)
+	}
+	return append(merged, entries...)
+}
+
+// Names of the probe options honoured by the Go probe, as found in the
+// Blackfire query or in the signing response.
+const (
+	OptionSignature     = "signature"
+	OptionExpires       = "expires"
+	OptionAgentIDs      = "agentIds"
+	OptionAutoEnable    = "auto_enable"
+	OptionAggregSamples = "aggreg_samples"
+	OptionFlagCPU       = "flag_cpu"
+	OptionFlagMemory    = "flag_memory"
+	OptionFlagNW        = "flag_nw"
+	OptionFlagTimespan  = "flag_timespan"
+	OptionSubProfile    = "sub_profile"
+)
+
+// The options known to the Blackfire probes of all languages, which are
+// forwarded in the probed-features header.
 var allowedProbedFeatures = map[string]bool{
-	"signature":               true,
-	"expires":                 true,
-	"agentIds":                true,
-	"auto_enable":             true,
-	"aggreg_samples":          true,
-	"flag_cpu":                true,
-	"flag_memory":             true,
+	OptionSignature:           true,
+	OptionExpires:             true,
+	OptionAgentIDs:            true,
+	OptionAutoEnable:          true,
+	OptionAggregSamples:       true,
+	OptionFlagCPU:             true,
+	OptionFlagMemory:          true,
 	"flag_no_builtins":        true,
-	"flag_nw":                 true,
+	OptionFlagNW:              true,
 	"flag_fn_args":            true,
-	"flag_timespan":           true,
+	OptionFlagTimespan:        true,
 	"flag_pdo":                true,
 	"flag_sessions":           true,
 	"flag_yml":                true,
 	"flag_composer":           true,
 	"config_yml":              true,
 	"profile_title":           true,
-	"sub_profile":             true,
+	OptionSubProfile:          true,
 	"timespan_threshold":      true,
 	"no_pruning":              true,
 	"no_signature_forwarding": true,
@@ -295,22 +661,30 @@ func isAllowedProbedFeature(name string) bool {
 	return ok
 }
 
-func generateProbedFeaturesHeader(options ProbeOptions) string {
-	var builder strings.Builder
-	firstItem := true
-	for k, v := range options {
-		if !isAllowedProbedFeature(k) {
-			continue
+// ProbedFeaturesHeader returns the probed-features header, listing the
+// options of the profile known to the Blackfire probes, sorted by name.
+func ProbedFeaturesHeader(options ProbeOptions) string {
+	var names []string
+	for name := range options {
+		if isAllowedProbedFeature(name) {
+			names = append(names, name)
 		}
-		if !firstItem {
+	}
+	sort.Strings(names)
+	var builder strings.Builder
+	for i, name := range names {
+		if i > 0 {
 			builder.WriteString("&")
 		}
-		builder.WriteString(fmt.Sprintf("%v=%v", k, v))
-		firstItem = false
+		builder.WriteString(fmt.Sprintf("%v=%v", name, options[name]))
 	}
 	return builder.String()
 }
 
+// ProbeOptions are the options requested by the server for a profile. Their
+// values are either strings (from a Blackfire query) or whatever the JSON
+// decoder chose (from a signing response), so they are best read with the
+// typed getters.
 type ProbeOptions map[string]interface{}
 
 func (p ProbeOptions) getOption(name string) interface{} {
@@ -320,8 +694,58 @@ func (p ProbeOptions) getOption(name string) interface{} {
 	return nil
 }
 
+// GetString returns the value of an option as a string, or defaultValue if
+// the option is not set.
+func (p ProbeOptions) GetString(name string, defaultValue string) string {
+	value := p.getOption(name)
+	if value == nil {
+		return defaultValue
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// GetBool returns the value of an option as a boolean (1, 0, true, false),
+// or defaultValue if the option is not set or not a boolean.
+func (p ProbeOptions) GetBool(name string, defaultValue bool) bool {
+	if value, ok := p.getOption(name).(bool); ok {
+		return value
+	}
+	value, err := strconv.ParseBool(p.GetString(name, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// GetInt returns the value of an option as an integer, or defaultValue if
+// the option is not set or not an integer.
+func (p ProbeOptions) GetInt(name string, defaultValue int) int {
+	if value, ok := p.getOption(name).(float64); ok && value == float64(int(value)) {
+		return int(value)
+	}
+	value, err := strconv.Atoi(p.GetString(name, ""))
+	if err != nil {
+		return defaultValue
+	}
+	return value
+}
+
+// IsTimespanFlagSet tells whether the server asked for the timeline of the
+// profile.
 func (p ProbeOptions) IsTimespanFlagSet() bool {
-	// Super ugly, but the actual type can be anything the json decoder chooses,
-	// so we must go by its string representation.
-	return fmt.Sprintf("%v", p.getOption("flag_timespan")) == "1"
+	return p.GetBool(OptionFlagTimespan, false)
+}
+
+// IsAutoEnableSet tells whether the probe must start profiling at startup.
+func (p ProbeOptions) IsAutoEnableSet() bool {
+	return p.GetBool(OptionAutoEnable, false)
+}
+
+// AggregSamples returns the number of iterations the server asked to
+// aggregate the profile over, or 1 if no aggregation was requested.
+func (p ProbeOptions) AggregSamples() int {
+	if iterations := p.GetInt(OptionAggregSamples, 1); iterations > 1 {
+		return iterations
+	}
+	return 1
 }
@@ -1,28 +1,46 @@
 package bf_format
 
 import (
+	"bufio"
 	"bytes"
+	"flag"
+	"io/ioutil"
+	"net/url"
+	"path/filepath"
+	"regexp"
 	"runtime"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/blackfireio/go-blackfire/pprof_reader"
 	"github.com/blackfireio/osinfo"
 	"github.com/stretchr/testify/assert"
 )
 
-type Headers map[string]interface{}
+type testHeaders map[string]interface{}
 
 func TestGenerateContextStringFromSlice(t *testing.T) {
 	args := []string{"./test", "--bar"}
 	expected := "script=.%2Ftest&argv%5B0%5D=.%2Ftest&argv%5B1%5D=--bar"
-	got := generateContextHeaderFromArgs(args)
+	got := ContextHeader(args, nil)
 	if expected != got {
 		t.Errorf("generateContextStringFromSlice: Expected %v. Got %v", expected, got)
 	}
 }
 
+func TestMaskArgs(t *testing.T) {
+	assert := assert.New(t)
+	patterns := []*regexp.Regexp{regexp.MustCompile(`password$`), regexp.MustCompile(`^sk_live_`)}
+	args := []string{"./test", "--db-password=secret", "--password", "secret", "--verbose", "sk_live_123", "--password"}
+	expected := []string{"./test", "--db-password=***", "--password", "***", "--verbose", "***", "--password"}
+	assert.Equal(expected, MaskArgs(args, patterns))
+	assert.Equal("script=.%2Ftest&argv%5B0%5D=.%2Ftest&argv%5B1%5D=--password&argv%5B2%5D=%2A%2A%2A",
+		ContextHeader([]string{"./test", "--password", "secret"}, patterns))
+	assert.Equal(args, MaskArgs(args, nil))
+}
+
 func TestProbeOptionsAccessors(t *testing.T) {
 	assert := assert.New(t)
 	options := make(ProbeOptions)
@@ -40,6 +58,46 @@ func TestProbeOptionsAccessors(t *testing.T) {
 
 	options["flag_timespan"] = 1
 	assert.True(options.IsTimespanFlagSet())
+
+	assert.False(options.IsAutoEnableSet())
+	options["auto_enable"] = "1"
+	assert.True(options.IsAutoEnableSet())
+
+	assert.Equal(1, options.AggregSamples())
+
+	options["aggreg_samples"] = "abc"
+	assert.Equal(1, options.AggregSamples())
+
+	options["aggreg_samples"] = float64(10)
+	assert.Equal(10, options.AggregSamples())
+}
+
+func TestProbeOptionsTypedGetters(t *testing.T) {
+	assert := assert.New(t)
+	options := ProbeOptions{
+		"string":    "value",
+		"one":       "1",
+		"json-true": true,
+		"json-zero": float64(0),
+		"json-int":  float64(42),
+		"json-real": 1.5,
+	}
+
+	assert.Equal("value", options.GetString("string", "default"))
+	assert.Equal("0", options.GetString("json-zero", "default"))
+	assert.Equal("default", options.GetString("unknown", "default"))
+
+	assert.True(options.GetBool("one", false))
+	assert.True(options.GetBool("json-true", false))
+	assert.False(options.GetBool("json-zero", true))
+	assert.True(options.GetBool("string", true))
+	assert.True(options.GetBool("unknown", true))
+
+	assert.Equal(1, options.GetInt("one", -1))
+	assert.Equal(42, options.GetInt("json-int", -1))
+	assert.Equal(-1, options.GetInt("json-real", -1))
+	assert.Equal(-1, options.GetInt("string", -1))
+	assert.Equal(-1, options.GetInt("unknown", -1))
 }
 
 func TestWriteBFFormat(t *testing.T) {
@@ -49,13 +107,19 @@ func TestWriteBFFormat(t *testing.T) {
 		Count:   1,
 		CPUTime: 100,
 	})
+	metricsProfile := pprof_reader.NewProfile()
+	metricsProfile.AddMetricSample([]pprof_reader.StackFrame{{Function: "main"}, {Function: "query"}}, "rows", 2, 30)
+	metricsProfile.AddMetricSample([]pprof_reader.StackFrame{{Function: "main"}}, "cache_hits", 1, 5)
+	contextProfile := pprof_reader.NewProfile()
+	contextProfile.Context = url.Values{"job": {"42"}}
+	contextProfile.Headers = map[string]string{"Context": "request_method=GET"}
 
 	cases := []struct {
 		name            string
 		profile         *pprof_reader.Profile
 		options         ProbeOptions
 		title           string
-		expectedHeaders Headers
+		expectedHeaders testHeaders
 		expectedBody    string
 	}{
 		{
@@ -63,7 +127,7 @@ func TestWriteBFFormat(t *testing.T) {
 			pprof_reader.NewProfile(),
 			make(ProbeOptions),
 			"",
-			Headers{},
+			testHeaders{},
 			"==>go//1 0 0\n",
 		},
 		{
@@ -71,7 +135,7 @@ func TestWriteBFFormat(t *testing.T) {
 			pprof_reader.NewProfile(),
 			make(ProbeOptions),
 			"This is my Title",
-			Headers{
+			testHeaders{
 				"Profile-Title": `{"blackfire-metadata":{"title":"This is my Title"}}`,
 			},
 			"==>go//1 0 0\n",
@@ -85,7 +149,7 @@ func TestWriteBFFormat(t *testing.T) {
 				"no_pruning":  "false",
 			},
 			"",
-			Headers{},
+			testHeaders{},
 			"==>go//1 0 0\n",
 		},
 		{
@@ -96,7 +160,7 @@ func TestWriteBFFormat(t *testing.T) {
 				"ignored": "true",
 			},
 			"",
-			Headers{"probed-features": ProbeOptions{}},
+			testHeaders{"probed-features": ProbeOptions{}},
 			"==>go//1 0 0\n",
 		},
 		{
@@ -104,9 +168,65 @@ func TestWriteBFFormat(t *testing.T) {
 			validProfile,
 			ProbeOptions{},
 			"",
-			Headers{},
+			testHeaders{},
 			"==>go//1 100 0\n",
 		},
+		{
+			"With aggregated samples",
+			validProfile,
+			ProbeOptions{
+				"aggreg_samples": "4",
+			},
+			"",
+			testHeaders{},
+			"==>go//1 25 0\n",
+		},
+		{
+			"Without memory",
+			validProfile,
+			ProbeOptions{
+				"flag_memory": "0",
+			},
+			"",
+			testHeaders{"Cost-Dimensions": "cpu"},
+			"==>go//1 100\n",
+		},
+		{
+			"Without CPU",
+			validProfile,
+			ProbeOptions{
+				"flag_cpu": "0",
+			},
+			"",
+			testHeaders{"Cost-Dimensions": "pmu"},
+			"==>go//1 0\n",
+		},
+		{
+			"With network",
+			validProfile,
+			ProbeOptions{
+				"flag_nw": "1",
+			},
+			"",
+			testHeaders{"Cost-Dimensions": "cpu pmu nw_in nw_out"},
+			"==>go//1 100 0 0 0\n",
+		},
+		{
+			"With metrics",
+			metricsProfile,
+			make(ProbeOptions),
+			"",
+			testHeaders{"Cost-Dimensions": "cpu pmu metric_cache_hits metric_rows"},
+			"go==>main//2 0 0 0 30\nmain==>query//2 0 0 0 30\ngo==>main//1 0 0 5 0\n==>go//1 0 0 5 30\n",
+		},
+		{
+			"With context",
+			contextProfile,
+			make(ProbeOptions),
+			"",
+			testHeaders{"Context": "job=42&request_method=GET"},
+			"==>go//1 0 0\n",
+		},
 		{
 			"All mixed",
 			validProfile,
@@ -117,7 +237,7 @@ func TestWriteBFFormat(t *testing.T) {
 				"ignored":    "true",
 			},
 			"My-title",
-			Headers{
+			testHeaders{
 				"probed-features": ProbeOptions{
 					"signature":  "abcd",
 					"no_pruning": "false",
@@ -136,7 +256,7 @@ func TestWriteBFFormat(t *testing.T) {
 	}
 }
 
-func _TestWriteBFFormat(t *testing.T, profile *pprof_reader.Profile, options ProbeOptions, title string, expectedHeaders Headers, expectedBody string) {
+func _TestWriteBFFormat(t *testing.T, profile *pprof_reader.Profile, options ProbeOptions, title string, expectedHeaders testHeaders, expectedBody string) {
 	assert := assert.New(t)
 	var buffer bytes.Buffer
 
@@ -151,11 +271,10 @@ func _TestWriteBFFormat(t *testing.T, profile *pprof_reader.Profile, options Pro
 	assert.Equal(expectedBody, parts[1])
 }
 
-// headersToMap Order of headers in string is not predictable.
-// Then we convert them back to a map since assert library can
-// handle their comparison.
-func headersToMap(headers string) (m Headers) {
-	m = Headers{}
+// headersToMap converts the headers back to a map, so that the test cases
+// don't depend on their order (see TestWriteBFFormatGolden for it).
+func headersToMap(headers string) (m testHeaders) {
+	m = testHeaders{}
 	for _, line := range strings.Split(headers, "\n") {
 		parts := strings.Split(line, ": ")
 		m[parts[0]] = parts[1]
@@ -174,13 +293,13 @@ func headersToMap(headers string) (m Headers) {
 	return
 }
 
-func defaultHeaders(profile *pprof_reader.Profile, options ProbeOptions, override Headers) (headers Headers) {
+func defaultHeaders(profile *pprof_reader.Profile, options ProbeOptions, override testHeaders) (headers testHeaders) {
 	osInfo, err := osinfo.GetOSInfo()
 	if err != nil {
 		panic("Cannot retrieve osInfo")
 	}
 
-	headers = Headers{
+	headers = testHeaders{
 		"file-format":            "BlackfireProbe",
 		"Cost-Dimensions":        "cpu pmu",
 		"graph-root-id":          "go",
@@ -190,10 +309,233 @@ func defaultHeaders(profile *pprof_reader.Profile, options ProbeOptions, overrid
 		"probed-runtime":         runtime.Version(),
 		"probed-cpu-sample-rate": strconv.Itoa(profile.CpuSampleRateHz),
 		"probed-features":        options,
-		"Context":                generateContextHeader(),
+		"Context":                generateContextHeader(nil),
 	}
 	for k, v := range override {
 		headers[k] = v
 	}
 	return
 }
+
+func TestWriterCustomizeHeaders(t *testing.T) {
+	assert := assert.New(t)
+	profile := pprof_reader.NewProfile()
+	writer := Writer{
+		CustomizeHeaders: func(headers Headers, p *pprof_reader.Profile) {
+			assert.Equal(profile, p)
+			headers["Converter"] = "test"
+			delete(headers, "Context")
+		},
+	}
+	var buffer bytes.Buffer
+	assert.Nil(writer.Write(&buffer, profile, ProbeOptions{}, "title"))
+
+	parts := strings.Split(buffer.String(), "\n\n")
+	headers := headersToMap(parts[0])
+	assert.Equal("test", headers["Converter"])
+	assert.NotContains(headers, "Context")
+	assert.Equal(`{"blackfire-metadata":{"title":"title"}}`, headers["Profile-Title"])
+
+	expected, err := ProfileHeaders(profile, ProbeOptions{}, "title")
+	assert.Nil(err)
+	assert.Equal(len(expected)+1, len(headers))
+}
+
+func TestProfileHeadersEscaping(t *testing.T) {
+	assert := assert.New(t)
+	profile := pprof_reader.NewProfile()
+	profile.Headers = map[string]string{"Context": "path=/a\r\nInjected: 1"}
+
+	headers, err := ProfileHeaders(profile, ProbeOptions{}, "\"}}\nInjected: 1")
+	assert.Nil(err)
+	assert.Equal(`{"blackfire-metadata":{"title":"\"}}\nInjected: 1"}}`, headers["Profile-Title"])
+	assert.Contains(headers["Context"], "path=/aInjected: 1")
+	for name, value := range headers {
+		assert.NotContains(value, "\n", name)
+		assert.NotContains(value, "\r", name)
+	}
+}
+
+func TestWriteBFFormatGraphRoot(t *testing.T) {
+	assert := assert.New(t)
+	profile := pprof_reader.NewProfile()
+	profile.Samples = []*pprof_reader.Sample{
+		{Count: 1, CPUTime: 10, Stack: []*pprof_reader.Function{{Name: "main.main"}}},
+	}
+	profile.GraphRoot = "wasm-host"
+	profile.Language = "wasm"
+	profile.Runtime = "wazero"
+
+	var buffer bytes.Buffer
+	assert.Nil(WriteBFFormat(profile, &buffer, ProbeOptions{}, ""))
+	parts := strings.Split(buffer.String(), "\n\n")
+	headers := headersToMap(parts[0])
+	assert.Equal("wasm-host", headers["graph-root-id"])
+	assert.Equal("wasm", headers["probed-language"])
+	assert.Equal("wazero", headers["probed-runtime"])
+	assert.Contains(parts[1], "wasm-host==>main.main//")
+	assert.Contains(parts[1], "==>wasm-host//")
+}
+
+var update = flag.Bool("update", false, "update the golden files in fixtures")
+
+// readFixtureProfile reads a pprof profile from the fixtures of pprof_reader.
+func readFixtureProfile(t *testing.T, name string) *pprof_reader.Profile {
+	data, err := ioutil.ReadFile(filepath.Join("..", "pprof_reader", "fixtures", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := pprof_reader.ReadFromPProf([]*bytes.Buffer{bytes.NewBuffer(data)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return profile
+}
+
+func TestWriteBFFormatGolden(t *testing.T) {
+	// The headers depending on the platform and the command line are pinned.
+	writer := Writer{
+		CustomizeHeaders: func(headers Headers, profile *pprof_reader.Profile) {
+			headers["probed-os"] = "linux"
+			headers["probed-runtime"] = "go1.11"
+			headers["Context"] = "script=golden"
+			headers["X-Extra"] = "extra"
+		},
+	}
+	cases := []struct {
+		golden  string
+		options ProbeOptions
+		title   string
+	}{
+		{"wt.bf", ProbeOptions{}, ""},
+		{"wt_options.bf", ProbeOptions{OptionFlagMemory: "0", OptionFlagNW: "1", "no_pruning": "1", OptionExpires: "9999999999", OptionSignature: "abc"}, "wt"},
+		{"wt_timespan.bf", ProbeOptions{OptionFlagTimespan: "1"}, "wt"},
+	}
+	for _, c := range cases {
+		t.Run(c.golden, func(t *testing.T) {
+			var buffer bytes.Buffer
+			if err := writer.Write(&buffer, readFixtureProfile(t, "wt.pprof.gz"), c.options, c.title); err != nil {
+				t.Fatal(err)
+			}
+			golden := filepath.Join("fixtures", c.golden)
+			if *update {
+				if err := ioutil.WriteFile(golden, buffer.Bytes(), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			expected, err := ioutil.ReadFile(golden)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assert.Equal(t, string(expected), buffer.String())
+
+			// The output is the same every time.
+			var again bytes.Buffer
+			assert.Nil(t, writer.Write(&again, readFixtureProfile(t, "wt.pprof.gz"), c.options, c.title))
+			assert.Equal(t, buffer.String(), again.String())
+		})
+	}
+}
+
+func TestInsertGCPauses(t *testing.T) {
+	assert := assert.New(t)
+	profile := pprof_reader.NewProfile()
+	profile.Duration = 100 * time.Millisecond
+	profile.GCPauses = []pprof_reader.GCPause{
+		{Start: 10 * time.Millisecond, Duration: 10 * time.Millisecond},
+		{Start: 60 * time.Millisecond, Duration: 20 * time.Millisecond},
+	}
+	f := &pprof_reader.Function{Name: "f"}
+	entries := []*timelineEntry{
+		{Function: f, CPUStart: 0, CPUEnd: 500},
+		{Function: f, CPUStart: 500, CPUEnd: 1000},
+	}
+
+	merged := insertGCPauses(entries, profile, 1000)
+	assert.Equal(4, len(merged))
+	assert.Equal("runtime.GC", merged[0].Parent.Name)
+	assert.Equal(uint64(100), merged[0].CPUStart)
+	assert.Equal(uint64(200), merged[0].CPUEnd)
+	assert.Equal(entries[0], merged[1])
+	assert.Equal(uint64(600), merged[2].CPUStart)
+	assert.Equal(uint64(800), merged[2].CPUEnd)
+	assert.Equal(entries[1], merged[3])
+}
+
+func TestInsertGaps(t *testing.T) {
+	assert := assert.New(t)
+	profile := pprof_reader.NewProfile()
+	profile.Duration = 100 * time.Millisecond
+	profile.Gaps = []pprof_reader.Gap{
+		{Start: 50 * time.Millisecond, Duration: 2 * time.Second},
+	}
+	f := &pprof_reader.Function{Name: "f"}
+	entries := []*timelineEntry{
+		{Function: f, CPUStart: 0, CPUEnd: 400},
+		{Function: f, CPUStart: 400, CPUEnd: 1000},
+	}
+
+	merged := insertGaps(entries, profile, 1000)
+	assert.Equal(3, len(merged))
+	assert.Equal(entries[0], merged[0])
+	assert.Equal("blackfire.paused", merged[1].Parent.Name)
+	assert.Equal("blackfire.gap(2s)", merged[1].Function.Name)
+	assert.Equal(uint64(500), merged[1].CPUStart)
+	assert.Equal(uint64(500), merged[1].CPUEnd)
+	assert.Equal(entries[1], merged[2])
+}
+
+func TestWriteSamplesMemoryAttribution(t *testing.T) {
+	a := &pprof_reader.Function{Name: "a"}
+	b := &pprof_reader.Function{Name: "b", DistributedMemoryCost: 50}
+	c := &pprof_reader.Function{Name: "c", DistributedMemoryCost: 40}
+	cpuSamples := func() []*pprof_reader.Sample {
+		return []*pprof_reader.Sample{
+			{Count: 1, CPUTime: 10, Stack: []*pprof_reader.Function{a, b}},
+			{Count: 1, CPUTime: 20, Stack: []*pprof_reader.Function{a, b, c}},
+		}
+	}
+
+	cases := []struct {
+		name        string
+		attribution pprof_reader.MemoryAttribution
+		samples     []*pprof_reader.Sample
+		expected    string
+	}{
+		{
+			"Distributed",
+			pprof_reader.MemoryDistributed,
+			cpuSamples(),
+			"go==>a//1 10 0\na==>b//1 10 50\ngo==>a//1 20 0\nb==>c//1 20 40\na==>b//1 20 90\n==>go//1 30 140\n",
+		},
+		{
+			"Leaf only",
+			pprof_reader.MemoryLeafOnly,
+			cpuSamples(),
+			"go==>a//1 10 0\na==>b//1 10 50\ngo==>a//1 20 0\nb==>c//1 20 40\na==>b//1 20 0\n==>go//1 30 90\n",
+		},
+		{
+			"Per stack",
+			pprof_reader.MemoryPerStack,
+			[]*pprof_reader.Sample{
+				{Count: 1, CPUTime: 10, Stack: []*pprof_reader.Function{a, b}, MemoryCosts: []uint64{}},
+				{Count: 0, MemUsage: 70, Stack: []*pprof_reader.Function{a, b, c}, MemoryCosts: []uint64{70}},
+			},
+			"go==>a//1 10 0\na==>b//1 10 0\ngo==>a//0 0 70\nb==>c//0 0 70\na==>b//0 0 70\n==>go//1 10 70\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			profile := pprof_reader.NewProfile()
+			profile.MemoryAttribution = c.attribution
+			profile.Samples = c.samples
+
+			var buffer bytes.Buffer
+			bufW := bufio.NewWriter(&buffer)
+			assert.Nil(t, writeSamples(profile, bufW, costDimensions{cpu: true, memory: true}))
+			assert.Nil(t, bufW.Flush())
+			assert.Equal(t, c.expected, buffer.String())
+		})
+	}
+}
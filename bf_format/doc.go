@@ -0,0 +1,29 @@
+// Package bf_format writes profiles in the Blackfire format, the payload
+// uploaded to the Blackfire agent.
+//
+// A profile read by pprof_reader is written with WriteBFFormat, or with a
+// Writer to customize its headers:
+//
+//	profile, err := pprof_reader.ReadFromPProf(cpuBuffers, memBuffers)
+//	if err != nil {
+//		return err
+//	}
+//	writer := bf_format.Writer{
+//		CustomizeHeaders: func(headers bf_format.Headers, profile *pprof_reader.Profile) {
+//			headers["Context"] = "script=converter"
+//		},
+//	}
+//	err = writer.Write(os.Stdout, profile, bf_format.ProbeOptions{}, "Converted profile")
+//
+// The headers are written in a stable order: file-format, the standard
+// headers, the other ones sorted by name, then the timeline headers requested
+// with the flag_timespan option. The same profile is thus always written the
+// same way, byte for byte.
+//
+// The options of the profile, as requested by the Blackfire server in the
+// Blackfire query or the signing response, are passed as ProbeOptions.
+//
+// The exported API of this package follows semantic versioning, like the
+// blackfire package: it only changes in backward compatible ways within a
+// major version.
+package bf_format
@@ -0,0 +1,1017 @@
+file-format: BlackfireProbe
+Cost-Dimensions: cpu nw_in nw_out
+graph-root-id: go
+probed-os: linux
+profiler-type: statistical
+probed-language: go
+probed-runtime: go1.11
+probed-cpu-sample-rate: 100
+probed-features: expires=9999999999&flag_memory=0&flag_nw=1&no_pruning=1&signature=abc
+Context: script=golden
+Profile-Title: {"blackfire-metadata":{"title":"wt"}}
+X-Extra: extra
+
+go==>net/http.(*conn).serve//7 70000 0 0
+syscall.write==>syscall.Syscall//7 70000 0 0
+syscall.Write==>syscall.write//7 70000 0 0
+internal/poll.(*FD).Write==>syscall.Write//7 70000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//7 70000 0 0
+net.(*conn).Write==>net.(*netFD).Write//7 70000 0 0
+net/http.checkConnErrorWriter.Write==>net.(*conn).Write//7 70000 0 0
+bufio.(*Writer).Flush==>net/http.checkConnErrorWriter.Write//7 70000 0 0
+net/http.(*response).finishRequest==>bufio.(*Writer).Flush//7 70000 0 0
+net/http.(*conn).serve==>net/http.(*response).finishRequest//7 70000 0 0
+go==>net/http.(*conn).serve//4 40000 0 0
+syscall.socket==>syscall.RawSyscall//4 40000 0 0
+syscall.Socket==>syscall.socket//4 40000 0 0
+net.sysSocket==>syscall.Socket//4 40000 0 0
+net.socket==>net.sysSocket//4 40000 0 0
+net.internetSocket==>net.socket//4 40000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//4 40000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//4 40000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//4 40000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//4 40000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//4 40000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//4 40000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//4 40000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//4 40000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//4 40000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//4 40000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//4 40000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//4 40000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//4 40000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//4 40000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//4 40000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//4 40000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//4 40000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//4 40000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//4 40000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//4 40000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//4 40000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//4 40000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+github.com/lib/pq.md5s==>runtime.convTslice//1 10000 0 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.md5s//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//3 30000 0 0
+syscall.Close==>syscall.Syscall//3 30000 0 0
+internal/poll.(*FD).destroy==>syscall.Close//3 30000 0 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//3 30000 0 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//3 30000 0 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//3 30000 0 0
+net.(*conn).Close==>net.(*netFD).Close//3 30000 0 0
+github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//3 30000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//3 30000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//3 30000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//3 30000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//3 30000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//3 30000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//3 30000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//3 30000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//3 30000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//3 30000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//3 30000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//3 30000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//3 30000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//3 30000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//3 30000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//3 30000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.netpollopen==>runtime.epollctl//1 10000 0 0
+internal/poll.runtime_pollOpen==>runtime.netpollopen//1 10000 0 0
+internal/poll.(*pollDesc).init==>internal/poll.runtime_pollOpen//1 10000 0 0
+internal/poll.(*FD).Init==>internal/poll.(*pollDesc).init//1 10000 0 0
+net.(*netFD).connect==>internal/poll.(*FD).Init//1 10000 0 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0 0
+net.socket==>net.(*netFD).dial//1 10000 0 0
+net.internetSocket==>net.socket//1 10000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0 0
+runtime.makeslice==>runtime.mallocgc//1 10000 0 0
+net/textproto.(*Reader).ReadMIMEHeader==>runtime.makeslice//1 10000 0 0
+net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//1 10000 0 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0 0
+go==>net/http.(*conn).serve//3 30000 0 0
+syscall.Close==>syscall.Syscall//3 30000 0 0
+internal/poll.(*FD).destroy==>syscall.Close//3 30000 0 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//3 30000 0 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//3 30000 0 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//3 30000 0 0
+net.(*conn).Close==>net.(*netFD).Close//3 30000 0 0
+net/http.(*conn).close==>net.(*conn).Close//3 30000 0 0
+net/http.(*conn).serve.func1==>net/http.(*conn).close//3 30000 0 0
+net/http.(*conn).serve==>net/http.(*conn).serve.func1//3 30000 0 0
+go==>net/http.(*conn).serve//3 30000 0 0
+syscall.write==>syscall.Syscall//3 30000 0 0
+syscall.Write==>syscall.write//3 30000 0 0
+internal/poll.(*FD).Write==>syscall.Write//3 30000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//3 30000 0 0
+net.(*conn).Write==>net.(*netFD).Write//3 30000 0 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//3 30000 0 0
+github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).send//3 30000 0 0
+github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//3 30000 0 0
+github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//3 30000 0 0
+database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//3 30000 0 0
+database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//3 30000 0 0
+database/sql.withLock==>database/sql.(*DB).queryDC.func1//3 30000 0 0
+database/sql.(*DB).queryDC==>database/sql.withLock//3 30000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).queryDC//3 30000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//3 30000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//3 30000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//3 30000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//3 30000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//3 30000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//3 30000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//3 30000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//3 30000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.futexwakeup==>runtime.futex//1 10000 0 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0 0
+github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//1 10000 0 0
+github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//1 10000 0 0
+github.com/lib/pq.(*conn).readBindResponse==>github.com/lib/pq.(*conn).recv1//1 10000 0 0
+github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//1 10000 0 0
+github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//1 10000 0 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//1 10000 0 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//2 20000 0 0
+syscall.write==>syscall.Syscall//2 20000 0 0
+syscall.Write==>syscall.write//2 20000 0 0
+internal/poll.(*FD).Write==>syscall.Write//2 20000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//2 20000 0 0
+net.(*conn).Write==>net.(*netFD).Write//2 20000 0 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//2 20000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//2 20000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//2 20000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//2 20000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//2 20000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//2 20000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//2 20000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//2 20000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//2 20000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//2 20000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//2 20000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//2 20000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//2 20000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.setsockopt==>syscall.Syscall6//1 10000 0 0
+syscall.SetsockoptInt==>syscall.setsockopt//1 10000 0 0
+internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//1 10000 0 0
+net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//1 10000 0 0
+net.(*Dialer).DialContext==>net.setKeepAlivePeriod//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//5 50000 0 0
+syscall.connect==>syscall.Syscall//5 50000 0 0
+syscall.Connect==>syscall.connect//5 50000 0 0
+net.(*netFD).connect==>syscall.Connect//5 50000 0 0
+net.(*netFD).dial==>net.(*netFD).connect//5 50000 0 0
+net.socket==>net.(*netFD).dial//5 50000 0 0
+net.internetSocket==>net.socket//5 50000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//5 50000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//5 50000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//5 50000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//5 50000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//5 50000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//5 50000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//5 50000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//5 50000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//5 50000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//5 50000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//5 50000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//5 50000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//5 50000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//5 50000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//5 50000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//5 50000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//5 50000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//5 50000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//5 50000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//5 50000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//5 50000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//5 50000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0 0
+github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//1 10000 0 0
+github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//1 10000 0 0
+github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).recv1//1 10000 0 0
+github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//1 10000 0 0
+github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//1 10000 0 0
+database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//1 10000 0 0
+database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//1 10000 0 0
+database/sql.withLock==>database/sql.(*DB).queryDC.func1//1 10000 0 0
+database/sql.(*DB).queryDC==>database/sql.withLock//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).queryDC//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.mcall//5 50000 0 0
+runtime.netpoll==>runtime.epollwait//5 50000 0 0
+runtime.findrunnable==>runtime.netpoll//5 50000 0 0
+runtime.schedule==>runtime.findrunnable//5 50000 0 0
+runtime.park_m==>runtime.schedule//5 50000 0 0
+runtime.mcall==>runtime.park_m//5 50000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+net/http.(*connReader).Read==>net.(*conn).Read//1 10000 0 0
+bufio.(*Reader).fill==>net/http.(*connReader).Read//1 10000 0 0
+bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//1 10000 0 0
+bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//1 10000 0 0
+net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//1 10000 0 0
+net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//1 10000 0 0
+net/http.readRequest==>net/textproto.(*Reader).ReadLine//1 10000 0 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+strings.IndexByte==>indexbytebody//1 10000 0 0
+strings.Index==>strings.IndexByte//1 10000 0 0
+strings.genSplit==>strings.Index//1 10000 0 0
+strings.SplitN==>strings.genSplit//1 10000 0 0
+github.com/lib/pq.parseEnviron==>strings.SplitN//1 10000 0 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.newproc1==>runtime.gfget//1 10000 0 0
+runtime.newproc.func1==>runtime.newproc1//1 10000 0 0
+runtime.systemstack==>runtime.newproc.func1//1 10000 0 0
+runtime.newproc==>runtime.systemstack//1 10000 0 0
+net/http.(*connReader).startBackgroundRead==>runtime.newproc//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*connReader).startBackgroundRead//1 10000 0 0
+go==>runtime.mstart//7 70000 0 0
+runtime.sysmon==>runtime.usleep//7 70000 0 0
+runtime.mstart1==>runtime.sysmon//7 70000 0 0
+runtime.mstart==>runtime.mstart1//7 70000 0 0
+go==>runtime.mcall//1 10000 0 0
+runtime.futexsleep==>runtime.futex//1 10000 0 0
+runtime.notesleep==>runtime.futexsleep//1 10000 0 0
+runtime.stopm==>runtime.notesleep//1 10000 0 0
+runtime.findrunnable==>runtime.stopm//1 10000 0 0
+runtime.schedule==>runtime.findrunnable//1 10000 0 0
+runtime.park_m==>runtime.schedule//1 10000 0 0
+runtime.mcall==>runtime.park_m//1 10000 0 0
+go==>runtime.gcBgMarkWorker//1 10000 0 0
+runtime.newArenaMayUnlock==>runtime.memclrNoHeapPointers//1 10000 0 0
+runtime.newMarkBits==>runtime.newArenaMayUnlock//1 10000 0 0
+runtime.(*mspan).sweep==>runtime.newMarkBits//1 10000 0 0
+runtime.(*mcentral).uncacheSpan==>runtime.(*mspan).sweep//1 10000 0 0
+runtime.(*mcache).releaseAll==>runtime.(*mcentral).uncacheSpan//1 10000 0 0
+runtime.(*mcache).prepareForSweep==>runtime.(*mcache).releaseAll//1 10000 0 0
+runtime.procresize==>runtime.(*mcache).prepareForSweep//1 10000 0 0
+runtime.startTheWorldWithSema==>runtime.procresize//1 10000 0 0
+runtime.gcMarkTermination.func3==>runtime.startTheWorldWithSema//1 10000 0 0
+runtime.systemstack==>runtime.gcMarkTermination.func3//1 10000 0 0
+runtime.gcMarkTermination==>runtime.systemstack//1 10000 0 0
+runtime.gcMarkDone==>runtime.gcMarkTermination//1 10000 0 0
+runtime.gcBgMarkWorker==>runtime.gcMarkDone//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//1 10000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//1 10000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//2 20000 0 0
+syscall.write==>syscall.Syscall//2 20000 0 0
+syscall.Write==>syscall.write//2 20000 0 0
+internal/poll.(*FD).Write==>syscall.Write//2 20000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//2 20000 0 0
+net.(*conn).Write==>net.(*netFD).Write//2 20000 0 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//2 20000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//2 20000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//2 20000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//2 20000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//2 20000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//2 20000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//2 20000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//2 20000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//2 20000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//2 20000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//2 20000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//2 20000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//2 20000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.pcvalue==>runtime.step//1 10000 0 0
+runtime.funcspdelta==>runtime.pcvalue//1 10000 0 0
+runtime.gentraceback==>runtime.funcspdelta//1 10000 0 0
+runtime.copystack==>runtime.gentraceback//1 10000 0 0
+runtime.newstack==>runtime.copystack//1 10000 0 0
+time.readFile==>runtime.newstack//1 10000 0 0
+time.loadTzinfoFromDirOrZip==>time.readFile//1 10000 0 0
+time.loadTzinfo==>time.loadTzinfoFromDirOrZip//1 10000 0 0
+time.loadLocation==>time.loadTzinfo//1 10000 0 0
+time.LoadLocation==>time.loadLocation//1 10000 0 0
+github.com/lib/pq.(*conn).processParameterStatus==>time.LoadLocation//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).processParameterStatus//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.mstart//2 20000 0 0
+runtime.futexsleep==>runtime.futex//2 20000 0 0
+runtime.notetsleep_internal==>runtime.futexsleep//2 20000 0 0
+runtime.notetsleep==>runtime.notetsleep_internal//2 20000 0 0
+runtime.sysmon==>runtime.notetsleep//2 20000 0 0
+runtime.mstart1==>runtime.sysmon//2 20000 0 0
+runtime.mstart==>runtime.mstart1//2 20000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+database/sql.(*DB).removeDepLocked==>runtime.mapdelete//1 10000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*DB).removeDepLocked//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+regexp.(*Regexp).FindStringSubmatchIndex==>regexp.(*Regexp).pad//1 10000 0 0
+github.com/gorilla/mux.routeRegexpGroup.setMatch==>regexp.(*Regexp).FindStringSubmatchIndex//1 10000 0 0
+github.com/gorilla/mux.(*Route).Match==>github.com/gorilla/mux.routeRegexpGroup.setMatch//1 10000 0 0
+github.com/gorilla/mux.(*Router).Match==>github.com/gorilla/mux.(*Route).Match//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>github.com/gorilla/mux.(*Router).Match//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//2 20000 0 0
+syscall.read==>syscall.Syscall//2 20000 0 0
+syscall.Read==>syscall.read//2 20000 0 0
+internal/poll.(*FD).Read==>syscall.Read//2 20000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//2 20000 0 0
+net.(*conn).Read==>net.(*netFD).Read//2 20000 0 0
+bufio.(*Reader).Read==>net.(*conn).Read//2 20000 0 0
+io.ReadAtLeast==>bufio.(*Reader).Read//2 20000 0 0
+io.ReadFull==>io.ReadAtLeast//2 20000 0 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//2 20000 0 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//2 20000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//2 20000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//2 20000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//2 20000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//2 20000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//2 20000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//2 20000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//2 20000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//2 20000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//2 20000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//2 20000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//2 20000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//2 20000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//2 20000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+strings.IndexByte==>internal/bytealg.IndexByteString//1 10000 0 0
+strings.Index==>strings.IndexByte//1 10000 0 0
+strings.genSplit==>strings.Index//1 10000 0 0
+strings.SplitN==>strings.genSplit//1 10000 0 0
+github.com/lib/pq.parseEnviron==>strings.SplitN//1 10000 0 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.gcBgMarkWorker//1 10000 0 0
+runtime.gcDrain==>runtime.scanobject//1 10000 0 0
+runtime.gcBgMarkWorker.func2==>runtime.gcDrain//1 10000 0 0
+runtime.systemstack==>runtime.gcBgMarkWorker.func2//1 10000 0 0
+runtime.gcBgMarkWorker==>runtime.systemstack//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.futexwakeup==>runtime.futex//1 10000 0 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+net/http.(*connReader).Read==>net.(*conn).Read//1 10000 0 0
+bufio.(*Reader).fill==>net/http.(*connReader).Read//1 10000 0 0
+bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//1 10000 0 0
+bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//1 10000 0 0
+net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//1 10000 0 0
+net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//1 10000 0 0
+net/http.readRequest==>net/textproto.(*Reader).ReadLine//1 10000 0 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0 0
+go==>runtime.mcall//4 40000 0 0
+runtime.futexsleep==>runtime.futex//4 40000 0 0
+runtime.notesleep==>runtime.futexsleep//4 40000 0 0
+runtime.stopm==>runtime.notesleep//4 40000 0 0
+runtime.findrunnable==>runtime.stopm//4 40000 0 0
+runtime.schedule==>runtime.findrunnable//4 40000 0 0
+runtime.park_m==>runtime.schedule//4 40000 0 0
+runtime.mcall==>runtime.park_m//4 40000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//1 10000 0 0
+github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//1 10000 0 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//1 10000 0 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.write==>syscall.Syscall//1 10000 0 0
+syscall.Write==>syscall.write//1 10000 0 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//1 10000 0 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).send//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.main//1 10000 0 0
+runtime.futexwakeup==>runtime.futex//1 10000 0 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0 0
+runtime.startm==>runtime.notewakeup//1 10000 0 0
+runtime.wakep==>runtime.startm//1 10000 0 0
+runtime.newproc1==>runtime.wakep//1 10000 0 0
+runtime.newproc.func1==>runtime.newproc1//1 10000 0 0
+runtime.systemstack==>runtime.newproc.func1//1 10000 0 0
+runtime.newproc==>runtime.systemstack//1 10000 0 0
+net/http.(*Server).Serve==>runtime.newproc//1 10000 0 0
+net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//1 10000 0 0
+net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//1 10000 0 0
+main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//1 10000 0 0
+main.runServer==>main.(*HttpServer).ListenAndServe//1 10000 0 0
+main.main==>main.runServer//1 10000 0 0
+runtime.main==>main.main//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.newobject==>runtime.mallocgc//1 10000 0 0
+runtime.makemap_small==>runtime.newobject//1 10000 0 0
+github.com/lib/pq.parseEnviron==>runtime.makemap_small//1 10000 0 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*connReader).backgroundRead//1 10000 0 0
+sync.runtime_notifyListNotifyAll==>runtime.readyWithTime//1 10000 0 0
+sync.(*Cond).Broadcast==>sync.runtime_notifyListNotifyAll//1 10000 0 0
+net/http.(*connReader).backgroundRead==>sync.(*Cond).Broadcast//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+encoding/json.ptrEncoder.encode==>encoding/json.structEncoder.encode//1 10000 0 0
+encoding/json.arrayEncoder.encode==>encoding/json.ptrEncoder.encode//1 10000 0 0
+encoding/json.sliceEncoder.encode==>encoding/json.arrayEncoder.encode//1 10000 0 0
+encoding/json.(*encodeState).reflectValue==>encoding/json.sliceEncoder.encode//1 10000 0 0
+encoding/json.(*encodeState).marshal==>encoding/json.(*encodeState).reflectValue//1 10000 0 0
+encoding/json.Marshal==>encoding/json.(*encodeState).marshal//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>encoding/json.Marshal//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0 0
+runtime.growslice==>runtime.mallocgc//1 10000 0 0
+main.(*Store).GetAllWidgets==>runtime.growslice//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.mapaccess1_faststr==>runtime.add//1 10000 0 0
+net/textproto.canonicalMIMEHeaderKey==>runtime.mapaccess1_faststr//1 10000 0 0
+net/textproto.(*Reader).ReadMIMEHeader==>net/textproto.canonicalMIMEHeaderKey//1 10000 0 0
+net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//1 10000 0 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.connect==>syscall.Syscall//1 10000 0 0
+syscall.Connect==>syscall.connect//1 10000 0 0
+net.(*netFD).connect==>syscall.Connect//1 10000 0 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0 0
+net.socket==>net.(*netFD).dial//1 10000 0 0
+net.internetSocket==>net.socket//1 10000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.write==>syscall.Syscall//1 10000 0 0
+syscall.Write==>syscall.write//1 10000 0 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0 0
+github.com/lib/pq.(*conn).sendStartupPacket==>net.(*conn).Write//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).sendStartupPacket//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.assertI2I2==>runtime.getitab//1 10000 0 0
+database/sql.(*DB).execDC==>runtime.assertI2I2//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.setsockopt==>syscall.Syscall6//1 10000 0 0
+syscall.SetsockoptInt==>syscall.setsockopt//1 10000 0 0
+internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//1 10000 0 0
+net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//1 10000 0 0
+net.(*Dialer).DialContext==>net.setKeepAlivePeriod//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+net/http.(*Request).wantsClose==>net/http.hasToken//1 10000 0 0
+net/http.(*conn).readRequest==>net/http.(*Request).wantsClose//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0 0
+go==>runtime.mcall//1 10000 0 0
+runtime.schedule==>runtime.findrunnable//1 10000 0 0
+runtime.park_m==>runtime.schedule//1 10000 0 0
+runtime.mcall==>runtime.park_m//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.futexwakeup==>runtime.futex//1 10000 0 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.getpeername==>syscall.RawSyscall//1 10000 0 0
+syscall.Getpeername==>syscall.getpeername//1 10000 0 0
+net.(*netFD).connect==>syscall.Getpeername//1 10000 0 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0 0
+net.socket==>net.(*netFD).dial//1 10000 0 0
+net.internetSocket==>net.socket//1 10000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.main//1 10000 0 0
+runtime.mallocgc==>runtime.makeSpanClass//1 10000 0 0
+runtime.newobject==>runtime.mallocgc//1 10000 0 0
+net/http.(*Server).newConn==>runtime.newobject//1 10000 0 0
+net/http.(*Server).Serve==>net/http.(*Server).newConn//1 10000 0 0
+net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//1 10000 0 0
+net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//1 10000 0 0
+main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//1 10000 0 0
+main.runServer==>main.(*HttpServer).ListenAndServe//1 10000 0 0
+main.main==>main.runServer//1 10000 0 0
+runtime.main==>main.main//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.futexwakeup==>runtime.futex//1 10000 0 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0 0
+syscall.read==>syscall.Syscall//1 10000 0 0
+syscall.Read==>syscall.read//1 10000 0 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).recv//1 10000 0 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.mcall//1 10000 0 0
+runtime.park_m==>runtime.schedule//1 10000 0 0
+runtime.mcall==>runtime.park_m//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0 0
+runtime.newobject==>runtime.mallocgc//1 10000 0 0
+net.sockaddrToTCP==>runtime.newobject//1 10000 0 0
+net.(*netFD).dial==>net.sockaddrToTCP//1 10000 0 0
+net.socket==>net.(*netFD).dial//1 10000 0 0
+net.internetSocket==>net.socket//1 10000 0 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>runtime.mcall//1 10000 0 0
+runtime.netpoll==>runtime.epollwait//1 10000 0 0
+runtime.findrunnable==>runtime.netpoll//1 10000 0 0
+runtime.schedule==>runtime.findrunnable//1 10000 0 0
+runtime.goexit0==>runtime.schedule//1 10000 0 0
+runtime.mcall==>runtime.goexit0//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.adjustframe==>runtime.getStackMap//1 10000 0 0
+runtime.tracebackdefers==>runtime.adjustframe//1 10000 0 0
+runtime.adjustdefers==>runtime.tracebackdefers//1 10000 0 0
+runtime.copystack==>runtime.adjustdefers//1 10000 0 0
+runtime.newstack==>runtime.copystack//1 10000 0 0
+internal/poll.(*FD).Write==>runtime.newstack//1 10000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//1 10000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//1 10000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+sync.runtime_notifyListWait==>runtime.acquireSudog//1 10000 0 0
+sync.(*Cond).Wait==>sync.runtime_notifyListWait//1 10000 0 0
+net/http.(*connReader).abortPendingRead==>sync.(*Cond).Wait//1 10000 0 0
+net/http.(*response).finishRequest==>net/http.(*connReader).abortPendingRead//1 10000 0 0
+net/http.(*conn).serve==>net/http.(*response).finishRequest//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//1 10000 0 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//1 10000 0 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//1 10000 0 0
+net.(*conn).Close==>net.(*netFD).Close//1 10000 0 0
+github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//1 10000 0 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//1 10000 0 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0 0
+runtime.newobject==>runtime.mallocgc//1 10000 0 0
+context.WithValue==>runtime.newobject//1 10000 0 0
+net/http.(*conn).serve==>context.WithValue//1 10000 0 0
+go==>net/http.(*conn).serve//1 10000 0 0
+syscall.write==>syscall.Syscall//1 10000 0 0
+syscall.Write==>syscall.write//1 10000 0 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//1 10000 0 0
+github.com/lib/pq.(*conn).prepareTo==>github.com/lib/pq.(*conn).send//1 10000 0 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*conn).prepareTo//1 10000 0 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0 0
+==>go//1 950000 0 0
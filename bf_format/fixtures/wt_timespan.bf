@@ -0,0 +1,2623 @@
+file-format: BlackfireProbe
+Cost-Dimensions: cpu pmu
+graph-root-id: go
+probed-os: linux
+profiler-type: statistical
+probed-language: go
+probed-runtime: go1.11
+probed-cpu-sample-rate: 100
+probed-features: flag_timespan=1
+Context: script=golden
+Profile-Title: {"blackfire-metadata":{"title":"wt"}}
+X-Extra: extra
+Threshold-0-start: syscall.write==>syscall.Syscall//0 0
+Threshold-0-end: syscall.write==>syscall.Syscall//70000 0
+Threshold-1-start: syscall.Write==>syscall.write//0 0
+Threshold-1-end: syscall.Write==>syscall.write//70000 0
+Threshold-2-start: internal/poll.(*FD).Write==>syscall.Write//0 0
+Threshold-2-end: internal/poll.(*FD).Write==>syscall.Write//70000 0
+Threshold-3-start: net.(*netFD).Write==>internal/poll.(*FD).Write//0 0
+Threshold-3-end: net.(*netFD).Write==>internal/poll.(*FD).Write//70000 0
+Threshold-4-start: net.(*conn).Write==>net.(*netFD).Write//0 0
+Threshold-4-end: net.(*conn).Write==>net.(*netFD).Write//70000 0
+Threshold-5-start: net/http.checkConnErrorWriter.Write==>net.(*conn).Write//0 0
+Threshold-5-end: net/http.checkConnErrorWriter.Write==>net.(*conn).Write//70000 0
+Threshold-6-start: bufio.(*Writer).Flush==>net/http.checkConnErrorWriter.Write//0 0
+Threshold-6-end: bufio.(*Writer).Flush==>net/http.checkConnErrorWriter.Write//70000 0
+Threshold-7-start: net/http.(*response).finishRequest==>bufio.(*Writer).Flush//0 0
+Threshold-7-end: net/http.(*response).finishRequest==>bufio.(*Writer).Flush//70000 0
+Threshold-8-start: net/http.(*conn).serve==>net/http.(*response).finishRequest//0 0
+Threshold-8-end: net/http.(*conn).serve==>net/http.(*response).finishRequest//70000 0
+Threshold-9-start: syscall.socket==>syscall.RawSyscall//70000 0
+Threshold-9-end: syscall.socket==>syscall.RawSyscall//110000 0
+Threshold-10-start: syscall.Socket==>syscall.socket//70000 0
+Threshold-10-end: syscall.Socket==>syscall.socket//110000 0
+Threshold-11-start: net.sysSocket==>syscall.Socket//70000 0
+Threshold-11-end: net.sysSocket==>syscall.Socket//110000 0
+Threshold-12-start: net.socket==>net.sysSocket//70000 0
+Threshold-12-end: net.socket==>net.sysSocket//110000 0
+Threshold-13-start: net.internetSocket==>net.socket//70000 0
+Threshold-13-end: net.internetSocket==>net.socket//110000 0
+Threshold-14-start: net.(*sysDialer).doDialTCP==>net.internetSocket//70000 0
+Threshold-14-end: net.(*sysDialer).doDialTCP==>net.internetSocket//110000 0
+Threshold-15-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//70000 0
+Threshold-15-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//110000 0
+Threshold-16-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//70000 0
+Threshold-16-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//110000 0
+Threshold-17-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//70000 0
+Threshold-17-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//110000 0
+Threshold-18-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//70000 0
+Threshold-18-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//110000 0
+Threshold-19-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//70000 0
+Threshold-19-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//110000 0
+Threshold-20-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//70000 0
+Threshold-20-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//110000 0
+Threshold-21-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//70000 0
+Threshold-21-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//110000 0
+Threshold-22-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//70000 0
+Threshold-22-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//110000 0
+Threshold-23-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//70000 0
+Threshold-23-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//110000 0
+Threshold-24-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//70000 0
+Threshold-24-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//110000 0
+Threshold-25-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//70000 0
+Threshold-25-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//110000 0
+Threshold-26-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//70000 0
+Threshold-26-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//110000 0
+Threshold-27-start: database/sql.(*DB).query==>database/sql.(*DB).conn//70000 0
+Threshold-27-end: database/sql.(*DB).query==>database/sql.(*DB).conn//110000 0
+Threshold-28-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//70000 0
+Threshold-28-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//110000 0
+Threshold-29-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//70000 0
+Threshold-29-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//110000 0
+Threshold-30-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//70000 0
+Threshold-30-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//110000 0
+Threshold-31-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//70000 0
+Threshold-31-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//110000 0
+Threshold-32-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//70000 0
+Threshold-32-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//110000 0
+Threshold-33-start: github.com/lib/pq.md5s==>runtime.convTslice//110000 0
+Threshold-33-end: github.com/lib/pq.md5s==>runtime.convTslice//120000 0
+Threshold-34-start: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.md5s//110000 0
+Threshold-34-end: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.md5s//120000 0
+Threshold-35-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//110000 0
+Threshold-35-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//120000 0
+Threshold-36-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//110000 0
+Threshold-36-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//120000 0
+Threshold-37-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//110000 0
+Threshold-37-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//120000 0
+Threshold-38-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//110000 0
+Threshold-38-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//120000 0
+Threshold-39-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//110000 0
+Threshold-39-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//120000 0
+Threshold-40-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//110000 0
+Threshold-40-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//120000 0
+Threshold-41-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//110000 0
+Threshold-41-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//120000 0
+Threshold-42-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//110000 0
+Threshold-42-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//120000 0
+Threshold-43-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//110000 0
+Threshold-43-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//120000 0
+Threshold-44-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//110000 0
+Threshold-44-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//120000 0
+Threshold-45-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//110000 0
+Threshold-45-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//120000 0
+Threshold-46-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//110000 0
+Threshold-46-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//120000 0
+Threshold-47-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//110000 0
+Threshold-47-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//120000 0
+Threshold-48-start: syscall.Close==>syscall.Syscall//120000 0
+Threshold-48-end: syscall.Close==>syscall.Syscall//150000 0
+Threshold-49-start: internal/poll.(*FD).destroy==>syscall.Close//120000 0
+Threshold-49-end: internal/poll.(*FD).destroy==>syscall.Close//150000 0
+Threshold-50-start: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//120000 0
+Threshold-50-end: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//150000 0
+Threshold-51-start: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//120000 0
+Threshold-51-end: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//150000 0
+Threshold-52-start: net.(*netFD).Close==>internal/poll.(*FD).Close//120000 0
+Threshold-52-end: net.(*netFD).Close==>internal/poll.(*FD).Close//150000 0
+Threshold-53-start: net.(*conn).Close==>net.(*netFD).Close//120000 0
+Threshold-53-end: net.(*conn).Close==>net.(*netFD).Close//150000 0
+Threshold-54-start: github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//120000 0
+Threshold-54-end: github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//150000 0
+Threshold-55-start: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//120000 0
+Threshold-55-end: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//150000 0
+Threshold-56-start: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//120000 0
+Threshold-56-end: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//150000 0
+Threshold-57-start: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//120000 0
+Threshold-57-end: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//150000 0
+Threshold-58-start: database/sql.(*driverConn).finalClose==>database/sql.withLock//120000 0
+Threshold-58-end: database/sql.(*driverConn).finalClose==>database/sql.withLock//150000 0
+Threshold-59-start: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//120000 0
+Threshold-59-end: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//150000 0
+Threshold-60-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//120000 0
+Threshold-60-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//150000 0
+Threshold-61-start: runtime.netpollopen==>runtime.epollctl//150000 0
+Threshold-61-end: runtime.netpollopen==>runtime.epollctl//160000 0
+Threshold-62-start: internal/poll.runtime_pollOpen==>runtime.netpollopen//150000 0
+Threshold-62-end: internal/poll.runtime_pollOpen==>runtime.netpollopen//160000 0
+Threshold-63-start: internal/poll.(*pollDesc).init==>internal/poll.runtime_pollOpen//150000 0
+Threshold-63-end: internal/poll.(*pollDesc).init==>internal/poll.runtime_pollOpen//160000 0
+Threshold-64-start: internal/poll.(*FD).Init==>internal/poll.(*pollDesc).init//150000 0
+Threshold-64-end: internal/poll.(*FD).Init==>internal/poll.(*pollDesc).init//160000 0
+Threshold-65-start: net.(*netFD).connect==>internal/poll.(*FD).Init//150000 0
+Threshold-65-end: net.(*netFD).connect==>internal/poll.(*FD).Init//160000 0
+Threshold-66-start: net.(*netFD).dial==>net.(*netFD).connect//150000 0
+Threshold-66-end: net.(*netFD).dial==>net.(*netFD).connect//160000 0
+Threshold-67-start: net.socket==>net.(*netFD).dial//150000 0
+Threshold-67-end: net.socket==>net.(*netFD).dial//160000 0
+Threshold-68-start: net.internetSocket==>net.socket//150000 0
+Threshold-68-end: net.internetSocket==>net.socket//160000 0
+Threshold-69-start: net.(*sysDialer).doDialTCP==>net.internetSocket//150000 0
+Threshold-69-end: net.(*sysDialer).doDialTCP==>net.internetSocket//160000 0
+Threshold-70-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//150000 0
+Threshold-70-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//160000 0
+Threshold-71-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//150000 0
+Threshold-71-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//160000 0
+Threshold-72-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//150000 0
+Threshold-72-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//160000 0
+Threshold-73-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//150000 0
+Threshold-73-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//160000 0
+Threshold-74-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//150000 0
+Threshold-74-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//160000 0
+Threshold-75-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//150000 0
+Threshold-75-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//160000 0
+Threshold-76-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//150000 0
+Threshold-76-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//160000 0
+Threshold-77-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//150000 0
+Threshold-77-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//160000 0
+Threshold-78-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//150000 0
+Threshold-78-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//160000 0
+Threshold-79-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//150000 0
+Threshold-79-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//160000 0
+Threshold-80-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//150000 0
+Threshold-80-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//160000 0
+Threshold-81-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//150000 0
+Threshold-81-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//160000 0
+Threshold-82-start: database/sql.(*DB).query==>database/sql.(*DB).conn//120000 0
+Threshold-82-end: database/sql.(*DB).query==>database/sql.(*DB).conn//160000 0
+Threshold-83-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//120000 0
+Threshold-83-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//160000 0
+Threshold-84-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//120000 0
+Threshold-84-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//160000 0
+Threshold-85-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//120000 0
+Threshold-85-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//160000 0
+Threshold-86-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//120000 0
+Threshold-86-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//160000 0
+Threshold-87-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//120000 0
+Threshold-87-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//160000 0
+Threshold-88-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//70000 0
+Threshold-88-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//160000 0
+Threshold-89-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//70000 0
+Threshold-89-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//160000 0
+Threshold-90-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//70000 0
+Threshold-90-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//160000 0
+Threshold-91-start: runtime.mallocgc==>runtime.heapBitsSetType//160000 0
+Threshold-91-end: runtime.mallocgc==>runtime.heapBitsSetType//170000 0
+Threshold-92-start: runtime.makeslice==>runtime.mallocgc//160000 0
+Threshold-92-end: runtime.makeslice==>runtime.mallocgc//170000 0
+Threshold-93-start: net/textproto.(*Reader).ReadMIMEHeader==>runtime.makeslice//160000 0
+Threshold-93-end: net/textproto.(*Reader).ReadMIMEHeader==>runtime.makeslice//170000 0
+Threshold-94-start: net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//160000 0
+Threshold-94-end: net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//170000 0
+Threshold-95-start: net/http.(*conn).readRequest==>net/http.readRequest//160000 0
+Threshold-95-end: net/http.(*conn).readRequest==>net/http.readRequest//170000 0
+Threshold-96-start: net/http.(*conn).serve==>net/http.(*conn).readRequest//160000 0
+Threshold-96-end: net/http.(*conn).serve==>net/http.(*conn).readRequest//170000 0
+Threshold-97-start: syscall.Close==>syscall.Syscall//170000 0
+Threshold-97-end: syscall.Close==>syscall.Syscall//200000 0
+Threshold-98-start: internal/poll.(*FD).destroy==>syscall.Close//170000 0
+Threshold-98-end: internal/poll.(*FD).destroy==>syscall.Close//200000 0
+Threshold-99-start: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//170000 0
+Threshold-99-end: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//200000 0
+Threshold-100-start: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//170000 0
+Threshold-100-end: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//200000 0
+Threshold-101-start: net.(*netFD).Close==>internal/poll.(*FD).Close//170000 0
+Threshold-101-end: net.(*netFD).Close==>internal/poll.(*FD).Close//200000 0
+Threshold-102-start: net.(*conn).Close==>net.(*netFD).Close//170000 0
+Threshold-102-end: net.(*conn).Close==>net.(*netFD).Close//200000 0
+Threshold-103-start: net/http.(*conn).close==>net.(*conn).Close//170000 0
+Threshold-103-end: net/http.(*conn).close==>net.(*conn).Close//200000 0
+Threshold-104-start: net/http.(*conn).serve.func1==>net/http.(*conn).close//170000 0
+Threshold-104-end: net/http.(*conn).serve.func1==>net/http.(*conn).close//200000 0
+Threshold-105-start: net/http.(*conn).serve==>net/http.(*conn).serve.func1//170000 0
+Threshold-105-end: net/http.(*conn).serve==>net/http.(*conn).serve.func1//200000 0
+Threshold-106-start: syscall.write==>syscall.Syscall//200000 0
+Threshold-106-end: syscall.write==>syscall.Syscall//230000 0
+Threshold-107-start: syscall.Write==>syscall.write//200000 0
+Threshold-107-end: syscall.Write==>syscall.write//230000 0
+Threshold-108-start: internal/poll.(*FD).Write==>syscall.Write//200000 0
+Threshold-108-end: internal/poll.(*FD).Write==>syscall.Write//230000 0
+Threshold-109-start: net.(*netFD).Write==>internal/poll.(*FD).Write//200000 0
+Threshold-109-end: net.(*netFD).Write==>internal/poll.(*FD).Write//230000 0
+Threshold-110-start: net.(*conn).Write==>net.(*netFD).Write//200000 0
+Threshold-110-end: net.(*conn).Write==>net.(*netFD).Write//230000 0
+Threshold-111-start: github.com/lib/pq.(*conn).send==>net.(*conn).Write//200000 0
+Threshold-111-end: github.com/lib/pq.(*conn).send==>net.(*conn).Write//230000 0
+Threshold-112-start: github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).send//200000 0
+Threshold-112-end: github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).send//230000 0
+Threshold-113-start: github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//200000 0
+Threshold-113-end: github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//230000 0
+Threshold-114-start: github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//200000 0
+Threshold-114-end: github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//230000 0
+Threshold-115-start: database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//200000 0
+Threshold-115-end: database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//230000 0
+Threshold-116-start: database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//200000 0
+Threshold-116-end: database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//230000 0
+Threshold-117-start: database/sql.withLock==>database/sql.(*DB).queryDC.func1//200000 0
+Threshold-117-end: database/sql.withLock==>database/sql.(*DB).queryDC.func1//230000 0
+Threshold-118-start: database/sql.(*DB).queryDC==>database/sql.withLock//200000 0
+Threshold-118-end: database/sql.(*DB).queryDC==>database/sql.withLock//230000 0
+Threshold-119-start: database/sql.(*DB).query==>database/sql.(*DB).queryDC//200000 0
+Threshold-119-end: database/sql.(*DB).query==>database/sql.(*DB).queryDC//230000 0
+Threshold-120-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//200000 0
+Threshold-120-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//230000 0
+Threshold-121-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//200000 0
+Threshold-121-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//230000 0
+Threshold-122-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//200000 0
+Threshold-122-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//230000 0
+Threshold-123-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//200000 0
+Threshold-123-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//230000 0
+Threshold-124-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//200000 0
+Threshold-124-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//230000 0
+Threshold-125-start: runtime.futexwakeup==>runtime.futex//230000 0
+Threshold-125-end: runtime.futexwakeup==>runtime.futex//240000 0
+Threshold-126-start: runtime.notewakeup==>runtime.futexwakeup//230000 0
+Threshold-126-end: runtime.notewakeup==>runtime.futexwakeup//240000 0
+Threshold-127-start: runtime.entersyscall_sysmon==>runtime.notewakeup//230000 0
+Threshold-127-end: runtime.entersyscall_sysmon==>runtime.notewakeup//240000 0
+Threshold-128-start: runtime.systemstack==>runtime.entersyscall_sysmon//230000 0
+Threshold-128-end: runtime.systemstack==>runtime.entersyscall_sysmon//240000 0
+Threshold-129-start: runtime.reentersyscall==>runtime.systemstack//230000 0
+Threshold-129-end: runtime.reentersyscall==>runtime.systemstack//240000 0
+Threshold-130-start: runtime.entersyscall==>runtime.reentersyscall//230000 0
+Threshold-130-end: runtime.entersyscall==>runtime.reentersyscall//240000 0
+Threshold-131-start: syscall.Syscall==>runtime.entersyscall//230000 0
+Threshold-131-end: syscall.Syscall==>runtime.entersyscall//240000 0
+Threshold-132-start: syscall.read==>syscall.Syscall//230000 0
+Threshold-132-end: syscall.read==>syscall.Syscall//240000 0
+Threshold-133-start: syscall.Read==>syscall.read//230000 0
+Threshold-133-end: syscall.Read==>syscall.read//240000 0
+Threshold-134-start: internal/poll.(*FD).Read==>syscall.Read//230000 0
+Threshold-134-end: internal/poll.(*FD).Read==>syscall.Read//240000 0
+Threshold-135-start: net.(*netFD).Read==>internal/poll.(*FD).Read//230000 0
+Threshold-135-end: net.(*netFD).Read==>internal/poll.(*FD).Read//240000 0
+Threshold-136-start: net.(*conn).Read==>net.(*netFD).Read//230000 0
+Threshold-136-end: net.(*conn).Read==>net.(*netFD).Read//240000 0
+Threshold-137-start: bufio.(*Reader).Read==>net.(*conn).Read//230000 0
+Threshold-137-end: bufio.(*Reader).Read==>net.(*conn).Read//240000 0
+Threshold-138-start: io.ReadAtLeast==>bufio.(*Reader).Read//230000 0
+Threshold-138-end: io.ReadAtLeast==>bufio.(*Reader).Read//240000 0
+Threshold-139-start: io.ReadFull==>io.ReadAtLeast//230000 0
+Threshold-139-end: io.ReadFull==>io.ReadAtLeast//240000 0
+Threshold-140-start: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//230000 0
+Threshold-140-end: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//240000 0
+Threshold-141-start: github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//230000 0
+Threshold-141-end: github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//240000 0
+Threshold-142-start: github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//230000 0
+Threshold-142-end: github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//240000 0
+Threshold-143-start: github.com/lib/pq.(*conn).readBindResponse==>github.com/lib/pq.(*conn).recv1//230000 0
+Threshold-143-end: github.com/lib/pq.(*conn).readBindResponse==>github.com/lib/pq.(*conn).recv1//240000 0
+Threshold-144-start: github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//230000 0
+Threshold-144-end: github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//240000 0
+Threshold-145-start: github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//230000 0
+Threshold-145-end: github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//240000 0
+Threshold-146-start: github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//230000 0
+Threshold-146-end: github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//240000 0
+Threshold-147-start: github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//230000 0
+Threshold-147-end: github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//240000 0
+Threshold-148-start: database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//230000 0
+Threshold-148-end: database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//240000 0
+Threshold-149-start: database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//230000 0
+Threshold-149-end: database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//240000 0
+Threshold-150-start: database/sql.withLock==>database/sql.(*DB).execDC.func2//230000 0
+Threshold-150-end: database/sql.withLock==>database/sql.(*DB).execDC.func2//240000 0
+Threshold-151-start: database/sql.(*DB).execDC==>database/sql.withLock//230000 0
+Threshold-151-end: database/sql.(*DB).execDC==>database/sql.withLock//240000 0
+Threshold-152-start: database/sql.(*DB).exec==>database/sql.(*DB).execDC//230000 0
+Threshold-152-end: database/sql.(*DB).exec==>database/sql.(*DB).execDC//240000 0
+Threshold-153-start: syscall.write==>syscall.Syscall//240000 0
+Threshold-153-end: syscall.write==>syscall.Syscall//260000 0
+Threshold-154-start: syscall.Write==>syscall.write//240000 0
+Threshold-154-end: syscall.Write==>syscall.write//260000 0
+Threshold-155-start: internal/poll.(*FD).Write==>syscall.Write//240000 0
+Threshold-155-end: internal/poll.(*FD).Write==>syscall.Write//260000 0
+Threshold-156-start: net.(*netFD).Write==>internal/poll.(*FD).Write//240000 0
+Threshold-156-end: net.(*netFD).Write==>internal/poll.(*FD).Write//260000 0
+Threshold-157-start: net.(*conn).Write==>net.(*netFD).Write//240000 0
+Threshold-157-end: net.(*conn).Write==>net.(*netFD).Write//260000 0
+Threshold-158-start: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//240000 0
+Threshold-158-end: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//260000 0
+Threshold-159-start: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//240000 0
+Threshold-159-end: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//260000 0
+Threshold-160-start: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//240000 0
+Threshold-160-end: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//260000 0
+Threshold-161-start: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//240000 0
+Threshold-161-end: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//260000 0
+Threshold-162-start: database/sql.(*driverConn).finalClose==>database/sql.withLock//240000 0
+Threshold-162-end: database/sql.(*driverConn).finalClose==>database/sql.withLock//260000 0
+Threshold-163-start: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//240000 0
+Threshold-163-end: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//260000 0
+Threshold-164-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//240000 0
+Threshold-164-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//260000 0
+Threshold-165-start: syscall.setsockopt==>syscall.Syscall6//260000 0
+Threshold-165-end: syscall.setsockopt==>syscall.Syscall6//270000 0
+Threshold-166-start: syscall.SetsockoptInt==>syscall.setsockopt//260000 0
+Threshold-166-end: syscall.SetsockoptInt==>syscall.setsockopt//270000 0
+Threshold-167-start: internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//260000 0
+Threshold-167-end: internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//270000 0
+Threshold-168-start: net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//260000 0
+Threshold-168-end: net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//270000 0
+Threshold-169-start: net.(*Dialer).DialContext==>net.setKeepAlivePeriod//260000 0
+Threshold-169-end: net.(*Dialer).DialContext==>net.setKeepAlivePeriod//270000 0
+Threshold-170-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//260000 0
+Threshold-170-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//270000 0
+Threshold-171-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//260000 0
+Threshold-171-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//270000 0
+Threshold-172-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//260000 0
+Threshold-172-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//270000 0
+Threshold-173-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//260000 0
+Threshold-173-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//270000 0
+Threshold-174-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//260000 0
+Threshold-174-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//270000 0
+Threshold-175-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//260000 0
+Threshold-175-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//270000 0
+Threshold-176-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//260000 0
+Threshold-176-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//270000 0
+Threshold-177-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//260000 0
+Threshold-177-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//270000 0
+Threshold-178-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//240000 0
+Threshold-178-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//270000 0
+Threshold-179-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//230000 0
+Threshold-179-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//270000 0
+Threshold-180-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//230000 0
+Threshold-180-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//270000 0
+Threshold-181-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//230000 0
+Threshold-181-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//270000 0
+Threshold-182-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//230000 0
+Threshold-182-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//270000 0
+Threshold-183-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//230000 0
+Threshold-183-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//270000 0
+Threshold-184-start: syscall.connect==>syscall.Syscall//270000 0
+Threshold-184-end: syscall.connect==>syscall.Syscall//320000 0
+Threshold-185-start: syscall.Connect==>syscall.connect//270000 0
+Threshold-185-end: syscall.Connect==>syscall.connect//320000 0
+Threshold-186-start: net.(*netFD).connect==>syscall.Connect//270000 0
+Threshold-186-end: net.(*netFD).connect==>syscall.Connect//320000 0
+Threshold-187-start: net.(*netFD).dial==>net.(*netFD).connect//270000 0
+Threshold-187-end: net.(*netFD).dial==>net.(*netFD).connect//320000 0
+Threshold-188-start: net.socket==>net.(*netFD).dial//270000 0
+Threshold-188-end: net.socket==>net.(*netFD).dial//320000 0
+Threshold-189-start: net.internetSocket==>net.socket//270000 0
+Threshold-189-end: net.internetSocket==>net.socket//320000 0
+Threshold-190-start: net.(*sysDialer).doDialTCP==>net.internetSocket//270000 0
+Threshold-190-end: net.(*sysDialer).doDialTCP==>net.internetSocket//320000 0
+Threshold-191-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//270000 0
+Threshold-191-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//320000 0
+Threshold-192-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//270000 0
+Threshold-192-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//320000 0
+Threshold-193-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//270000 0
+Threshold-193-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//320000 0
+Threshold-194-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//270000 0
+Threshold-194-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//320000 0
+Threshold-195-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//270000 0
+Threshold-195-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//320000 0
+Threshold-196-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//270000 0
+Threshold-196-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//320000 0
+Threshold-197-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//270000 0
+Threshold-197-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//320000 0
+Threshold-198-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//270000 0
+Threshold-198-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//320000 0
+Threshold-199-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//270000 0
+Threshold-199-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//320000 0
+Threshold-200-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//270000 0
+Threshold-200-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//320000 0
+Threshold-201-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//270000 0
+Threshold-201-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//320000 0
+Threshold-202-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//270000 0
+Threshold-202-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//320000 0
+Threshold-203-start: database/sql.(*DB).query==>database/sql.(*DB).conn//270000 0
+Threshold-203-end: database/sql.(*DB).query==>database/sql.(*DB).conn//320000 0
+Threshold-204-start: syscall.read==>syscall.Syscall//320000 0
+Threshold-204-end: syscall.read==>syscall.Syscall//330000 0
+Threshold-205-start: syscall.Read==>syscall.read//320000 0
+Threshold-205-end: syscall.Read==>syscall.read//330000 0
+Threshold-206-start: internal/poll.(*FD).Read==>syscall.Read//320000 0
+Threshold-206-end: internal/poll.(*FD).Read==>syscall.Read//330000 0
+Threshold-207-start: net.(*netFD).Read==>internal/poll.(*FD).Read//320000 0
+Threshold-207-end: net.(*netFD).Read==>internal/poll.(*FD).Read//330000 0
+Threshold-208-start: net.(*conn).Read==>net.(*netFD).Read//320000 0
+Threshold-208-end: net.(*conn).Read==>net.(*netFD).Read//330000 0
+Threshold-209-start: bufio.(*Reader).Read==>net.(*conn).Read//320000 0
+Threshold-209-end: bufio.(*Reader).Read==>net.(*conn).Read//330000 0
+Threshold-210-start: io.ReadAtLeast==>bufio.(*Reader).Read//320000 0
+Threshold-210-end: io.ReadAtLeast==>bufio.(*Reader).Read//330000 0
+Threshold-211-start: io.ReadFull==>io.ReadAtLeast//320000 0
+Threshold-211-end: io.ReadFull==>io.ReadAtLeast//330000 0
+Threshold-212-start: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//320000 0
+Threshold-212-end: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//330000 0
+Threshold-213-start: github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//320000 0
+Threshold-213-end: github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//330000 0
+Threshold-214-start: github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//320000 0
+Threshold-214-end: github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//330000 0
+Threshold-215-start: github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).recv1//320000 0
+Threshold-215-end: github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).recv1//330000 0
+Threshold-216-start: github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//320000 0
+Threshold-216-end: github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//330000 0
+Threshold-217-start: github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//320000 0
+Threshold-217-end: github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//330000 0
+Threshold-218-start: database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//320000 0
+Threshold-218-end: database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//330000 0
+Threshold-219-start: database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//320000 0
+Threshold-219-end: database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//330000 0
+Threshold-220-start: database/sql.withLock==>database/sql.(*DB).queryDC.func1//320000 0
+Threshold-220-end: database/sql.withLock==>database/sql.(*DB).queryDC.func1//330000 0
+Threshold-221-start: database/sql.(*DB).queryDC==>database/sql.withLock//320000 0
+Threshold-221-end: database/sql.(*DB).queryDC==>database/sql.withLock//330000 0
+Threshold-222-start: database/sql.(*DB).query==>database/sql.(*DB).queryDC//320000 0
+Threshold-222-end: database/sql.(*DB).query==>database/sql.(*DB).queryDC//330000 0
+Threshold-223-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//270000 0
+Threshold-223-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//330000 0
+Threshold-224-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//270000 0
+Threshold-224-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//330000 0
+Threshold-225-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//270000 0
+Threshold-225-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//330000 0
+Threshold-226-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//270000 0
+Threshold-226-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//330000 0
+Threshold-227-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//270000 0
+Threshold-227-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//330000 0
+Threshold-228-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//200000 0
+Threshold-228-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//330000 0
+Threshold-229-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//200000 0
+Threshold-229-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//330000 0
+Threshold-230-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//200000 0
+Threshold-230-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//330000 0
+Threshold-231-start: go==>net/http.(*conn).serve//0 0
+Threshold-231-end: go==>net/http.(*conn).serve//330000 0
+Threshold-232-start: runtime.netpoll==>runtime.epollwait//330000 0
+Threshold-232-end: runtime.netpoll==>runtime.epollwait//380000 0
+Threshold-233-start: runtime.findrunnable==>runtime.netpoll//330000 0
+Threshold-233-end: runtime.findrunnable==>runtime.netpoll//380000 0
+Threshold-234-start: runtime.schedule==>runtime.findrunnable//330000 0
+Threshold-234-end: runtime.schedule==>runtime.findrunnable//380000 0
+Threshold-235-start: runtime.park_m==>runtime.schedule//330000 0
+Threshold-235-end: runtime.park_m==>runtime.schedule//380000 0
+Threshold-236-start: runtime.mcall==>runtime.park_m//330000 0
+Threshold-236-end: runtime.mcall==>runtime.park_m//380000 0
+Threshold-237-start: go==>runtime.mcall//330000 0
+Threshold-237-end: go==>runtime.mcall//380000 0
+Threshold-238-start: syscall.read==>syscall.Syscall//380000 0
+Threshold-238-end: syscall.read==>syscall.Syscall//390000 0
+Threshold-239-start: syscall.Read==>syscall.read//380000 0
+Threshold-239-end: syscall.Read==>syscall.read//390000 0
+Threshold-240-start: internal/poll.(*FD).Read==>syscall.Read//380000 0
+Threshold-240-end: internal/poll.(*FD).Read==>syscall.Read//390000 0
+Threshold-241-start: net.(*netFD).Read==>internal/poll.(*FD).Read//380000 0
+Threshold-241-end: net.(*netFD).Read==>internal/poll.(*FD).Read//390000 0
+Threshold-242-start: net.(*conn).Read==>net.(*netFD).Read//380000 0
+Threshold-242-end: net.(*conn).Read==>net.(*netFD).Read//390000 0
+Threshold-243-start: net/http.(*connReader).Read==>net.(*conn).Read//380000 0
+Threshold-243-end: net/http.(*connReader).Read==>net.(*conn).Read//390000 0
+Threshold-244-start: bufio.(*Reader).fill==>net/http.(*connReader).Read//380000 0
+Threshold-244-end: bufio.(*Reader).fill==>net/http.(*connReader).Read//390000 0
+Threshold-245-start: bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//380000 0
+Threshold-245-end: bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//390000 0
+Threshold-246-start: bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//380000 0
+Threshold-246-end: bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//390000 0
+Threshold-247-start: net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//380000 0
+Threshold-247-end: net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//390000 0
+Threshold-248-start: net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//380000 0
+Threshold-248-end: net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//390000 0
+Threshold-249-start: net/http.readRequest==>net/textproto.(*Reader).ReadLine//380000 0
+Threshold-249-end: net/http.readRequest==>net/textproto.(*Reader).ReadLine//390000 0
+Threshold-250-start: net/http.(*conn).readRequest==>net/http.readRequest//380000 0
+Threshold-250-end: net/http.(*conn).readRequest==>net/http.readRequest//390000 0
+Threshold-251-start: net/http.(*conn).serve==>net/http.(*conn).readRequest//380000 0
+Threshold-251-end: net/http.(*conn).serve==>net/http.(*conn).readRequest//390000 0
+Threshold-252-start: strings.IndexByte==>indexbytebody//390000 0
+Threshold-252-end: strings.IndexByte==>indexbytebody//400000 0
+Threshold-253-start: strings.Index==>strings.IndexByte//390000 0
+Threshold-253-end: strings.Index==>strings.IndexByte//400000 0
+Threshold-254-start: strings.genSplit==>strings.Index//390000 0
+Threshold-254-end: strings.genSplit==>strings.Index//400000 0
+Threshold-255-start: strings.SplitN==>strings.genSplit//390000 0
+Threshold-255-end: strings.SplitN==>strings.genSplit//400000 0
+Threshold-256-start: github.com/lib/pq.parseEnviron==>strings.SplitN//390000 0
+Threshold-256-end: github.com/lib/pq.parseEnviron==>strings.SplitN//400000 0
+Threshold-257-start: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//390000 0
+Threshold-257-end: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//400000 0
+Threshold-258-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//390000 0
+Threshold-258-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//400000 0
+Threshold-259-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//390000 0
+Threshold-259-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//400000 0
+Threshold-260-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//390000 0
+Threshold-260-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//400000 0
+Threshold-261-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//390000 0
+Threshold-261-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//400000 0
+Threshold-262-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//390000 0
+Threshold-262-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//400000 0
+Threshold-263-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//390000 0
+Threshold-263-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//400000 0
+Threshold-264-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//390000 0
+Threshold-264-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//400000 0
+Threshold-265-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//390000 0
+Threshold-265-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//400000 0
+Threshold-266-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//390000 0
+Threshold-266-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//400000 0
+Threshold-267-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//390000 0
+Threshold-267-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//400000 0
+Threshold-268-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//390000 0
+Threshold-268-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//400000 0
+Threshold-269-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//390000 0
+Threshold-269-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//400000 0
+Threshold-270-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//390000 0
+Threshold-270-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//400000 0
+Threshold-271-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//390000 0
+Threshold-271-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//400000 0
+Threshold-272-start: runtime.newproc1==>runtime.gfget//400000 0
+Threshold-272-end: runtime.newproc1==>runtime.gfget//410000 0
+Threshold-273-start: runtime.newproc.func1==>runtime.newproc1//400000 0
+Threshold-273-end: runtime.newproc.func1==>runtime.newproc1//410000 0
+Threshold-274-start: runtime.systemstack==>runtime.newproc.func1//400000 0
+Threshold-274-end: runtime.systemstack==>runtime.newproc.func1//410000 0
+Threshold-275-start: runtime.newproc==>runtime.systemstack//400000 0
+Threshold-275-end: runtime.newproc==>runtime.systemstack//410000 0
+Threshold-276-start: net/http.(*connReader).startBackgroundRead==>runtime.newproc//400000 0
+Threshold-276-end: net/http.(*connReader).startBackgroundRead==>runtime.newproc//410000 0
+Threshold-277-start: net/http.(*conn).serve==>net/http.(*connReader).startBackgroundRead//400000 0
+Threshold-277-end: net/http.(*conn).serve==>net/http.(*connReader).startBackgroundRead//410000 0
+Threshold-278-start: go==>net/http.(*conn).serve//380000 0
+Threshold-278-end: go==>net/http.(*conn).serve//410000 0
+Threshold-279-start: runtime.sysmon==>runtime.usleep//410000 0
+Threshold-279-end: runtime.sysmon==>runtime.usleep//480000 0
+Threshold-280-start: runtime.mstart1==>runtime.sysmon//410000 0
+Threshold-280-end: runtime.mstart1==>runtime.sysmon//480000 0
+Threshold-281-start: runtime.mstart==>runtime.mstart1//410000 0
+Threshold-281-end: runtime.mstart==>runtime.mstart1//480000 0
+Threshold-282-start: go==>runtime.mstart//410000 0
+Threshold-282-end: go==>runtime.mstart//480000 0
+Threshold-283-start: runtime.futexsleep==>runtime.futex//480000 0
+Threshold-283-end: runtime.futexsleep==>runtime.futex//490000 0
+Threshold-284-start: runtime.notesleep==>runtime.futexsleep//480000 0
+Threshold-284-end: runtime.notesleep==>runtime.futexsleep//490000 0
+Threshold-285-start: runtime.stopm==>runtime.notesleep//480000 0
+Threshold-285-end: runtime.stopm==>runtime.notesleep//490000 0
+Threshold-286-start: runtime.findrunnable==>runtime.stopm//480000 0
+Threshold-286-end: runtime.findrunnable==>runtime.stopm//490000 0
+Threshold-287-start: runtime.schedule==>runtime.findrunnable//480000 0
+Threshold-287-end: runtime.schedule==>runtime.findrunnable//490000 0
+Threshold-288-start: runtime.park_m==>runtime.schedule//480000 0
+Threshold-288-end: runtime.park_m==>runtime.schedule//490000 0
+Threshold-289-start: runtime.mcall==>runtime.park_m//480000 0
+Threshold-289-end: runtime.mcall==>runtime.park_m//490000 0
+Threshold-290-start: go==>runtime.mcall//480000 0
+Threshold-290-end: go==>runtime.mcall//490000 0
+Threshold-291-start: runtime.newArenaMayUnlock==>runtime.memclrNoHeapPointers//490000 0
+Threshold-291-end: runtime.newArenaMayUnlock==>runtime.memclrNoHeapPointers//500000 0
+Threshold-292-start: runtime.newMarkBits==>runtime.newArenaMayUnlock//490000 0
+Threshold-292-end: runtime.newMarkBits==>runtime.newArenaMayUnlock//500000 0
+Threshold-293-start: runtime.(*mspan).sweep==>runtime.newMarkBits//490000 0
+Threshold-293-end: runtime.(*mspan).sweep==>runtime.newMarkBits//500000 0
+Threshold-294-start: runtime.(*mcentral).uncacheSpan==>runtime.(*mspan).sweep//490000 0
+Threshold-294-end: runtime.(*mcentral).uncacheSpan==>runtime.(*mspan).sweep//500000 0
+Threshold-295-start: runtime.(*mcache).releaseAll==>runtime.(*mcentral).uncacheSpan//490000 0
+Threshold-295-end: runtime.(*mcache).releaseAll==>runtime.(*mcentral).uncacheSpan//500000 0
+Threshold-296-start: runtime.(*mcache).prepareForSweep==>runtime.(*mcache).releaseAll//490000 0
+Threshold-296-end: runtime.(*mcache).prepareForSweep==>runtime.(*mcache).releaseAll//500000 0
+Threshold-297-start: runtime.procresize==>runtime.(*mcache).prepareForSweep//490000 0
+Threshold-297-end: runtime.procresize==>runtime.(*mcache).prepareForSweep//500000 0
+Threshold-298-start: runtime.startTheWorldWithSema==>runtime.procresize//490000 0
+Threshold-298-end: runtime.startTheWorldWithSema==>runtime.procresize//500000 0
+Threshold-299-start: runtime.gcMarkTermination.func3==>runtime.startTheWorldWithSema//490000 0
+Threshold-299-end: runtime.gcMarkTermination.func3==>runtime.startTheWorldWithSema//500000 0
+Threshold-300-start: runtime.systemstack==>runtime.gcMarkTermination.func3//490000 0
+Threshold-300-end: runtime.systemstack==>runtime.gcMarkTermination.func3//500000 0
+Threshold-301-start: runtime.gcMarkTermination==>runtime.systemstack//490000 0
+Threshold-301-end: runtime.gcMarkTermination==>runtime.systemstack//500000 0
+Threshold-302-start: runtime.gcMarkDone==>runtime.gcMarkTermination//490000 0
+Threshold-302-end: runtime.gcMarkDone==>runtime.gcMarkTermination//500000 0
+Threshold-303-start: runtime.gcBgMarkWorker==>runtime.gcMarkDone//490000 0
+Threshold-303-end: runtime.gcBgMarkWorker==>runtime.gcMarkDone//500000 0
+Threshold-304-start: go==>runtime.gcBgMarkWorker//490000 0
+Threshold-304-end: go==>runtime.gcBgMarkWorker//500000 0
+Threshold-305-start: syscall.write==>syscall.Syscall//510000 0
+Threshold-305-end: syscall.write==>syscall.Syscall//530000 0
+Threshold-306-start: syscall.Write==>syscall.write//510000 0
+Threshold-306-end: syscall.Write==>syscall.write//530000 0
+Threshold-307-start: internal/poll.(*FD).Write==>syscall.Write//510000 0
+Threshold-307-end: internal/poll.(*FD).Write==>syscall.Write//530000 0
+Threshold-308-start: net.(*netFD).Write==>internal/poll.(*FD).Write//510000 0
+Threshold-308-end: net.(*netFD).Write==>internal/poll.(*FD).Write//530000 0
+Threshold-309-start: net.(*conn).Write==>net.(*netFD).Write//510000 0
+Threshold-309-end: net.(*conn).Write==>net.(*netFD).Write//530000 0
+Threshold-310-start: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//500000 0
+Threshold-310-end: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//530000 0
+Threshold-311-start: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//500000 0
+Threshold-311-end: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//530000 0
+Threshold-312-start: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//500000 0
+Threshold-312-end: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//530000 0
+Threshold-313-start: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//500000 0
+Threshold-313-end: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//530000 0
+Threshold-314-start: database/sql.(*driverConn).finalClose==>database/sql.withLock//500000 0
+Threshold-314-end: database/sql.(*driverConn).finalClose==>database/sql.withLock//530000 0
+Threshold-315-start: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//500000 0
+Threshold-315-end: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//530000 0
+Threshold-316-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//500000 0
+Threshold-316-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//530000 0
+Threshold-317-start: database/sql.(*DB).query==>database/sql.(*DB).conn//500000 0
+Threshold-317-end: database/sql.(*DB).query==>database/sql.(*DB).conn//530000 0
+Threshold-318-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//500000 0
+Threshold-318-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//530000 0
+Threshold-319-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//500000 0
+Threshold-319-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//530000 0
+Threshold-320-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//500000 0
+Threshold-320-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//530000 0
+Threshold-321-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//500000 0
+Threshold-321-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//530000 0
+Threshold-322-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//500000 0
+Threshold-322-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//530000 0
+Threshold-323-start: runtime.pcvalue==>runtime.step//530000 0
+Threshold-323-end: runtime.pcvalue==>runtime.step//540000 0
+Threshold-324-start: runtime.funcspdelta==>runtime.pcvalue//530000 0
+Threshold-324-end: runtime.funcspdelta==>runtime.pcvalue//540000 0
+Threshold-325-start: runtime.gentraceback==>runtime.funcspdelta//530000 0
+Threshold-325-end: runtime.gentraceback==>runtime.funcspdelta//540000 0
+Threshold-326-start: runtime.copystack==>runtime.gentraceback//530000 0
+Threshold-326-end: runtime.copystack==>runtime.gentraceback//540000 0
+Threshold-327-start: runtime.newstack==>runtime.copystack//530000 0
+Threshold-327-end: runtime.newstack==>runtime.copystack//540000 0
+Threshold-328-start: time.readFile==>runtime.newstack//530000 0
+Threshold-328-end: time.readFile==>runtime.newstack//540000 0
+Threshold-329-start: time.loadTzinfoFromDirOrZip==>time.readFile//530000 0
+Threshold-329-end: time.loadTzinfoFromDirOrZip==>time.readFile//540000 0
+Threshold-330-start: time.loadTzinfo==>time.loadTzinfoFromDirOrZip//530000 0
+Threshold-330-end: time.loadTzinfo==>time.loadTzinfoFromDirOrZip//540000 0
+Threshold-331-start: time.loadLocation==>time.loadTzinfo//530000 0
+Threshold-331-end: time.loadLocation==>time.loadTzinfo//540000 0
+Threshold-332-start: time.LoadLocation==>time.loadLocation//530000 0
+Threshold-332-end: time.LoadLocation==>time.loadLocation//540000 0
+Threshold-333-start: github.com/lib/pq.(*conn).processParameterStatus==>time.LoadLocation//530000 0
+Threshold-333-end: github.com/lib/pq.(*conn).processParameterStatus==>time.LoadLocation//540000 0
+Threshold-334-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).processParameterStatus//530000 0
+Threshold-334-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).processParameterStatus//540000 0
+Threshold-335-start: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//540000 0
+Threshold-335-end: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//550000 0
+Threshold-336-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//540000 0
+Threshold-336-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//550000 0
+Threshold-337-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//530000 0
+Threshold-337-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//550000 0
+Threshold-338-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//530000 0
+Threshold-338-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//550000 0
+Threshold-339-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//530000 0
+Threshold-339-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//550000 0
+Threshold-340-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//530000 0
+Threshold-340-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//550000 0
+Threshold-341-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//530000 0
+Threshold-341-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//550000 0
+Threshold-342-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//530000 0
+Threshold-342-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//550000 0
+Threshold-343-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//530000 0
+Threshold-343-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//550000 0
+Threshold-344-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//530000 0
+Threshold-344-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//550000 0
+Threshold-345-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//530000 0
+Threshold-345-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//550000 0
+Threshold-346-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//530000 0
+Threshold-346-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//550000 0
+Threshold-347-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//530000 0
+Threshold-347-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//550000 0
+Threshold-348-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//530000 0
+Threshold-348-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//550000 0
+Threshold-349-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//500000 0
+Threshold-349-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//550000 0
+Threshold-350-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//500000 0
+Threshold-350-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//550000 0
+Threshold-351-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//500000 0
+Threshold-351-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//550000 0
+Threshold-352-start: go==>net/http.(*conn).serve//500000 0
+Threshold-352-end: go==>net/http.(*conn).serve//550000 0
+Threshold-353-start: runtime.futexsleep==>runtime.futex//550000 0
+Threshold-353-end: runtime.futexsleep==>runtime.futex//570000 0
+Threshold-354-start: runtime.notetsleep_internal==>runtime.futexsleep//550000 0
+Threshold-354-end: runtime.notetsleep_internal==>runtime.futexsleep//570000 0
+Threshold-355-start: runtime.notetsleep==>runtime.notetsleep_internal//550000 0
+Threshold-355-end: runtime.notetsleep==>runtime.notetsleep_internal//570000 0
+Threshold-356-start: runtime.sysmon==>runtime.notetsleep//550000 0
+Threshold-356-end: runtime.sysmon==>runtime.notetsleep//570000 0
+Threshold-357-start: runtime.mstart1==>runtime.sysmon//550000 0
+Threshold-357-end: runtime.mstart1==>runtime.sysmon//570000 0
+Threshold-358-start: runtime.mstart==>runtime.mstart1//550000 0
+Threshold-358-end: runtime.mstart==>runtime.mstart1//570000 0
+Threshold-359-start: go==>runtime.mstart//550000 0
+Threshold-359-end: go==>runtime.mstart//570000 0
+Threshold-360-start: database/sql.(*DB).removeDepLocked==>runtime.mapdelete//570000 0
+Threshold-360-end: database/sql.(*DB).removeDepLocked==>runtime.mapdelete//580000 0
+Threshold-361-start: database/sql.(*driverConn).Close==>database/sql.(*DB).removeDepLocked//570000 0
+Threshold-361-end: database/sql.(*driverConn).Close==>database/sql.(*DB).removeDepLocked//580000 0
+Threshold-362-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//570000 0
+Threshold-362-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//580000 0
+Threshold-363-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//570000 0
+Threshold-363-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//580000 0
+Threshold-364-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//570000 0
+Threshold-364-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//580000 0
+Threshold-365-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//570000 0
+Threshold-365-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//580000 0
+Threshold-366-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//570000 0
+Threshold-366-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//580000 0
+Threshold-367-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//570000 0
+Threshold-367-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//580000 0
+Threshold-368-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//570000 0
+Threshold-368-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//580000 0
+Threshold-369-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//570000 0
+Threshold-369-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//580000 0
+Threshold-370-start: regexp.(*Regexp).FindStringSubmatchIndex==>regexp.(*Regexp).pad//580000 0
+Threshold-370-end: regexp.(*Regexp).FindStringSubmatchIndex==>regexp.(*Regexp).pad//590000 0
+Threshold-371-start: github.com/gorilla/mux.routeRegexpGroup.setMatch==>regexp.(*Regexp).FindStringSubmatchIndex//580000 0
+Threshold-371-end: github.com/gorilla/mux.routeRegexpGroup.setMatch==>regexp.(*Regexp).FindStringSubmatchIndex//590000 0
+Threshold-372-start: github.com/gorilla/mux.(*Route).Match==>github.com/gorilla/mux.routeRegexpGroup.setMatch//580000 0
+Threshold-372-end: github.com/gorilla/mux.(*Route).Match==>github.com/gorilla/mux.routeRegexpGroup.setMatch//590000 0
+Threshold-373-start: github.com/gorilla/mux.(*Router).Match==>github.com/gorilla/mux.(*Route).Match//580000 0
+Threshold-373-end: github.com/gorilla/mux.(*Router).Match==>github.com/gorilla/mux.(*Route).Match//590000 0
+Threshold-374-start: github.com/gorilla/mux.(*Router).ServeHTTP==>github.com/gorilla/mux.(*Router).Match//580000 0
+Threshold-374-end: github.com/gorilla/mux.(*Router).ServeHTTP==>github.com/gorilla/mux.(*Router).Match//590000 0
+Threshold-375-start: syscall.read==>syscall.Syscall//590000 0
+Threshold-375-end: syscall.read==>syscall.Syscall//610000 0
+Threshold-376-start: syscall.Read==>syscall.read//590000 0
+Threshold-376-end: syscall.Read==>syscall.read//610000 0
+Threshold-377-start: internal/poll.(*FD).Read==>syscall.Read//590000 0
+Threshold-377-end: internal/poll.(*FD).Read==>syscall.Read//610000 0
+Threshold-378-start: net.(*netFD).Read==>internal/poll.(*FD).Read//590000 0
+Threshold-378-end: net.(*netFD).Read==>internal/poll.(*FD).Read//610000 0
+Threshold-379-start: net.(*conn).Read==>net.(*netFD).Read//590000 0
+Threshold-379-end: net.(*conn).Read==>net.(*netFD).Read//610000 0
+Threshold-380-start: bufio.(*Reader).Read==>net.(*conn).Read//590000 0
+Threshold-380-end: bufio.(*Reader).Read==>net.(*conn).Read//610000 0
+Threshold-381-start: io.ReadAtLeast==>bufio.(*Reader).Read//590000 0
+Threshold-381-end: io.ReadAtLeast==>bufio.(*Reader).Read//610000 0
+Threshold-382-start: io.ReadFull==>io.ReadAtLeast//590000 0
+Threshold-382-end: io.ReadFull==>io.ReadAtLeast//610000 0
+Threshold-383-start: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//590000 0
+Threshold-383-end: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//610000 0
+Threshold-384-start: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//590000 0
+Threshold-384-end: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//610000 0
+Threshold-385-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//590000 0
+Threshold-385-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//610000 0
+Threshold-386-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//590000 0
+Threshold-386-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//610000 0
+Threshold-387-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//590000 0
+Threshold-387-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//610000 0
+Threshold-388-start: strings.IndexByte==>internal/bytealg.IndexByteString//610000 0
+Threshold-388-end: strings.IndexByte==>internal/bytealg.IndexByteString//620000 0
+Threshold-389-start: strings.Index==>strings.IndexByte//610000 0
+Threshold-389-end: strings.Index==>strings.IndexByte//620000 0
+Threshold-390-start: strings.genSplit==>strings.Index//610000 0
+Threshold-390-end: strings.genSplit==>strings.Index//620000 0
+Threshold-391-start: strings.SplitN==>strings.genSplit//610000 0
+Threshold-391-end: strings.SplitN==>strings.genSplit//620000 0
+Threshold-392-start: github.com/lib/pq.parseEnviron==>strings.SplitN//610000 0
+Threshold-392-end: github.com/lib/pq.parseEnviron==>strings.SplitN//620000 0
+Threshold-393-start: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//610000 0
+Threshold-393-end: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//620000 0
+Threshold-394-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//610000 0
+Threshold-394-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//620000 0
+Threshold-395-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//590000 0
+Threshold-395-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//620000 0
+Threshold-396-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//590000 0
+Threshold-396-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//620000 0
+Threshold-397-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//590000 0
+Threshold-397-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//620000 0
+Threshold-398-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//590000 0
+Threshold-398-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//620000 0
+Threshold-399-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//590000 0
+Threshold-399-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//620000 0
+Threshold-400-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//590000 0
+Threshold-400-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//620000 0
+Threshold-401-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//590000 0
+Threshold-401-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//620000 0
+Threshold-402-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//590000 0
+Threshold-402-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//620000 0
+Threshold-403-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//590000 0
+Threshold-403-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//620000 0
+Threshold-404-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//590000 0
+Threshold-404-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//620000 0
+Threshold-405-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//590000 0
+Threshold-405-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//620000 0
+Threshold-406-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//570000 0
+Threshold-406-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//620000 0
+Threshold-407-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//570000 0
+Threshold-407-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//620000 0
+Threshold-408-start: go==>net/http.(*conn).serve//570000 0
+Threshold-408-end: go==>net/http.(*conn).serve//620000 0
+Threshold-409-start: runtime.gcDrain==>runtime.scanobject//620000 0
+Threshold-409-end: runtime.gcDrain==>runtime.scanobject//630000 0
+Threshold-410-start: runtime.gcBgMarkWorker.func2==>runtime.gcDrain//620000 0
+Threshold-410-end: runtime.gcBgMarkWorker.func2==>runtime.gcDrain//630000 0
+Threshold-411-start: runtime.systemstack==>runtime.gcBgMarkWorker.func2//620000 0
+Threshold-411-end: runtime.systemstack==>runtime.gcBgMarkWorker.func2//630000 0
+Threshold-412-start: runtime.gcBgMarkWorker==>runtime.systemstack//620000 0
+Threshold-412-end: runtime.gcBgMarkWorker==>runtime.systemstack//630000 0
+Threshold-413-start: go==>runtime.gcBgMarkWorker//620000 0
+Threshold-413-end: go==>runtime.gcBgMarkWorker//630000 0
+Threshold-414-start: runtime.futexwakeup==>runtime.futex//630000 0
+Threshold-414-end: runtime.futexwakeup==>runtime.futex//640000 0
+Threshold-415-start: runtime.notewakeup==>runtime.futexwakeup//630000 0
+Threshold-415-end: runtime.notewakeup==>runtime.futexwakeup//640000 0
+Threshold-416-start: runtime.entersyscall_sysmon==>runtime.notewakeup//630000 0
+Threshold-416-end: runtime.entersyscall_sysmon==>runtime.notewakeup//640000 0
+Threshold-417-start: runtime.systemstack==>runtime.entersyscall_sysmon//630000 0
+Threshold-417-end: runtime.systemstack==>runtime.entersyscall_sysmon//640000 0
+Threshold-418-start: runtime.reentersyscall==>runtime.systemstack//630000 0
+Threshold-418-end: runtime.reentersyscall==>runtime.systemstack//640000 0
+Threshold-419-start: runtime.entersyscall==>runtime.reentersyscall//630000 0
+Threshold-419-end: runtime.entersyscall==>runtime.reentersyscall//640000 0
+Threshold-420-start: syscall.Syscall==>runtime.entersyscall//630000 0
+Threshold-420-end: syscall.Syscall==>runtime.entersyscall//640000 0
+Threshold-421-start: syscall.read==>syscall.Syscall//630000 0
+Threshold-421-end: syscall.read==>syscall.Syscall//640000 0
+Threshold-422-start: syscall.Read==>syscall.read//630000 0
+Threshold-422-end: syscall.Read==>syscall.read//640000 0
+Threshold-423-start: internal/poll.(*FD).Read==>syscall.Read//630000 0
+Threshold-423-end: internal/poll.(*FD).Read==>syscall.Read//640000 0
+Threshold-424-start: net.(*netFD).Read==>internal/poll.(*FD).Read//630000 0
+Threshold-424-end: net.(*netFD).Read==>internal/poll.(*FD).Read//640000 0
+Threshold-425-start: net.(*conn).Read==>net.(*netFD).Read//630000 0
+Threshold-425-end: net.(*conn).Read==>net.(*netFD).Read//640000 0
+Threshold-426-start: net/http.(*connReader).Read==>net.(*conn).Read//630000 0
+Threshold-426-end: net/http.(*connReader).Read==>net.(*conn).Read//640000 0
+Threshold-427-start: bufio.(*Reader).fill==>net/http.(*connReader).Read//630000 0
+Threshold-427-end: bufio.(*Reader).fill==>net/http.(*connReader).Read//640000 0
+Threshold-428-start: bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//630000 0
+Threshold-428-end: bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//640000 0
+Threshold-429-start: bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//630000 0
+Threshold-429-end: bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//640000 0
+Threshold-430-start: net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//630000 0
+Threshold-430-end: net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//640000 0
+Threshold-431-start: net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//630000 0
+Threshold-431-end: net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//640000 0
+Threshold-432-start: net/http.readRequest==>net/textproto.(*Reader).ReadLine//630000 0
+Threshold-432-end: net/http.readRequest==>net/textproto.(*Reader).ReadLine//640000 0
+Threshold-433-start: net/http.(*conn).readRequest==>net/http.readRequest//630000 0
+Threshold-433-end: net/http.(*conn).readRequest==>net/http.readRequest//640000 0
+Threshold-434-start: net/http.(*conn).serve==>net/http.(*conn).readRequest//630000 0
+Threshold-434-end: net/http.(*conn).serve==>net/http.(*conn).readRequest//640000 0
+Threshold-435-start: go==>net/http.(*conn).serve//630000 0
+Threshold-435-end: go==>net/http.(*conn).serve//640000 0
+Threshold-436-start: runtime.futexsleep==>runtime.futex//640000 0
+Threshold-436-end: runtime.futexsleep==>runtime.futex//680000 0
+Threshold-437-start: runtime.notesleep==>runtime.futexsleep//640000 0
+Threshold-437-end: runtime.notesleep==>runtime.futexsleep//680000 0
+Threshold-438-start: runtime.stopm==>runtime.notesleep//640000 0
+Threshold-438-end: runtime.stopm==>runtime.notesleep//680000 0
+Threshold-439-start: runtime.findrunnable==>runtime.stopm//640000 0
+Threshold-439-end: runtime.findrunnable==>runtime.stopm//680000 0
+Threshold-440-start: runtime.schedule==>runtime.findrunnable//640000 0
+Threshold-440-end: runtime.schedule==>runtime.findrunnable//680000 0
+Threshold-441-start: runtime.park_m==>runtime.schedule//640000 0
+Threshold-441-end: runtime.park_m==>runtime.schedule//680000 0
+Threshold-442-start: runtime.mcall==>runtime.park_m//640000 0
+Threshold-442-end: runtime.mcall==>runtime.park_m//680000 0
+Threshold-443-start: go==>runtime.mcall//640000 0
+Threshold-443-end: go==>runtime.mcall//680000 0
+Threshold-444-start: github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//680000 0
+Threshold-444-end: github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//690000 0
+Threshold-445-start: github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//680000 0
+Threshold-445-end: github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//690000 0
+Threshold-446-start: github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//680000 0
+Threshold-446-end: github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//690000 0
+Threshold-447-start: github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//680000 0
+Threshold-447-end: github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//690000 0
+Threshold-448-start: database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//680000 0
+Threshold-448-end: database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//690000 0
+Threshold-449-start: database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//680000 0
+Threshold-449-end: database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//690000 0
+Threshold-450-start: database/sql.withLock==>database/sql.(*DB).execDC.func2//680000 0
+Threshold-450-end: database/sql.withLock==>database/sql.(*DB).execDC.func2//690000 0
+Threshold-451-start: database/sql.(*DB).execDC==>database/sql.withLock//680000 0
+Threshold-451-end: database/sql.(*DB).execDC==>database/sql.withLock//690000 0
+Threshold-452-start: database/sql.(*DB).exec==>database/sql.(*DB).execDC//680000 0
+Threshold-452-end: database/sql.(*DB).exec==>database/sql.(*DB).execDC//690000 0
+Threshold-453-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//680000 0
+Threshold-453-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//690000 0
+Threshold-454-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//680000 0
+Threshold-454-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//690000 0
+Threshold-455-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//680000 0
+Threshold-455-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//690000 0
+Threshold-456-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//680000 0
+Threshold-456-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//690000 0
+Threshold-457-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//680000 0
+Threshold-457-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//690000 0
+Threshold-458-start: syscall.write==>syscall.Syscall//690000 0
+Threshold-458-end: syscall.write==>syscall.Syscall//700000 0
+Threshold-459-start: syscall.Write==>syscall.write//690000 0
+Threshold-459-end: syscall.Write==>syscall.write//700000 0
+Threshold-460-start: internal/poll.(*FD).Write==>syscall.Write//690000 0
+Threshold-460-end: internal/poll.(*FD).Write==>syscall.Write//700000 0
+Threshold-461-start: net.(*netFD).Write==>internal/poll.(*FD).Write//690000 0
+Threshold-461-end: net.(*netFD).Write==>internal/poll.(*FD).Write//700000 0
+Threshold-462-start: net.(*conn).Write==>net.(*netFD).Write//690000 0
+Threshold-462-end: net.(*conn).Write==>net.(*netFD).Write//700000 0
+Threshold-463-start: github.com/lib/pq.(*conn).send==>net.(*conn).Write//690000 0
+Threshold-463-end: github.com/lib/pq.(*conn).send==>net.(*conn).Write//700000 0
+Threshold-464-start: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).send//690000 0
+Threshold-464-end: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).send//700000 0
+Threshold-465-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//690000 0
+Threshold-465-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//700000 0
+Threshold-466-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//690000 0
+Threshold-466-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//700000 0
+Threshold-467-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//690000 0
+Threshold-467-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//700000 0
+Threshold-468-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//690000 0
+Threshold-468-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//700000 0
+Threshold-469-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//690000 0
+Threshold-469-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//700000 0
+Threshold-470-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//690000 0
+Threshold-470-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//700000 0
+Threshold-471-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//690000 0
+Threshold-471-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//700000 0
+Threshold-472-start: database/sql.(*DB).query==>database/sql.(*DB).conn//690000 0
+Threshold-472-end: database/sql.(*DB).query==>database/sql.(*DB).conn//700000 0
+Threshold-473-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//690000 0
+Threshold-473-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//700000 0
+Threshold-474-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//690000 0
+Threshold-474-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//700000 0
+Threshold-475-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//690000 0
+Threshold-475-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//700000 0
+Threshold-476-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//690000 0
+Threshold-476-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//700000 0
+Threshold-477-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//690000 0
+Threshold-477-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//700000 0
+Threshold-478-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//680000 0
+Threshold-478-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//700000 0
+Threshold-479-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//680000 0
+Threshold-479-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//700000 0
+Threshold-480-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//680000 0
+Threshold-480-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//700000 0
+Threshold-481-start: go==>net/http.(*conn).serve//680000 0
+Threshold-481-end: go==>net/http.(*conn).serve//700000 0
+Threshold-482-start: runtime.futexwakeup==>runtime.futex//700000 0
+Threshold-482-end: runtime.futexwakeup==>runtime.futex//710000 0
+Threshold-483-start: runtime.notewakeup==>runtime.futexwakeup//700000 0
+Threshold-483-end: runtime.notewakeup==>runtime.futexwakeup//710000 0
+Threshold-484-start: runtime.startm==>runtime.notewakeup//700000 0
+Threshold-484-end: runtime.startm==>runtime.notewakeup//710000 0
+Threshold-485-start: runtime.wakep==>runtime.startm//700000 0
+Threshold-485-end: runtime.wakep==>runtime.startm//710000 0
+Threshold-486-start: runtime.newproc1==>runtime.wakep//700000 0
+Threshold-486-end: runtime.newproc1==>runtime.wakep//710000 0
+Threshold-487-start: runtime.newproc.func1==>runtime.newproc1//700000 0
+Threshold-487-end: runtime.newproc.func1==>runtime.newproc1//710000 0
+Threshold-488-start: runtime.systemstack==>runtime.newproc.func1//700000 0
+Threshold-488-end: runtime.systemstack==>runtime.newproc.func1//710000 0
+Threshold-489-start: runtime.newproc==>runtime.systemstack//700000 0
+Threshold-489-end: runtime.newproc==>runtime.systemstack//710000 0
+Threshold-490-start: net/http.(*Server).Serve==>runtime.newproc//700000 0
+Threshold-490-end: net/http.(*Server).Serve==>runtime.newproc//710000 0
+Threshold-491-start: net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//700000 0
+Threshold-491-end: net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//710000 0
+Threshold-492-start: net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//700000 0
+Threshold-492-end: net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//710000 0
+Threshold-493-start: main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//700000 0
+Threshold-493-end: main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//710000 0
+Threshold-494-start: main.runServer==>main.(*HttpServer).ListenAndServe//700000 0
+Threshold-494-end: main.runServer==>main.(*HttpServer).ListenAndServe//710000 0
+Threshold-495-start: main.main==>main.runServer//700000 0
+Threshold-495-end: main.main==>main.runServer//710000 0
+Threshold-496-start: runtime.main==>main.main//700000 0
+Threshold-496-end: runtime.main==>main.main//710000 0
+Threshold-497-start: go==>runtime.main//700000 0
+Threshold-497-end: go==>runtime.main//710000 0
+Threshold-498-start: runtime.newobject==>runtime.mallocgc//710000 0
+Threshold-498-end: runtime.newobject==>runtime.mallocgc//720000 0
+Threshold-499-start: runtime.makemap_small==>runtime.newobject//710000 0
+Threshold-499-end: runtime.makemap_small==>runtime.newobject//720000 0
+Threshold-500-start: github.com/lib/pq.parseEnviron==>runtime.makemap_small//710000 0
+Threshold-500-end: github.com/lib/pq.parseEnviron==>runtime.makemap_small//720000 0
+Threshold-501-start: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//710000 0
+Threshold-501-end: github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//720000 0
+Threshold-502-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//710000 0
+Threshold-502-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//720000 0
+Threshold-503-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//710000 0
+Threshold-503-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//720000 0
+Threshold-504-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//710000 0
+Threshold-504-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//720000 0
+Threshold-505-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//710000 0
+Threshold-505-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//720000 0
+Threshold-506-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//710000 0
+Threshold-506-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//720000 0
+Threshold-507-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//710000 0
+Threshold-507-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//720000 0
+Threshold-508-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//710000 0
+Threshold-508-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//720000 0
+Threshold-509-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//710000 0
+Threshold-509-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//720000 0
+Threshold-510-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//710000 0
+Threshold-510-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//720000 0
+Threshold-511-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//710000 0
+Threshold-511-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//720000 0
+Threshold-512-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//710000 0
+Threshold-512-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//720000 0
+Threshold-513-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//710000 0
+Threshold-513-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//720000 0
+Threshold-514-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//710000 0
+Threshold-514-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//720000 0
+Threshold-515-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//710000 0
+Threshold-515-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//720000 0
+Threshold-516-start: go==>net/http.(*conn).serve//710000 0
+Threshold-516-end: go==>net/http.(*conn).serve//720000 0
+Threshold-517-start: sync.runtime_notifyListNotifyAll==>runtime.readyWithTime//720000 0
+Threshold-517-end: sync.runtime_notifyListNotifyAll==>runtime.readyWithTime//730000 0
+Threshold-518-start: sync.(*Cond).Broadcast==>sync.runtime_notifyListNotifyAll//720000 0
+Threshold-518-end: sync.(*Cond).Broadcast==>sync.runtime_notifyListNotifyAll//730000 0
+Threshold-519-start: net/http.(*connReader).backgroundRead==>sync.(*Cond).Broadcast//720000 0
+Threshold-519-end: net/http.(*connReader).backgroundRead==>sync.(*Cond).Broadcast//730000 0
+Threshold-520-start: go==>net/http.(*connReader).backgroundRead//720000 0
+Threshold-520-end: go==>net/http.(*connReader).backgroundRead//730000 0
+Threshold-521-start: encoding/json.ptrEncoder.encode==>encoding/json.structEncoder.encode//730000 0
+Threshold-521-end: encoding/json.ptrEncoder.encode==>encoding/json.structEncoder.encode//740000 0
+Threshold-522-start: encoding/json.arrayEncoder.encode==>encoding/json.ptrEncoder.encode//730000 0
+Threshold-522-end: encoding/json.arrayEncoder.encode==>encoding/json.ptrEncoder.encode//740000 0
+Threshold-523-start: encoding/json.sliceEncoder.encode==>encoding/json.arrayEncoder.encode//730000 0
+Threshold-523-end: encoding/json.sliceEncoder.encode==>encoding/json.arrayEncoder.encode//740000 0
+Threshold-524-start: encoding/json.(*encodeState).reflectValue==>encoding/json.sliceEncoder.encode//730000 0
+Threshold-524-end: encoding/json.(*encodeState).reflectValue==>encoding/json.sliceEncoder.encode//740000 0
+Threshold-525-start: encoding/json.(*encodeState).marshal==>encoding/json.(*encodeState).reflectValue//730000 0
+Threshold-525-end: encoding/json.(*encodeState).marshal==>encoding/json.(*encodeState).reflectValue//740000 0
+Threshold-526-start: encoding/json.Marshal==>encoding/json.(*encodeState).marshal//730000 0
+Threshold-526-end: encoding/json.Marshal==>encoding/json.(*encodeState).marshal//740000 0
+Threshold-527-start: main.(*HttpServer).httpHandlerGetWidgets==>encoding/json.Marshal//730000 0
+Threshold-527-end: main.(*HttpServer).httpHandlerGetWidgets==>encoding/json.Marshal//740000 0
+Threshold-528-start: runtime.mallocgc==>runtime.heapBitsSetType//740000 0
+Threshold-528-end: runtime.mallocgc==>runtime.heapBitsSetType//750000 0
+Threshold-529-start: runtime.growslice==>runtime.mallocgc//740000 0
+Threshold-529-end: runtime.growslice==>runtime.mallocgc//750000 0
+Threshold-530-start: main.(*Store).GetAllWidgets==>runtime.growslice//740000 0
+Threshold-530-end: main.(*Store).GetAllWidgets==>runtime.growslice//750000 0
+Threshold-531-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//740000 0
+Threshold-531-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//750000 0
+Threshold-532-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//730000 0
+Threshold-532-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//750000 0
+Threshold-533-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//730000 0
+Threshold-533-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//750000 0
+Threshold-534-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//730000 0
+Threshold-534-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//750000 0
+Threshold-535-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//730000 0
+Threshold-535-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//750000 0
+Threshold-536-start: runtime.mapaccess1_faststr==>runtime.add//750000 0
+Threshold-536-end: runtime.mapaccess1_faststr==>runtime.add//760000 0
+Threshold-537-start: net/textproto.canonicalMIMEHeaderKey==>runtime.mapaccess1_faststr//750000 0
+Threshold-537-end: net/textproto.canonicalMIMEHeaderKey==>runtime.mapaccess1_faststr//760000 0
+Threshold-538-start: net/textproto.(*Reader).ReadMIMEHeader==>net/textproto.canonicalMIMEHeaderKey//750000 0
+Threshold-538-end: net/textproto.(*Reader).ReadMIMEHeader==>net/textproto.canonicalMIMEHeaderKey//760000 0
+Threshold-539-start: net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//750000 0
+Threshold-539-end: net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//760000 0
+Threshold-540-start: net/http.(*conn).readRequest==>net/http.readRequest//750000 0
+Threshold-540-end: net/http.(*conn).readRequest==>net/http.readRequest//760000 0
+Threshold-541-start: net/http.(*conn).serve==>net/http.(*conn).readRequest//750000 0
+Threshold-541-end: net/http.(*conn).serve==>net/http.(*conn).readRequest//760000 0
+Threshold-542-start: syscall.connect==>syscall.Syscall//760000 0
+Threshold-542-end: syscall.connect==>syscall.Syscall//770000 0
+Threshold-543-start: syscall.Connect==>syscall.connect//760000 0
+Threshold-543-end: syscall.Connect==>syscall.connect//770000 0
+Threshold-544-start: net.(*netFD).connect==>syscall.Connect//760000 0
+Threshold-544-end: net.(*netFD).connect==>syscall.Connect//770000 0
+Threshold-545-start: net.(*netFD).dial==>net.(*netFD).connect//760000 0
+Threshold-545-end: net.(*netFD).dial==>net.(*netFD).connect//770000 0
+Threshold-546-start: net.socket==>net.(*netFD).dial//760000 0
+Threshold-546-end: net.socket==>net.(*netFD).dial//770000 0
+Threshold-547-start: net.internetSocket==>net.socket//760000 0
+Threshold-547-end: net.internetSocket==>net.socket//770000 0
+Threshold-548-start: net.(*sysDialer).doDialTCP==>net.internetSocket//760000 0
+Threshold-548-end: net.(*sysDialer).doDialTCP==>net.internetSocket//770000 0
+Threshold-549-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//760000 0
+Threshold-549-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//770000 0
+Threshold-550-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//760000 0
+Threshold-550-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//770000 0
+Threshold-551-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//760000 0
+Threshold-551-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//770000 0
+Threshold-552-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//760000 0
+Threshold-552-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//770000 0
+Threshold-553-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//760000 0
+Threshold-553-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//770000 0
+Threshold-554-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//760000 0
+Threshold-554-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//770000 0
+Threshold-555-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//760000 0
+Threshold-555-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//770000 0
+Threshold-556-start: syscall.write==>syscall.Syscall//770000 0
+Threshold-556-end: syscall.write==>syscall.Syscall//780000 0
+Threshold-557-start: syscall.Write==>syscall.write//770000 0
+Threshold-557-end: syscall.Write==>syscall.write//780000 0
+Threshold-558-start: internal/poll.(*FD).Write==>syscall.Write//770000 0
+Threshold-558-end: internal/poll.(*FD).Write==>syscall.Write//780000 0
+Threshold-559-start: net.(*netFD).Write==>internal/poll.(*FD).Write//770000 0
+Threshold-559-end: net.(*netFD).Write==>internal/poll.(*FD).Write//780000 0
+Threshold-560-start: net.(*conn).Write==>net.(*netFD).Write//770000 0
+Threshold-560-end: net.(*conn).Write==>net.(*netFD).Write//780000 0
+Threshold-561-start: github.com/lib/pq.(*conn).sendStartupPacket==>net.(*conn).Write//770000 0
+Threshold-561-end: github.com/lib/pq.(*conn).sendStartupPacket==>net.(*conn).Write//780000 0
+Threshold-562-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).sendStartupPacket//770000 0
+Threshold-562-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).sendStartupPacket//780000 0
+Threshold-563-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//770000 0
+Threshold-563-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//780000 0
+Threshold-564-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//760000 0
+Threshold-564-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//780000 0
+Threshold-565-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//760000 0
+Threshold-565-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//780000 0
+Threshold-566-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//760000 0
+Threshold-566-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//780000 0
+Threshold-567-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//760000 0
+Threshold-567-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//780000 0
+Threshold-568-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//760000 0
+Threshold-568-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//780000 0
+Threshold-569-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//760000 0
+Threshold-569-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//780000 0
+Threshold-570-start: runtime.assertI2I2==>runtime.getitab//780000 0
+Threshold-570-end: runtime.assertI2I2==>runtime.getitab//790000 0
+Threshold-571-start: database/sql.(*DB).execDC==>runtime.assertI2I2//780000 0
+Threshold-571-end: database/sql.(*DB).execDC==>runtime.assertI2I2//790000 0
+Threshold-572-start: database/sql.(*DB).exec==>database/sql.(*DB).execDC//780000 0
+Threshold-572-end: database/sql.(*DB).exec==>database/sql.(*DB).execDC//790000 0
+Threshold-573-start: syscall.setsockopt==>syscall.Syscall6//790000 0
+Threshold-573-end: syscall.setsockopt==>syscall.Syscall6//800000 0
+Threshold-574-start: syscall.SetsockoptInt==>syscall.setsockopt//790000 0
+Threshold-574-end: syscall.SetsockoptInt==>syscall.setsockopt//800000 0
+Threshold-575-start: internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//790000 0
+Threshold-575-end: internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//800000 0
+Threshold-576-start: net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//790000 0
+Threshold-576-end: net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//800000 0
+Threshold-577-start: net.(*Dialer).DialContext==>net.setKeepAlivePeriod//790000 0
+Threshold-577-end: net.(*Dialer).DialContext==>net.setKeepAlivePeriod//800000 0
+Threshold-578-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//790000 0
+Threshold-578-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//800000 0
+Threshold-579-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//790000 0
+Threshold-579-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//800000 0
+Threshold-580-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//790000 0
+Threshold-580-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//800000 0
+Threshold-581-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//790000 0
+Threshold-581-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//800000 0
+Threshold-582-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//790000 0
+Threshold-582-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//800000 0
+Threshold-583-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//790000 0
+Threshold-583-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//800000 0
+Threshold-584-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//790000 0
+Threshold-584-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//800000 0
+Threshold-585-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//790000 0
+Threshold-585-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//800000 0
+Threshold-586-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//790000 0
+Threshold-586-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//800000 0
+Threshold-587-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//760000 0
+Threshold-587-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//800000 0
+Threshold-588-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//760000 0
+Threshold-588-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//800000 0
+Threshold-589-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//760000 0
+Threshold-589-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//800000 0
+Threshold-590-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//760000 0
+Threshold-590-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//800000 0
+Threshold-591-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//760000 0
+Threshold-591-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//800000 0
+Threshold-592-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//760000 0
+Threshold-592-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//800000 0
+Threshold-593-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//760000 0
+Threshold-593-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//800000 0
+Threshold-594-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//760000 0
+Threshold-594-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//800000 0
+Threshold-595-start: net/http.(*Request).wantsClose==>net/http.hasToken//800000 0
+Threshold-595-end: net/http.(*Request).wantsClose==>net/http.hasToken//810000 0
+Threshold-596-start: net/http.(*conn).readRequest==>net/http.(*Request).wantsClose//800000 0
+Threshold-596-end: net/http.(*conn).readRequest==>net/http.(*Request).wantsClose//810000 0
+Threshold-597-start: net/http.(*conn).serve==>net/http.(*conn).readRequest//800000 0
+Threshold-597-end: net/http.(*conn).serve==>net/http.(*conn).readRequest//810000 0
+Threshold-598-start: go==>net/http.(*conn).serve//730000 0
+Threshold-598-end: go==>net/http.(*conn).serve//810000 0
+Threshold-599-start: runtime.schedule==>runtime.findrunnable//810000 0
+Threshold-599-end: runtime.schedule==>runtime.findrunnable//820000 0
+Threshold-600-start: runtime.park_m==>runtime.schedule//810000 0
+Threshold-600-end: runtime.park_m==>runtime.schedule//820000 0
+Threshold-601-start: runtime.mcall==>runtime.park_m//810000 0
+Threshold-601-end: runtime.mcall==>runtime.park_m//820000 0
+Threshold-602-start: go==>runtime.mcall//810000 0
+Threshold-602-end: go==>runtime.mcall//820000 0
+Threshold-603-start: runtime.futexwakeup==>runtime.futex//820000 0
+Threshold-603-end: runtime.futexwakeup==>runtime.futex//830000 0
+Threshold-604-start: runtime.notewakeup==>runtime.futexwakeup//820000 0
+Threshold-604-end: runtime.notewakeup==>runtime.futexwakeup//830000 0
+Threshold-605-start: runtime.entersyscall_sysmon==>runtime.notewakeup//820000 0
+Threshold-605-end: runtime.entersyscall_sysmon==>runtime.notewakeup//830000 0
+Threshold-606-start: runtime.systemstack==>runtime.entersyscall_sysmon//820000 0
+Threshold-606-end: runtime.systemstack==>runtime.entersyscall_sysmon//830000 0
+Threshold-607-start: runtime.reentersyscall==>runtime.systemstack//820000 0
+Threshold-607-end: runtime.reentersyscall==>runtime.systemstack//830000 0
+Threshold-608-start: runtime.entersyscall==>runtime.reentersyscall//820000 0
+Threshold-608-end: runtime.entersyscall==>runtime.reentersyscall//830000 0
+Threshold-609-start: syscall.Syscall==>runtime.entersyscall//820000 0
+Threshold-609-end: syscall.Syscall==>runtime.entersyscall//830000 0
+Threshold-610-start: syscall.read==>syscall.Syscall//820000 0
+Threshold-610-end: syscall.read==>syscall.Syscall//830000 0
+Threshold-611-start: syscall.Read==>syscall.read//820000 0
+Threshold-611-end: syscall.Read==>syscall.read//830000 0
+Threshold-612-start: internal/poll.(*FD).Read==>syscall.Read//820000 0
+Threshold-612-end: internal/poll.(*FD).Read==>syscall.Read//830000 0
+Threshold-613-start: net.(*netFD).Read==>internal/poll.(*FD).Read//820000 0
+Threshold-613-end: net.(*netFD).Read==>internal/poll.(*FD).Read//830000 0
+Threshold-614-start: net.(*conn).Read==>net.(*netFD).Read//820000 0
+Threshold-614-end: net.(*conn).Read==>net.(*netFD).Read//830000 0
+Threshold-615-start: bufio.(*Reader).Read==>net.(*conn).Read//820000 0
+Threshold-615-end: bufio.(*Reader).Read==>net.(*conn).Read//830000 0
+Threshold-616-start: io.ReadAtLeast==>bufio.(*Reader).Read//820000 0
+Threshold-616-end: io.ReadAtLeast==>bufio.(*Reader).Read//830000 0
+Threshold-617-start: io.ReadFull==>io.ReadAtLeast//820000 0
+Threshold-617-end: io.ReadFull==>io.ReadAtLeast//830000 0
+Threshold-618-start: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//820000 0
+Threshold-618-end: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//830000 0
+Threshold-619-start: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//820000 0
+Threshold-619-end: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//830000 0
+Threshold-620-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//820000 0
+Threshold-620-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//830000 0
+Threshold-621-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//820000 0
+Threshold-621-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//830000 0
+Threshold-622-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//820000 0
+Threshold-622-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//830000 0
+Threshold-623-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//820000 0
+Threshold-623-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//830000 0
+Threshold-624-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//820000 0
+Threshold-624-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//830000 0
+Threshold-625-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//820000 0
+Threshold-625-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//830000 0
+Threshold-626-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//820000 0
+Threshold-626-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//830000 0
+Threshold-627-start: database/sql.(*DB).query==>database/sql.(*DB).conn//820000 0
+Threshold-627-end: database/sql.(*DB).query==>database/sql.(*DB).conn//830000 0
+Threshold-628-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//820000 0
+Threshold-628-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//830000 0
+Threshold-629-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//820000 0
+Threshold-629-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//830000 0
+Threshold-630-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//820000 0
+Threshold-630-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//830000 0
+Threshold-631-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//820000 0
+Threshold-631-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//830000 0
+Threshold-632-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//820000 0
+Threshold-632-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//830000 0
+Threshold-633-start: syscall.getpeername==>syscall.RawSyscall//830000 0
+Threshold-633-end: syscall.getpeername==>syscall.RawSyscall//840000 0
+Threshold-634-start: syscall.Getpeername==>syscall.getpeername//830000 0
+Threshold-634-end: syscall.Getpeername==>syscall.getpeername//840000 0
+Threshold-635-start: net.(*netFD).connect==>syscall.Getpeername//830000 0
+Threshold-635-end: net.(*netFD).connect==>syscall.Getpeername//840000 0
+Threshold-636-start: net.(*netFD).dial==>net.(*netFD).connect//830000 0
+Threshold-636-end: net.(*netFD).dial==>net.(*netFD).connect//840000 0
+Threshold-637-start: net.socket==>net.(*netFD).dial//830000 0
+Threshold-637-end: net.socket==>net.(*netFD).dial//840000 0
+Threshold-638-start: net.internetSocket==>net.socket//830000 0
+Threshold-638-end: net.internetSocket==>net.socket//840000 0
+Threshold-639-start: net.(*sysDialer).doDialTCP==>net.internetSocket//830000 0
+Threshold-639-end: net.(*sysDialer).doDialTCP==>net.internetSocket//840000 0
+Threshold-640-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//830000 0
+Threshold-640-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//840000 0
+Threshold-641-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//830000 0
+Threshold-641-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//840000 0
+Threshold-642-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//830000 0
+Threshold-642-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//840000 0
+Threshold-643-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//830000 0
+Threshold-643-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//840000 0
+Threshold-644-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//830000 0
+Threshold-644-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//840000 0
+Threshold-645-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//830000 0
+Threshold-645-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//840000 0
+Threshold-646-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//830000 0
+Threshold-646-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//840000 0
+Threshold-647-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//830000 0
+Threshold-647-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//840000 0
+Threshold-648-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//830000 0
+Threshold-648-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//840000 0
+Threshold-649-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//830000 0
+Threshold-649-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//840000 0
+Threshold-650-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//830000 0
+Threshold-650-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//840000 0
+Threshold-651-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//830000 0
+Threshold-651-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//840000 0
+Threshold-652-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//830000 0
+Threshold-652-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//840000 0
+Threshold-653-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//830000 0
+Threshold-653-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//840000 0
+Threshold-654-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//830000 0
+Threshold-654-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//840000 0
+Threshold-655-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//830000 0
+Threshold-655-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//840000 0
+Threshold-656-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//830000 0
+Threshold-656-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//840000 0
+Threshold-657-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//830000 0
+Threshold-657-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//840000 0
+Threshold-658-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//820000 0
+Threshold-658-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//840000 0
+Threshold-659-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//820000 0
+Threshold-659-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//840000 0
+Threshold-660-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//820000 0
+Threshold-660-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//840000 0
+Threshold-661-start: go==>net/http.(*conn).serve//820000 0
+Threshold-661-end: go==>net/http.(*conn).serve//840000 0
+Threshold-662-start: runtime.mallocgc==>runtime.makeSpanClass//840000 0
+Threshold-662-end: runtime.mallocgc==>runtime.makeSpanClass//850000 0
+Threshold-663-start: runtime.newobject==>runtime.mallocgc//840000 0
+Threshold-663-end: runtime.newobject==>runtime.mallocgc//850000 0
+Threshold-664-start: net/http.(*Server).newConn==>runtime.newobject//840000 0
+Threshold-664-end: net/http.(*Server).newConn==>runtime.newobject//850000 0
+Threshold-665-start: net/http.(*Server).Serve==>net/http.(*Server).newConn//840000 0
+Threshold-665-end: net/http.(*Server).Serve==>net/http.(*Server).newConn//850000 0
+Threshold-666-start: net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//840000 0
+Threshold-666-end: net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//850000 0
+Threshold-667-start: net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//840000 0
+Threshold-667-end: net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//850000 0
+Threshold-668-start: main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//840000 0
+Threshold-668-end: main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//850000 0
+Threshold-669-start: main.runServer==>main.(*HttpServer).ListenAndServe//840000 0
+Threshold-669-end: main.runServer==>main.(*HttpServer).ListenAndServe//850000 0
+Threshold-670-start: main.main==>main.runServer//840000 0
+Threshold-670-end: main.main==>main.runServer//850000 0
+Threshold-671-start: runtime.main==>main.main//840000 0
+Threshold-671-end: runtime.main==>main.main//850000 0
+Threshold-672-start: go==>runtime.main//840000 0
+Threshold-672-end: go==>runtime.main//850000 0
+Threshold-673-start: runtime.futexwakeup==>runtime.futex//850000 0
+Threshold-673-end: runtime.futexwakeup==>runtime.futex//860000 0
+Threshold-674-start: runtime.notewakeup==>runtime.futexwakeup//850000 0
+Threshold-674-end: runtime.notewakeup==>runtime.futexwakeup//860000 0
+Threshold-675-start: runtime.entersyscall_sysmon==>runtime.notewakeup//850000 0
+Threshold-675-end: runtime.entersyscall_sysmon==>runtime.notewakeup//860000 0
+Threshold-676-start: runtime.systemstack==>runtime.entersyscall_sysmon//850000 0
+Threshold-676-end: runtime.systemstack==>runtime.entersyscall_sysmon//860000 0
+Threshold-677-start: runtime.reentersyscall==>runtime.systemstack//850000 0
+Threshold-677-end: runtime.reentersyscall==>runtime.systemstack//860000 0
+Threshold-678-start: runtime.entersyscall==>runtime.reentersyscall//850000 0
+Threshold-678-end: runtime.entersyscall==>runtime.reentersyscall//860000 0
+Threshold-679-start: syscall.Syscall==>runtime.entersyscall//850000 0
+Threshold-679-end: syscall.Syscall==>runtime.entersyscall//860000 0
+Threshold-680-start: syscall.read==>syscall.Syscall//850000 0
+Threshold-680-end: syscall.read==>syscall.Syscall//860000 0
+Threshold-681-start: syscall.Read==>syscall.read//850000 0
+Threshold-681-end: syscall.Read==>syscall.read//860000 0
+Threshold-682-start: internal/poll.(*FD).Read==>syscall.Read//850000 0
+Threshold-682-end: internal/poll.(*FD).Read==>syscall.Read//860000 0
+Threshold-683-start: net.(*netFD).Read==>internal/poll.(*FD).Read//850000 0
+Threshold-683-end: net.(*netFD).Read==>internal/poll.(*FD).Read//860000 0
+Threshold-684-start: net.(*conn).Read==>net.(*netFD).Read//850000 0
+Threshold-684-end: net.(*conn).Read==>net.(*netFD).Read//860000 0
+Threshold-685-start: bufio.(*Reader).Read==>net.(*conn).Read//850000 0
+Threshold-685-end: bufio.(*Reader).Read==>net.(*conn).Read//860000 0
+Threshold-686-start: io.ReadAtLeast==>bufio.(*Reader).Read//850000 0
+Threshold-686-end: io.ReadAtLeast==>bufio.(*Reader).Read//860000 0
+Threshold-687-start: io.ReadFull==>io.ReadAtLeast//850000 0
+Threshold-687-end: io.ReadFull==>io.ReadAtLeast//860000 0
+Threshold-688-start: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//850000 0
+Threshold-688-end: github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//860000 0
+Threshold-689-start: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//850000 0
+Threshold-689-end: github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//860000 0
+Threshold-690-start: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).recv//850000 0
+Threshold-690-end: github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).recv//860000 0
+Threshold-691-start: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//850000 0
+Threshold-691-end: github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//860000 0
+Threshold-692-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//850000 0
+Threshold-692-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//860000 0
+Threshold-693-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//850000 0
+Threshold-693-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//860000 0
+Threshold-694-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//850000 0
+Threshold-694-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//860000 0
+Threshold-695-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//850000 0
+Threshold-695-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//860000 0
+Threshold-696-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//850000 0
+Threshold-696-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//860000 0
+Threshold-697-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//850000 0
+Threshold-697-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//860000 0
+Threshold-698-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//850000 0
+Threshold-698-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//860000 0
+Threshold-699-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//850000 0
+Threshold-699-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//860000 0
+Threshold-700-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//850000 0
+Threshold-700-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//860000 0
+Threshold-701-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//850000 0
+Threshold-701-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//860000 0
+Threshold-702-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//850000 0
+Threshold-702-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//860000 0
+Threshold-703-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//850000 0
+Threshold-703-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//860000 0
+Threshold-704-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//850000 0
+Threshold-704-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//860000 0
+Threshold-705-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//850000 0
+Threshold-705-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//860000 0
+Threshold-706-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//850000 0
+Threshold-706-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//860000 0
+Threshold-707-start: go==>net/http.(*conn).serve//850000 0
+Threshold-707-end: go==>net/http.(*conn).serve//860000 0
+Threshold-708-start: runtime.park_m==>runtime.schedule//860000 0
+Threshold-708-end: runtime.park_m==>runtime.schedule//870000 0
+Threshold-709-start: runtime.mcall==>runtime.park_m//860000 0
+Threshold-709-end: runtime.mcall==>runtime.park_m//870000 0
+Threshold-710-start: go==>runtime.mcall//860000 0
+Threshold-710-end: go==>runtime.mcall//870000 0
+Threshold-711-start: runtime.mallocgc==>runtime.heapBitsSetType//870000 0
+Threshold-711-end: runtime.mallocgc==>runtime.heapBitsSetType//880000 0
+Threshold-712-start: runtime.newobject==>runtime.mallocgc//870000 0
+Threshold-712-end: runtime.newobject==>runtime.mallocgc//880000 0
+Threshold-713-start: net.sockaddrToTCP==>runtime.newobject//870000 0
+Threshold-713-end: net.sockaddrToTCP==>runtime.newobject//880000 0
+Threshold-714-start: net.(*netFD).dial==>net.sockaddrToTCP//870000 0
+Threshold-714-end: net.(*netFD).dial==>net.sockaddrToTCP//880000 0
+Threshold-715-start: net.socket==>net.(*netFD).dial//870000 0
+Threshold-715-end: net.socket==>net.(*netFD).dial//880000 0
+Threshold-716-start: net.internetSocket==>net.socket//870000 0
+Threshold-716-end: net.internetSocket==>net.socket//880000 0
+Threshold-717-start: net.(*sysDialer).doDialTCP==>net.internetSocket//870000 0
+Threshold-717-end: net.(*sysDialer).doDialTCP==>net.internetSocket//880000 0
+Threshold-718-start: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//870000 0
+Threshold-718-end: net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//880000 0
+Threshold-719-start: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//870000 0
+Threshold-719-end: net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//880000 0
+Threshold-720-start: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//870000 0
+Threshold-720-end: net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//880000 0
+Threshold-721-start: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//870000 0
+Threshold-721-end: net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//880000 0
+Threshold-722-start: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//870000 0
+Threshold-722-end: github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//880000 0
+Threshold-723-start: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//870000 0
+Threshold-723-end: github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//880000 0
+Threshold-724-start: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//870000 0
+Threshold-724-end: github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//880000 0
+Threshold-725-start: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//870000 0
+Threshold-725-end: github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//880000 0
+Threshold-726-start: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//870000 0
+Threshold-726-end: github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//880000 0
+Threshold-727-start: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//870000 0
+Threshold-727-end: github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//880000 0
+Threshold-728-start: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//870000 0
+Threshold-728-end: database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//880000 0
+Threshold-729-start: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//870000 0
+Threshold-729-end: database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//880000 0
+Threshold-730-start: database/sql.(*DB).query==>database/sql.(*DB).conn//870000 0
+Threshold-730-end: database/sql.(*DB).query==>database/sql.(*DB).conn//880000 0
+Threshold-731-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//870000 0
+Threshold-731-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//880000 0
+Threshold-732-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//870000 0
+Threshold-732-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//880000 0
+Threshold-733-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//870000 0
+Threshold-733-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//880000 0
+Threshold-734-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//870000 0
+Threshold-734-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//880000 0
+Threshold-735-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//870000 0
+Threshold-735-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//880000 0
+Threshold-736-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//870000 0
+Threshold-736-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//880000 0
+Threshold-737-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//870000 0
+Threshold-737-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//880000 0
+Threshold-738-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//870000 0
+Threshold-738-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//880000 0
+Threshold-739-start: go==>net/http.(*conn).serve//870000 0
+Threshold-739-end: go==>net/http.(*conn).serve//880000 0
+Threshold-740-start: runtime.netpoll==>runtime.epollwait//880000 0
+Threshold-740-end: runtime.netpoll==>runtime.epollwait//890000 0
+Threshold-741-start: runtime.findrunnable==>runtime.netpoll//880000 0
+Threshold-741-end: runtime.findrunnable==>runtime.netpoll//890000 0
+Threshold-742-start: runtime.schedule==>runtime.findrunnable//880000 0
+Threshold-742-end: runtime.schedule==>runtime.findrunnable//890000 0
+Threshold-743-start: runtime.goexit0==>runtime.schedule//880000 0
+Threshold-743-end: runtime.goexit0==>runtime.schedule//890000 0
+Threshold-744-start: runtime.mcall==>runtime.goexit0//880000 0
+Threshold-744-end: runtime.mcall==>runtime.goexit0//890000 0
+Threshold-745-start: go==>runtime.mcall//880000 0
+Threshold-745-end: go==>runtime.mcall//890000 0
+Threshold-746-start: runtime.adjustframe==>runtime.getStackMap//890000 0
+Threshold-746-end: runtime.adjustframe==>runtime.getStackMap//900000 0
+Threshold-747-start: runtime.tracebackdefers==>runtime.adjustframe//890000 0
+Threshold-747-end: runtime.tracebackdefers==>runtime.adjustframe//900000 0
+Threshold-748-start: runtime.adjustdefers==>runtime.tracebackdefers//890000 0
+Threshold-748-end: runtime.adjustdefers==>runtime.tracebackdefers//900000 0
+Threshold-749-start: runtime.copystack==>runtime.adjustdefers//890000 0
+Threshold-749-end: runtime.copystack==>runtime.adjustdefers//900000 0
+Threshold-750-start: runtime.newstack==>runtime.copystack//890000 0
+Threshold-750-end: runtime.newstack==>runtime.copystack//900000 0
+Threshold-751-start: internal/poll.(*FD).Write==>runtime.newstack//890000 0
+Threshold-751-end: internal/poll.(*FD).Write==>runtime.newstack//900000 0
+Threshold-752-start: net.(*netFD).Write==>internal/poll.(*FD).Write//890000 0
+Threshold-752-end: net.(*netFD).Write==>internal/poll.(*FD).Write//900000 0
+Threshold-753-start: net.(*conn).Write==>net.(*netFD).Write//890000 0
+Threshold-753-end: net.(*conn).Write==>net.(*netFD).Write//900000 0
+Threshold-754-start: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//890000 0
+Threshold-754-end: github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//900000 0
+Threshold-755-start: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//890000 0
+Threshold-755-end: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//900000 0
+Threshold-756-start: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//890000 0
+Threshold-756-end: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//900000 0
+Threshold-757-start: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//890000 0
+Threshold-757-end: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//900000 0
+Threshold-758-start: database/sql.(*driverConn).finalClose==>database/sql.withLock//890000 0
+Threshold-758-end: database/sql.(*driverConn).finalClose==>database/sql.withLock//900000 0
+Threshold-759-start: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//890000 0
+Threshold-759-end: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//900000 0
+Threshold-760-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//890000 0
+Threshold-760-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//900000 0
+Threshold-761-start: database/sql.(*DB).exec==>database/sql.(*DB).conn//890000 0
+Threshold-761-end: database/sql.(*DB).exec==>database/sql.(*DB).conn//900000 0
+Threshold-762-start: database/sql.(*DB).execDC==>database/sql.withLock//900000 0
+Threshold-762-end: database/sql.(*DB).execDC==>database/sql.withLock//910000 0
+Threshold-763-start: database/sql.(*DB).exec==>database/sql.(*DB).execDC//900000 0
+Threshold-763-end: database/sql.(*DB).exec==>database/sql.(*DB).execDC//910000 0
+Threshold-764-start: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//890000 0
+Threshold-764-end: database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//910000 0
+Threshold-765-start: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//890000 0
+Threshold-765-end: database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//910000 0
+Threshold-766-start: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//890000 0
+Threshold-766-end: main.(*Store).StoreWidget==>database/sql.(*DB).Exec//910000 0
+Threshold-767-start: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//890000 0
+Threshold-767-end: main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//910000 0
+Threshold-768-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//890000 0
+Threshold-768-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//910000 0
+Threshold-769-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//890000 0
+Threshold-769-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//910000 0
+Threshold-770-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//890000 0
+Threshold-770-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//910000 0
+Threshold-771-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//890000 0
+Threshold-771-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//910000 0
+Threshold-772-start: sync.runtime_notifyListWait==>runtime.acquireSudog//910000 0
+Threshold-772-end: sync.runtime_notifyListWait==>runtime.acquireSudog//920000 0
+Threshold-773-start: sync.(*Cond).Wait==>sync.runtime_notifyListWait//910000 0
+Threshold-773-end: sync.(*Cond).Wait==>sync.runtime_notifyListWait//920000 0
+Threshold-774-start: net/http.(*connReader).abortPendingRead==>sync.(*Cond).Wait//910000 0
+Threshold-774-end: net/http.(*connReader).abortPendingRead==>sync.(*Cond).Wait//920000 0
+Threshold-775-start: net/http.(*response).finishRequest==>net/http.(*connReader).abortPendingRead//910000 0
+Threshold-775-end: net/http.(*response).finishRequest==>net/http.(*connReader).abortPendingRead//920000 0
+Threshold-776-start: net/http.(*conn).serve==>net/http.(*response).finishRequest//910000 0
+Threshold-776-end: net/http.(*conn).serve==>net/http.(*response).finishRequest//920000 0
+Threshold-777-start: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//920000 0
+Threshold-777-end: internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//930000 0
+Threshold-778-start: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//920000 0
+Threshold-778-end: internal/poll.(*FD).Close==>internal/poll.(*FD).decref//930000 0
+Threshold-779-start: net.(*netFD).Close==>internal/poll.(*FD).Close//920000 0
+Threshold-779-end: net.(*netFD).Close==>internal/poll.(*FD).Close//930000 0
+Threshold-780-start: net.(*conn).Close==>net.(*netFD).Close//920000 0
+Threshold-780-end: net.(*conn).Close==>net.(*netFD).Close//930000 0
+Threshold-781-start: github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//920000 0
+Threshold-781-end: github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//930000 0
+Threshold-782-start: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//920000 0
+Threshold-782-end: github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//930000 0
+Threshold-783-start: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//920000 0
+Threshold-783-end: database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//930000 0
+Threshold-784-start: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//920000 0
+Threshold-784-end: database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//930000 0
+Threshold-785-start: database/sql.(*driverConn).finalClose==>database/sql.withLock//920000 0
+Threshold-785-end: database/sql.(*driverConn).finalClose==>database/sql.withLock//930000 0
+Threshold-786-start: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//920000 0
+Threshold-786-end: database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//930000 0
+Threshold-787-start: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//920000 0
+Threshold-787-end: database/sql.(*DB).conn==>database/sql.(*driverConn).Close//930000 0
+Threshold-788-start: database/sql.(*DB).query==>database/sql.(*DB).conn//920000 0
+Threshold-788-end: database/sql.(*DB).query==>database/sql.(*DB).conn//930000 0
+Threshold-789-start: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//920000 0
+Threshold-789-end: database/sql.(*DB).QueryContext==>database/sql.(*DB).query//930000 0
+Threshold-790-start: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//920000 0
+Threshold-790-end: database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//930000 0
+Threshold-791-start: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//920000 0
+Threshold-791-end: main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//930000 0
+Threshold-792-start: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//920000 0
+Threshold-792-end: main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//930000 0
+Threshold-793-start: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//920000 0
+Threshold-793-end: net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//930000 0
+Threshold-794-start: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//920000 0
+Threshold-794-end: github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//930000 0
+Threshold-795-start: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//920000 0
+Threshold-795-end: net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//930000 0
+Threshold-796-start: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//920000 0
+Threshold-796-end: net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//930000 0
+Threshold-797-start: runtime.mallocgc==>runtime.heapBitsSetType//930000 0
+Threshold-797-end: runtime.mallocgc==>runtime.heapBitsSetType//940000 0
+Threshold-798-start: runtime.newobject==>runtime.mallocgc//930000 0
+Threshold-798-end: runtime.newobject==>runtime.mallocgc//940000 0
+Threshold-799-start: context.WithValue==>runtime.newobject//930000 0
+Threshold-799-end: context.WithValue==>runtime.newobject//940000 0
+Threshold-800-start: net/http.(*conn).serve==>context.WithValue//930000 0
+Threshold-800-end: net/http.(*conn).serve==>context.WithValue//940000 0
+Threshold-801-start: go==>net/http.(*conn).serve//890000 0
+Threshold-801-end: go==>net/http.(*conn).serve//950000 0
+Threshold-802-start: golang==>go//0 0
+Threshold-802-end: golang==>go//950000 0
+
+go==>net/http.(*conn).serve//7 70000 0
+syscall.write==>syscall.Syscall//7 70000 0
+syscall.Write==>syscall.write//7 70000 0
+internal/poll.(*FD).Write==>syscall.Write//7 70000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//7 70000 0
+net.(*conn).Write==>net.(*netFD).Write//7 70000 0
+net/http.checkConnErrorWriter.Write==>net.(*conn).Write//7 70000 0
+bufio.(*Writer).Flush==>net/http.checkConnErrorWriter.Write//7 70000 0
+net/http.(*response).finishRequest==>bufio.(*Writer).Flush//7 70000 0
+net/http.(*conn).serve==>net/http.(*response).finishRequest//7 70000 0
+go==>net/http.(*conn).serve//4 40000 0
+syscall.socket==>syscall.RawSyscall//4 40000 0
+syscall.Socket==>syscall.socket//4 40000 0
+net.sysSocket==>syscall.Socket//4 40000 0
+net.socket==>net.sysSocket//4 40000 0
+net.internetSocket==>net.socket//4 40000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//4 40000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//4 40000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//4 40000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//4 40000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//4 40000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//4 40000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//4 40000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//4 40000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//4 40000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//4 40000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//4 40000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//4 40000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//4 40000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//4 40000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//4 40000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//4 40000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//4 40000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//4 40000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//4 40000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//4 40000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//4 40000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//4 40000 0
+go==>net/http.(*conn).serve//1 10000 0
+github.com/lib/pq.md5s==>runtime.convTslice//1 10000 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.md5s//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//3 30000 0
+syscall.Close==>syscall.Syscall//3 30000 0
+internal/poll.(*FD).destroy==>syscall.Close//3 30000 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//3 30000 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//3 30000 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//3 30000 0
+net.(*conn).Close==>net.(*netFD).Close//3 30000 0
+github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//3 30000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//3 30000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//3 30000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//3 30000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//3 30000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//3 30000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//3 30000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//3 30000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//3 30000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//3 30000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//3 30000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//3 30000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//3 30000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//3 30000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//3 30000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//3 30000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.netpollopen==>runtime.epollctl//1 10000 0
+internal/poll.runtime_pollOpen==>runtime.netpollopen//1 10000 0
+internal/poll.(*pollDesc).init==>internal/poll.runtime_pollOpen//1 10000 0
+internal/poll.(*FD).Init==>internal/poll.(*pollDesc).init//1 10000 0
+net.(*netFD).connect==>internal/poll.(*FD).Init//1 10000 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0
+net.socket==>net.(*netFD).dial//1 10000 0
+net.internetSocket==>net.socket//1 10000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0
+runtime.makeslice==>runtime.mallocgc//1 10000 0
+net/textproto.(*Reader).ReadMIMEHeader==>runtime.makeslice//1 10000 0
+net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//1 10000 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0
+go==>net/http.(*conn).serve//3 30000 0
+syscall.Close==>syscall.Syscall//3 30000 0
+internal/poll.(*FD).destroy==>syscall.Close//3 30000 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//3 30000 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//3 30000 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//3 30000 0
+net.(*conn).Close==>net.(*netFD).Close//3 30000 0
+net/http.(*conn).close==>net.(*conn).Close//3 30000 0
+net/http.(*conn).serve.func1==>net/http.(*conn).close//3 30000 0
+net/http.(*conn).serve==>net/http.(*conn).serve.func1//3 30000 0
+go==>net/http.(*conn).serve//3 30000 0
+syscall.write==>syscall.Syscall//3 30000 0
+syscall.Write==>syscall.write//3 30000 0
+internal/poll.(*FD).Write==>syscall.Write//3 30000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//3 30000 0
+net.(*conn).Write==>net.(*netFD).Write//3 30000 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//3 30000 0
+github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).send//3 30000 0
+github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//3 30000 0
+github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//3 30000 0
+database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//3 30000 0
+database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//3 30000 0
+database/sql.withLock==>database/sql.(*DB).queryDC.func1//3 30000 0
+database/sql.(*DB).queryDC==>database/sql.withLock//3 30000 0
+database/sql.(*DB).query==>database/sql.(*DB).queryDC//3 30000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//3 30000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//3 30000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//3 30000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//3 30000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//3 30000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//3 30000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//3 30000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//3 30000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.futexwakeup==>runtime.futex//1 10000 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0
+github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//1 10000 0
+github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//1 10000 0
+github.com/lib/pq.(*conn).readBindResponse==>github.com/lib/pq.(*conn).recv1//1 10000 0
+github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//1 10000 0
+github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//1 10000 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//1 10000 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//2 20000 0
+syscall.write==>syscall.Syscall//2 20000 0
+syscall.Write==>syscall.write//2 20000 0
+internal/poll.(*FD).Write==>syscall.Write//2 20000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//2 20000 0
+net.(*conn).Write==>net.(*netFD).Write//2 20000 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//2 20000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//2 20000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//2 20000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//2 20000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//2 20000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//2 20000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//2 20000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//2 20000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//2 20000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//2 20000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//2 20000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//2 20000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//2 20000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.setsockopt==>syscall.Syscall6//1 10000 0
+syscall.SetsockoptInt==>syscall.setsockopt//1 10000 0
+internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//1 10000 0
+net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//1 10000 0
+net.(*Dialer).DialContext==>net.setKeepAlivePeriod//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//5 50000 0
+syscall.connect==>syscall.Syscall//5 50000 0
+syscall.Connect==>syscall.connect//5 50000 0
+net.(*netFD).connect==>syscall.Connect//5 50000 0
+net.(*netFD).dial==>net.(*netFD).connect//5 50000 0
+net.socket==>net.(*netFD).dial//5 50000 0
+net.internetSocket==>net.socket//5 50000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//5 50000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//5 50000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//5 50000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//5 50000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//5 50000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//5 50000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//5 50000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//5 50000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//5 50000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//5 50000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//5 50000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//5 50000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//5 50000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//5 50000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//5 50000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//5 50000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//5 50000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//5 50000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//5 50000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//5 50000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//5 50000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//5 50000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0
+github.com/lib/pq.(*conn).recv1Buf==>github.com/lib/pq.(*conn).recvMessage//1 10000 0
+github.com/lib/pq.(*conn).recv1==>github.com/lib/pq.(*conn).recv1Buf//1 10000 0
+github.com/lib/pq.(*conn).simpleQuery==>github.com/lib/pq.(*conn).recv1//1 10000 0
+github.com/lib/pq.(*conn).query==>github.com/lib/pq.(*conn).simpleQuery//1 10000 0
+github.com/lib/pq.(*conn).QueryContext==>github.com/lib/pq.(*conn).query//1 10000 0
+database/sql.ctxDriverQuery==>github.com/lib/pq.(*conn).QueryContext//1 10000 0
+database/sql.(*DB).queryDC.func1==>database/sql.ctxDriverQuery//1 10000 0
+database/sql.withLock==>database/sql.(*DB).queryDC.func1//1 10000 0
+database/sql.(*DB).queryDC==>database/sql.withLock//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).queryDC//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.mcall//5 50000 0
+runtime.netpoll==>runtime.epollwait//5 50000 0
+runtime.findrunnable==>runtime.netpoll//5 50000 0
+runtime.schedule==>runtime.findrunnable//5 50000 0
+runtime.park_m==>runtime.schedule//5 50000 0
+runtime.mcall==>runtime.park_m//5 50000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+net/http.(*connReader).Read==>net.(*conn).Read//1 10000 0
+bufio.(*Reader).fill==>net/http.(*connReader).Read//1 10000 0
+bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//1 10000 0
+bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//1 10000 0
+net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//1 10000 0
+net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//1 10000 0
+net/http.readRequest==>net/textproto.(*Reader).ReadLine//1 10000 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+strings.IndexByte==>indexbytebody//1 10000 0
+strings.Index==>strings.IndexByte//1 10000 0
+strings.genSplit==>strings.Index//1 10000 0
+strings.SplitN==>strings.genSplit//1 10000 0
+github.com/lib/pq.parseEnviron==>strings.SplitN//1 10000 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.newproc1==>runtime.gfget//1 10000 0
+runtime.newproc.func1==>runtime.newproc1//1 10000 0
+runtime.systemstack==>runtime.newproc.func1//1 10000 0
+runtime.newproc==>runtime.systemstack//1 10000 0
+net/http.(*connReader).startBackgroundRead==>runtime.newproc//1 10000 0
+net/http.(*conn).serve==>net/http.(*connReader).startBackgroundRead//1 10000 0
+go==>runtime.mstart//7 70000 0
+runtime.sysmon==>runtime.usleep//7 70000 0
+runtime.mstart1==>runtime.sysmon//7 70000 0
+runtime.mstart==>runtime.mstart1//7 70000 0
+go==>runtime.mcall//1 10000 0
+runtime.futexsleep==>runtime.futex//1 10000 0
+runtime.notesleep==>runtime.futexsleep//1 10000 0
+runtime.stopm==>runtime.notesleep//1 10000 0
+runtime.findrunnable==>runtime.stopm//1 10000 0
+runtime.schedule==>runtime.findrunnable//1 10000 0
+runtime.park_m==>runtime.schedule//1 10000 0
+runtime.mcall==>runtime.park_m//1 10000 0
+go==>runtime.gcBgMarkWorker//1 10000 0
+runtime.newArenaMayUnlock==>runtime.memclrNoHeapPointers//1 10000 0
+runtime.newMarkBits==>runtime.newArenaMayUnlock//1 10000 0
+runtime.(*mspan).sweep==>runtime.newMarkBits//1 10000 0
+runtime.(*mcentral).uncacheSpan==>runtime.(*mspan).sweep//1 10000 0
+runtime.(*mcache).releaseAll==>runtime.(*mcentral).uncacheSpan//1 10000 0
+runtime.(*mcache).prepareForSweep==>runtime.(*mcache).releaseAll//1 10000 0
+runtime.procresize==>runtime.(*mcache).prepareForSweep//1 10000 0
+runtime.startTheWorldWithSema==>runtime.procresize//1 10000 0
+runtime.gcMarkTermination.func3==>runtime.startTheWorldWithSema//1 10000 0
+runtime.systemstack==>runtime.gcMarkTermination.func3//1 10000 0
+runtime.gcMarkTermination==>runtime.systemstack//1 10000 0
+runtime.gcMarkDone==>runtime.gcMarkTermination//1 10000 0
+runtime.gcBgMarkWorker==>runtime.gcMarkDone//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//1 10000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//1 10000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//2 20000 0
+syscall.write==>syscall.Syscall//2 20000 0
+syscall.Write==>syscall.write//2 20000 0
+internal/poll.(*FD).Write==>syscall.Write//2 20000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//2 20000 0
+net.(*conn).Write==>net.(*netFD).Write//2 20000 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//2 20000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//2 20000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//2 20000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//2 20000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//2 20000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//2 20000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//2 20000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//2 20000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//2 20000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//2 20000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//2 20000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//2 20000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//2 20000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.pcvalue==>runtime.step//1 10000 0
+runtime.funcspdelta==>runtime.pcvalue//1 10000 0
+runtime.gentraceback==>runtime.funcspdelta//1 10000 0
+runtime.copystack==>runtime.gentraceback//1 10000 0
+runtime.newstack==>runtime.copystack//1 10000 0
+time.readFile==>runtime.newstack//1 10000 0
+time.loadTzinfoFromDirOrZip==>time.readFile//1 10000 0
+time.loadTzinfo==>time.loadTzinfoFromDirOrZip//1 10000 0
+time.loadLocation==>time.loadTzinfo//1 10000 0
+time.LoadLocation==>time.loadLocation//1 10000 0
+github.com/lib/pq.(*conn).processParameterStatus==>time.LoadLocation//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).processParameterStatus//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.mstart//2 20000 0
+runtime.futexsleep==>runtime.futex//2 20000 0
+runtime.notetsleep_internal==>runtime.futexsleep//2 20000 0
+runtime.notetsleep==>runtime.notetsleep_internal//2 20000 0
+runtime.sysmon==>runtime.notetsleep//2 20000 0
+runtime.mstart1==>runtime.sysmon//2 20000 0
+runtime.mstart==>runtime.mstart1//2 20000 0
+go==>net/http.(*conn).serve//1 10000 0
+database/sql.(*DB).removeDepLocked==>runtime.mapdelete//1 10000 0
+database/sql.(*driverConn).Close==>database/sql.(*DB).removeDepLocked//1 10000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+regexp.(*Regexp).FindStringSubmatchIndex==>regexp.(*Regexp).pad//1 10000 0
+github.com/gorilla/mux.routeRegexpGroup.setMatch==>regexp.(*Regexp).FindStringSubmatchIndex//1 10000 0
+github.com/gorilla/mux.(*Route).Match==>github.com/gorilla/mux.routeRegexpGroup.setMatch//1 10000 0
+github.com/gorilla/mux.(*Router).Match==>github.com/gorilla/mux.(*Route).Match//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>github.com/gorilla/mux.(*Router).Match//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//2 20000 0
+syscall.read==>syscall.Syscall//2 20000 0
+syscall.Read==>syscall.read//2 20000 0
+internal/poll.(*FD).Read==>syscall.Read//2 20000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//2 20000 0
+net.(*conn).Read==>net.(*netFD).Read//2 20000 0
+bufio.(*Reader).Read==>net.(*conn).Read//2 20000 0
+io.ReadAtLeast==>bufio.(*Reader).Read//2 20000 0
+io.ReadFull==>io.ReadAtLeast//2 20000 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//2 20000 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//2 20000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//2 20000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//2 20000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//2 20000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//2 20000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//2 20000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//2 20000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//2 20000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//2 20000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//2 20000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//2 20000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//2 20000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//2 20000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//2 20000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//2 20000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//2 20000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//2 20000 0
+go==>net/http.(*conn).serve//1 10000 0
+strings.IndexByte==>internal/bytealg.IndexByteString//1 10000 0
+strings.Index==>strings.IndexByte//1 10000 0
+strings.genSplit==>strings.Index//1 10000 0
+strings.SplitN==>strings.genSplit//1 10000 0
+github.com/lib/pq.parseEnviron==>strings.SplitN//1 10000 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.gcBgMarkWorker//1 10000 0
+runtime.gcDrain==>runtime.scanobject//1 10000 0
+runtime.gcBgMarkWorker.func2==>runtime.gcDrain//1 10000 0
+runtime.systemstack==>runtime.gcBgMarkWorker.func2//1 10000 0
+runtime.gcBgMarkWorker==>runtime.systemstack//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.futexwakeup==>runtime.futex//1 10000 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+net/http.(*connReader).Read==>net.(*conn).Read//1 10000 0
+bufio.(*Reader).fill==>net/http.(*connReader).Read//1 10000 0
+bufio.(*Reader).ReadSlice==>bufio.(*Reader).fill//1 10000 0
+bufio.(*Reader).ReadLine==>bufio.(*Reader).ReadSlice//1 10000 0
+net/textproto.(*Reader).readLineSlice==>bufio.(*Reader).ReadLine//1 10000 0
+net/textproto.(*Reader).ReadLine==>net/textproto.(*Reader).readLineSlice//1 10000 0
+net/http.readRequest==>net/textproto.(*Reader).ReadLine//1 10000 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0
+go==>runtime.mcall//4 40000 0
+runtime.futexsleep==>runtime.futex//4 40000 0
+runtime.notesleep==>runtime.futexsleep//4 40000 0
+runtime.stopm==>runtime.notesleep//4 40000 0
+runtime.findrunnable==>runtime.stopm//4 40000 0
+runtime.schedule==>runtime.findrunnable//4 40000 0
+runtime.park_m==>runtime.schedule//4 40000 0
+runtime.mcall==>runtime.park_m//4 40000 0
+go==>net/http.(*conn).serve//1 10000 0
+github.com/lib/pq.(*stmt).exec==>github.com/lib/pq.(*conn).readBindResponse//1 10000 0
+github.com/lib/pq.(*stmt).Exec==>github.com/lib/pq.(*stmt).exec//1 10000 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*stmt).Exec//1 10000 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.write==>syscall.Syscall//1 10000 0
+syscall.Write==>syscall.write//1 10000 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//1 10000 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).send//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.main//1 10000 0
+runtime.futexwakeup==>runtime.futex//1 10000 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0
+runtime.startm==>runtime.notewakeup//1 10000 0
+runtime.wakep==>runtime.startm//1 10000 0
+runtime.newproc1==>runtime.wakep//1 10000 0
+runtime.newproc.func1==>runtime.newproc1//1 10000 0
+runtime.systemstack==>runtime.newproc.func1//1 10000 0
+runtime.newproc==>runtime.systemstack//1 10000 0
+net/http.(*Server).Serve==>runtime.newproc//1 10000 0
+net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//1 10000 0
+net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//1 10000 0
+main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//1 10000 0
+main.runServer==>main.(*HttpServer).ListenAndServe//1 10000 0
+main.main==>main.runServer//1 10000 0
+runtime.main==>main.main//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.newobject==>runtime.mallocgc//1 10000 0
+runtime.makemap_small==>runtime.newobject//1 10000 0
+github.com/lib/pq.parseEnviron==>runtime.makemap_small//1 10000 0
+github.com/lib/pq.NewConnector==>github.com/lib/pq.parseEnviron//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.NewConnector//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*connReader).backgroundRead//1 10000 0
+sync.runtime_notifyListNotifyAll==>runtime.readyWithTime//1 10000 0
+sync.(*Cond).Broadcast==>sync.runtime_notifyListNotifyAll//1 10000 0
+net/http.(*connReader).backgroundRead==>sync.(*Cond).Broadcast//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+encoding/json.ptrEncoder.encode==>encoding/json.structEncoder.encode//1 10000 0
+encoding/json.arrayEncoder.encode==>encoding/json.ptrEncoder.encode//1 10000 0
+encoding/json.sliceEncoder.encode==>encoding/json.arrayEncoder.encode//1 10000 0
+encoding/json.(*encodeState).reflectValue==>encoding/json.sliceEncoder.encode//1 10000 0
+encoding/json.(*encodeState).marshal==>encoding/json.(*encodeState).reflectValue//1 10000 0
+encoding/json.Marshal==>encoding/json.(*encodeState).marshal//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>encoding/json.Marshal//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0
+runtime.growslice==>runtime.mallocgc//1 10000 0
+main.(*Store).GetAllWidgets==>runtime.growslice//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.mapaccess1_faststr==>runtime.add//1 10000 0
+net/textproto.canonicalMIMEHeaderKey==>runtime.mapaccess1_faststr//1 10000 0
+net/textproto.(*Reader).ReadMIMEHeader==>net/textproto.canonicalMIMEHeaderKey//1 10000 0
+net/http.readRequest==>net/textproto.(*Reader).ReadMIMEHeader//1 10000 0
+net/http.(*conn).readRequest==>net/http.readRequest//1 10000 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.connect==>syscall.Syscall//1 10000 0
+syscall.Connect==>syscall.connect//1 10000 0
+net.(*netFD).connect==>syscall.Connect//1 10000 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0
+net.socket==>net.(*netFD).dial//1 10000 0
+net.internetSocket==>net.socket//1 10000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.write==>syscall.Syscall//1 10000 0
+syscall.Write==>syscall.write//1 10000 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0
+github.com/lib/pq.(*conn).sendStartupPacket==>net.(*conn).Write//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).sendStartupPacket//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.assertI2I2==>runtime.getitab//1 10000 0
+database/sql.(*DB).execDC==>runtime.assertI2I2//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.setsockopt==>syscall.Syscall6//1 10000 0
+syscall.SetsockoptInt==>syscall.setsockopt//1 10000 0
+internal/poll.(*FD).SetsockoptInt==>syscall.SetsockoptInt//1 10000 0
+net.setKeepAlivePeriod==>internal/poll.(*FD).SetsockoptInt//1 10000 0
+net.(*Dialer).DialContext==>net.setKeepAlivePeriod//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+net/http.(*Request).wantsClose==>net/http.hasToken//1 10000 0
+net/http.(*conn).readRequest==>net/http.(*Request).wantsClose//1 10000 0
+net/http.(*conn).serve==>net/http.(*conn).readRequest//1 10000 0
+go==>runtime.mcall//1 10000 0
+runtime.schedule==>runtime.findrunnable//1 10000 0
+runtime.park_m==>runtime.schedule//1 10000 0
+runtime.mcall==>runtime.park_m//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.futexwakeup==>runtime.futex//1 10000 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).recv//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.getpeername==>syscall.RawSyscall//1 10000 0
+syscall.Getpeername==>syscall.getpeername//1 10000 0
+net.(*netFD).connect==>syscall.Getpeername//1 10000 0
+net.(*netFD).dial==>net.(*netFD).connect//1 10000 0
+net.socket==>net.(*netFD).dial//1 10000 0
+net.internetSocket==>net.socket//1 10000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.main//1 10000 0
+runtime.mallocgc==>runtime.makeSpanClass//1 10000 0
+runtime.newobject==>runtime.mallocgc//1 10000 0
+net/http.(*Server).newConn==>runtime.newobject//1 10000 0
+net/http.(*Server).Serve==>net/http.(*Server).newConn//1 10000 0
+net/http.(*Server).ListenAndServe==>net/http.(*Server).Serve//1 10000 0
+net/http.ListenAndServe==>net/http.(*Server).ListenAndServe//1 10000 0
+main.(*HttpServer).ListenAndServe==>net/http.ListenAndServe//1 10000 0
+main.runServer==>main.(*HttpServer).ListenAndServe//1 10000 0
+main.main==>main.runServer//1 10000 0
+runtime.main==>main.main//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.futexwakeup==>runtime.futex//1 10000 0
+runtime.notewakeup==>runtime.futexwakeup//1 10000 0
+runtime.entersyscall_sysmon==>runtime.notewakeup//1 10000 0
+runtime.systemstack==>runtime.entersyscall_sysmon//1 10000 0
+runtime.reentersyscall==>runtime.systemstack//1 10000 0
+runtime.entersyscall==>runtime.reentersyscall//1 10000 0
+syscall.Syscall==>runtime.entersyscall//1 10000 0
+syscall.read==>syscall.Syscall//1 10000 0
+syscall.Read==>syscall.read//1 10000 0
+internal/poll.(*FD).Read==>syscall.Read//1 10000 0
+net.(*netFD).Read==>internal/poll.(*FD).Read//1 10000 0
+net.(*conn).Read==>net.(*netFD).Read//1 10000 0
+bufio.(*Reader).Read==>net.(*conn).Read//1 10000 0
+io.ReadAtLeast==>bufio.(*Reader).Read//1 10000 0
+io.ReadFull==>io.ReadAtLeast//1 10000 0
+github.com/lib/pq.(*conn).recvMessage==>io.ReadFull//1 10000 0
+github.com/lib/pq.(*conn).recv==>github.com/lib/pq.(*conn).recvMessage//1 10000 0
+github.com/lib/pq.(*conn).auth==>github.com/lib/pq.(*conn).recv//1 10000 0
+github.com/lib/pq.(*conn).startup==>github.com/lib/pq.(*conn).auth//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.(*conn).startup//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.mcall//1 10000 0
+runtime.park_m==>runtime.schedule//1 10000 0
+runtime.mcall==>runtime.park_m//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0
+runtime.newobject==>runtime.mallocgc//1 10000 0
+net.sockaddrToTCP==>runtime.newobject//1 10000 0
+net.(*netFD).dial==>net.sockaddrToTCP//1 10000 0
+net.socket==>net.(*netFD).dial//1 10000 0
+net.internetSocket==>net.socket//1 10000 0
+net.(*sysDialer).doDialTCP==>net.internetSocket//1 10000 0
+net.(*sysDialer).dialTCP==>net.(*sysDialer).doDialTCP//1 10000 0
+net.(*sysDialer).dialSingle==>net.(*sysDialer).dialTCP//1 10000 0
+net.(*sysDialer).dialSerial==>net.(*sysDialer).dialSingle//1 10000 0
+net.(*Dialer).DialContext==>net.(*sysDialer).dialSerial//1 10000 0
+github.com/lib/pq.defaultDialer.DialContext==>net.(*Dialer).DialContext//1 10000 0
+github.com/lib/pq.dial==>github.com/lib/pq.defaultDialer.DialContext//1 10000 0
+github.com/lib/pq.(*Connector).open==>github.com/lib/pq.dial//1 10000 0
+github.com/lib/pq.DialOpen==>github.com/lib/pq.(*Connector).open//1 10000 0
+github.com/lib/pq.Open==>github.com/lib/pq.DialOpen//1 10000 0
+github.com/lib/pq.(*Driver).Open==>github.com/lib/pq.Open//1 10000 0
+database/sql.dsnConnector.Connect==>github.com/lib/pq.(*Driver).Open//1 10000 0
+database/sql.(*DB).conn==>database/sql.dsnConnector.Connect//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>runtime.mcall//1 10000 0
+runtime.netpoll==>runtime.epollwait//1 10000 0
+runtime.findrunnable==>runtime.netpoll//1 10000 0
+runtime.schedule==>runtime.findrunnable//1 10000 0
+runtime.goexit0==>runtime.schedule//1 10000 0
+runtime.mcall==>runtime.goexit0//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.adjustframe==>runtime.getStackMap//1 10000 0
+runtime.tracebackdefers==>runtime.adjustframe//1 10000 0
+runtime.adjustdefers==>runtime.tracebackdefers//1 10000 0
+runtime.copystack==>runtime.adjustdefers//1 10000 0
+runtime.newstack==>runtime.copystack//1 10000 0
+internal/poll.(*FD).Write==>runtime.newstack//1 10000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0
+github.com/lib/pq.(*conn).sendSimpleMessage==>net.(*conn).Write//1 10000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).sendSimpleMessage//1 10000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+sync.runtime_notifyListWait==>runtime.acquireSudog//1 10000 0
+sync.(*Cond).Wait==>sync.runtime_notifyListWait//1 10000 0
+net/http.(*connReader).abortPendingRead==>sync.(*Cond).Wait//1 10000 0
+net/http.(*response).finishRequest==>net/http.(*connReader).abortPendingRead//1 10000 0
+net/http.(*conn).serve==>net/http.(*response).finishRequest//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+internal/poll.(*FD).decref==>internal/poll.(*FD).destroy//1 10000 0
+internal/poll.(*FD).Close==>internal/poll.(*FD).decref//1 10000 0
+net.(*netFD).Close==>internal/poll.(*FD).Close//1 10000 0
+net.(*conn).Close==>net.(*netFD).Close//1 10000 0
+github.com/lib/pq.(*conn).Close.func1==>net.(*conn).Close//1 10000 0
+github.com/lib/pq.(*conn).Close==>github.com/lib/pq.(*conn).Close.func1//1 10000 0
+database/sql.(*driverConn).finalClose.func2==>github.com/lib/pq.(*conn).Close//1 10000 0
+database/sql.withLock==>database/sql.(*driverConn).finalClose.func2//1 10000 0
+database/sql.(*driverConn).finalClose==>database/sql.withLock//1 10000 0
+database/sql.(*driverConn).Close==>database/sql.(*driverConn).finalClose//1 10000 0
+database/sql.(*DB).conn==>database/sql.(*driverConn).Close//1 10000 0
+database/sql.(*DB).query==>database/sql.(*DB).conn//1 10000 0
+database/sql.(*DB).QueryContext==>database/sql.(*DB).query//1 10000 0
+database/sql.(*DB).Query==>database/sql.(*DB).QueryContext//1 10000 0
+main.(*Store).GetAllWidgets==>database/sql.(*DB).Query//1 10000 0
+main.(*HttpServer).httpHandlerGetWidgets==>main.(*Store).GetAllWidgets//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerGetWidgets//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+runtime.mallocgc==>runtime.heapBitsSetType//1 10000 0
+runtime.newobject==>runtime.mallocgc//1 10000 0
+context.WithValue==>runtime.newobject//1 10000 0
+net/http.(*conn).serve==>context.WithValue//1 10000 0
+go==>net/http.(*conn).serve//1 10000 0
+syscall.write==>syscall.Syscall//1 10000 0
+syscall.Write==>syscall.write//1 10000 0
+internal/poll.(*FD).Write==>syscall.Write//1 10000 0
+net.(*netFD).Write==>internal/poll.(*FD).Write//1 10000 0
+net.(*conn).Write==>net.(*netFD).Write//1 10000 0
+github.com/lib/pq.(*conn).send==>net.(*conn).Write//1 10000 0
+github.com/lib/pq.(*conn).prepareTo==>github.com/lib/pq.(*conn).send//1 10000 0
+github.com/lib/pq.(*conn).Exec==>github.com/lib/pq.(*conn).prepareTo//1 10000 0
+github.com/lib/pq.(*conn).ExecContext==>github.com/lib/pq.(*conn).Exec//1 10000 0
+database/sql.ctxDriverExec==>github.com/lib/pq.(*conn).ExecContext//1 10000 0
+database/sql.(*DB).execDC.func2==>database/sql.ctxDriverExec//1 10000 0
+database/sql.withLock==>database/sql.(*DB).execDC.func2//1 10000 0
+database/sql.(*DB).execDC==>database/sql.withLock//1 10000 0
+database/sql.(*DB).exec==>database/sql.(*DB).execDC//1 10000 0
+database/sql.(*DB).ExecContext==>database/sql.(*DB).exec//1 10000 0
+database/sql.(*DB).Exec==>database/sql.(*DB).ExecContext//1 10000 0
+main.(*Store).StoreWidget==>database/sql.(*DB).Exec//1 10000 0
+main.(*HttpServer).httpHandlerCreateWidget==>main.(*Store).StoreWidget//1 10000 0
+net/http.HandlerFunc.ServeHTTP==>main.(*HttpServer).httpHandlerCreateWidget//1 10000 0
+github.com/gorilla/mux.(*Router).ServeHTTP==>net/http.HandlerFunc.ServeHTTP//1 10000 0
+net/http.serverHandler.ServeHTTP==>github.com/gorilla/mux.(*Router).ServeHTTP//1 10000 0
+net/http.(*conn).serve==>net/http.serverHandler.ServeHTTP//1 10000 0
+==>go//1 950000 0
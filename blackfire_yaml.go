@@ -0,0 +1,61 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The top-level keys of .blackfire.yml files.
+var blackfireYamlKeys = map[string]bool{
+	"metrics":         true,
+	"recommendations": true,
+	"scenarios":       true,
+	"tests":           true,
+}
+
+// LintBlackfireYaml checks the .blackfire.yml file the global probe sends
+// along with the profiles (see Probe.LintBlackfireYaml).
+func LintBlackfireYaml() error {
+	return globalProbe.LintBlackfireYaml()
+}
+
+// LintBlackfireYaml checks the YAML syntax and the top-level keys of the
+// .blackfire.yml file sent along with the profiles (see
+// Configuration.BlackfireYamlPath). It returns nil if there is no such file.
+func (p *Probe) LintBlackfireYaml() error {
+	if err := p.configuration.load(); err != nil {
+		return err
+	}
+	filename, data, err := readBlackfireYaml(p.configuration.BlackfireYamlPath)
+	if err != nil || filename == "" {
+		return err
+	}
+	if err := lintBlackfireYaml(data); err != nil {
+		return fmt.Errorf("%s: %v", filename, err)
+	}
+	return nil
+}
+
+func lintBlackfireYaml(data []byte) error {
+	var document map[string]interface{}
+	if err := yaml.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("invalid YAML: %v", err)
+	}
+	var unknown []string
+	for key := range document {
+		if !blackfireYamlKeys[key] {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("unknown top-level keys: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
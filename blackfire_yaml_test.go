@@ -0,0 +1,42 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestLintBlackfireYaml(c *C) {
+	c.Assert(lintBlackfireYaml(nil), IsNil)
+	c.Assert(lintBlackfireYaml([]byte(`
+tests:
+    "Pages are fast":
+        path: "/.*"
+        assertions:
+            - "main.wall_time < 100ms"
+scenarios: |
+    #!blackfire-player
+`)), IsNil)
+	c.Assert(lintBlackfireYaml([]byte("tests: [")), ErrorMatches, "invalid YAML: .*")
+	c.Assert(lintBlackfireYaml([]byte("- tests")), ErrorMatches, "(?s)invalid YAML: .*cannot unmarshal.*")
+	c.Assert(lintBlackfireYaml([]byte("test: {}\ntests: {}\nmetric: {}\n")), ErrorMatches, "unknown top-level keys: metric, test")
+}
+
+func (s *BlackfireSuite) TestProbeLintBlackfireYaml(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-yaml")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, ".blackfire.yml")
+
+	p := newTestProbe(newFakeClock())
+	p.configuration.BlackfireYamlPath = path
+	c.Assert(p.LintBlackfireYaml(), ErrorMatches, ".*no such file or directory")
+
+	c.Assert(ioutil.WriteFile(path, []byte("tsets: {}\n"), 0644), IsNil)
+	c.Assert(p.LintBlackfireYaml(), ErrorMatches, ".*\\.blackfire\\.yml: unknown top-level keys: tsets")
+}
@@ -0,0 +1,94 @@
+// Package blackfiretest profiles tests and benchmarks with Blackfire.
+//
+//	func TestParse(t *testing.T) {
+//		defer blackfiretest.Profile(t)()
+//		parse(input)
+//	}
+//
+//	func BenchmarkParse(b *testing.B) {
+//		blackfiretest.ProfileBenchmark(b, func(b *testing.B) {
+//			for i := 0; i < b.N; i++ {
+//				parse(input)
+//			}
+//		})
+//	}
+//
+// Profiling is skipped, without failing the test, when the probe is not
+// configured (see blackfire.Configuration for the ways to provide the
+// Blackfire credentials).
+package blackfiretest
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/blackfireio/go-blackfire"
+)
+
+// Profile profiles the rest of the test, using the test name as the profile
+// title. The returned function ends the profile and uploads it, and is meant
+// to be deferred.
+func Profile(tb testing.TB) (stop func()) {
+	tb.Helper()
+	return profile(tb, blackfire.GlobalProbe(), tb.Name())
+}
+
+// ProfileBenchmark runs the benchmark body as a sub-benchmark, and profiles
+// its final round only, whose b.N is the one reported. The earlier rounds,
+// which the testing package runs to size b.N, are profiled too, as there is
+// no telling which round is the final one, but their profiles are discarded.
+// The time spent starting the profiles is not measured.
+func ProfileBenchmark(b *testing.B, body func(b *testing.B)) {
+	b.Helper()
+	profileBenchmark(b, blackfire.GlobalProbe(), body)
+}
+
+func profileBenchmark(b *testing.B, probe *blackfire.Probe, body func(b *testing.B)) {
+	b.Helper()
+	pending := false
+	b.Run("profiled", func(b *testing.B) {
+		if pending {
+			pending = false
+			if err := probe.EndToFile(os.DevNull); err != nil {
+				b.Logf("Blackfire: Unable to discard the profile of %s: %v", b.Name(), err)
+			}
+		}
+		stop := profile(b, probe, fmt.Sprintf("%s (N=%d)", b.Name(), b.N))
+		b.ResetTimer()
+		body(b)
+		b.StopTimer()
+		if probe.IsProfiling() {
+			// Keep the profile until the next round tells whether this one
+			// was the final round.
+			if err := probe.Pause(); err != nil {
+				b.Logf("Blackfire: Unable to pause the profile of %s: %v", b.Name(), err)
+				stop()
+				return
+			}
+			pending = true
+		}
+	})
+	if pending {
+		if err := probe.End(); err != nil {
+			b.Logf("Blackfire: Unable to upload the profile of %s: %v", b.Name(), err)
+		}
+	}
+}
+
+func profile(tb testing.TB, probe *blackfire.Probe, title string) (stop func()) {
+	tb.Helper()
+	stop = func() {}
+	// A zero duration profiles for the maximum duration allowed.
+	if err := probe.EnableNowForWithOptions(0, blackfire.ProfileOptions{Title: title}); err != nil {
+		return
+	}
+	if !probe.IsProfiling() {
+		return
+	}
+	return func() {
+		if err := probe.End(); err != nil {
+			tb.Logf("Blackfire: Unable to upload the profile of %s: %v", tb.Name(), err)
+		}
+	}
+}
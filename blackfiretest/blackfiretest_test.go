@@ -0,0 +1,74 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfiretest
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blackfireio/go-blackfire"
+)
+
+func TestProfile(t *testing.T) {
+	output := filepath.Join(os.TempDir(), "blackfiretest.bf")
+	probe := blackfire.NewProbe(&blackfire.Configuration{OutputFile: output})
+
+	stop := profile(t, probe, t.Name())
+	if !probe.IsProfiling() {
+		t.Fatal("Expected the test to be profiled")
+	}
+	stop()
+	if probe.IsProfiling() {
+		t.Fatal("Expected the profile to end")
+	}
+}
+
+func TestProfileBenchmark(t *testing.T) {
+	output := filepath.Join(os.TempDir(), "blackfiretest-benchmark.bf")
+	os.Remove(output)
+	probe := blackfire.NewProbe(&blackfire.Configuration{OutputFile: output})
+
+	rounds := 0
+	var n int
+	testing.Benchmark(func(b *testing.B) {
+		profileBenchmark(b, probe, func(b *testing.B) {
+			rounds++
+			n = b.N
+			probe.Metric("rounds").Add(1)
+			time.Sleep(time.Millisecond)
+		})
+	})
+	if rounds < 2 {
+		t.Fatalf("Expected several rounds, got %d", rounds)
+	}
+	if probe.IsProfiling() {
+		t.Fatal("Expected the profile to end with the benchmark")
+	}
+	// Only the final round is written to the output file.
+	contents, err := ioutil.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if title := fmt.Sprintf("(N=%d)", n); !strings.Contains(string(contents), title) {
+		t.Fatalf("Expected the profile of the final round %s, got:\n%s", title, contents)
+	}
+}
+
+func TestProfileWithoutCredentials(t *testing.T) {
+	os.Setenv("BLACKFIRE_INTERNAL_IGNORE_INI", "1")
+	defer os.Unsetenv("BLACKFIRE_INTERNAL_IGNORE_INI")
+	probe := blackfire.NewProbe(&blackfire.Configuration{})
+
+	t.Run("not profiled", func(t *testing.T) {
+		profile(t, probe, t.Name())()
+		if probe.IsProfiling() {
+			t.Fatal("Expected the test not to be profiled")
+		}
+	})
+}
@@ -0,0 +1,46 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+)
+
+// CheckAgent checks that the global probe is able to upload profiles (see
+// Probe.CheckAgent).
+func CheckAgent(ctx context.Context) (*AgentDiagnosis, error) {
+	return globalProbe.CheckAgent(ctx)
+}
+
+// CheckAgent checks that the probe is able to upload profiles: it connects to
+// the agent socket, and checks the credentials with a signing request. It
+// returns an error if the configuration is invalid; connection and
+// authentication problems are reported in the diagnosis.
+func (p *Probe) CheckAgent(ctx context.Context) (*AgentDiagnosis, error) {
+	if err := p.configuration.load(); err != nil {
+		return nil, err
+	}
+	if p.configuration.Disabled {
+		return nil, errDisabled
+	}
+	client, err := NewAgentClient(p.configuration)
+	if err != nil {
+		return nil, err
+	}
+
+	diagnosis := &AgentDiagnosis{
+		AgentSocket: p.configuration.AgentSocket,
+		Endpoint:    p.configuration.HTTPEndpoint.String(),
+	}
+	diagnosis.SocketError = checkAgentSocket(ctx, p.configuration.AgentSocket)
+	diagnosis.SocketReachable = diagnosis.SocketError == nil
+
+	if p.configuration.ClientID == "" || p.configuration.ClientToken == "" {
+		diagnosis.SigningSkipped = true
+		return diagnosis, nil
+	}
+	diagnosis.EndpointReachable, diagnosis.AuthError = client.checkSigning(ctx)
+	diagnosis.AuthOK = diagnosis.AuthError == nil
+	return diagnosis, nil
+}
@@ -0,0 +1,78 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func newCheckProbe(c *C, agentSocket string, endpoint string) *Probe {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-check-test.log"), 4)
+	p := NewProbe(&Configuration{
+		AgentSocket:  agentSocket,
+		ClientID:     "id",
+		ClientToken:  "token",
+		HTTPEndpoint: URL(endpoint),
+		Logger:       &logger,
+	})
+	c.Assert(p.configuration.load(), IsNil)
+	return p
+}
+
+func (s *BlackfireSuite) TestCheckAgent(c *C) {
+	agent, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer agent.Close()
+	api := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c.Check(r.URL.Path, Equals, "/api/v1/signing")
+		if user, password, _ := r.BasicAuth(); user != "id" || password != "token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer api.Close()
+
+	p := newCheckProbe(c, "tcp://"+agent.Addr().String(), api.URL)
+	diagnosis, err := p.CheckAgent(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(diagnosis.SocketReachable, Equals, true)
+	c.Assert(diagnosis.EndpointReachable, Equals, true)
+	c.Assert(diagnosis.AuthOK, Equals, true)
+	c.Assert(diagnosis.Err(), IsNil)
+
+	// The configuration must not be altered by the check.
+	c.Assert(p.configuration.HTTPEndpoint.String(), Equals, api.URL)
+
+	p.configuration.ClientToken = "wrong"
+	diagnosis, err = p.CheckAgent(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(diagnosis.EndpointReachable, Equals, true)
+	c.Assert(diagnosis.AuthOK, Equals, false)
+	c.Assert(diagnosis.Err(), ErrorMatches, "Blackfire credentials rejected: .*401.*")
+}
+
+func (s *BlackfireSuite) TestCheckAgentUnreachable(c *C) {
+	agent, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	address := agent.Addr().String()
+	agent.Close()
+
+	p := newCheckProbe(c, "tcp://"+address, "http://"+address)
+	diagnosis, err := p.CheckAgent(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(diagnosis.SocketReachable, Equals, false)
+	c.Assert(diagnosis.EndpointReachable, Equals, false)
+	c.Assert(diagnosis.Err(), ErrorMatches, "Blackfire agent unreachable at tcp://.*")
+}
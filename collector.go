@@ -0,0 +1,260 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+const (
+	// collectorTimeout caps the time spent pushing a profile to the
+	// collector.
+	collectorTimeout = 30 * time.Second
+	// maxCollectorPayloadSize caps the size of a profile pushed to the
+	// collector.
+	maxCollectorPayloadSize = 64 << 20
+	// maxPendingSubProfiles caps the profiles of the child processes kept
+	// until the probe ends a profile.
+	maxPendingSubProfiles = 64
+)
+
+// collectorPayload is the profile a child process pushes to the collector:
+// its pprof buffers, as they were recorded. For the heap growth between two
+// snapshots (see Probe.DiffHeap), HeapBefore is the first snapshot and Mem
+// the second one.
+type collectorPayload struct {
+	Process    string   `json:"process"`
+	CPU        [][]byte `json:"cpu"`
+	Mem        [][]byte `json:"mem"`
+	HeapBefore []byte   `json:"heap_before,omitempty"`
+}
+
+// collectorResponse acknowledges a pushed profile.
+type collectorResponse struct {
+	Error string `json:"error,omitempty"`
+}
+
+// Collector receives the profiles of child processes on a unix socket, and
+// merges them as sub-profiles of the next profile of its probe (see
+// Probe.StartCollector).
+type Collector struct {
+	probe    *Probe
+	path     string
+	listener net.Listener
+}
+
+// StartCollector collects the profiles of the child processes in the global
+// probe (see Probe.StartCollector).
+func StartCollector(path string) (*Collector, error) {
+	return globalProbe.StartCollector(path)
+}
+
+// StartCollector listens on the unix socket at path for the profiles of the
+// child processes, which are merged into the next profile this probe ends,
+// each one under a node named after its process. The child processes must be
+// started with the environment returned by Collector.Env, to push their
+// profiles there (see Configuration.CollectorSocket) instead of uploading
+// them. Only the user running the process can connect to the socket. No
+// collector is started when the probe is disabled by configuration: a nil
+// Collector is returned, whose methods are no-ops.
+func (p *Probe) StartCollector(path string) (*Collector, error) {
+	if err := p.configuration.load(); err != nil {
+		return nil, err
+	}
+	if p.configuration.Disabled {
+		return nil, nil
+	}
+	listener, err := listen("unix://" + path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	c := &Collector{
+		probe:    p,
+		path:     path,
+		listener: listener,
+	}
+	p.configuration.Logger.Info().Msgf("Blackfire (collector): Collecting the profiles of the child processes on %s", path)
+	go c.serve()
+	return c, nil
+}
+
+func (c *Collector) serve() {
+	for {
+		conn, err := c.listener.Accept()
+		if err != nil {
+			return
+		}
+		go c.receive(conn)
+	}
+}
+
+// receive reads a profile pushed by a child process, and keeps it for the
+// next profile of the probe.
+func (c *Collector) receive(conn net.Conn) {
+	defer conn.Close()
+	logger := c.probe.configuration.Logger
+	conn.SetDeadline(time.Now().Add(collectorTimeout))
+
+	var response collectorResponse
+	var payload collectorPayload
+	reader := &io.LimitedReader{R: conn, N: maxCollectorPayloadSize}
+	if err := json.NewDecoder(reader).Decode(&payload); err != nil {
+		if reader.N == 0 {
+			err = fmt.Errorf("the profile exceeds %d bytes", maxCollectorPayloadSize)
+		}
+		response.Error = err.Error()
+	} else if profile, err := c.read(payload); err != nil {
+		response.Error = err.Error()
+	} else if err := c.probe.addSubProfile(payload.Process, profile); err != nil {
+		response.Error = err.Error()
+	} else {
+		logger.Debug().Msgf("Blackfire (collector): Received the profile of %s", payload.Process)
+	}
+	if response.Error != "" {
+		logger.Error().Msgf("Blackfire (collector): Unable to receive a profile: %s", response.Error)
+	}
+	json.NewEncoder(conn).Encode(response)
+}
+
+// read converts the pprof buffers of a payload to a profile.
+func (c *Collector) read(payload collectorPayload) (*pprof_reader.Profile, error) {
+	options := c.probe.readOptions()
+	if payload.HeapBefore == nil {
+		return pprof_reader.ReadFromPProfWithOptions(buffersOf(payload.CPU), buffersOf(payload.Mem), options)
+	}
+	options.MemoryAttribution = pprof_reader.MemoryPerStack
+	before, err := pprof_reader.ReadFromPProfWithOptions(nil, buffersOf([][]byte{payload.HeapBefore}), options)
+	if err != nil {
+		return nil, err
+	}
+	after, err := pprof_reader.ReadFromPProfWithOptions(nil, buffersOf(payload.Mem), options)
+	if err != nil {
+		return nil, err
+	}
+	return pprof_reader.DiffMemory(before, after), nil
+}
+
+// Env returns the environment of the current process, with the
+// BLACKFIRE_COLLECTOR_SOCKET env var telling the child processes to push their
+// profiles to the collector, typically for exec.Cmd.Env. The environment of
+// the current process is left untouched, for its own profiles to be uploaded.
+func (c *Collector) Env() []string {
+	env := os.Environ()
+	if c == nil {
+		return env
+	}
+	filtered := make([]string, 0, len(env)+1)
+	for _, v := range env {
+		if !strings.HasPrefix(v, collectorSocketEnvVar+"=") {
+			filtered = append(filtered, v)
+		}
+	}
+	return append(filtered, collectorSocketEnvVar+"="+c.path)
+}
+
+// Close stops collecting the profiles. The ones already received are still
+// merged into the next profile.
+func (c *Collector) Close() error {
+	if c == nil {
+		return nil
+	}
+	return c.listener.Close()
+}
+
+// addSubProfile keeps the profile of a child process for the next profile,
+// unless too many are already pending.
+func (p *Probe) addSubProfile(process string, profile *pprof_reader.Profile) error {
+	p.subProfilesMutex.Lock()
+	defer p.subProfilesMutex.Unlock()
+	if len(p.subProfiles) >= maxPendingSubProfiles {
+		return fmt.Errorf("too many profiles are pending (%d)", maxPendingSubProfiles)
+	}
+	p.subProfiles = append(p.subProfiles, pprof_reader.ProcessProfile{
+		Name:    process,
+		Profile: profile,
+	})
+	return nil
+}
+
+// takeSubProfiles returns the profiles received from the child processes
+// since the last call.
+func (p *Probe) takeSubProfiles() []pprof_reader.ProcessProfile {
+	p.subProfilesMutex.Lock()
+	defer p.subProfilesMutex.Unlock()
+	profiles := p.subProfiles
+	p.subProfiles = nil
+	return profiles
+}
+
+// pushToCollector sends the recorded pprof buffers to the collector of the
+// parent process, instead of converting and uploading them.
+func (p *Probe) pushToCollector(path string) error {
+	defer p.resetProfileBufferSet()
+
+	payload := collectorPayload{
+		Process: fmt.Sprintf("%s (pid %d)", p.title(), os.Getpid()),
+	}
+	for _, buffer := range p.cpuProfileBuffers {
+		payload.CPU = append(payload.CPU, buffer.Bytes())
+	}
+	for _, buffer := range p.memProfileBuffers {
+		payload.Mem = append(payload.Mem, buffer.Bytes())
+	}
+	return pushPayload(path, payload)
+}
+
+// pushHeapGrowthToCollector sends two heap snapshots to the collector of the
+// parent process, which uploads the heap growth between them with its next
+// profile.
+func pushHeapGrowthToCollector(path, title string, before, after *HeapSnapshot) error {
+	return pushPayload(path, collectorPayload{
+		Process:    fmt.Sprintf("%s (pid %d)", title, os.Getpid()),
+		Mem:        [][]byte{after.data},
+		HeapBefore: before.data,
+	})
+}
+
+func pushPayload(path string, payload collectorPayload) error {
+	conn, err := net.DialTimeout("unix", path, collectorTimeout)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(collectorTimeout))
+	if err := json.NewEncoder(conn).Encode(payload); err != nil {
+		return err
+	}
+	// Wait for the collector to acknowledge the profile, for it not to be
+	// lost if the process exits right away.
+	var response collectorResponse
+	if err := json.NewDecoder(conn).Decode(&response); err != nil {
+		return err
+	}
+	if response.Error != "" {
+		return fmt.Errorf("the collector rejected the profile: %s", response.Error)
+	}
+	return nil
+}
+
+func buffersOf(data [][]byte) []*bytes.Buffer {
+	buffers := make([]*bytes.Buffer, 0, len(data))
+	for _, d := range data {
+		buffers = append(buffers, bytes.NewBuffer(d))
+	}
+	return buffers
+}
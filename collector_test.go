@@ -0,0 +1,60 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestCollector(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-collector-test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "collector.sock")
+
+	parent := newTestProbe(newFakeClock())
+	collector, err := parent.StartCollector(socket)
+	c.Assert(err, IsNil)
+	c.Assert(os.Getenv(collectorSocketEnvVar), Equals, "")
+	c.Assert(collector.Env()[len(collector.Env())-1], Equals, collectorSocketEnvVar+"="+socket)
+	info, err := os.Stat(socket)
+	c.Assert(err, IsNil)
+	c.Assert(info.Mode().Perm(), Equals, os.FileMode(0600))
+
+	data, err := ioutil.ReadFile(filepath.Join("pprof_reader", "fixtures", "wt.pprof.gz"))
+	c.Assert(err, IsNil)
+	child := newTestProbe(newFakeClock())
+	child.currentTitle = "worker"
+	child.cpuProfileBuffers = []*bytes.Buffer{bytes.NewBuffer(data)}
+	c.Assert(child.pushToCollector(socket), IsNil)
+	c.Assert(child.cpuProfileBuffers, HasLen, 0)
+
+	subProfiles := parent.takeSubProfiles()
+	c.Assert(subProfiles, HasLen, 1)
+	c.Assert(strings.HasPrefix(subProfiles[0].Name, "worker (pid "), Equals, true)
+	c.Assert(subProfiles[0].Profile.HasData(), Equals, true)
+	c.Assert(parent.takeSubProfiles(), HasLen, 0)
+
+	// Broken payloads are rejected.
+	child.cpuProfileBuffers = []*bytes.Buffer{bytes.NewBufferString("not a pprof profile")}
+	c.Assert(child.pushToCollector(socket), ErrorMatches, "the collector rejected the profile: .*")
+
+	// The pending profiles are capped.
+	for i := 0; i < maxPendingSubProfiles; i++ {
+		c.Assert(parent.addSubProfile("worker", subProfiles[0].Profile), IsNil)
+	}
+	child.cpuProfileBuffers = []*bytes.Buffer{bytes.NewBuffer(data)}
+	c.Assert(child.pushToCollector(socket), ErrorMatches, "the collector rejected the profile: too many profiles are pending .*")
+	c.Assert(parent.takeSubProfiles(), HasLen, maxPendingSubProfiles)
+
+	c.Assert(collector.Close(), IsNil)
+	child.cpuProfileBuffers = []*bytes.Buffer{bytes.NewBuffer(data)}
+	c.Assert(child.pushToCollector(socket), NotNil)
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"net/url"
 	"os"
 	"path"
@@ -11,9 +12,12 @@ import (
 	"regexp"
 	"runtime"
 	"strconv"
+	"strings"
 	"sync"
+	"text/template"
 	"time"
 
+	"github.com/blackfireio/go-blackfire/pprof_reader"
 	"github.com/go-ini/ini"
 	"github.com/rs/zerolog"
 )
@@ -22,12 +26,17 @@ import (
 // It's always been 100hz since the beginning, so it should be safe.
 const golangDefaultCPUSampleRate = 100
 
+// This must match the initial value of runtime.MemProfileRate.
+const golangDefaultMemProfileRate = 512 * 1024
+
 type Configuration struct {
 	// The configuration path to the Blackfire CLI ini file
 	// Defaults to ~/.blackfire.ini
 	ConfigFile string
 
-	// Time before dropping an unresponsive agent connection (default 250ms)
+	// Time before dropping an unresponsive agent connection (default 250ms).
+	// The durations read from the INI file and env vars are in Go syntax
+	// (250ms, 1m30s) or a number of seconds.
 	AgentTimeout time.Duration
 
 	// The socket to use when connecting to the Blackfire agent (default depends on OS)
@@ -56,29 +65,288 @@ type Configuration struct {
 	// A zerolog Logger (default stderr)
 	Logger *zerolog.Logger
 
+	// The number of recent profiles listed on the dashboard (default 10).
+	ProfileHistorySize int
+
+	// If not empty, the recent profiles are persisted to this file, so that
+	// the dashboard still lists them after a restart.
+	ProfileHistoryFile string
+
 	// The maximum duration of a profile. A profile operation can never exceed
 	// this duration (default 10 minutes).
-	// This guards against runaway profile operations.
+	// This guards against runaway profile operations. The
+	// BLACKFIRE_MAX_PROFILE_DURATION env variable overrides it, and the
+	// max-profile-duration INI key provides it when not set.
 	MaxProfileDuration time.Duration
 
+	// The duration of the profiles started by the HTTP endpoints when the
+	// request has no duration parameter, and by EnableOnSignal when given no
+	// duration (default 30 seconds, or MaxProfileDuration if shorter). It
+	// can't exceed MaxProfileDuration.
+	DefaultProfileDuration time.Duration
+
 	// Default rate at which the CPU samples are taken. Values > 500 will likely
 	// exceed the abilities of most environments.
 	// See https://golang.org/src/runtime/pprof/pprof.go#L727
+	// The BLACKFIRE_CPU_SAMPLE_RATE env variable overrides it, and the
+	// cpu-sample-rate INI key provides it when not set.
 	DefaultCPUSampleRateHz int
 
+	// Heap sampling rate (runtime.MemProfileRate) to use while profiling. The
+	// previous rate is restored when profiling is disabled. The default rate
+	// samples one allocation every 512KiB, which misses small allocations.
+	//
+	// Heap samples are scaled using the rate in effect when the heap profile
+	// is written, so objects allocated outside of profiling windows get
+	// inaccurate estimates. For this reason, the rate is left untouched if
+	// the application already changed it from the Go default.
+	MemProfileRate int
+
 	// If not empty, dump the original pprof profiles to this directory whenever
-	// a profile ends.
+	// a profile ends. The BLACKFIRE_PPROF_DUMP_DIR env variable overrides it,
+	// and the pprof-dump-dir INI key provides it when not set.
 	PProfDumpDir string
 
+	// Name the C frames of cgo programs in profiles, using addr2line when it
+	// is available. They are otherwise dropped from the call stacks.
+	SymbolizeCFrames bool
+
+	// Append the enclosing function and definition file:line to closure
+	// names (pkg.Func.func1), which are otherwise hard to locate.
+	AnnotateClosures bool
+
+	// Merge the instantiations of each generic function into a single node.
+	CollapseGenerics bool
+
+	// Append the source file and line to function names: either the line
+	// where the function is defined, or the line being executed (which
+	// splits functions into one node per line).
+	FileLine pprof_reader.FileLineMode
+
+	// How heap memory is attributed to call stacks (default: distributed
+	// across the CPU samples of the allocating functions).
+	MemoryAttribution pprof_reader.MemoryAttribution
+
+	// How recursive call stacks are represented: with @1, @2... copies of
+	// the recursive functions (the default), or collapsed into a single node
+	// per function.
+	Recursion pprof_reader.RecursionMode
+
+	// How inlined functions are represented: as frames of their own (the
+	// default), collapsed into the function they are inlined into, or only
+	// keeping the innermost one, whose graph is closer to the call structure
+	// of the source code in inlining-heavy code.
+	Inlining pprof_reader.InliningPolicy
+
+	// pprof label keys (set via pprof.Do or pprof.Labels) used to segment the
+	// profile: samples carrying any of these labels are grouped under a
+	// sub-graph named after their label values.
+	SegmentByLabels []string
+
+	// Only keep the samples taken in goroutines tagged via ProfileGoroutine()
+	// (or their children) in the uploaded profile.
+	OnlyProfiledGoroutines bool
+
+	// What to do with the samples taken in the goroutines of the probe itself
+	// (serialization, upload), which pollute continuous profiles: keep them
+	// (the default), prune them, or group them under a "blackfire-probe"
+	// node.
+	ProbeFrames ProbeFramesPolicy
+
+	// Aggregate the CPU time of each profile by goroutine creation site (the
+	// function the goroutines were started with), reported on the dashboard
+	// and by Probe.GoroutineCPU, to tell which background loop is burning CPU
+	// at a glance.
+	ReportGoroutineCPU bool
+
+	// If positive, truncate the call stacks deeper than this number of
+	// frames, such as the ones of deep recursions, which blow up the size of
+	// the profiles. The deepest frames are replaced with a "…truncated" node
+	// (see ProbeStats.TruncatedStacks).
+	MaxStackDepth int
+
+	// The packages whose goroutines are the probe's own, for ProbeFrames
+	// (default: the go-blackfire packages).
+	ProbePackages []string
+
+	// Account the OS threads created while profiling to the call stacks
+	// creating them, in a threads_created cost dimension (from the
+	// threadcreate pprof profile), for cgo-heavy programs whose thread count
+	// explodes. The thread counts are always reported in a threads header.
+	ProfileThreadCreation bool
+
+	// Add a probe-overhead header to the profiles, reporting the time the
+	// probe spent recording them (see Stats).
+	ReportOverhead bool
+
+	// If not empty, write profiles in Blackfire format to this file instead
+	// of uploading them to the agent. No agent or credentials are needed.
+	OutputFile string
+
+	// If set, send profiles to this exporter instead of uploading them to the
+	// agent. No agent or credentials are needed. OutputFile takes precedence.
+	Exporter Exporter
+
+	// If not empty, push the profiles to the collector of the parent process
+	// listening on this unix socket (see Probe.StartCollector), which merges
+	// them into its own, instead of uploading them to the agent. No agent or
+	// credentials are needed. OutputFile and Exporter take precedence. The
+	// BLACKFIRE_COLLECTOR_SOCKET env variable, passed by the parent process
+	// (see Collector.Env), overrides it.
+	CollectorSocket string
+
+	// The encoder writing the profiles uploaded to the agent or written to
+	// OutputFile or to the output directory of a sink. Defaults to
+	// BFEncoder, which writes them in the Blackfire format expected by the
+	// agent; other encoders are meant for experiments and debugging.
+	Encoder ProfileEncoder
+
+	// Additional destinations every profile is delivered to, after the agent
+	// (or OutputFile or Exporter). Deliveries are tracked per sink in the
+	// stats, and a failing sink doesn't fail the upload.
+	Sinks []Sink
+
+	// If not empty, a JSON payload with the UUID, URL and title of each
+	// profile uploaded to the agent is posted to this URL, to deliver the
+	// profile links to a chat or a pull request.
+	WebhookURL string
+
+	// Post to WebhookURL once the graph of the profile is finalized rather
+	// than right after the upload, so that the payload includes the envelope
+	// of the profile. The client ID and token are needed.
+	WebhookWaitForGraph bool
+
+	// If not empty, profiles the agent failed to receive are written to this
+	// directory in Blackfire format, so that they are not lost.
+	SpoolDir string
+
+	// The request headers the middleware copies to the tags of the profiles,
+	// so that a profile can be traced back to its request (default
+	// X-Request-ID and traceparent). Set to an empty slice to disable.
+	MiddlewareTagHeaders []string
+
+	// If set, returns the context of the profiles, sent in their Context
+	// header instead of the command line arguments. Batch jobs can report
+	// their job ID or queue this way, and the arguments can be left out.
+	ContextProvider func() url.Values
+
+	// Override the root node of the graph (graph-root-id header) and the
+	// probed-language and probed-runtime headers of the profiles, which
+	// default to "go", "go" and the Go version. Mixed-runtime programs (cgo
+	// wrappers, WebAssembly hosts) can describe themselves this way, and a
+	// distinct root helps comparing experiments.
+	GraphRoot      string
+	ProbedLanguage string
+	ProbedRuntime  string
+
+	// Regular expressions matching the command line arguments which are
+	// masked in the Context header of the profiles: the values of the flags
+	// whose name matches (--db-password=value or --db-password value), and
+	// the other arguments which match. Defaults to the flags named after
+	// passwords, secrets, tokens and keys. Set to an empty slice to disable.
+	SensitiveArgs []string
+
+	// If set, a text/template the titles of the profiles are made of when
+	// they end, such as "{{.Hostname}}-{{.Route}}-{{.Time}}" (see
+	// TitleVariables). MiddlewareTitleTemplate overrides it for the profiles
+	// of the middleware.
+	TitleTemplate           string
+	MiddlewareTitleTemplate string
+
+	// The text/template making the title of the profiles which were given
+	// none (see SetCurrentTitle), whose Time is when the profile started.
+	// Defaults to the executable name, the hostname and the time.
+	DefaultTitleTemplate string
+
+	// If set, the middleware records these attributes of the profiled
+	// requests in their profiles. Recording the status code wraps the
+	// http.ResponseWriter, hiding its optional interfaces (http.Flusher...).
+	MiddlewareCapture *RequestCapture
+
+	// Tag the profiles of the requests whose handler panics with
+	// panicked=true and the panic message, like CapturePanic does.
+	MiddlewareCapturePanics bool
+
+	// Maximum number of profiles started per hour (default 0, unlimited),
+	// so that a misbehaving automation can't flood the agent and the
+	// Blackfire API. Profiles beyond the limit are not started, and a
+	// RateLimitError is returned. The profiles started by the signal
+	// triggers (EnableOnSignal, ToggleOnSignal), by the HTTP endpoints and
+	// middleware, and automatically (AutoEnable) can be limited further.
+	MaxProfilesPerHour       int
+	MaxSignalProfilesPerHour int
+	MaxHTTPProfilesPerHour   int
+	MaxAutoProfilesPerHour   int
+
+	// If set, called in its own goroutine when the probe stops profiling on
+	// its own, because the profile duration or MaxProfileDuration elapsed,
+	// with the reason (StopReasonDuration or StopReasonMaxDuration). The
+	// samples are kept for the next End.
+	OnAutoStop func(reason string)
+
+	// If set, called as the profiles are uploaded to the agent, each time a
+	// chunk of the payload is written. It is called from the uploading
+	// goroutine, and must return quickly.
+	OnUploadProgress func(progress UploadProgress)
+
+	// Protect the HTTP endpoints of NewServeMux (except /status) with a
+	// shared token, to be sent in an "Authorization: Bearer" header.
+	HTTPAuthToken string
+
+	// Protect the HTTP endpoints of NewServeMux (except /status) with basic
+	// authentication. Requests carrying either the token or these
+	// credentials are allowed.
+	HTTPUsername string
+	HTTPPassword string
+
+	// If set, wraps the HTTP endpoints of NewServeMux (except /status), to
+	// plug in the application's own authentication.
+	HTTPMiddleware func(http.Handler) http.Handler
+
+	// Minimum interval between two requests to the enable and profile HTTP
+	// endpoints. Requests coming faster are rejected with a 429 status code.
+	// Disabling or ending a profile is never rate limited.
+	HTTPRateLimit time.Duration
+
+	// The .blackfire.yml file to send along with the profiles. By default, it
+	// is looked up in the working directory, then in its parents up to the
+	// root of the Go module.
+	BlackfireYamlPath string
+
+	// Start profiling as soon as the probe is configured, for
+	// AutoEnableDuration (default 30 seconds), and upload the profile when it
+	// completes. Processes exiting earlier should call End() before exiting.
+	// The BLACKFIRE_AUTO_ENABLE env variable (set to 1, true, or a duration)
+	// enables it, and makes the global probe start profiling at package init.
+	AutoEnable         bool
+	AutoEnableDuration time.Duration
+
+	// Turns every API call into a no-op: no configuration is loaded, and no
+	// agent connection nor goroutine is ever started. The BLACKFIRE_DISABLED
+	// env variable (set to 1 or true) overrides this setting.
+	Disabled bool
+
 	// Disables the profiler unless the BLACKFIRE_QUERY env variable is set.
 	// When the profiler is disabled, all API calls become no-ops.
 	onDemandOnly bool
 
-	loader sync.Once
-	err    error
+	loader        sync.Once
+	err           error
+	warnings      ConfigurationErrors
+	sensitiveArgs []*regexp.Regexp
+	// The source of each setting, by name.
+	sources map[string]string
+	// The parsed TitleTemplate, MiddlewareTitleTemplate and
+	// DefaultTitleTemplate.
+	titleTemplate           *template.Template
+	middlewareTitleTemplate *template.Template
+	defaultTitleTemplate    *template.Template
 }
 
 func (c *Configuration) canProfile() bool {
+	if c.Disabled {
+		return false
+	}
 	if c.BlackfireQuery == "" && c.onDemandOnly {
 		return false
 	}
@@ -159,12 +427,36 @@ func (c *Configuration) configureFromDefaults() {
 	if c.AgentTimeout < 1 {
 		c.AgentTimeout = time.Millisecond * 250
 	}
+	if c.SensitiveArgs == nil {
+		c.SensitiveArgs = []string{`(?i)^--?[\w-]*(password|passwd|secret|token|api[-_]?key)$`}
+	}
+	if c.MiddlewareTagHeaders == nil {
+		c.MiddlewareTagHeaders = []string{"X-Request-ID", "traceparent"}
+	}
+	if c.ProfileHistorySize < 1 {
+		c.ProfileHistorySize = 10
+	}
 	if c.MaxProfileDuration < 1 {
 		c.MaxProfileDuration = time.Minute * 10
 	}
+	if c.DefaultProfileDuration < 1 {
+		c.DefaultProfileDuration = time.Second * 30
+		if c.DefaultProfileDuration > c.MaxProfileDuration {
+			c.DefaultProfileDuration = c.MaxProfileDuration
+		}
+	}
+	if c.AutoEnableDuration < 1 {
+		c.AutoEnableDuration = time.Second * 30
+	}
 	if c.DefaultCPUSampleRateHz == 0 {
 		c.DefaultCPUSampleRateHz = golangDefaultCPUSampleRate
 	}
+	if c.DefaultTitleTemplate == "" {
+		c.DefaultTitleTemplate = `{{.Executable}}@{{.Hostname}} {{.Time.Format "2006-01-02 15:04:05"}}`
+	}
+	if c.Encoder == nil {
+		c.Encoder = BFEncoder{}
+	}
 }
 
 func (c *Configuration) configureFromIniFile() {
@@ -198,10 +490,28 @@ func (c *Configuration) configureFromIniFile() {
 	}
 
 	if section.HasKey("timeout") && c.AgentTimeout == 0 {
-		timeout := c.getStringFromIniSection(section, "timeout")
-		var err error
-		if c.AgentTimeout, err = parseSeconds(timeout); err != nil {
-			c.Logger.Error().Msgf("Blackfire: Unable to set from ini file %s, timeout %s: %v", path, timeout, err)
+		c.durationFromIniSection(path, section, "timeout", &c.AgentTimeout)
+	}
+
+	if section.HasKey("max-profile-duration") && c.MaxProfileDuration == 0 {
+		c.durationFromIniSection(path, section, "max-profile-duration", &c.MaxProfileDuration)
+	}
+
+	if section.HasKey("cpu-sample-rate") && c.DefaultCPUSampleRateHz == 0 {
+		rate := c.getStringFromIniSection(section, "cpu-sample-rate")
+		if hz, err := parseSampleRate(rate); err == nil {
+			c.DefaultCPUSampleRateHz = hz
+		} else {
+			c.Logger.Error().Msgf("Blackfire: Unable to set from ini file %s, cpu-sample-rate %s: %v", path, rate, err)
+		}
+	}
+
+	if section.HasKey("pprof-dump-dir") && c.PProfDumpDir == "" {
+		dir := c.getStringFromIniSection(section, "pprof-dump-dir")
+		if absPath, err := filepath.Abs(dir); err == nil {
+			c.PProfDumpDir = absPath
+		} else {
+			c.Logger.Error().Msgf("Blackfire: Unable to set from ini file %s, pprof-dump-dir %s: %v", path, dir, err)
 		}
 	}
 }
@@ -211,6 +521,26 @@ func (c *Configuration) configureFromEnv() {
 		c.AgentSocket = v
 	}
 
+	if v := c.readEnvVar("BLACKFIRE_PROFILE_HISTORY_FILE"); v != "" {
+		c.ProfileHistoryFile = v
+	}
+
+	if v := c.readEnvVar("BLACKFIRE_WEBHOOK_URL"); v != "" {
+		c.WebhookURL = v
+	}
+
+	if v := c.readEnvVar("BLACKFIRE_SPOOL_DIR"); v != "" {
+		c.SpoolDir = v
+	}
+
+	if v := c.readEnvVar("BLACKFIRE_MAX_PROFILES_PER_HOUR"); v != "" {
+		if limit, err := strconv.Atoi(v); err == nil {
+			c.MaxProfilesPerHour = limit
+		} else {
+			c.Logger.Error().Msgf("Blackfire: Unable to set from env var BLACKFIRE_MAX_PROFILES_PER_HOUR %s: %v", v, err)
+		}
+	}
+
 	if v := c.readEnvVar("BLACKFIRE_QUERY"); v != "" {
 		c.BlackfireQuery = v
 		os.Unsetenv("BLACKFIRE_QUERY")
@@ -238,6 +568,36 @@ func (c *Configuration) configureFromEnv() {
 		}
 	}
 
+	if v := c.readEnvVar("BLACKFIRE_HTTP_TOKEN"); v != "" {
+		c.HTTPAuthToken = v
+	}
+
+	if v := c.readEnvVar("BLACKFIRE_AUTO_ENABLE"); v != "" {
+		if enabled, err := strconv.ParseBool(v); err == nil {
+			c.AutoEnable = enabled
+		} else if duration, err := parseDuration(v); err == nil && duration > 0 {
+			c.AutoEnable = true
+			c.AutoEnableDuration = duration
+		} else {
+			c.Logger.Error().Msgf("Blackfire: Unable to set from env var BLACKFIRE_AUTO_ENABLE %s: expecting a boolean or a duration", v)
+		}
+	}
+
+	c.durationFromEnv("BLACKFIRE_DEFAULT_PROFILE_DURATION", &c.DefaultProfileDuration)
+	c.durationFromEnv("BLACKFIRE_MAX_PROFILE_DURATION", &c.MaxProfileDuration)
+
+	if v := c.readEnvVar("BLACKFIRE_CPU_SAMPLE_RATE"); v != "" {
+		if hz, err := parseSampleRate(v); err == nil {
+			c.DefaultCPUSampleRateHz = hz
+		} else {
+			c.Logger.Error().Msgf("Blackfire: Unable to set from env var BLACKFIRE_CPU_SAMPLE_RATE %s: %v", v, err)
+		}
+	}
+
+	if v := c.readEnvVar(collectorSocketEnvVar); v != "" {
+		c.CollectorSocket = v
+	}
+
 	if v := c.readEnvVar("BLACKFIRE_PPROF_DUMP_DIR"); v != "" {
 		absPath, err := filepath.Abs(v)
 		if err != nil {
@@ -250,58 +610,278 @@ func (c *Configuration) configureFromEnv() {
 
 func (c *Configuration) load() error {
 	c.loader.Do(func() {
+		if isDisabledFromEnv() {
+			c.Disabled = true
+		}
+		if c.Disabled {
+			if c.Logger == nil {
+				logger := zerolog.Nop()
+				c.Logger = &logger
+			}
+			return
+		}
 		if c.Logger == nil {
-			logger := NewLoggerFromEnvVars()
+			logger, err := newLoggerFromEnvVars()
 			c.Logger = &logger
+			if err != nil {
+				c.warnings = append(c.warnings, &ConfigurationError{
+					Setting: "Logger",
+					Kind:    ErrUnwritableLogFile,
+					Message: err.Error(),
+				})
+			}
 		}
+		tracker := newSourceTracker(c)
 		c.configureFromEnv()
+		tracker.record(ConfigSourceEnv)
 		// Used for test purposes
 		if "1" != os.Getenv("BLACKFIRE_INTERNAL_IGNORE_INI") {
 			c.configureFromIniFile()
+			tracker.record(ConfigSourceINI)
 		}
 		c.configureFromDefaults()
+		tracker.record(ConfigSourceDefault)
+		c.sources = tracker.sources
 		if c.err = c.validate(); c.err != nil {
 			c.Logger.Warn().Err(c.err).Msg("Blackfire: Bad configuration")
 		}
+		if event := c.Logger.Debug(); event.Enabled() {
+			event.Msgf("Blackfire: Effective configuration: %s", c.effectiveSummary())
+		}
 	})
 	return c.err
 }
 
+// Warnings returns the problems of the configuration which don't prevent it
+// from loading, such as a log file which could not be opened, the logs going
+// to stderr instead. The configuration is loaded if it was not already.
+func (c *Configuration) Warnings() ConfigurationErrors {
+	c.load()
+	return c.warnings
+}
+
+// isDisabledFromEnv checks the BLACKFIRE_DISABLED kill switch.
+func isDisabledFromEnv() bool {
+	disabled, _ := strconv.ParseBool(os.Getenv("BLACKFIRE_DISABLED"))
+	return disabled
+}
+
+// isAutoEnabledFromEnv checks whether BLACKFIRE_AUTO_ENABLE requests
+// profiling at startup.
+func isAutoEnabledFromEnv() bool {
+	v := os.Getenv("BLACKFIRE_AUTO_ENABLE")
+	if enabled, err := strconv.ParseBool(v); err == nil {
+		return enabled
+	}
+	duration, err := parseDuration(v)
+	return err == nil && duration > 0
+}
+
+// The kinds of problems a ConfigurationError reports.
+var (
+	ErrMissingCredentials = errors.New("missing credentials")
+	ErrInvalidEndpoint    = errors.New("invalid endpoint")
+	ErrInvalidAgentSocket = errors.New("invalid agent socket")
+	ErrUnwritableDumpDir  = errors.New("unwritable pprof dump dir")
+	ErrUnwritableLogFile  = errors.New("unwritable log file")
+	ErrInvalidSetting     = errors.New("invalid setting")
+)
+
+// ConfigurationError is a problem with a setting of the configuration.
+type ConfigurationError struct {
+	// The setting at fault, such as ClientID or PProfDumpDir.
+	Setting string
+	// The kind of problem (ErrMissingCredentials, ErrInvalidEndpoint,
+	// ErrInvalidAgentSocket, ErrUnwritableDumpDir, ErrUnwritableLogFile or
+	// ErrInvalidSetting).
+	Kind    error
+	Message string
+}
+
+func (e *ConfigurationError) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the kind of problem.
+func (e *ConfigurationError) Unwrap() error {
+	return e.Kind
+}
+
+// ConfigurationErrors lists all the problems of a configuration, as returned
+// when it fails to load.
+type ConfigurationErrors []*ConfigurationError
+
+func (e ConfigurationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap returns the problems, so that errors.Is and errors.As match the
+// kind of any of them.
+func (e ConfigurationErrors) Unwrap() []error {
+	errs := make([]error, len(e))
+	for i, err := range e {
+		errs[i] = err
+	}
+	return errs
+}
+
+// agentSocketPattern splits an agent socket into its network and address.
+var agentSocketPattern = regexp.MustCompile(`^([^:]+)://(.*)`)
+
+// agentSocketNetworks are the networks the agent can be reached on.
+var agentSocketNetworks = map[string]bool{
+	"tcp":  true,
+	"tcp4": true,
+	"tcp6": true,
+	"unix": true,
+}
+
+// ProbeFramesPolicy selects what happens to the samples taken in the
+// goroutines of the probe itself.
+type ProbeFramesPolicy int
+
+const (
+	// The samples are left in the profile.
+	ProbeFramesKeep ProbeFramesPolicy = iota
+	// The samples are removed from the profile.
+	ProbeFramesPrune
+	// The samples are moved under a "blackfire-probe" node.
+	ProbeFramesGroup
+)
+
+// collectorSocketEnvVar tells the child processes where to push their
+// profiles (see Configuration.CollectorSocket).
+const collectorSocketEnvVar = "BLACKFIRE_COLLECTOR_SOCKET"
+
+// defaultProbePackages are the packages of the probe, whose goroutines are
+// subject to ProbeFrames.
+var defaultProbePackages = []string{"github.com/blackfireio/go-blackfire"}
+
+// validate checks the whole configuration, and returns a ConfigurationErrors
+// listing all its problems, if any.
 func (c *Configuration) validate() error {
-	if c.BlackfireQuery == "" {
+	var errs ConfigurationErrors
+	report := func(setting string, kind error, format string, args ...interface{}) {
+		errs = append(errs, &ConfigurationError{
+			Setting: setting,
+			Kind:    kind,
+			Message: fmt.Sprintf(format, args...),
+		})
+	}
+
+	if c.BlackfireQuery == "" && c.OutputFile == "" && c.Exporter == nil && c.CollectorSocket == "" {
 		if c.ClientID == "" || c.ClientToken == "" {
-			return errors.New("either BLACKFIRE_QUERY must be set, or client ID and client token must be set")
+			report("ClientID", ErrMissingCredentials, "either BLACKFIRE_QUERY must be set, or client ID and client token must be set")
 		}
 	}
 
-	if c.PProfDumpDir != "" {
-		info, err := os.Stat(c.PProfDumpDir)
-		if err != nil {
-			return fmt.Errorf("Cannot dump pprof files to %v: %v", c.PProfDumpDir, err)
-		}
-		if !info.IsDir() {
-			return fmt.Errorf("Cannot dump pprof files to %v: not a directory", c.PProfDumpDir)
+	if u := c.HTTPEndpoint; u != nil && ((u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+		report("HTTPEndpoint", ErrInvalidEndpoint, "Invalid endpoint %s: expecting an http or https URL", u)
+	}
+
+	// The agent socket only matters when the profiles are sent to the agent.
+	toAgent := c.OutputFile == "" && c.Exporter == nil && c.CollectorSocket == ""
+	if matches := agentSocketPattern.FindStringSubmatch(c.AgentSocket); toAgent && (matches == nil || !agentSocketNetworks[matches[1]]) {
+		report("AgentSocket", ErrInvalidAgentSocket, "Invalid agent socket %s: expecting tcp://host:port or unix://path", c.AgentSocket)
+	}
+
+	for i, sink := range c.Sinks {
+		if err := sink.validate(); err != nil {
+			report("Sinks", ErrInvalidSetting, "Invalid sink %d: %v", i, err)
 		}
+	}
 
-		// There's no 100% portable way to check for writability, so we just create
-		// a temp zero-byte file and see if it succeeds.
-		exePath, err := os.Executable()
+	c.sensitiveArgs = nil
+	for _, pattern := range c.SensitiveArgs {
+		re, err := regexp.Compile(pattern)
 		if err != nil {
-			exePath = "go-unknown"
-		} else {
-			exePath = path.Base(exePath)
+			report("SensitiveArgs", ErrInvalidSetting, "Invalid sensitive argument pattern %s: %v", pattern, err)
+			continue
 		}
-		testPath := path.Join(c.PProfDumpDir, exePath+"-writability-test")
-		// Delete it before starting, and make sure it gets deleted after
-		os.Remove(testPath)
-		defer os.Remove(testPath)
-		if err = ioutil.WriteFile(testPath, []byte{}, 0644); err != nil {
-			return fmt.Errorf("Cannot dump pprof files to %v: directory does not seem writable: %v", c.PProfDumpDir, err)
+		c.sensitiveArgs = append(c.sensitiveArgs, re)
+	}
+
+	var err error
+	if c.titleTemplate, err = parseTitleTemplate(c.TitleTemplate); err != nil {
+		report("TitleTemplate", ErrInvalidSetting, "%v", err)
+	}
+	if c.middlewareTitleTemplate, err = parseTitleTemplate(c.MiddlewareTitleTemplate); err != nil {
+		report("MiddlewareTitleTemplate", ErrInvalidSetting, "%v", err)
+	}
+	if c.defaultTitleTemplate, err = parseTitleTemplate(c.DefaultTitleTemplate); err != nil {
+		report("DefaultTitleTemplate", ErrInvalidSetting, "%v", err)
+	}
+
+	if c.DefaultCPUSampleRateHz < 0 {
+		report("DefaultCPUSampleRateHz", ErrInvalidSetting, "Invalid CPU sample rate %d: it must be positive", c.DefaultCPUSampleRateHz)
+	}
+
+	if c.DefaultProfileDuration > c.MaxProfileDuration {
+		report("DefaultProfileDuration", ErrInvalidSetting, "The default profile duration %v exceeds the maximum profile duration %v", c.DefaultProfileDuration, c.MaxProfileDuration)
+	}
+
+	if c.WebhookURL != "" {
+		if u, err := url.Parse(c.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+			report("WebhookURL", ErrInvalidSetting, "Invalid webhook URL %s", c.WebhookURL)
 		}
 	}
+
+	if c.PProfDumpDir != "" {
+		if err := checkDumpDir(c.PProfDumpDir); err != nil {
+			report("PProfDumpDir", ErrUnwritableDumpDir, "%v", err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// checkDumpDir checks that the pprof profiles can be dumped to a directory.
+func checkDumpDir(dir string) error {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return fmt.Errorf("Cannot dump pprof files to %v: %v", dir, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("Cannot dump pprof files to %v: not a directory", dir)
+	}
+
+	// There's no 100% portable way to check for writability, so we just create
+	// a temp zero-byte file and see if it succeeds.
+	exePath, err := os.Executable()
+	if err != nil {
+		exePath = "go-unknown"
+	} else {
+		exePath = path.Base(exePath)
+	}
+	testPath := path.Join(dir, exePath+"-writability-test")
+	// Delete it before starting, and make sure it gets deleted after
+	os.Remove(testPath)
+	defer os.Remove(testPath)
+	if err = ioutil.WriteFile(testPath, []byte{}, 0644); err != nil {
+		return fmt.Errorf("Cannot dump pprof files to %v: directory does not seem writable: %v", dir, err)
+	}
 	return nil
 }
 
+// parseTitleTemplate parses a title template, if any.
+func parseTitleTemplate(text string) (*template.Template, error) {
+	if text == "" {
+		return nil, nil
+	}
+	t, err := template.New("title").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid title template %s: %v", text, err)
+	}
+	return t, nil
+}
+
 func (c *Configuration) readEnvVar(name string) string {
 	if v := os.Getenv(name); v != "" {
 		c.Logger.Debug().Msgf("Blackfire: Read ENV var %s: %s", name, v)
@@ -318,17 +898,59 @@ func (c *Configuration) getStringFromIniSection(section *ini.Section, key string
 	return ""
 }
 
-func parseSeconds(value string) (time.Duration, error) {
-	re := regexp.MustCompile(`([0-9.]+)`)
-	found := re.FindStringSubmatch(value)
+// durationFromEnv sets a duration from an env var, if it is set to a valid
+// duration (see parseDuration).
+func (c *Configuration) durationFromEnv(name string, duration *time.Duration) {
+	v := c.readEnvVar(name)
+	if v == "" {
+		return
+	}
+	parsed, err := parseDuration(v)
+	if err != nil {
+		c.Logger.Error().Msgf("Blackfire: Unable to set from env var %s %s: %v", name, v, err)
+		return
+	}
+	*duration = parsed
+}
 
-	if len(found) == 0 {
-		return 0, fmt.Errorf("%s: No seconds value found", value)
+// durationFromIniSection sets a duration from an INI key, if it is set to a
+// valid duration (see parseDuration).
+func (c *Configuration) durationFromIniSection(path string, section *ini.Section, key string, duration *time.Duration) {
+	v := c.getStringFromIniSection(section, key)
+	if v == "" {
+		return
 	}
+	parsed, err := parseDuration(v)
+	if err != nil {
+		c.Logger.Error().Msgf("Blackfire: Unable to set from ini file %s, %s %s: %v", path, key, v, err)
+		return
+	}
+	*duration = parsed
+}
+
+// parseSampleRate parses a CPU sample rate, in Hz.
+func parseSampleRate(value string) (int, error) {
+	hz, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || hz <= 0 {
+		return 0, fmt.Errorf("%s: expecting a positive number of Hz", value)
+	}
+	return hz, nil
+}
 
-	seconds, err := strconv.ParseFloat(found[1], 64)
+// parseDuration parses a duration of the configuration, either in Go syntax
+// ("250ms", "1m30s") or as a number of seconds ("1", "0.25").
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	duration, err := time.ParseDuration(value)
 	if err != nil {
-		return 0, err
+		seconds, parseErr := strconv.ParseFloat(value, 64)
+		if parseErr != nil {
+			return 0, fmt.Errorf("%s: expecting a duration (such as 250ms or 1m30s) or a number of seconds", value)
+		}
+		duration = time.Duration(float64(time.Second) * seconds)
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("%s: the duration must not be negative", value)
 	}
-	return time.Duration(float64(time.Second) * seconds), nil
+	return duration, nil
 }
@@ -0,0 +1,191 @@
+package blackfire
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strings"
+)
+
+// The sources a setting of the configuration can come from: the settings of
+// the Configuration struct (the code) are overridden by the env vars, then the
+// INI file and the defaults provide the settings which are still unset.
+const (
+	ConfigSourceDefault = "default"
+	ConfigSourceCode    = "code"
+	ConfigSourceEnv     = "env"
+	ConfigSourceINI     = "ini"
+)
+
+// ConfigurationSetting is a setting of the configuration, as resolved once
+// loaded (see Configuration.Effective).
+type ConfigurationSetting struct {
+	Name string
+	// The value of the setting, with the secrets redacted. Hooks and
+	// interfaces are reported by type.
+	Value string
+	// Where the value comes from (ConfigSourceDefault, ConfigSourceCode,
+	// ConfigSourceEnv or ConfigSourceINI).
+	Source string
+}
+
+// redactedSettings are the settings whose values are secrets.
+var redactedSettings = map[string]bool{
+	"BlackfireQuery": true,
+	"ClientToken":    true,
+	"ServerToken":    true,
+	"HTTPAuthToken":  true,
+	"HTTPPassword":   true,
+	"WebhookURL":     true,
+}
+
+// Effective returns the settings of the configuration as resolved from the
+// code, the env vars, the INI file and the defaults, along with the source of
+// each of them. The configuration is loaded if it was not already.
+func (c *Configuration) Effective() []ConfigurationSetting {
+	c.load()
+	return c.effective()
+}
+
+func (c *Configuration) effective() []ConfigurationSetting {
+	values := c.settingValues()
+	settings := make([]ConfigurationSetting, 0, len(values))
+	for _, value := range values {
+		source := c.sources[value.name]
+		if source == "" {
+			source = ConfigSourceDefault
+			if !value.zero {
+				source = ConfigSourceCode
+			}
+		}
+		formatted := withoutUserinfo(value.value)
+		if redactedSettings[value.name] && !value.zero {
+			formatted = "<redacted>"
+		}
+		settings = append(settings, ConfigurationSetting{
+			Name:   value.name,
+			Value:  formatted,
+			Source: source,
+		})
+	}
+	return settings
+}
+
+// effectiveSummary formats the effective configuration for the logs.
+func (c *Configuration) effectiveSummary() string {
+	var summary strings.Builder
+	for i, setting := range c.effective() {
+		if i > 0 {
+			summary.WriteString(", ")
+		}
+		fmt.Fprintf(&summary, "%s=%q (%s)", setting.Name, setting.Value, setting.Source)
+	}
+	return summary.String()
+}
+
+// withoutUserinfo removes the credentials of a value which is a URL.
+func withoutUserinfo(value string) string {
+	if u, err := url.Parse(value); err == nil && u.User != nil {
+		return redactedURL(u)
+	}
+	return value
+}
+
+// redactedURL formats a URL without its credentials, for the logs.
+func redactedURL(u *url.URL) string {
+	if u.User == nil {
+		return u.String()
+	}
+	redacted := *u
+	redacted.User = nil
+	return redacted.String()
+}
+
+type settingValue struct {
+	name  string
+	value string
+	zero  bool
+}
+
+// settingValues returns the formatted values of the exported settings, but
+// the logger.
+func (c *Configuration) settingValues() []settingValue {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+	values := make([]settingValue, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" || field.Name == "Logger" {
+			continue
+		}
+		value := formatSetting(v.Field(i))
+		values = append(values, settingValue{
+			name:  field.Name,
+			value: value,
+			zero:  value == formatSetting(reflect.Zero(field.Type)),
+		})
+	}
+	return values
+}
+
+func formatSetting(v reflect.Value) string {
+	switch v.Kind() {
+	case reflect.Func:
+		if v.IsNil() {
+			return ""
+		}
+		return "set"
+	case reflect.Interface:
+		if v.IsNil() {
+			return ""
+		}
+		return fmt.Sprintf("%T", v.Interface())
+	case reflect.Ptr:
+		if v.IsNil() {
+			return ""
+		}
+		if u, ok := v.Interface().(*url.URL); ok {
+			return u.String()
+		}
+		return fmt.Sprintf("%+v", v.Elem().Interface())
+	case reflect.Struct:
+		return fmt.Sprintf("%+v", v.Interface())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+// sourceTracker records the source of the settings as the configuration is
+// loaded, by comparing their values after each step.
+type sourceTracker struct {
+	configuration *Configuration
+	values        map[string]string
+	sources       map[string]string
+}
+
+// newSourceTracker starts tracking the sources of the settings, the ones
+// already set coming from the code.
+func newSourceTracker(c *Configuration) *sourceTracker {
+	tracker := &sourceTracker{
+		configuration: c,
+		values:        make(map[string]string),
+		sources:       make(map[string]string),
+	}
+	for _, value := range c.settingValues() {
+		tracker.values[value.name] = value.value
+		if !value.zero {
+			tracker.sources[value.name] = ConfigSourceCode
+		}
+	}
+	return tracker
+}
+
+// record attributes the settings which changed since the previous step to
+// the source.
+func (t *sourceTracker) record(source string) {
+	for _, value := range t.configuration.settingValues() {
+		if t.values[value.name] != value.value {
+			t.values[value.name] = value.value
+			t.sources[value.name] = source
+		}
+	}
+}
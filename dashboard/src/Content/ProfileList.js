@@ -11,17 +11,26 @@ function upperCaseFirst(word) {
     return `${word[0].toUpperCase()}${word.substr(1)}`;
 }
 
+function formatMicroseconds(microseconds) {
+    if (microseconds >= 1000000) {
+        return `${(microseconds / 1000000).toFixed(2)} s`;
+    }
+
+    return `${(microseconds / 1000).toFixed(1)} ms`;
+}
+
 function ProfileList({ profiles }) {
     return (
         <div>
             <h2>{'Profiles:'}</h2>
             {profiles.map((profile) => (
-                <div key={profile}>
+                <div key={profile.UUID}>
                     <Timeago date={profile.created_at} />
                     {` - ${upperCaseFirst(profile.status)} - `}
                     <a href={profile.url} rel="noopener noreferrer" target="_blank">
                         {profile.name === "" ? <i>{'Untitled'}</i> : profile.name}
                     </a>
+                    {profile.envelope && profile.envelope.cpu > 0 ? ` - CPU ${formatMicroseconds(profile.envelope.cpu)}` : null}
                 </div>
             ))}
             {profiles.length === 0 ? <i>{'No profiles yet'}</i> : null}
@@ -31,7 +40,12 @@ function ProfileList({ profiles }) {
 
 ProfileList.propTypes = {
     profiles: PropTypes.arrayOf(PropTypes.shape({
+        UUID: PropTypes.string,
         name: PropTypes.string,
+        url: PropTypes.string,
+        envelope: PropTypes.shape({
+            cpu: PropTypes.number,
+        }),
     })).isRequired,
 };
 
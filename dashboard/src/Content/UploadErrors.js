@@ -0,0 +1,33 @@
+import React from 'react';
+import PropTypes from 'prop-types';
+import { connect } from 'react-redux';
+import Timeago from '../Timeago';
+
+function UploadErrors({ upload_errors }) {
+    if (upload_errors.length === 0) {
+        return null;
+    }
+
+    return (
+        <div>
+            <h2>{'Upload errors:'}</h2>
+            {upload_errors.map((error) => (
+                <div key={`${error.time}-${error.message}`} className="upload-error">
+                    <Timeago date={error.time} />
+                    {` - ${error.message}`}
+                </div>
+            ))}
+        </div>
+    );
+}
+
+UploadErrors.propTypes = {
+    upload_errors: PropTypes.arrayOf(PropTypes.shape({
+        message: PropTypes.string.isRequired,
+        time: PropTypes.string.isRequired,
+    })).isRequired,
+};
+
+export default connect((state) => ({
+    upload_errors: state.DashboardReducer.get('upload_errors'),
+}))(UploadErrors);
@@ -4,6 +4,7 @@ import PropTypes from 'prop-types';
 import React, { Component } from 'react';
 import ProfilingStatus from './ProfilingStatus';
 import ProfileList from './ProfileList';
+import UploadErrors from './UploadErrors';
 import * as DashboardActions from '../redux/actions/DashboardActions';
 
 class Content extends Component {
@@ -46,6 +47,7 @@ class Content extends Component {
         return (
             <div className="wrapper">
                 <ProfilingStatus />
+                <UploadErrors />
                 <ProfileList />
             </div>
         );
@@ -62,13 +62,16 @@ function craftErrorIfEmpty(response) {
     return data;
 }
 
-export function enableProfiler(title) {
+export function enableProfiler(title, duration = 0) {
     return (dispatch) => {
         dispatch({
             type: DashboardConstants.PROFILER_ENABLING,
         });
 
-        const query = 'title=' + encodeURIComponent(title.trim());
+        let query = 'title=' + encodeURIComponent(title.trim());
+        if (duration > 0) {
+            query += '&duration=' + duration;
+        }
 
         doFetch('./enable?' + query, 'POST')
             .then((response) => {
@@ -4,8 +4,12 @@ import DashboardConstants from '../constants/DashboardConstants';
 const _state = Immutable.Map({
     loading: false,
     profiling_enabled: false,
+    profiling_state: 'off',
     profiling_sample_rate: -1,
+    profiling_max_duration: 0,
+    agent_socket: '',
     profiles: [],
+    upload_errors: [],
     profiler_enabling: false,
     profiler_disabling: false,
     profiler_ending: false,
@@ -20,6 +24,17 @@ function isError(data) {
     return data.status;
 }
 
+function setStatus(ctx, data) {
+    ctx
+        .set('profiling_enabled', data.profiling.enabled)
+        .set('profiling_state', data.profiling.state)
+        .set('profiling_sample_rate', data.profiling.sample_rate)
+        .set('profiling_max_duration', data.profiling.max_duration)
+        .set('agent_socket', data.profiling.agent_socket)
+        .set('profiles', data.profiles._embedded)
+        .set('upload_errors', data.errors || []);
+}
+
 function disablePropAndSetError(state, prop, data) {
     const is_error = isError(data);
 
@@ -29,10 +44,7 @@ function disablePropAndSetError(state, prop, data) {
             .set('error', is_error ? data : null);
 
         if (!is_error && data.profiling.enabled !== undefined) {
-            ctx
-                .set('profiling_enabled', data.profiling.enabled)
-                .set('profiling_sample_rate', data.profiling.sample_rate)
-                .set('profiles', data.profiles._embedded);
+            setStatus(ctx, data);
         }
     });
 }
@@ -47,11 +59,8 @@ function enablePropAndClearError(state, prop) {
 
 function dashboardLoaded(state, data) {
     return state.withMutations((ctx) => {
-        ctx
-            .set('loading', false)
-            .set('profiling_enabled', data.profiling.enabled)
-            .set('profiling_sample_rate', data.profiling.sample_rate)
-            .set('profiles', data.profiles._embedded)
+        ctx.set('loading', false);
+        setStatus(ctx, data);
     });
 }
 
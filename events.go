@@ -0,0 +1,64 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"time"
+)
+
+// Types of the profile lifecycle events.
+const (
+	lifecycleStarted   = "started"
+	lifecycleStopped   = "stopped"
+	lifecycleUploading = "uploading"
+	lifecycleUploaded  = "uploaded"
+	lifecycleErrored   = "errored"
+)
+
+// eventBufferSize is the number of events buffered per subscriber. Events are
+// dropped for the subscribers which don't keep up.
+const eventBufferSize = 16
+
+// lifecycleEvent is a profile lifecycle event, as streamed by the /events
+// endpoint.
+type lifecycleEvent struct {
+	Type  string `json:"type"`
+	Time  string `json:"time"`
+	Title string `json:"title,omitempty"`
+	UUID  string `json:"uuid,omitempty"`
+	URL   string `json:"url,omitempty"`
+	Error string `json:"error,omitempty"`
+	// Reason is set on the stopped event when the probe stopped profiling on
+	// its own (see Probe.StopReason).
+	Reason string `json:"reason,omitempty"`
+}
+
+// subscribe returns a channel receiving the events of the probe, and a
+// function to call to unsubscribe.
+func (p *Probe) subscribe() (<-chan lifecycleEvent, func()) {
+	events := make(chan lifecycleEvent, eventBufferSize)
+	p.eventsMutex.Lock()
+	defer p.eventsMutex.Unlock()
+	if p.subscribers == nil {
+		p.subscribers = make(map[chan lifecycleEvent]struct{})
+	}
+	p.subscribers[events] = struct{}{}
+	return events, func() {
+		p.eventsMutex.Lock()
+		defer p.eventsMutex.Unlock()
+		delete(p.subscribers, events)
+	}
+}
+
+func (p *Probe) publish(event lifecycleEvent) {
+	event.Time = p.clock.Now().Format(time.RFC3339Nano)
+	p.eventsMutex.Lock()
+	defer p.eventsMutex.Unlock()
+	for events := range p.subscribers {
+		select {
+		case events <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,59 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProbeEvents(c *C) {
+	p := newTestProbe(newFakeClock())
+	events, unsubscribe := p.subscribe()
+
+	p.SetCurrentTitle("events")
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert((<-events).Type, Equals, lifecycleStarted)
+	c.Assert(p.Disable(), IsNil)
+	event := <-events
+	c.Assert(event.Type, Equals, lifecycleStopped)
+	c.Assert(event.Title, Equals, "events")
+	c.Assert(event.Time, Equals, "2020-01-01T00:00:00Z")
+
+	unsubscribe()
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.End(), IsNil)
+	c.Assert(events, HasLen, 0)
+}
+
+func (s *BlackfireSuite) TestEventsHandler(c *C) {
+	p := newTestProbe(newFakeClock())
+	mux, err := NewServeMuxFor(p, MuxPrefix("_blackfire"))
+	c.Assert(err, IsNil)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	response, err := http.Get(server.URL + "/_blackfire/events")
+	c.Assert(err, IsNil)
+	defer response.Body.Close()
+	c.Assert(response.Header.Get("Content-Type"), Equals, "text/event-stream")
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	defer p.End()
+	reader := bufio.NewReader(response.Body)
+	line, err := reader.ReadString('\n')
+	c.Assert(err, IsNil)
+	c.Assert(line, Equals, "event: started\n")
+	line, err = reader.ReadString('\n')
+	c.Assert(err, IsNil)
+	var event lifecycleEvent
+	c.Assert(json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &event), IsNil)
+	c.Assert(event.Type, Equals, lifecycleStarted)
+}
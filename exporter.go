@@ -0,0 +1,108 @@
+package blackfire
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+
+	"github.com/blackfireio/go-blackfire/bf_format"
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// Exporter sends profiles to a backend other than the Blackfire agent (see
+// Configuration.Exporter).
+type Exporter interface {
+	// Export sends the profile, and returns the size of the payload sent.
+	Export(profile *pprof_reader.Profile, title string) (int, error)
+}
+
+// ProfileMetadata describes the profile being encoded.
+type ProfileMetadata struct {
+	// Title of the profile, if any.
+	Title string
+}
+
+// ProfileEncoder writes profiles in a given format (see
+// Configuration.Encoder).
+type ProfileEncoder interface {
+	// Encode writes the profile, with the options requested by the server,
+	// to w.
+	Encode(w io.Writer, profile *pprof_reader.Profile, options bf_format.ProbeOptions, metadata ProfileMetadata) error
+}
+
+// BFEncoder writes profiles in the Blackfire format, the one expected by the
+// agent. It is the default encoder.
+type BFEncoder struct{}
+
+func (BFEncoder) Encode(w io.Writer, profile *pprof_reader.Profile, options bf_format.ProbeOptions, metadata ProfileMetadata) error {
+	return bf_format.WriteBFFormat(profile, w, options, metadata.Title)
+}
+
+// Sink is an additional destination of the profiles (see
+// Configuration.Sinks). Exactly one of AgentSocket, OutputDir and Exporter
+// must be set. Profiles are delivered to the sinks in the background, once
+// uploaded.
+type Sink struct {
+	// Name of the sink in the stats. Defaults to a description of the
+	// destination.
+	Name string
+	// Upload the profiles to this agent.
+	AgentSocket string
+	// The credentials and the Blackfire API endpoint used to sign the
+	// profiles uploaded to AgentSocket, such as the ones of another
+	// environment. Default to the ones of the probe.
+	ClientID     string
+	ClientToken  string
+	HTTPEndpoint *url.URL
+	// Write the profiles in Blackfire format to new files in this directory,
+	// to keep an archive of them.
+	OutputDir string
+	// Send the profiles to this exporter.
+	Exporter Exporter
+}
+
+// String describes the sink, without its credentials, as reported by
+// Configuration.Effective.
+func (s Sink) String() string {
+	return s.name()
+}
+
+func (s Sink) name() string {
+	switch {
+	case s.Name != "":
+		return s.Name
+	case s.AgentSocket != "":
+		return "agent " + s.AgentSocket
+	case s.OutputDir != "":
+		return "directory " + s.OutputDir
+	default:
+		return fmt.Sprintf("exporter %T", s.Exporter)
+	}
+}
+
+func (s Sink) validate() error {
+	destinations := 0
+	if s.AgentSocket != "" {
+		destinations++
+	}
+	if s.OutputDir != "" {
+		destinations++
+	}
+	if s.Exporter != nil {
+		destinations++
+	}
+	if destinations != 1 {
+		return errors.New("exactly one of AgentSocket, OutputDir and Exporter must be set")
+	}
+	if (s.ClientID != "" || s.ClientToken != "" || s.HTTPEndpoint != nil) && s.AgentSocket == "" {
+		return errors.New("ClientID, ClientToken and HTTPEndpoint only apply to AgentSocket")
+	}
+	if (s.ClientID == "") != (s.ClientToken == "") {
+		return errors.New("ClientID and ClientToken must be set together")
+	}
+	if u := s.HTTPEndpoint; u != nil && ((u.Scheme != "http" && u.Scheme != "https") || u.Host == "") {
+		return fmt.Errorf("invalid endpoint %s: expecting an http or https URL", u)
+	}
+	return nil
+}
@@ -0,0 +1,333 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// fakeQuery is the Blackfire query signed by the fake Blackfire API, as
+// expected by the agent transcripts.
+const fakeQuery = "signature=abc&expires=9999999999"
+
+// agentTranscript is a recorded agent conversation, as described in
+// agent_protocol_test.go.
+type agentTranscript struct {
+	// lines are the lines played by the agent.
+	lines []string
+	// blackfireYaml tells whether the probe offers a .blackfire.yml.
+	blackfireYaml bool
+	// serverID and serverToken are the server credentials known by the
+	// probe, if any.
+	serverID    string
+	serverToken string
+	// expectedError is the error expected from the upload, if any.
+	expectedError string
+}
+
+// loadAgentTranscript reads a transcript from fixtures/agent_transcripts.
+func loadAgentTranscript(name string) (*agentTranscript, error) {
+	data, err := ioutil.ReadFile(filepath.Join("fixtures", "agent_transcripts", name))
+	if err != nil {
+		return nil, err
+	}
+	osVersion, err := getProfileOSHeaderValue()
+	if err != nil {
+		return nil, err
+	}
+	replacer := strings.NewReplacer("{go_version}", (&agentClient{}).getGoVersion(), "{os_version}", osVersion.Encode())
+
+	transcript := &agentTranscript{}
+	for _, line := range strings.Split(strings.TrimSuffix(replacer.Replace(string(data)), "\n"), "\n") {
+		switch {
+		case strings.HasPrefix(line, "#"):
+		case line == "@ blackfire_yml":
+			transcript.blackfireYaml = true
+		case strings.HasPrefix(line, "@ auth "):
+			credentials := strings.SplitN(strings.TrimPrefix(line, "@ auth "), ":", 2)
+			transcript.serverID, transcript.serverToken = credentials[0], credentials[1]
+		case strings.HasPrefix(line, "[error] "):
+			transcript.expectedError = strings.TrimPrefix(line, "[error] ")
+		default:
+			transcript.lines = append(transcript.lines, line)
+		}
+	}
+	return transcript, nil
+}
+
+// fakeBlackfire is a hermetic Blackfire: an API signing queries and serving
+// the profiles, and an agent playing a transcript on each connection.
+type fakeBlackfire struct {
+	api        *httptest.Server
+	agent      net.Listener
+	transcript *agentTranscript
+	// played receives the outcome of each transcript played by the agent.
+	played chan error
+
+	mutex    sync.Mutex
+	signings int
+}
+
+// newFakeBlackfire starts a fake Blackfire whose agent plays the named
+// transcript.
+func newFakeBlackfire(c *C, transcript string) *fakeBlackfire {
+	t, err := loadAgentTranscript(transcript)
+	c.Assert(err, IsNil)
+	agent, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	f := &fakeBlackfire{
+		agent:      agent,
+		transcript: t,
+		played:     make(chan error, 10),
+	}
+	f.api = httptest.NewServer(http.HandlerFunc(f.serveAPI))
+	go f.serveAgent()
+	return f
+}
+
+func (f *fakeBlackfire) close() {
+	f.agent.Close()
+	f.api.Close()
+}
+
+// newProbe returns a probe using the fake Blackfire.
+func (f *fakeBlackfire) newProbe(c *C) *Probe {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+
+	endpoint, err := url.Parse(f.api.URL)
+	c.Assert(err, IsNil)
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-fake-test.log"), 4)
+	config := &Configuration{
+		AgentSocket:  "tcp://" + f.agent.Addr().String(),
+		AgentTimeout: time.Second,
+		ClientID:     "id",
+		ClientToken:  "token",
+		HTTPEndpoint: endpoint,
+		ServerID:     f.transcript.serverID,
+		ServerToken:  f.transcript.serverToken,
+		Logger:       &logger,
+	}
+	if f.transcript.blackfireYaml {
+		config.BlackfireYamlPath = filepath.Join("fixtures", "agent_transcripts", "blackfire.yml")
+	}
+	p := NewProbe(config)
+	c.Assert(p.configuration.load(), IsNil)
+	return p
+}
+
+// waitPlayed waits for the agent to play the transcript once, and returns
+// the outcome.
+func (f *fakeBlackfire) waitPlayed() error {
+	select {
+	case err := <-f.played:
+		return err
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("the agent was not contacted")
+	}
+}
+
+// signingCount returns the number of queries signed by the API.
+func (f *fakeBlackfire) signingCount() int {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.signings
+}
+
+// serveAPI signs queries for the "id" client, and serves the profiles as
+// finished.
+func (f *fakeBlackfire) serveAPI(w http.ResponseWriter, r *http.Request) {
+	if user, password, _ := r.BasicAuth(); user != "id" || password != "token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/api/v1/signing":
+		f.mutex.Lock()
+		f.signings++
+		uuid := fmt.Sprintf("uuid-%d", f.signings)
+		f.mutex.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"uuid":%q,"query_string":%q,"_links":{"profile":{"href":"%s/api/v1/profiles/%s"},"graph_url":{"href":"%s/profiles/%s/graph"}}}`,
+			uuid, fakeQuery, f.api.URL, uuid, f.api.URL, uuid)
+	case r.Method == "GET" && strings.HasPrefix(r.URL.Path, "/api/v1/profiles/"):
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"label":"fake profile","created_at":"2020-01-01T00:00:00Z","status":{"name":"finished","code":64},"envelope":{"ct":1,"cpu":100,"mu":10,"pmu":20}}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeBlackfire) serveAgent() {
+	for {
+		conn, err := f.agent.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			f.played <- playAgentTranscript(conn, f.transcript.lines)
+		}()
+	}
+}
+
+// recordWhenProfiling records a metric once the probe starts profiling, so
+// that the profile is not empty and gets uploaded.
+func recordWhenProfiling(p *Probe) {
+	for !p.IsProfiling() {
+		time.Sleep(time.Millisecond)
+	}
+	p.Metric("ops").Add(1)
+}
+
+func (s *BlackfireSuite) TestFakeBlackfireProfileHandler(c *C) {
+	f := newFakeBlackfire(c, "no_yaml.txt")
+	defer f.close()
+	p := f.newProbe(c)
+	mux, err := p.NewServeMux("_blackfire")
+	c.Assert(err, IsNil)
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	go recordWhenProfiling(p)
+	response, err := http.Post(server.URL+"/_blackfire/profile?duration=0.1&title=fake", "", nil)
+	c.Assert(err, IsNil)
+	defer response.Body.Close()
+	body, err := ioutil.ReadAll(response.Body)
+	c.Assert(err, IsNil)
+	c.Assert(response.StatusCode, Equals, http.StatusOK, Commentf("%s", body))
+	c.Assert(f.waitPlayed(), IsNil)
+	c.Assert(string(body), Matches, `.*"uuid":"uuid-1".*`)
+	c.Assert(string(body), Matches, `.*"title":"fake".*`)
+
+	// The dashboard lists the profile, as loaded from the API.
+	response, err = http.Get(server.URL + "/_blackfire/dashboard_api")
+	c.Assert(err, IsNil)
+	defer response.Body.Close()
+	var status dashboardStatus
+	c.Assert(json.NewDecoder(response.Body).Decode(&status), IsNil)
+	c.Assert(status.Profiles.Embedded, HasLen, 1)
+	profile := status.Profiles.Embedded[0]
+	c.Assert(profile.UUID, Equals, "uuid-1")
+	c.Assert(profile.URL, Equals, f.api.URL+"/profiles/uuid-1/graph")
+	c.Assert(profile.Name, Equals, "fake profile")
+	c.Assert(profile.Status, Equals, "finished")
+}
+
+func (s *BlackfireSuite) TestFakeBlackfireAgentError(c *C) {
+	f := newFakeBlackfire(c, "error.txt")
+	defer f.close()
+	p := f.newProbe(c)
+	mux, err := p.NewServeMux("")
+	c.Assert(err, IsNil)
+
+	go recordWhenProfiling(p)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/profile?duration=0.1", nil))
+	c.Assert(f.waitPlayed(), IsNil)
+	c.Assert(recorder.Code, Equals, http.StatusInternalServerError)
+	c.Assert(recorder.Body.String(), Matches, `.*`+f.transcript.expectedError+`.*`)
+}
+
+func (s *BlackfireSuite) TestFakeBlackfireMiddleware(c *C) {
+	f := newFakeBlackfire(c, "yaml_requested.txt")
+	defer f.close()
+	p := f.newProbe(c)
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.Metric("ops").Add(1)
+		w.Write([]byte("done"))
+	}))
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Requests without a Blackfire query are not profiled.
+	response, err := http.Get(server.URL + "/plain")
+	c.Assert(err, IsNil)
+	response.Body.Close()
+
+	request, err := http.NewRequest("GET", server.URL+"/profiled", nil)
+	c.Assert(err, IsNil)
+	request.Header.Set(blackfireQueryHeader, fakeQuery)
+	response, err = http.DefaultClient.Do(request)
+	c.Assert(err, IsNil)
+	response.Body.Close()
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+	c.Assert(f.waitPlayed(), IsNil)
+	// The query came with the request: nothing was signed.
+	c.Assert(f.signingCount(), Equals, 0)
+	select {
+	case err := <-f.played:
+		c.Fatalf("unexpected agent connection: %v", err)
+	default:
+	}
+}
+
+func (s *BlackfireSuite) TestFakeBlackfireUploadProgress(c *C) {
+	f := newFakeBlackfire(c, "no_yaml.txt")
+	defer f.close()
+	p := f.newProbe(c)
+	var mutex sync.Mutex
+	var progresses []UploadProgress
+	p.configuration.OnUploadProgress = func(progress UploadProgress) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		progresses = append(progresses, progress)
+	}
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(f.waitPlayed(), IsNil)
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	c.Assert(len(progresses) > 0, Equals, true)
+	last := progresses[len(progresses)-1]
+	c.Assert(last.Written, Equals, last.Total)
+	c.Assert(uint64(last.Total), Equals, p.Stats().PayloadBytes)
+	c.Assert(last.Elapsed > 0, Equals, true)
+}
+
+func (s *BlackfireSuite) TestFakeBlackfireCachedProfile(c *C) {
+	f := newFakeBlackfire(c, "no_yaml.txt")
+	defer f.close()
+	p := f.newProbe(c)
+	c.Assert(p.CachedProfile(), IsNil)
+
+	cached := func() *Profile {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			if profile := p.CachedProfile(); profile != nil {
+				return profile
+			}
+			time.Sleep(time.Millisecond)
+		}
+		return nil
+	}
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	profile := cached()
+	c.Assert(profile, NotNil)
+	c.Assert(profile.UUID, Equals, "uuid-1")
+	c.Assert(profile.URL, Equals, f.api.URL+"/profiles/uuid-1/graph")
+
+	// The cached profile goes with the profile.
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(f.waitPlayed(), IsNil)
+	c.Assert(p.CachedProfile(), IsNil)
+}
@@ -0,0 +1,22 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"runtime/pprof"
+)
+
+// ProfileGoroutine tags the current goroutine with a pprof label, which is
+// inherited by the goroutines it starts afterwards. When
+// Configuration.OnlyProfiledGoroutines is set, only the samples taken in
+// tagged goroutines end up in the uploaded profile.
+//
+// The returned context carries the label, and should be passed to pprof.Do
+// when adding more labels so that the tag is preserved.
+func ProfileGoroutine(ctx context.Context, label string) context.Context {
+	ctx = pprof.WithLabels(ctx, pprof.Labels(GoroutineLabel, label))
+	pprof.SetGoroutineLabels(ctx)
+	return ctx
+}
@@ -0,0 +1,197 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"runtime"
+	"runtime/pprof"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// HeapSnapshot is the heap in use by the program at some point (see
+// Probe.SnapshotHeap).
+type HeapSnapshot struct {
+	takenAt time.Time
+	data    []byte
+}
+
+// SnapshotHeap takes a snapshot of the heap with the global probe (see
+// Probe.SnapshotHeap).
+func SnapshotHeap() (*HeapSnapshot, error) {
+	return globalProbe.SnapshotHeap()
+}
+
+// DiffHeap uploads the heap growth between two snapshots with the global
+// probe (see Probe.DiffHeap).
+func DiffHeap(before, after *HeapSnapshot) error {
+	return globalProbe.DiffHeap(before, after)
+}
+
+// SnapshotHeap takes a snapshot of the heap in use, to be compared to a later
+// one with DiffHeap. A garbage collection is run first, for the snapshot not
+// to include the memory which is already unreachable. No snapshot is taken
+// when the probe is disabled by configuration: a nil HeapSnapshot is
+// returned, which DiffHeap ignores.
+func (p *Probe) SnapshotHeap() (snapshot *HeapSnapshot, err error) {
+	if p.disabledFromPanic {
+		return nil, errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
+
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if p.configuration.Disabled {
+		return
+	}
+
+	defer p.measure(&p.stats.HeapSnapshotTime)()
+	runtime.GC()
+	var buffer bytes.Buffer
+	if err = pprof.WriteHeapProfile(&buffer); err != nil {
+		return
+	}
+	return &HeapSnapshot{
+		takenAt: p.clock.Now(),
+		data:    buffer.Bytes(),
+	}, nil
+}
+
+// DiffHeap uploads a profile of the memory growth between two heap
+// snapshots: its memory dimension is the in-use memory of each call stack in
+// after minus the one in before, the stacks which did not grow being left
+// out (see pprof_reader.DiffMemory). Leaks are then attributed to the code
+// retaining more and more memory. The profile is titled after the current
+// title, prefixed with "Heap growth: ", and is sent like the other profiles,
+// to the collector, the output file, the exporter or the agent, and to the
+// sinks. Profiles sent to the agent are signed separately from the ones of
+// the probe, which needs the client ID and token. Nothing is sent when the
+// heap did not grow. The other probe calls are not held while the profile is
+// sent.
+func (p *Probe) DiffHeap(before, after *HeapSnapshot) (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
+
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if p.configuration.Disabled || before == nil || after == nil {
+		return
+	}
+	config := p.configuration
+	logger := config.Logger
+
+	// The state of the probe is only read with the probe mutex held; the
+	// heap growth is then converted and sent without it.
+	dimensions := []string{DimensionMemory}
+	p.mutex.Lock()
+	title := "Heap growth: " + p.title()
+	options := p.readOptions()
+	fileOptions := withDimensions(p.pendingProbeOptions(), dimensions)
+	p.mutex.Unlock()
+	defer func() {
+		if err != nil {
+			p.publish(lifecycleEvent{Type: lifecycleErrored, Title: title, Error: err.Error()})
+		}
+	}()
+
+	p.heapMutex.Lock()
+	defer p.heapMutex.Unlock()
+
+	if collector := config.CollectorSocket; config.OutputFile == "" && config.Exporter == nil && collector != "" {
+		logger.Debug().Msgf("Blackfire: Push the heap snapshots to the collector at %s", collector)
+		err = pushHeapGrowthToCollector(collector, title, before, after)
+		p.recordUpload(0, err)
+		return
+	}
+
+	options.MemoryAttribution = pprof_reader.MemoryPerStack
+	beforeProfile, err := pprof_reader.ReadFromPProfWithOptions(nil, []*bytes.Buffer{bytes.NewBuffer(before.data)}, options)
+	if err != nil {
+		return
+	}
+	afterProfile, err := pprof_reader.ReadFromPProfWithOptions(nil, []*bytes.Buffer{bytes.NewBuffer(after.data)}, options)
+	if err != nil {
+		return
+	}
+	profile := pprof_reader.DiffMemory(beforeProfile, afterProfile)
+	if !profile.HasData() {
+		logger.Debug().Msgf("Blackfire: The heap did not grow between the snapshots")
+		return
+	}
+	profile.Duration = after.takenAt.Sub(before.takenAt)
+	profile.Headers = make(map[string]string)
+	profile.SensitiveArgs = config.sensitiveArgs
+	profile.GraphRoot = config.GraphRoot
+	profile.Language = config.ProbedLanguage
+	profile.Runtime = config.ProbedRuntime
+
+	logger.Debug().Msgf("Blackfire: Sending the heap growth over %v", profile.Duration)
+	p.publish(lifecycleEvent{Type: lifecycleUploading, Title: title})
+	stopMeasure := p.measure(&p.stats.UploadTime)
+	var size int
+	uploaded := lifecycleEvent{Type: lifecycleUploaded, Title: title}
+	if config.OutputFile != "" {
+		size, err = writeProfileToFile(config, profile, config.OutputFile, title, fileOptions)
+	} else if exporter := config.Exporter; exporter != nil {
+		size, err = exporter.Export(profile, title)
+	} else {
+		var client *agentClient
+		if client, err = p.heapClient(); err == nil {
+			size, err = client.SendProfile(context.Background(), profile, title, dimensions)
+			if sent := client.lastSentProfile(); sent != nil && err == nil {
+				uploaded.UUID = sent.UUID
+				uploaded.URL = sent.URL
+				p.notifyWebhook(sent, title)
+			}
+		}
+	}
+	stopMeasure()
+	if err == nil {
+		p.publish(uploaded)
+	}
+	p.mutex.Lock()
+	p.deliverToSinks(profile, title, dimensions)
+	p.mutex.Unlock()
+	p.recordUpload(size, err)
+	return
+}
+
+// heapClient returns the agent client uploading the heap growth profiles,
+// which requests its own signing, not to use the one of the profile being
+// recorded. It must be called with the heap mutex held.
+func (p *Probe) heapClient() (*agentClient, error) {
+	if p.heapAgentClient != nil {
+		return p.heapAgentClient, nil
+	}
+	config := p.configuration
+	if config.ClientID == "" || config.ClientToken == "" {
+		return nil, fmt.Errorf("The client ID and token are needed to send the heap growth to the agent")
+	}
+	client, err := NewAgentClient(config)
+	if err != nil {
+		return nil, err
+	}
+	client.signingResponse = nil
+	client.signingResponseIsConsumed = true
+	// Only the profiles of the probe are persisted.
+	client.history = newProfileHistory(config.ProfileHistorySize, "")
+	p.heapAgentClient = client
+	return client, nil
+}
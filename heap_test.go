@@ -0,0 +1,114 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	. "gopkg.in/check.v1"
+)
+
+var retainedForHeapTest [][]byte
+
+func (s *BlackfireSuite) TestDiffHeap(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-heap-test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+
+	p := newTestProbe(newFakeClock())
+	p.configuration.Sinks = []Sink{{OutputDir: dir}}
+	os.Remove(p.configuration.OutputFile)
+	defer os.Remove(p.configuration.OutputFile)
+	events, unsubscribe := p.subscribe()
+	defer unsubscribe()
+
+	before, err := p.SnapshotHeap()
+	c.Assert(err, IsNil)
+	for i := 0; i < 64; i++ {
+		retainedForHeapTest = append(retainedForHeapTest, make([]byte, 1<<20))
+	}
+	after, err := p.SnapshotHeap()
+	c.Assert(err, IsNil)
+	defer func() { retainedForHeapTest = nil }()
+
+	c.Assert(p.DiffHeap(before, after), IsNil)
+	p.sinkDeliveries.Wait()
+	data, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(len(data) > 0, Equals, true)
+	c.Assert(p.Stats().UploadsSucceeded, Equals, 1)
+	c.Assert((<-events).Type, Equals, lifecycleUploading)
+	uploaded := <-events
+	c.Assert(uploaded.Type, Equals, lifecycleUploaded)
+	c.Assert(strings.HasPrefix(uploaded.Title, "Heap growth: "), Equals, true)
+	delivered, err := ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(delivered, HasLen, 1)
+	c.Assert(p.Sinks()[0].UploadsSucceeded, Equals, 1)
+
+	// Snapshots of a disabled probe are ignored.
+	c.Assert(p.DiffHeap(nil, after), IsNil)
+	c.Assert(p.Stats().UploadsSucceeded, Equals, 1)
+}
+
+func (s *BlackfireSuite) TestDiffHeapToCollector(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-heap-test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "collector.sock")
+
+	parent := newTestProbe(newFakeClock())
+	collector, err := parent.StartCollector(socket)
+	c.Assert(err, IsNil)
+	defer collector.Close()
+
+	child := newTestProbe(newFakeClock())
+	child.configuration.OutputFile = ""
+	child.configuration.CollectorSocket = socket
+	child.currentTitle = "worker"
+	before, err := child.SnapshotHeap()
+	c.Assert(err, IsNil)
+	for i := 0; i < 64; i++ {
+		retainedForHeapTest = append(retainedForHeapTest, make([]byte, 1<<20))
+	}
+	after, err := child.SnapshotHeap()
+	c.Assert(err, IsNil)
+	defer func() { retainedForHeapTest = nil }()
+
+	c.Assert(child.DiffHeap(before, after), IsNil)
+	c.Assert(child.Stats().UploadsSucceeded, Equals, 1)
+	subProfiles := parent.takeSubProfiles()
+	c.Assert(subProfiles, HasLen, 1)
+	c.Assert(strings.HasPrefix(subProfiles[0].Name, "Heap growth: worker (pid "), Equals, true)
+	c.Assert(subProfiles[0].Profile.HasData(), Equals, true)
+}
+
+func (s *BlackfireSuite) TestDiffHeapToAgent(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := NewProbe(&Configuration{
+		BlackfireQuery: "expires=2000000000&signature=sig",
+		Logger:         &logger,
+	})
+	c.Assert(p.prepareAgentClient(), IsNil)
+
+	before, err := p.SnapshotHeap()
+	c.Assert(err, IsNil)
+	for i := 0; i < 64; i++ {
+		retainedForHeapTest = append(retainedForHeapTest, make([]byte, 1<<20))
+	}
+	after, err := p.SnapshotHeap()
+	c.Assert(err, IsNil)
+	defer func() { retainedForHeapTest = nil }()
+
+	// The heap growth is signed separately, leaving the Blackfire query to
+	// the profile of the probe.
+	c.Assert(p.DiffHeap(before, after), ErrorMatches, "The client ID and token are needed to send the heap growth to the agent")
+	c.Assert(p.agentClient.signingResponseIsConsumed, Equals, false)
+	c.Assert(p.Stats().UploadsFailed, Equals, 1)
+}
@@ -1,18 +1,100 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
 package blackfire
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"path"
+	"runtime"
+	"runtime/debug"
 	"strconv"
 	"strings"
 	"time"
 
 	_ "github.com/blackfireio/go-blackfire/statik"
 	"github.com/rakyll/statik/fs"
+	"github.com/rs/zerolog"
 )
 
+// statusAgentTimeout caps the time StatusHandler waits for the agent.
+const statusAgentTimeout = time.Second
+
+type probeStatus struct {
+	State           string       `json:"state"`
+	StopReason      string       `json:"stop_reason,omitempty"`
+	Ready           bool         `json:"ready"`
+	Agent           *agentStatus `json:"agent,omitempty"`
+	LastUploadError string       `json:"last_upload_error,omitempty"`
+	QueueDepth      int          `json:"queue_depth"`
+	Version         string       `json:"version"`
+}
+
+type probeHealth struct {
+	Status    string       `json:"status"`
+	State     string       `json:"state"`
+	Version   string       `json:"version"`
+	GoVersion string       `json:"go_version"`
+	Agent     *agentStatus `json:"agent,omitempty"`
+}
+
+type agentStatus struct {
+	Socket    string `json:"socket"`
+	Reachable bool   `json:"reachable"`
+	Error     string `json:"error,omitempty"`
+}
+
+type dashboardStatus struct {
+	Profiling  dashboardProfiling   `json:"profiling"`
+	Profiles   dashboardProfiles    `json:"profiles"`
+	Errors     []dashboardError     `json:"errors"`
+	Goroutines []dashboardGoroutine `json:"goroutines,omitempty"`
+}
+
+type dashboardGoroutine struct {
+	Function string `json:"function"`
+	CPUTime  uint64 `json:"cpu_us"`
+	Samples  int    `json:"samples"`
+}
+
+type dashboardProfiling struct {
+	Enabled     bool    `json:"enabled"`
+	State       string  `json:"state"`
+	SampleRate  int     `json:"sample_rate"`
+	MaxDuration float64 `json:"max_duration"`
+	AgentSocket string  `json:"agent_socket"`
+}
+
+type dashboardError struct {
+	Message string `json:"message"`
+	Time    string `json:"time"`
+}
+
+type dashboardProfiles struct {
+	Embedded []dashboardProfile `json:"_embedded"`
+}
+
+type dashboardProfile struct {
+	UUID      string   `json:"UUID"`
+	URL       string   `json:"url"`
+	Name      string   `json:"name"`
+	Status    string   `json:"status"`
+	CreatedAt string   `json:"created_at"`
+	Envelope  Envelope `json:"envelope"`
+}
+
+type profileResult struct {
+	UUID     string  `json:"uuid,omitempty"`
+	URL      string  `json:"url,omitempty"`
+	Title    string  `json:"title"`
+	Duration float64 `json:"duration"`
+}
+
 type problem struct {
 	Title  string `json:"title"`
 	Status int    `json:"status"`
@@ -21,23 +103,179 @@ type problem struct {
 
 // NewServeMux returns an http.ServerMux that allows to manage profiling from HTTP
 func NewServeMux(prefix string) (mux *http.ServeMux, err error) {
-	if err = globalProbe.configuration.load(); err != nil {
+	return globalProbe.NewServeMux(prefix)
+}
+
+// DashboardHandler displays the current status of the profiler
+func DashboardHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.DashboardHandler(w, r)
+}
+
+func DashboardApiHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.DashboardApiHandler(w, r)
+}
+
+// StatusHandler reports whether the profiler is ready to profile
+func StatusHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.StatusHandler(w, r)
+}
+
+// HealthHandler reports whether the profiling endpoints are functional
+func HealthHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.HealthHandler(w, r)
+}
+
+// EnableHandler starts profiling via HTTP
+func EnableHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.EnableHandler(w, r)
+}
+
+// DisableHandler stops profiling via HTTP
+func DisableHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.DisableHandler(w, r)
+}
+
+// EndHandler stops profiling via HTTP and send the profile to the agent
+func EndHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.EndHandler(w, r)
+}
+
+// ProfileHandler profiles for the requested duration via HTTP, and responds
+// once the profile is uploaded with its UUID and graph URL
+func ProfileHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.ProfileHandler(w, r)
+}
+
+// EventsHandler streams the profile lifecycle events as Server-Sent Events
+func EventsHandler(w http.ResponseWriter, r *http.Request) {
+	globalProbe.EventsHandler(w, r)
+}
+
+// NewServeMux returns an http.ServerMux that allows to manage profiling of
+// this probe from HTTP. The endpoints are protected according to the HTTP*
+// configuration settings.
+func (p *Probe) NewServeMux(prefix string) (mux *http.ServeMux, err error) {
+	return NewServeMuxFor(p, MuxPrefix(prefix))
+}
+
+// NewServeMuxFor returns an http.ServerMux that allows to manage profiling of
+// the given probe from HTTP. The endpoints are protected according to the
+// HTTP* configuration settings of the probe.
+func NewServeMuxFor(p *Probe, opts ...MuxOption) (mux *http.ServeMux, err error) {
+	if err = p.configuration.load(); err != nil {
 		return
 	}
-	prefix = strings.Trim(prefix, "/")
+	h := &httpHandlers{probe: p}
+	for _, opt := range opts {
+		opt(&h.options)
+	}
+
+	prefix := "/" + strings.Trim(h.options.prefix, "/")
+	h.options.statusPath = path.Join(prefix, "status")
 	mux = http.NewServeMux()
-	mux.HandleFunc("/"+prefix+"/dashboard", DashboardHandler)
-	mux.HandleFunc("/"+prefix+"/dashboard_api", DashboardApiHandler)
-	mux.HandleFunc("/"+prefix+"/enable", EnableHandler)
-	mux.HandleFunc("/"+prefix+"/disable", DisableHandler)
-	mux.HandleFunc("/"+prefix+"/end", EndHandler)
+	// Only the endpoints starting a profile are rate limited, so that a
+	// profile just started can always be stopped.
+	limiter := p.newRateLimiter()
+	control := func(handler http.Handler) http.Handler {
+		return h.protect(h.allowMethods(handler))
+	}
+	mux.Handle(path.Join(prefix, "dashboard"), h.protect(http.HandlerFunc(h.dashboard)))
+	mux.Handle(path.Join(prefix, "dashboard_api"), h.protect(http.HandlerFunc(h.dashboardApi)))
+	mux.HandleFunc(path.Join(prefix, "status"), h.status)
+	mux.HandleFunc(path.Join(prefix, "health"), h.health)
+	mux.Handle(path.Join(prefix, "enable"), control(h.limit(limiter, http.HandlerFunc(h.enable))))
+	mux.Handle(path.Join(prefix, "disable"), control(http.HandlerFunc(h.disable)))
+	mux.Handle(path.Join(prefix, "end"), control(http.HandlerFunc(h.end)))
+	mux.Handle(path.Join(prefix, "profile"), control(h.limit(limiter, http.HandlerFunc(h.profile))))
+	mux.Handle(path.Join(prefix, "events"), h.protect(http.HandlerFunc(h.events)))
 
 	return
 }
 
+// httpHandlers implements the HTTP endpoints of a probe.
+type httpHandlers struct {
+	probe   *Probe
+	options muxOptions
+}
+
+func (h *httpHandlers) logger() *zerolog.Logger {
+	if h.options.logger != nil {
+		return h.options.logger
+	}
+	return h.probe.configuration.Logger
+}
+
+func (h *httpHandlers) allowMethods(handler http.Handler) http.Handler {
+	if len(h.options.methods) == 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, method := range h.options.methods {
+			if r.Method == method {
+				handler.ServeHTTP(w, r)
+				return
+			}
+		}
+		w.Header().Set("Allow", strings.Join(h.options.methods, ", "))
+		h.writeJsonError(w, &problem{Status: 405, Title: "Method not allowed", Detail: r.Method + " is not allowed"})
+	})
+}
+
 // DashboardHandler displays the current status of the profiler
-func DashboardHandler(w http.ResponseWriter, r *http.Request) {
-	logger := globalProbe.configuration.Logger
+func (p *Probe) DashboardHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).dashboard(w, r)
+}
+
+func (p *Probe) DashboardApiHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).dashboardApi(w, r)
+}
+
+// StatusHandler reports whether the profiler is ready to profile, to be used
+// as a readiness or liveness probe. It responds with a 503 status code when
+// the agent is unreachable.
+func (p *Probe) StatusHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).status(w, r)
+}
+
+// HealthHandler reports that the profiling endpoints are functional, with the
+// state of the probe, its version and whether the agent is reachable, to be
+// used by fleet automation. Unlike StatusHandler, it responds with a 200
+// status code even when the agent is unreachable.
+func (p *Probe) HealthHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).health(w, r)
+}
+
+// EnableHandler starts profiling via HTTP
+func (p *Probe) EnableHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).enable(w, r)
+}
+
+// DisableHandler stops profiling via HTTP
+func (p *Probe) DisableHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).disable(w, r)
+}
+
+// EndHandler stops profiling via HTTP and send the profile to the agent. With
+// wait=false, it responds right away with a 202 status code, and the status
+// endpoint URL in the Location header to follow the upload.
+func (p *Probe) EndHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).end(w, r)
+}
+
+// ProfileHandler profiles for the requested duration via HTTP, and responds
+// once the profile is uploaded with its UUID and graph URL
+func (p *Probe) ProfileHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).profile(w, r)
+}
+
+// EventsHandler streams the profile lifecycle events (started, stopped,
+// uploading, uploaded, errored) as Server-Sent Events
+func (p *Probe) EventsHandler(w http.ResponseWriter, r *http.Request) {
+	(&httpHandlers{probe: p}).events(w, r)
+}
+
+func (h *httpHandlers) dashboard(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger()
 	statikFS, err := fs.New()
 	if err != nil {
 		logger.Error().Msgf("Blackfire (HTTP): %s", err)
@@ -63,66 +301,332 @@ func DashboardHandler(w http.ResponseWriter, r *http.Request) {
 	w.Write(contents)
 }
 
-func DashboardApiHandler(w http.ResponseWriter, r *http.Request) {
-	writeJsonStatus(w)
+func (h *httpHandlers) dashboardApi(w http.ResponseWriter, r *http.Request) {
+	h.writeJsonStatus(w, r)
 }
 
-// EnableHandler starts profiling via HTTP
-func EnableHandler(w http.ResponseWriter, r *http.Request) {
-	logger := globalProbe.configuration.Logger
-	if title, found := parseString(r, "title"); found {
-		globalProbe.SetCurrentTitle(title)
+func (h *httpHandlers) status(w http.ResponseWriter, r *http.Request) {
+	p := h.probe
+	status := probeStatus{
+		State:      p.State(),
+		StopReason: p.StopReason(),
+		Ready:      true,
+		QueueDepth: len(p.commands),
+		Version:    probeVersion(),
+	}
+	p.statsMutex.Lock()
+	if p.lastUploadError != nil {
+		status.LastUploadError = p.lastUploadError.Error()
+	}
+	p.statsMutex.Unlock()
+
+	if status.Agent = h.agentStatus(r.Context()); status.Agent != nil && !status.Agent.Reachable {
+		status.Ready = false
 	}
-	durationInSeconds, err := parseFloat(r, "duration")
+
+	data, err := json.Marshal(status)
 	if err != nil {
-		writeJsonError(w, &problem{Status: 400, Title: "Wrong duration", Detail: err.Error()})
+		h.writeJsonError(w, &problem{Status: 500, Title: "Status error", Detail: err.Error()})
 		return
 	}
+	w.Header().Set("Content-Type", "application/json")
+	if !status.Ready {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	w.Write(data)
+}
 
-	duration := time.Duration(durationInSeconds * float64(time.Second))
-	if durationInSeconds > 0 {
-		logger.Info().Msgf("Blackfire (HTTP): Profiling for %f seconds", float64(duration)/1000000000)
-	} else {
-		logger.Info().Msgf("Blackfire (HTTP): Enable profiling")
+func (h *httpHandlers) health(w http.ResponseWriter, r *http.Request) {
+	health := probeHealth{
+		Status:    "ok",
+		State:     h.probe.State(),
+		Version:   probeVersion(),
+		GoVersion: runtime.Version(),
+		Agent:     h.agentStatus(r.Context()),
+	}
+	if health.Agent != nil && !health.Agent.Reachable {
+		health.Status = "degraded"
 	}
-	err = globalProbe.EnableNowFor(duration)
+	data, err := json.Marshal(health)
 	if err != nil {
-		writeJsonError(w, &problem{Status: 500, Title: "Enable error", Detail: err.Error()})
+		h.writeJsonError(w, &problem{Status: 500, Title: "Health error", Detail: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+// agentStatus checks whether the agent is reachable. It returns nil when the
+// profiles are not sent to the agent.
+func (h *httpHandlers) agentStatus(ctx context.Context) *agentStatus {
+	configuration := h.probe.configuration
+	if configuration.Disabled || configuration.OutputFile != "" || configuration.Exporter != nil || configuration.CollectorSocket != "" {
+		return nil
+	}
+	status := &agentStatus{Socket: configuration.AgentSocket}
+	ctx, cancel := context.WithTimeout(ctx, statusAgentTimeout)
+	defer cancel()
+	if err := checkAgentSocket(ctx, configuration.AgentSocket); err != nil {
+		status.Error = err.Error()
 	} else {
-		writeJsonStatus(w)
+		status.Reachable = true
 	}
+	return status
 }
 
-// DisableHandler stops profiling via HTTP
-func DisableHandler(w http.ResponseWriter, r *http.Request) {
-	logger := globalProbe.configuration.Logger
-	logger.Info().Msgf("Blackfire (HTTP): Disable profiling")
-	if err := globalProbe.Disable(); err != nil {
-		writeJsonError(w, &problem{Status: 500, Title: "Disable error", Detail: err.Error()})
+// probeVersion returns the version of the go-blackfire module the application
+// is built with, or "(devel)" when it is unknown.
+func probeVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "(devel)"
+	}
+	if info.Main.Path == "github.com/blackfireio/go-blackfire" {
+		return info.Main.Version
+	}
+	for _, dep := range info.Deps {
+		if dep.Path == "github.com/blackfireio/go-blackfire" {
+			if dep.Replace != nil && dep.Replace.Version != "" {
+				return dep.Replace.Version
+			}
+			return dep.Version
+		}
+	}
+	return "(devel)"
+}
+
+// parseProfileRequest reads the title, duration and options of the profile to
+// record from the request. It writes an error response if they are invalid.
+func (h *httpHandlers) parseProfileRequest(w http.ResponseWriter, r *http.Request) (duration time.Duration, options ProfileOptions, ok bool) {
+	if title, found := parseString(r, "title"); found {
+		if h.options.validateTitle != nil {
+			if err := h.options.validateTitle(title); err != nil {
+				h.writeJsonError(w, &problem{Status: 400, Title: "Wrong title", Detail: err.Error()})
+				return
+			}
+		}
+		options.Title = title
+	}
+	if sampleRate, found := parseString(r, "sample_rate"); found {
+		var err error
+		if options.CPUSampleRateHz, err = strconv.Atoi(sampleRate); err != nil {
+			h.writeJsonError(w, &problem{Status: 400, Title: "Wrong sample rate", Detail: err.Error()})
+			return
+		}
+	}
+	for _, tag := range r.URL.Query()["tag"] {
+		parts := strings.SplitN(tag, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			h.writeJsonError(w, &problem{Status: 400, Title: "Wrong tag", Detail: fmt.Sprintf("tag %q is not in the name:value form", tag)})
+			return
+		}
+		if options.Tags == nil {
+			options.Tags = make(map[string]string)
+		}
+		options.Tags[parts[0]] = parts[1]
+	}
+	if dimensions, found := parseString(r, "dimensions"); found && dimensions != "" {
+		options.Dimensions = strings.Split(dimensions, ",")
+	}
+	if err := options.validate(); err != nil {
+		h.writeJsonError(w, &problem{Status: 400, Title: "Wrong profile options", Detail: err.Error()})
+		return
+	}
+	if value, found := parseString(r, "duration"); found {
+		var err error
+		if duration, err = h.parseDuration(value); err != nil {
+			h.writeJsonError(w, &problem{Status: 400, Title: "Wrong duration", Detail: err.Error()})
+			return
+		}
+	}
+	if maxDuration := h.options.maxDuration; maxDuration > 0 {
+		if duration > maxDuration {
+			h.writeJsonError(w, &problem{Status: 400, Title: "Wrong duration", Detail: fmt.Sprintf("duration cannot exceed %v", maxDuration)})
+			return
+		}
+		if duration <= 0 {
+			duration = h.defaultDuration()
+		}
+	}
+	return duration, options, true
+}
+
+// defaultDuration returns the duration of the profiles requested without one,
+// capped to the maximum duration of the mux.
+func (h *httpHandlers) defaultDuration() time.Duration {
+	// A configuration error is reported when enabling profiling.
+	h.probe.configuration.load()
+	duration := h.probe.configuration.DefaultProfileDuration
+	if maxDuration := h.options.maxDuration; maxDuration > 0 && duration > maxDuration {
+		duration = maxDuration
+	}
+	return duration
+}
+
+func (h *httpHandlers) enable(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger()
+	duration, options, ok := h.parseProfileRequest(w, r)
+	if !ok {
+		return
+	}
+	if duration <= 0 {
+		duration = h.defaultDuration()
+	}
+	logger.Info().Msgf("Blackfire (HTTP): Profiling for %f seconds", duration.Seconds())
+	if err := h.probe.enableNowFor(duration, options, triggerHTTP, nil); err != nil {
+		h.writeEnableError(w, err)
 	} else {
-		writeJsonStatus(w)
+		h.writeJsonStatus(w, r)
 	}
 }
 
-// EndHandler stops profiling via HTTP and send the profile to the agent
-func EndHandler(w http.ResponseWriter, r *http.Request) {
-	logger := globalProbe.configuration.Logger
-	logger.Info().Msgf("Blackfire (HTTP): End profiling")
-	if err := globalProbe.End(); err != nil {
-		writeJsonError(w, &problem{Status: 500, Title: "End error", Detail: err.Error()})
+func (h *httpHandlers) disable(w http.ResponseWriter, r *http.Request) {
+	h.logger().Info().Msgf("Blackfire (HTTP): Disable profiling")
+	if err := h.probe.Disable(); err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "Disable error", Detail: err.Error()})
 	} else {
-		writeJsonStatus(w)
+		h.writeJsonStatus(w, r)
 	}
 }
 
-func parseFloat(r *http.Request, paramName string) (value float64, err error) {
-	value = 0
-	if values, ok := r.URL.Query()[paramName]; ok {
-		if len(values) > 0 {
-			value, err = strconv.ParseFloat(values[0], 64)
+func (h *httpHandlers) end(w http.ResponseWriter, r *http.Request) {
+	if wait, found := parseString(r, "wait"); found {
+		if wait, err := strconv.ParseBool(wait); err != nil {
+			h.writeJsonError(w, &problem{Status: 400, Title: "Wrong wait parameter", Detail: err.Error()})
+			return
+		} else if !wait {
+			h.endNoWait(w, r)
+			return
 		}
 	}
-	return
+	h.logger().Info().Msgf("Blackfire (HTTP): End profiling")
+	if err := h.probe.End(); err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "End error", Detail: err.Error()})
+	} else {
+		h.writeJsonStatus(w, r)
+	}
+}
+
+// endNoWait ends the profile without waiting for the upload, and points the
+// client to the status endpoint to follow it.
+func (h *httpHandlers) endNoWait(w http.ResponseWriter, r *http.Request) {
+	h.logger().Info().Msgf("Blackfire (HTTP): End profiling without waiting")
+	if err := h.probe.EndNoWait(); err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "End error", Detail: err.Error()})
+		return
+	}
+	w.Header().Set("Location", h.statusURL())
+	h.writeJsonStatusCode(w, r, http.StatusAccepted)
+}
+
+// statusURL returns the URL of the status endpoint, relative to the other
+// endpoints when they are not served by NewServeMuxFor.
+func (h *httpHandlers) statusURL() string {
+	if h.options.statusPath != "" {
+		return h.options.statusPath
+	}
+	return "status"
+}
+
+func (h *httpHandlers) profile(w http.ResponseWriter, r *http.Request) {
+	logger := h.logger()
+	duration, options, ok := h.parseProfileRequest(w, r)
+	if !ok {
+		return
+	}
+	if duration <= 0 {
+		h.writeJsonError(w, &problem{Status: 400, Title: "Wrong duration", Detail: "a duration is required"})
+		return
+	}
+
+	logger.Info().Msgf("Blackfire (HTTP): Profiling for %f seconds and waiting for the upload", duration.Seconds())
+	p := h.probe
+	timer := p.clock.NewTimer(duration)
+	defer timer.Stop()
+	if err := p.enableNowFor(duration, options, triggerHTTP, nil); err != nil {
+		h.writeEnableError(w, err)
+		return
+	}
+	profile, err := p.CurrentProfile()
+	if err != nil {
+		p.End()
+		h.writeJsonError(w, &problem{Status: 500, Title: "Profile error", Detail: err.Error()})
+		return
+	}
+
+	// The profile is ended and uploaded even if the client goes away.
+	select {
+	case <-timer.C():
+	case <-r.Context().Done():
+	}
+	if err := p.End(); err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "End error", Detail: err.Error()})
+		return
+	}
+
+	result := profileResult{
+		Title:    options.Title,
+		Duration: duration.Seconds(),
+	}
+	if profile != nil {
+		result.UUID = profile.UUID
+		result.URL = profile.URL
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "Profile error", Detail: err.Error()})
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(data)
+}
+
+func (h *httpHandlers) events(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		h.writeJsonError(w, &problem{Status: 500, Title: "Events error", Detail: "streaming is not supported"})
+		return
+	}
+	events, unsubscribe := h.probe.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	for {
+		select {
+		case event := <-events:
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger().Error().Msgf("Blackfire (HTTP): %s", err)
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data)
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// parseDuration parses the duration parameter of a profiling request, in
+// seconds. It must be positive, and can't exceed the maximum profile
+// duration.
+func (h *httpHandlers) parseDuration(value string) (time.Duration, error) {
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil || math.IsNaN(seconds) || math.IsInf(seconds, 0) {
+		return 0, fmt.Errorf("duration %q is not a number of seconds", value)
+	}
+	if seconds <= 0 {
+		return 0, fmt.Errorf("duration must be positive, got %s", value)
+	}
+	// A configuration error is reported when enabling profiling.
+	h.probe.configuration.load()
+	if maxDuration := h.probe.configuration.MaxProfileDuration; maxDuration > 0 && seconds > maxDuration.Seconds() {
+		return 0, fmt.Errorf("duration cannot exceed the maximum profile duration of %v", maxDuration)
+	}
+	return time.Duration(seconds * float64(time.Second)), nil
 }
 
 func parseString(r *http.Request, paramName string) (value string, found bool) {
@@ -136,42 +640,77 @@ func parseString(r *http.Request, paramName string) (value string, found bool) {
 	return
 }
 
-func writeJsonError(w http.ResponseWriter, problem *problem) {
-	logger := globalProbe.configuration.Logger
-	logger.Error().Msgf("Blackfire (HTTP): %s: %s", problem.Title, problem.Detail)
+func (h *httpHandlers) writeJsonError(w http.ResponseWriter, problem *problem) {
+	h.logger().Error().Msgf("Blackfire (HTTP): %s: %s", problem.Title, problem.Detail)
 	w.Header().Set("Content-Type", "application/problem+json")
 	w.WriteHeader(problem.Status)
 	data, _ := json.Marshal(problem)
 	w.Write(data)
 }
 
-func writeJsonStatus(w http.ResponseWriter) {
-	profiling := "false"
-	if globalProbe.currentState == profilerStateEnabled {
-		profiling = "true"
-	}
-	profiles := []string{}
-	if globalProbe.agentClient != nil {
-		for _, profile := range globalProbe.agentClient.LastProfiles() {
-			profiles = append(profiles, fmt.Sprintf(`{
-	"UUID": "%s",
-	"url": "%s",
-	"name": "%s",
-	"status": "%s",
-	"created_at": "%s"
-}`, profile.UUID, profile.URL, profile.Title, profile.Status.Name, profile.CreatedAt.Format(time.RFC3339)))
+// writeEnableError reports why profiling could not be enabled, telling the
+// client when to retry if a rate limit is reached.
+func (h *httpHandlers) writeEnableError(w http.ResponseWriter, err error) {
+	if limitErr, ok := err.(*RateLimitError); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+		h.writeJsonError(w, &problem{Status: http.StatusTooManyRequests, Title: "Too many profiles", Detail: err.Error()})
+		return
+	}
+	h.writeJsonError(w, &problem{Status: 500, Title: "Enable error", Detail: err.Error()})
+}
+
+func (h *httpHandlers) writeJsonStatus(w http.ResponseWriter, r *http.Request) {
+	h.writeJsonStatusCode(w, r, http.StatusOK)
+}
+
+func (h *httpHandlers) writeJsonStatusCode(w http.ResponseWriter, r *http.Request, code int) {
+	p := h.probe
+	status := dashboardStatus{
+		Profiling: dashboardProfiling{
+			Enabled:     p.getState() == profilerStateEnabled,
+			State:       p.State(),
+			SampleRate:  p.configuration.DefaultCPUSampleRateHz,
+			MaxDuration: p.configuration.MaxProfileDuration.Seconds(),
+			AgentSocket: p.configuration.AgentSocket,
+		},
+		Profiles: dashboardProfiles{
+			Embedded: []dashboardProfile{},
+		},
+		Errors: []dashboardError{},
+	}
+	p.statsMutex.Lock()
+	for i := len(p.uploadErrors) - 1; i >= 0; i-- {
+		status.Errors = append(status.Errors, dashboardError{
+			Message: p.uploadErrors[i].err.Error(),
+			Time:    p.uploadErrors[i].time.Format(time.RFC3339),
+		})
+	}
+	for _, entry := range p.goroutineCPU {
+		status.Goroutines = append(status.Goroutines, dashboardGoroutine{
+			Function: entry.Function,
+			CPUTime:  entry.CPUTime,
+			Samples:  entry.Count,
+		})
+	}
+	p.statsMutex.Unlock()
+	if client := p.getAgentClient(); client != nil {
+		for _, profile := range client.LastProfiles(r.Context()) {
+			status.Profiles.Embedded = append(status.Profiles.Embedded, dashboardProfile{
+				UUID:      profile.UUID,
+				URL:       profile.URL,
+				Name:      profile.Title,
+				Status:    profile.Status.Name,
+				CreatedAt: profile.CreatedAt.Format(time.RFC3339),
+				Envelope:  profile.Envelope,
+			})
 		}
 	}
+	data, err := json.Marshal(status)
+	if err != nil {
+		h.writeJsonError(w, &problem{Status: 500, Title: "Status error", Detail: err.Error()})
+		return
+	}
 	w.Header().Set("Content-Type", "application/json")
-	w.Write([]byte(fmt.Sprintf(`{
-	"profiling": {
-		"enabled": %s,
-		"sample_rate": %d
-	},
-	"profiles": {
-		"_embedded": [
-			%s
-		]
-	}
-}`, profiling, globalProbe.configuration.DefaultCPUSampleRateHz, strings.Join(profiles, ","))))
+	w.WriteHeader(code)
+	w.Write(data)
 }
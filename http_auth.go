@@ -0,0 +1,97 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// protect wraps the handler with the authentication configured for the HTTP
+// endpoints.
+func (h *httpHandlers) protect(handler http.Handler) http.Handler {
+	config := h.probe.configuration
+	if config.HTTPMiddleware != nil {
+		handler = config.HTTPMiddleware(handler)
+	}
+	if config.HTTPAuthToken == "" && config.HTTPUsername == "" {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !h.isAuthorized(r) {
+			if config.HTTPUsername != "" {
+				w.Header().Set("WWW-Authenticate", `Basic realm="Blackfire"`)
+			}
+			h.writeJsonError(w, &problem{Status: 401, Title: "Unauthorized", Detail: "Missing or invalid credentials"})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+func (h *httpHandlers) isAuthorized(r *http.Request) bool {
+	config := h.probe.configuration
+	if config.HTTPAuthToken != "" {
+		// The token is not accepted from the query string, which ends up
+		// in access logs.
+		auth := r.Header.Get("Authorization")
+		if strings.HasPrefix(auth, "Bearer ") && secureCompare(strings.TrimPrefix(auth, "Bearer "), config.HTTPAuthToken) {
+			return true
+		}
+	}
+	if config.HTTPUsername != "" {
+		username, password, ok := r.BasicAuth()
+		if ok && secureCompare(username, config.HTTPUsername) && secureCompare(password, config.HTTPPassword) {
+			return true
+		}
+	}
+	return false
+}
+
+func secureCompare(given, expected string) bool {
+	return subtle.ConstantTimeCompare([]byte(given), []byte(expected)) == 1
+}
+
+// rateLimiter rejects the requests coming less than interval after the
+// previous accepted one.
+type rateLimiter struct {
+	mutex    sync.Mutex
+	clock    clock
+	interval time.Duration
+	last     time.Time
+}
+
+func (p *Probe) newRateLimiter() *rateLimiter {
+	return &rateLimiter{
+		clock:    p.clock,
+		interval: p.configuration.HTTPRateLimit,
+	}
+}
+
+func (l *rateLimiter) allow() bool {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	now := l.clock.Now()
+	if !l.last.IsZero() && now.Sub(l.last) < l.interval {
+		return false
+	}
+	l.last = now
+	return true
+}
+
+func (h *httpHandlers) limit(limiter *rateLimiter, handler http.Handler) http.Handler {
+	if limiter.interval <= 0 {
+		return handler
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !limiter.allow() {
+			h.writeJsonError(w, &problem{Status: 429, Title: "Too many requests", Detail: "Profiling requests are rate limited"})
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,392 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestStatusHandler(c *C) {
+	agent, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	p := newCheckProbe(c, "tcp://"+agent.Addr().String(), "http://"+agent.Addr().String())
+
+	var status probeStatus
+	recorder := httptest.NewRecorder()
+	p.StatusHandler(recorder, httptest.NewRequest("GET", "/status", nil))
+	c.Assert(recorder.Code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.State, Equals, "off")
+	c.Assert(status.Ready, Equals, true)
+	c.Assert(status.Agent.Reachable, Equals, true)
+	c.Assert(status.QueueDepth, Equals, 0)
+
+	agent.Close()
+	recorder = httptest.NewRecorder()
+	p.StatusHandler(recorder, httptest.NewRequest("GET", "/status", nil))
+	c.Assert(recorder.Code, Equals, http.StatusServiceUnavailable)
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.Ready, Equals, false)
+	c.Assert(status.Agent.Reachable, Equals, false)
+	c.Assert(status.Agent.Error, Not(Equals), "")
+}
+
+func (s *BlackfireSuite) TestHealthHandler(c *C) {
+	agent, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	p := newCheckProbe(c, "tcp://"+agent.Addr().String(), "http://"+agent.Addr().String())
+
+	var health probeHealth
+	recorder := httptest.NewRecorder()
+	p.HealthHandler(recorder, httptest.NewRequest("GET", "/health", nil))
+	c.Assert(recorder.Code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &health), IsNil)
+	c.Assert(health.Status, Equals, "ok")
+	c.Assert(health.State, Equals, "off")
+	c.Assert(health.Version, Not(Equals), "")
+	c.Assert(health.Agent.Reachable, Equals, true)
+
+	// The endpoints are still functional without the agent.
+	agent.Close()
+	recorder = httptest.NewRecorder()
+	p.HealthHandler(recorder, httptest.NewRequest("GET", "/health", nil))
+	c.Assert(recorder.Code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &health), IsNil)
+	c.Assert(health.Status, Equals, "degraded")
+	c.Assert(health.Agent.Reachable, Equals, false)
+}
+
+func (s *BlackfireSuite) TestDashboardApiHandler(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.agentClient = &agentClient{
+		logger:  p.configuration.Logger,
+		history: newProfileHistory(10, ""),
+	}
+	p.agentClient.history.add(&Profile{
+		UUID:     "1234",
+		Title:    `a "quoted" title`,
+		Status:   Status{Name: "finished"},
+		Envelope: Envelope{Ct: 1, CPU: 2, MU: 3, PMU: 4},
+		loaded:   true,
+	})
+
+	var status dashboardStatus
+	recorder := httptest.NewRecorder()
+	p.DashboardApiHandler(recorder, httptest.NewRequest("GET", "/dashboard_api", nil))
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.Profiling.Enabled, Equals, false)
+	c.Assert(status.Profiling.State, Equals, "off")
+	c.Assert(status.Profiling.SampleRate, Equals, p.configuration.DefaultCPUSampleRateHz)
+	c.Assert(status.Profiles.Embedded, HasLen, 1)
+	c.Assert(status.Profiles.Embedded[0].Name, Equals, `a "quoted" title`)
+	c.Assert(status.Profiles.Embedded[0].Envelope, Equals, Envelope{Ct: 1, CPU: 2, MU: 3, PMU: 4})
+	c.Assert(status.Errors, HasLen, 0)
+
+	// Upload errors are listed from the most recent one.
+	p.uploadErrors = []uploadError{
+		{time: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), err: errors.New("first")},
+		{time: time.Date(2020, 1, 2, 0, 0, 0, 0, time.UTC), err: errors.New("second")},
+	}
+	recorder = httptest.NewRecorder()
+	p.DashboardApiHandler(recorder, httptest.NewRequest("GET", "/dashboard_api", nil))
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.Errors, DeepEquals, []dashboardError{
+		{Message: "second", Time: "2020-01-02T00:00:00Z"},
+		{Message: "first", Time: "2020-01-01T00:00:00Z"},
+	})
+}
+
+// TestDashboardApiHandlerWhileSigning lists the profiles while new ones are
+// signed, to be run with the race detector.
+func (s *BlackfireSuite) TestDashboardApiHandlerWhileSigning(c *C) {
+	var signed int32
+	var api *httptest.Server
+	api = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/signing" {
+			n := atomic.AddInt32(&signed, 1)
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprintf(w, `{"uuid":"%d","query_string":"expires=1&signature=%d","_links":{"profile":{"href":"%s/api/v1/profiles/%d"}}}`, n, n, api.URL, n)
+			return
+		}
+		fmt.Fprint(w, `{"label":"profile","status":{"name":"finished"}}`)
+	}))
+	defer api.Close()
+	p := newCheckProbe(c, "tcp://127.0.0.1:1", api.URL)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			c.Check(p.prepareAgentClient(), IsNil)
+			_, err := p.getAgentClient().consumeSigningResponse(context.Background())
+			c.Check(err, IsNil)
+		}()
+		go func() {
+			defer wg.Done()
+			recorder := httptest.NewRecorder()
+			p.DashboardApiHandler(recorder, httptest.NewRequest("GET", "/dashboard_api", nil))
+			c.Check(recorder.Code, Equals, http.StatusOK)
+		}()
+	}
+	wg.Wait()
+
+	var status dashboardStatus
+	recorder := httptest.NewRecorder()
+	p.DashboardApiHandler(recorder, httptest.NewRequest("GET", "/dashboard_api", nil))
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.Profiles.Embedded, HasLen, 5)
+	c.Assert(status.Profiles.Embedded[0].UUID, Equals, "5")
+	c.Assert(status.Profiles.Embedded[0].Status, Equals, "finished")
+}
+
+func serveForTest(mux *http.ServeMux, request *http.Request) int {
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, request)
+	return recorder.Code
+}
+
+func (s *BlackfireSuite) TestServeMuxAuthentication(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.configuration.HTTPAuthToken = "secret"
+	p.configuration.HTTPUsername = "user"
+	p.configuration.HTTPPassword = "password"
+	mux, err := p.NewServeMux("_blackfire")
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/dashboard_api", nil)), Equals, http.StatusUnauthorized)
+	// The token is only accepted from the Authorization header.
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/dashboard_api?token=secret", nil)), Equals, http.StatusUnauthorized)
+
+	request := httptest.NewRequest("GET", "/_blackfire/dashboard_api", nil)
+	request.Header.Set("Authorization", "Bearer wrong")
+	c.Assert(serveForTest(mux, request), Equals, http.StatusUnauthorized)
+	request.Header.Set("Authorization", "Bearer secret")
+	c.Assert(serveForTest(mux, request), Equals, http.StatusOK)
+
+	request = httptest.NewRequest("GET", "/_blackfire/dashboard_api", nil)
+	request.SetBasicAuth("user", "wrong")
+	c.Assert(serveForTest(mux, request), Equals, http.StatusUnauthorized)
+	request.SetBasicAuth("user", "password")
+	c.Assert(serveForTest(mux, request), Equals, http.StatusOK)
+
+	// The status endpoint is left open for readiness probes.
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/status", nil)), Equals, http.StatusOK)
+}
+
+func (s *BlackfireSuite) TestServeMuxMiddleware(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.configuration.HTTPMiddleware = func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		})
+	}
+	mux, err := p.NewServeMux("_blackfire")
+	c.Assert(err, IsNil)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/enable", nil)), Equals, http.StatusForbidden)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestServeMuxRateLimit(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.HTTPRateLimit = time.Minute
+	mux, err := p.NewServeMux("_blackfire")
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/enable", nil)), Equals, http.StatusOK)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/enable", nil)), Equals, http.StatusTooManyRequests)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/profile", nil)), Equals, http.StatusTooManyRequests)
+	// A profile just started can always be stopped.
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/disable", nil)), Equals, http.StatusOK)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/end", nil)), Equals, http.StatusOK)
+	// Read-only endpoints are not rate limited.
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/dashboard_api", nil)), Equals, http.StatusOK)
+
+	clock.Advance(time.Minute)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/enable", nil)), Equals, http.StatusOK)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/end", nil)), Equals, http.StatusOK)
+}
+
+func (s *BlackfireSuite) TestServeMuxOptions(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.configuration.DefaultProfileDuration = 2 * time.Minute
+	mux, err := NewServeMuxFor(p,
+		MuxPrefix("/_blackfire/"),
+		MuxMethods("POST"),
+		MuxMaxDuration(time.Minute),
+		MuxTitleValidator(func(title string) error {
+			if strings.ContainsAny(title, "<>") {
+				return errors.New("invalid characters")
+			}
+			return nil
+		}),
+	)
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/enable", nil)), Equals, http.StatusMethodNotAllowed)
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/_blackfire/enable?duration=120", nil)), Equals, http.StatusBadRequest)
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/_blackfire/enable?title=%3Cscript%3E", nil)), Equals, http.StatusBadRequest)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	// Read-only endpoints still accept GET requests.
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/_blackfire/dashboard_api", nil)), Equals, http.StatusOK)
+
+	// The default duration of the requests without one is capped to the
+	// maximum duration.
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/_blackfire/enable?title=ok", nil)), Equals, http.StatusOK)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	p.clock.(*fakeClock).Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+}
+
+func (s *BlackfireSuite) TestEnableHandlerDefaultDuration(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.DefaultProfileDuration = 20 * time.Second
+	mux, err := NewServeMuxFor(p)
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/enable", nil)), Equals, http.StatusOK)
+	c.Assert(p.requestedDuration, Equals, 20*time.Second)
+	clock.Advance(20 * time.Second)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.End(), IsNil)
+
+	// The duration parameter overrides it.
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/enable?duration=45", nil)), Equals, http.StatusOK)
+	c.Assert(p.requestedDuration, Equals, 45*time.Second)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestEnableHandlerDurationValidation(c *C) {
+	p := newTestProbe(newFakeClock())
+	mux, err := NewServeMuxFor(p)
+	c.Assert(err, IsNil)
+
+	for duration, detail := range map[string]string{
+		"abc":  `duration "abc" is not a number of seconds`,
+		"":     `duration "" is not a number of seconds`,
+		"NaN":  `duration "NaN" is not a number of seconds`,
+		"-Inf": `duration "-Inf" is not a number of seconds`,
+		"-5":   "duration must be positive, got -5",
+		"0":    "duration must be positive, got 0",
+		"601":  "duration cannot exceed the maximum profile duration of 10m0s",
+		"1e20": "duration cannot exceed the maximum profile duration of 10m0s",
+	} {
+		comment := Commentf("duration=%s", duration)
+		recorder := httptest.NewRecorder()
+		mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/enable?duration="+url.QueryEscape(duration), nil))
+		c.Assert(recorder.Code, Equals, http.StatusBadRequest, comment)
+		c.Assert(recorder.Header().Get("Content-Type"), Equals, "application/problem+json", comment)
+		var result problem
+		c.Assert(json.Unmarshal(recorder.Body.Bytes(), &result), IsNil, comment)
+		c.Assert(result, Equals, problem{Status: 400, Title: "Wrong duration", Detail: detail}, comment)
+	}
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/enable?duration=600", nil)), Equals, http.StatusOK)
+	c.Assert(p.requestedDuration, Equals, 10*time.Minute)
+	c.Assert(p.End(), IsNil)
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/enable?duration=0.5", nil)), Equals, http.StatusOK)
+	c.Assert(p.requestedDuration, Equals, 500*time.Millisecond)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestDisableAndEndHandlers(c *C) {
+	p := newTestProbe(newFakeClock())
+	mux, err := NewServeMuxFor(p)
+	c.Assert(err, IsNil)
+
+	// Nothing to disable or end.
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/disable", nil))
+	c.Assert(recorder.Code, Equals, http.StatusInternalServerError)
+	c.Assert(recorder.Header().Get("Content-Type"), Equals, "application/problem+json")
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/end", nil)), Equals, http.StatusInternalServerError)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/enable?duration=10", nil)), Equals, http.StatusOK)
+	recorder = httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/disable", nil))
+	c.Assert(recorder.Code, Equals, http.StatusOK)
+	var status dashboardStatus
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &status), IsNil)
+	c.Assert(status.Profiling.State, Equals, "disabled")
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/end", nil)), Equals, http.StatusOK)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestProfileHandler(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	mux, err := NewServeMuxFor(p, MuxPrefix("_blackfire"))
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/_blackfire/profile", nil)), Equals, http.StatusBadRequest)
+
+	recorder := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/_blackfire/profile?duration=10&title=sync", nil))
+		close(done)
+	}()
+	c.Assert(waitForState(p, profilerStateEnabled), Equals, profilerStateEnabled)
+	clock.Advance(10 * time.Second)
+	<-done
+
+	var result profileResult
+	c.Assert(recorder.Code, Equals, http.StatusOK)
+	c.Assert(json.Unmarshal(recorder.Body.Bytes(), &result), IsNil)
+	c.Assert(result.Title, Equals, "sync")
+	c.Assert(result.Duration, Equals, 10.0)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestEndHandlerNoWait(c *C) {
+	p := newTestProbe(newFakeClock())
+	mux, err := NewServeMuxFor(p, MuxPrefix("_blackfire"))
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("POST", "/_blackfire/end?wait=maybe", nil)), Equals, http.StatusBadRequest)
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	recorder := httptest.NewRecorder()
+	mux.ServeHTTP(recorder, httptest.NewRequest("POST", "/_blackfire/end?wait=false", nil))
+	c.Assert(recorder.Code, Equals, http.StatusAccepted)
+	c.Assert(recorder.Header().Get("Location"), Equals, "/_blackfire/status")
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestEnableHandlerProfileOptions(c *C) {
+	p := newTestProbe(newFakeClock())
+	mux, err := NewServeMuxFor(p)
+	c.Assert(err, IsNil)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/enable?sample_rate=fast", nil)), Equals, http.StatusBadRequest)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/enable?tag=env", nil)), Equals, http.StatusBadRequest)
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/enable?dimensions=cpu,disk", nil)), Equals, http.StatusBadRequest)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	c.Assert(serveForTest(mux, httptest.NewRequest("GET", "/enable?sample_rate=200&tag=env:prod&dimensions=cpu,network", nil)), Equals, http.StatusOK)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	c.Assert(p.profileOptions, DeepEquals, ProfileOptions{
+		CPUSampleRateHz: 200,
+		Tags:            map[string]string{"env": "prod"},
+		Dimensions:      []string{DimensionCPU, DimensionNetwork},
+	})
+	c.Assert(p.End(), IsNil)
+}
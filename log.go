@@ -1,19 +1,34 @@
 package blackfire
 
 import (
+	"fmt"
 	"io"
-	"log"
 	"os"
 	"strconv"
 
 	"github.com/rs/zerolog"
 )
 
+// NewLogger returns a logger writing to the file at path (or to "stdout",
+// "stderr", "journald" for the systemd journal on Linux, or "eventlog" for the
+// Windows Event Log), up to the level (1 for errors to 4 for debug messages).
+// If the file can't be opened, it logs the error to stderr, and keeps logging
+// there instead.
 func NewLogger(path string, level int) zerolog.Logger {
-	return zerolog.New(logWriter(path)).Level(logLevel(level)).With().Timestamp().Logger()
+	logger, _ := newLogger(path, level)
+	return logger
 }
 
+// NewLoggerFromEnvVars returns a logger configured by the BLACKFIRE_LOG_FILE
+// and BLACKFIRE_LOG_LEVEL env vars, like NewLogger.
 func NewLoggerFromEnvVars() zerolog.Logger {
+	logger, _ := newLoggerFromEnvVars()
+	return logger
+}
+
+// newLoggerFromEnvVars is like NewLoggerFromEnvVars, but also returns the
+// error opening the log file, if any.
+func newLoggerFromEnvVars() (zerolog.Logger, error) {
 	level := 1
 	if v := os.Getenv("BLACKFIRE_LOG_LEVEL"); v != "" {
 		level, _ = strconv.Atoi(v)
@@ -22,7 +37,16 @@ func NewLoggerFromEnvVars() zerolog.Logger {
 	if v := os.Getenv("BLACKFIRE_LOG_FILE"); v != "" {
 		path = v
 	}
-	return zerolog.New(logWriter(path)).Level(logLevel(level)).With().Timestamp().Logger()
+	return newLogger(path, level)
+}
+
+func newLogger(path string, level int) (zerolog.Logger, error) {
+	writer, err := logWriter(path)
+	logger := zerolog.New(writer).Level(logLevel(level)).With().Timestamp().Logger()
+	if err != nil {
+		logger.Error().Err(err).Msg("Blackfire: Logging to stderr instead")
+	}
+	return logger, err
 }
 
 func logLevel(level int) zerolog.Level {
@@ -41,16 +65,27 @@ func logLevel(level int) zerolog.Level {
 	return levels[level]
 }
 
-func logWriter(path string) io.Writer {
+// logWriter opens the log file (or connects to the journal or the event log),
+// falling back to stderr if it can't.
+func logWriter(path string) (io.Writer, error) {
 	if path == "" || path == "stderr" {
-		return os.Stderr
+		return os.Stderr, nil
 	}
 	if path == "stdout" {
-		return os.Stdout
+		return os.Stdout, nil
+	}
+	var writer io.Writer
+	var err error
+	switch path {
+	case "journald":
+		writer, err = newJournaldWriter()
+	case "eventlog":
+		writer, err = newEventlogWriter()
+	default:
+		writer, err = os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0664)
 	}
-	writer, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0664)
 	if err != nil {
-		log.Fatalf("could not open log file at %s: %v", path, err)
+		return os.Stderr, fmt.Errorf("Could not open log file at %s: %v", path, err)
 	}
-	return writer
+	return writer, nil
 }
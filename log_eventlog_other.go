@@ -0,0 +1,13 @@
+//go:build !windows
+// +build !windows
+
+package blackfire
+
+import (
+	"errors"
+	"io"
+)
+
+func newEventlogWriter() (io.Writer, error) {
+	return nil, errors.New("the event log is only available on Windows")
+}
@@ -0,0 +1,71 @@
+//go:build windows
+// +build windows
+
+package blackfire
+
+import (
+	"bytes"
+	"io"
+	"syscall"
+	"unsafe"
+
+	"github.com/rs/zerolog"
+)
+
+// The event types of ReportEvent.
+const (
+	eventlogErrorType       = 0x0001
+	eventlogWarningType     = 0x0002
+	eventlogInformationType = 0x0004
+)
+
+var (
+	advapi32                = syscall.NewLazyDLL("advapi32.dll")
+	procRegisterEventSource = advapi32.NewProc("RegisterEventSourceW")
+	procReportEvent         = advapi32.NewProc("ReportEventW")
+)
+
+// eventlogSource is the source of the events reported by the probe.
+const eventlogSource = "Blackfire"
+
+// eventlogWriter reports the log entries to the Windows Event Log.
+type eventlogWriter struct {
+	handle uintptr
+}
+
+func newEventlogWriter() (io.Writer, error) {
+	source, err := syscall.UTF16PtrFromString(eventlogSource)
+	if err != nil {
+		return nil, err
+	}
+	handle, _, err := procRegisterEventSource.Call(0, uintptr(unsafe.Pointer(source)))
+	if handle == 0 {
+		return nil, err
+	}
+	return &eventlogWriter{handle: handle}, nil
+}
+
+func (w *eventlogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel reports an event of the type of the level.
+func (w *eventlogWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	message, err := syscall.UTF16PtrFromString(string(bytes.TrimRight(p, "\n")))
+	if err != nil {
+		return 0, err
+	}
+	eventType := eventlogInformationType
+	switch level {
+	case zerolog.WarnLevel:
+		eventType = eventlogWarningType
+	case zerolog.ErrorLevel, zerolog.FatalLevel, zerolog.PanicLevel:
+		eventType = eventlogErrorType
+	}
+	strings := []*uint16{message}
+	ok, _, err := procReportEvent.Call(w.handle, uintptr(eventType), 0, 1, 0, 1, 0, uintptr(unsafe.Pointer(&strings[0])), 0)
+	if ok == 0 {
+		return 0, err
+	}
+	return len(p), nil
+}
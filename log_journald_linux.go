@@ -0,0 +1,75 @@
+//go:build linux
+// +build linux
+
+package blackfire
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+)
+
+// journaldSocket is where journald receives the entries of the native
+// protocol.
+const journaldSocket = "/run/systemd/journal/socket"
+
+// journaldWriter sends the log entries to the systemd journal.
+type journaldWriter struct {
+	conn       *net.UnixConn
+	identifier string
+}
+
+func newJournaldWriter() (io.Writer, error) {
+	conn, err := net.DialUnix("unixgram", nil, &net.UnixAddr{Name: journaldSocket, Net: "unixgram"})
+	if err != nil {
+		return nil, err
+	}
+	return &journaldWriter{
+		conn:       conn,
+		identifier: filepath.Base(os.Args[0]),
+	}, nil
+}
+
+func (w *journaldWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+// WriteLevel sends an entry with the syslog priority of the level.
+func (w *journaldWriter) WriteLevel(level zerolog.Level, p []byte) (int, error) {
+	var entry bytes.Buffer
+	entry.WriteString("PRIORITY=" + journaldPriority(level) + "\n")
+	entry.WriteString("SYSLOG_IDENTIFIER=" + w.identifier + "\n")
+	// The message may contain newlines: it is sent in the binary form,
+	// prefixed with its size.
+	message := bytes.TrimRight(p, "\n")
+	entry.WriteString("MESSAGE\n")
+	binary.Write(&entry, binary.LittleEndian, uint64(len(message)))
+	entry.Write(message)
+	entry.WriteByte('\n')
+	if _, err := w.conn.Write(entry.Bytes()); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// journaldPriority returns the syslog priority of a level.
+func journaldPriority(level zerolog.Level) string {
+	switch level {
+	case zerolog.DebugLevel:
+		return "7"
+	case zerolog.InfoLevel:
+		return "6"
+	case zerolog.WarnLevel:
+		return "4"
+	case zerolog.ErrorLevel:
+		return "3"
+	case zerolog.FatalLevel, zerolog.PanicLevel:
+		return "2"
+	}
+	return "6"
+}
@@ -0,0 +1,13 @@
+//go:build !linux
+// +build !linux
+
+package blackfire
+
+import (
+	"errors"
+	"io"
+)
+
+func newJournaldWriter() (io.Writer, error) {
+	return nil, errors.New("journald is only available on Linux")
+}
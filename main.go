@@ -0,0 +1,73 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/blackfireio/go-blackfire/bf_format"
+)
+
+// Main runs the main function of a short-lived program, such as a CLI, and
+// exits with the status code it returns, once the profile is uploaded (see
+// Probe.RunMain).
+//
+//	func main() {
+//		blackfire.Main(run)
+//	}
+func Main(main func() int) {
+	os.Exit(globalProbe.RunMain(main))
+}
+
+// RunMain runs the main function of a short-lived program with the global
+// probe, and returns its status code (see Probe.RunMain).
+func RunMain(main func() int) int {
+	return globalProbe.RunMain(main)
+}
+
+// RunMain runs the main function of a short-lived program, profiling it when
+// it is launched with `blackfire run` (see Enable), and returns its status
+// code once the profile is uploaded. The profile is also uploaded when main
+// panics, tagged like with CapturePanic, before the panic goes on. The
+// profile is titled after the command line, its sensitive arguments being
+// masked, unless a title was set. main must return instead of calling
+// os.Exit, which would exit before the upload. When the program is not
+// launched with `blackfire run`, main just runs.
+func (p *Probe) RunMain(main func() int) int {
+	if err := p.configuration.load(); err != nil {
+		p.configuration.Logger.Debug().Err(err).Msg("Blackfire: Not profiling the program")
+	} else if p.configuration.BlackfireQuery != "" {
+		p.mutex.Lock()
+		if p.currentTitle == "" {
+			p.currentTitle = commandLineTitle(p.configuration)
+		}
+		p.mutex.Unlock()
+		if err := p.Enable(); err != nil {
+			p.configuration.Logger.Error().Err(err).Msg("Blackfire: Unable to enable profiling")
+		}
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			p.endPanicked(r)
+			panic(r)
+		}
+	}()
+	code := main()
+	p.End()
+	return code
+}
+
+// commandLineTitle returns the command line of the program, the values of the
+// sensitive arguments being masked.
+func commandLineTitle(configuration *Configuration) string {
+	args := bf_format.MaskArgs(os.Args, configuration.sensitiveArgs)
+	if len(args) == 0 {
+		return ""
+	}
+	args[0] = filepath.Base(args[0])
+	return strings.Join(args, " ")
+}
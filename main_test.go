@@ -0,0 +1,40 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"os"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProbeRunMain(c *C) {
+	// Outside of `blackfire run`, main just runs.
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.RunMain(func() int { return 3 }), Equals, 3)
+	c.Assert(p.currentTitle, Equals, "")
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	p = newTestProbe(newFakeClock())
+	p.configuration.BlackfireQuery = "expires=9999999999&signature=abc"
+	c.Assert(p.RunMain(func() int { return 3 }), Equals, 3)
+	c.Assert(p.currentTitle, Not(Equals), "")
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	c.Assert(func() {
+		p.RunMain(func() int { panic("boom") })
+	}, PanicMatches, "boom")
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestCommandLineTitle(c *C) {
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.configuration.load(), IsNil)
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	os.Args = []string{"/usr/local/bin/tool", "import", "--token", "secret", "file.csv"}
+	c.Assert(commandLineTitle(p.configuration), Equals, "tool import --token *** file.csv")
+	c.Assert(os.Args[0], Equals, "/usr/local/bin/tool")
+}
@@ -0,0 +1,147 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"regexp"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// Counter is a custom metric, such as cache hits or rows scanned. While
+// profiling, the amounts added to it are accounted to the calling functions
+// and show up as an extra cost dimension of the profile, next to the CPU time
+// and memory.
+type Counter struct {
+	probe *Probe
+	name  string
+}
+
+var invalidMetricNameChars = regexp.MustCompile(`[^A-Za-z0-9_.]+`)
+
+// Metric returns the counter of the global probe with the specified name
+// (see Probe.Metric).
+func Metric(name string) *Counter {
+	return globalProbe.Metric(name)
+}
+
+// Metric returns the counter with the specified name. Characters other than
+// letters, digits, underscores and dots are replaced with underscores in the
+// name.
+func (p *Probe) Metric(name string) *Counter {
+	return &Counter{
+		probe: p,
+		name:  invalidMetricNameChars.ReplaceAllString(name, "_"),
+	}
+}
+
+// Name returns the name of the counter, as it appears in profiles.
+func (c *Counter) Name() string {
+	return c.name
+}
+
+// Add adds n to the counter. Nothing is recorded when not profiling. The call
+// stacks are sampled: the updates in between are accounted to the next one
+// taken.
+func (c *Counter) Add(n uint64) {
+	c.probe.recordMetric(c.name, n)
+}
+
+// metricKey identifies the updates of a metric from a call stack.
+type metricKey struct {
+	name  string
+	stack callStack
+}
+
+// metricRecord accumulates the updates of a metric from a call stack.
+type metricRecord struct {
+	count int
+	value uint64
+}
+
+// metricSampler returns the sampler of the updates of a metric.
+func (p *Probe) metricSampler(name string) *callSampler {
+	if sampler, ok := p.metricSamplers.Load(name); ok {
+		return sampler.(*callSampler)
+	}
+	sampler, _ := p.metricSamplers.LoadOrStore(name, &callSampler{})
+	return sampler.(*callSampler)
+}
+
+func (p *Probe) recordMetric(name string, n uint64) {
+	if atomic.LoadInt32(&p.recordingMetrics) == 0 {
+		return
+	}
+	sampler := p.metricSampler(name)
+	if !sampler.add(n, 0) {
+		return
+	}
+	key := metricKey{name: name}
+	// Skip runtime.Callers, recordMetric and Counter.Add.
+	runtime.Callers(3, key.stack[:])
+
+	p.metricsMutex.Lock()
+	defer p.metricsMutex.Unlock()
+	if p.lastMetricStacks == nil {
+		p.lastMetricStacks = make(map[string]callStack)
+	}
+	p.lastMetricStacks[name] = key.stack
+	p.addMetricRecord(key, sampler)
+}
+
+// addMetricRecord accounts the updates recorded since the previous call stack
+// taken to the specified one.
+func (p *Probe) addMetricRecord(key metricKey, sampler *callSampler) {
+	count, value, _ := sampler.take()
+	if count == 0 {
+		return
+	}
+	if p.metrics == nil {
+		p.metrics = make(map[metricKey]*metricRecord)
+	}
+	record, ok := p.metrics[key]
+	if !ok {
+		record = &metricRecord{}
+		p.metrics[key] = record
+	}
+	record.count += count
+	record.value += value
+}
+
+// setRecordingMetrics starts or stops accounting the updates of the counters.
+func (p *Probe) setRecordingMetrics(recording bool) {
+	value := int32(0)
+	if recording {
+		value = 1
+	}
+	atomic.StoreInt32(&p.recordingMetrics, value)
+}
+
+// takeMetricRecords returns the metric updates recorded so far, and resets
+// them.
+func (p *Probe) takeMetricRecords() map[metricKey]*metricRecord {
+	p.metricsMutex.Lock()
+	defer p.metricsMutex.Unlock()
+	p.metricSamplers.Range(func(name, sampler interface{}) bool {
+		// The updates since the last call stack taken are accounted to it.
+		if stack, ok := p.lastMetricStacks[name.(string)]; ok {
+			p.addMetricRecord(metricKey{name: name.(string), stack: stack}, sampler.(*callSampler))
+		}
+		sampler.(*callSampler).reset()
+		return true
+	})
+	metrics := p.metrics
+	p.metrics = nil
+	p.lastMetricStacks = nil
+	return metrics
+}
+
+// addMetricSamples adds the metric updates recorded so far to the profile.
+func (p *Probe) addMetricSamples(profile *pprof_reader.Profile) {
+	for key, record := range p.takeMetricRecords() {
+		profile.AddMetricSample(key.stack.frames(), key.name, record.count, record.value)
+	}
+}
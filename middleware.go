@@ -0,0 +1,183 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// blackfireQueryHeader is the header set by the Blackfire CLI and browser
+// extension on the requests to profile.
+const blackfireQueryHeader = "X-Blackfire-Query"
+
+// Middleware profiles the HTTP requests triggered from Blackfire (blackfire
+// curl, browser extension) with the global probe.
+func Middleware(next http.Handler) http.Handler {
+	return globalProbe.Middleware(next)
+}
+
+// Middleware profiles the HTTP requests triggered from Blackfire (blackfire
+// curl, browser extension): a request carrying a Blackfire query is profiled
+// while it is being handled, and the profile is uploaded once it is done. The
+// profile is titled after the request method and path (see
+// Configuration.MiddlewareTitleTemplate), and tagged with the
+// request headers listed in Configuration.MiddlewareTagHeaders.
+// Requests received while the probe is already profiling are not profiled.
+func (p *Probe) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.Header.Get(blackfireQueryHeader)
+		if query == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		err := p.enableForQuery(query, ProfileOptions{
+			Title: r.Method + " " + r.URL.Path,
+			Tags:  p.requestTags(r),
+		})
+		logger := p.configuration.Logger
+		if err == ProfilerErrorAlreadyProfiling {
+			logger.Debug().Msgf("Blackfire (middleware): Not profiling %s %s: %v", r.Method, r.URL.Path, err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		if err != nil {
+			logger.Error().Msgf("Blackfire (middleware): %v", err)
+			next.ServeHTTP(w, r)
+			return
+		}
+		p.setProfileRequest(r)
+		start := p.clock.Now()
+		capture := p.configuration.MiddlewareCapture
+		var recorder *statusRecorder
+		if capture != nil && capture.StatusCode {
+			recorder = &statusRecorder{ResponseWriter: w}
+			w = recorder
+		}
+		defer func() {
+			var recovered interface{}
+			if p.configuration.MiddlewareCapturePanics {
+				if recovered = recover(); recovered != nil {
+					p.tagPanic(recovered)
+				}
+			}
+			if capture != nil {
+				p.setProfileContext(capture.context(r, recorder, p.clock.Now().Sub(start)))
+			}
+			if err := p.End(); err != nil {
+				logger.Error().Msgf("Blackfire (middleware): %v", err)
+			}
+			if recovered != nil {
+				panic(recovered)
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
+
+// setProfileRequest records the request being profiled, for the title
+// templates.
+func (p *Probe) setProfileRequest(r *http.Request) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.profileMethod = r.Method
+	p.profileRoute = r.URL.Path
+}
+
+// setProfileContext sets the context of the current profile, sent in its
+// Context header.
+func (p *Probe) setProfileContext(context string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.profileContext = context
+}
+
+// context returns the captured attributes of the request, URL-encoded.
+func (c *RequestCapture) context(r *http.Request, recorder *statusRecorder, duration time.Duration) string {
+	maxSize := c.MaxSize
+	if maxSize <= 0 {
+		maxSize = 1024
+	}
+	var context strings.Builder
+	add := func(name, value string) {
+		if c.Redact != nil {
+			value = c.Redact(name, value)
+		}
+		if value == "" {
+			return
+		}
+		attribute := url.QueryEscape("request_"+name) + "=" + url.QueryEscape(value)
+		if context.Len() > 0 {
+			attribute = "&" + attribute
+		}
+		if context.Len()+len(attribute) > maxSize {
+			return
+		}
+		context.WriteString(attribute)
+	}
+	if c.Method {
+		add("method", r.Method)
+	}
+	if c.Path {
+		add("path", r.URL.Path)
+	}
+	if c.StatusCode && recorder != nil {
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		add("status_code", strconv.Itoa(status))
+	}
+	if c.Duration {
+		add("duration", duration.String())
+	}
+	return context.String()
+}
+
+// statusRecorder records the status code of a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	if r.status == 0 {
+		r.status = status
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(data []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(data)
+}
+
+// requestTags returns the tags identifying the request in the profile: the
+// values of the MiddlewareTagHeaders, and the trace ID of a W3C traceparent
+// header.
+func (p *Probe) requestTags(r *http.Request) map[string]string {
+	// The configuration errors are reported when enabling the profile.
+	p.configuration.load()
+	tags := make(map[string]string)
+	for _, name := range p.configuration.MiddlewareTagHeaders {
+		value := r.Header.Get(name)
+		if value == "" {
+			continue
+		}
+		tags[strings.ToLower(name)] = value
+		if strings.EqualFold(name, "traceparent") {
+			// version-trace_id-parent_id-flags
+			if fields := strings.Split(value, "-"); len(fields) == 4 && len(fields[1]) == 32 {
+				tags["trace_id"] = fields[1]
+			}
+		}
+	}
+	return tags
+}
@@ -0,0 +1,201 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"net"
+	"runtime"
+	"sync/atomic"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// maxCallStackDepth is the maximum depth of the call stacks network traffic
+// and custom metrics are accounted to.
+const maxCallStackDepth = 64
+
+// callSampleInterval is the number of network I/Os or metric updates per
+// call stack taken. Taking a call stack on each call is too costly on hot
+// paths: the calls in between are accounted to the next call stack taken,
+// like the memory profiler of the runtime does with allocations.
+const callSampleInterval = 16
+
+// callStack is the call stack of a network I/O or of a metric update, as
+// program counters.
+type callStack [maxCallStackDepth]uintptr
+
+// callSampler accumulates the amounts of the calls until a call stack is
+// taken to account them to.
+type callSampler struct {
+	calls   uint64
+	pending uint64
+	amounts [2]uint64
+}
+
+// add records a call and its amounts, and tells whether its call stack must
+// be taken. The first call is always sampled.
+func (s *callSampler) add(a, b uint64) bool {
+	atomic.AddUint64(&s.amounts[0], a)
+	atomic.AddUint64(&s.amounts[1], b)
+	atomic.AddUint64(&s.pending, 1)
+	return atomic.AddUint64(&s.calls, 1)%callSampleInterval == 1
+}
+
+// take returns the number of calls and their amounts recorded since the
+// previous take.
+func (s *callSampler) take() (calls int, a, b uint64) {
+	calls = int(atomic.SwapUint64(&s.pending, 0))
+	a = atomic.SwapUint64(&s.amounts[0], 0)
+	b = atomic.SwapUint64(&s.amounts[1], 0)
+	return
+}
+
+// reset forgets the calls recorded so far, for the first call of the next
+// profile to be sampled.
+func (s *callSampler) reset() {
+	s.take()
+	atomic.StoreUint64(&s.calls, 0)
+}
+
+// networkRecord accumulates the network traffic of a call stack.
+type networkRecord struct {
+	count int
+	in    uint64
+	out   uint64
+}
+
+// WrapConn wraps a network connection so that its traffic is accounted in
+// the profiles of the global probe (see Probe.WrapConn).
+func WrapConn(conn net.Conn) net.Conn {
+	return globalProbe.WrapConn(conn)
+}
+
+// WrapConn wraps a network connection so that, when the server requests the
+// network dimension (the flag_nw option), the bytes read and written while
+// profiling are accounted to the call stacks doing the I/O. The call stacks
+// are sampled: the I/Os in between are accounted to the next one taken.
+func (p *Probe) WrapConn(conn net.Conn) net.Conn {
+	return &profiledConn{Conn: conn, probe: p}
+}
+
+type profiledConn struct {
+	net.Conn
+	probe *Probe
+}
+
+func (c *profiledConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if n > 0 {
+		c.probe.recordNetwork(uint64(n), 0)
+	}
+	return n, err
+}
+
+func (c *profiledConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if n > 0 {
+		c.probe.recordNetwork(0, uint64(n))
+	}
+	return n, err
+}
+
+func (p *Probe) recordNetwork(in, out uint64) {
+	if atomic.LoadInt32(&p.recordingNetwork) == 0 {
+		return
+	}
+	if !p.networkSampler.add(in, out) {
+		return
+	}
+	var stack callStack
+	// Skip runtime.Callers, recordNetwork and the Read or Write method.
+	runtime.Callers(3, stack[:])
+
+	p.networkMutex.Lock()
+	defer p.networkMutex.Unlock()
+	p.lastNetworkStack = stack
+	p.addNetworkRecord(stack)
+}
+
+// addNetworkRecord accounts the calls recorded since the previous call stack
+// taken to the specified one.
+func (p *Probe) addNetworkRecord(stack callStack) {
+	count, in, out := p.networkSampler.take()
+	if count == 0 {
+		return
+	}
+	if p.network == nil {
+		p.network = make(map[callStack]*networkRecord)
+	}
+	record, ok := p.network[stack]
+	if !ok {
+		record = &networkRecord{}
+		p.network[stack] = record
+	}
+	record.count += count
+	record.in += in
+	record.out += out
+}
+
+// setRecordingNetwork starts or stops accounting the network traffic of the
+// wrapped connections.
+func (p *Probe) setRecordingNetwork(recording bool) {
+	value := int32(0)
+	if recording {
+		value = 1
+	}
+	atomic.StoreInt32(&p.recordingNetwork, value)
+}
+
+// takeNetworkRecords returns the network traffic recorded so far, and resets
+// it.
+func (p *Probe) takeNetworkRecords() map[callStack]*networkRecord {
+	p.networkMutex.Lock()
+	defer p.networkMutex.Unlock()
+	if p.network != nil {
+		// The calls since the last call stack taken are accounted to it.
+		p.addNetworkRecord(p.lastNetworkStack)
+	}
+	p.networkSampler.reset()
+	network := p.network
+	p.network = nil
+	return network
+}
+
+// addNetworkSamples adds the network traffic recorded so far to the profile.
+func (p *Probe) addNetworkSamples(profile *pprof_reader.Profile) {
+	for stack, record := range p.takeNetworkRecords() {
+		profile.AddNetworkSample(stack.frames(), record.count, record.in, record.out)
+	}
+}
+
+// frames returns the frames of the call stack, root first as in profiles.
+func (s callStack) frames() []pprof_reader.StackFrame {
+	pcs := s[:]
+	for i, pc := range s {
+		if pc == 0 {
+			pcs = s[:i]
+			break
+		}
+	}
+
+	var stack []pprof_reader.StackFrame
+	frames := runtime.CallersFrames(pcs)
+	for {
+		frame, more := frames.Next()
+		if frame.Function != "" {
+			stack = append(stack, pprof_reader.StackFrame{
+				Function: frame.Function,
+				File:     frame.File,
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	for i, j := 0, len(stack)-1; i < j; i, j = i+1, j-1 {
+		stack[i], stack[j] = stack[j], stack[i]
+	}
+	return stack
+}
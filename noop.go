@@ -0,0 +1,255 @@
+//go:build blackfire_noop
+// +build blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/bf_format"
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// This file replaces the probe with no-ops when building with the
+// blackfire_noop build tag: the API calls can stay in the code, but nothing
+// is ever profiled, no goroutine is started and no connection is made.
+
+var errNoop = errors.New("The Blackfire probe is not available (built with the blackfire_noop tag).")
+
+// Probe does nothing when building with the blackfire_noop tag.
+type Probe struct{}
+
+type noopEnder struct{}
+
+func (noopEnder) End()       {}
+func (noopEnder) EndNoWait() {}
+
+var globalProbe = &Probe{}
+
+func NewProbe(config *Configuration) *Probe {
+	return &Probe{}
+}
+
+func GlobalProbe() *Probe {
+	return globalProbe
+}
+
+func ProfilerStates() []string {
+	return []string{"off"}
+}
+
+func Configure(config *Configuration)           {}
+func AutoEnable()                               {}
+func IsProfiling() bool                         { return false }
+func EnableNowFor(duration time.Duration) Ender { return noopEnder{} }
+func EnableNowForWithOptions(duration time.Duration, options ProfileOptions) Ender {
+	return noopEnder{}
+}
+func WaitAndEnable(ctx context.Context, duration time.Duration) (Ender, error) {
+	return noopEnder{}, nil
+}
+func EnableWithOptions(options ProfileOptions) Ender { return noopEnder{} }
+func ProfileStartup(duration time.Duration) Ender    { return noopEnder{} }
+func EnableNow() Ender                               { return noopEnder{} }
+func Enable() Ender                                  { return noopEnder{} }
+func Disable()                                       {}
+func Pause() error                                   { return nil }
+func Resume() error                                  { return nil }
+func End()                                           {}
+func EndNoWait()                                     {}
+func EndToFile(path string) error                    { return nil }
+func RetryUpload() error                             { return nil }
+func GenerateSubProfileQuery() (string, error)       { return "", errNoop }
+func CurrentProbeOptions() bf_format.ProbeOptions    { return bf_format.ProbeOptions{} }
+func SetCurrentTitle(title string)                   {}
+func CurrentProfile() (*Profile, error)              { return nil, nil }
+func CachedProfile() *Profile                        { return nil }
+func GetProfile(ctx context.Context, uuid string) (*Profile, error) {
+	return nil, errNoop
+}
+func AssertProfile(ctx context.Context, uuid string, check func(Envelope) error) error {
+	return errNoop
+}
+func ProfileWithCallback(duration time.Duration, callback func(ProfileResult)) error {
+	return nil
+}
+func Stats() ProbeStats                                       { return ProbeStats{} }
+func Sinks() []SinkStats                                      { return nil }
+func GoroutineCPU() []pprof_reader.GoroutineCPU               { return nil }
+func State() string                                           { return "off" }
+func StopReason() string                                      { return "" }
+func LintBlackfireYaml() error                                { return nil }
+func CheckAgent(ctx context.Context) (*AgentDiagnosis, error) { return nil, errNoop }
+
+func ProfileGoroutine(ctx context.Context, label string) context.Context {
+	return ctx
+}
+
+func (p *Probe) Configure(config *Configuration)           {}
+func (p *Probe) IsProfiling() bool                         { return false }
+func (p *Probe) EnableNowFor(duration time.Duration) error { return nil }
+func (p *Probe) EnableNowForWithOptions(duration time.Duration, options ProfileOptions) error {
+	return nil
+}
+func (p *Probe) WaitAndEnable(ctx context.Context, duration time.Duration) error { return nil }
+func (p *Probe) EnableWithOptions(options ProfileOptions) error                  { return nil }
+func (p *Probe) ProfileStartup(duration time.Duration) error                     { return nil }
+func (p *Probe) EnableNow() error                                                { return nil }
+func (p *Probe) Enable() error                                                   { return nil }
+func (p *Probe) Disable() error                                                  { return nil }
+func (p *Probe) Pause() error                                                    { return nil }
+func (p *Probe) Resume() error                                                   { return nil }
+func (p *Probe) End() error                                                      { return nil }
+func (p *Probe) EndNoWait() error                                                { return nil }
+func (p *Probe) EndToFile(path string) error                                     { return nil }
+func (p *Probe) RetryUpload() error                                              { return nil }
+func (p *Probe) GenerateSubProfileQuery() (string, error)                        { return "", errNoop }
+func (p *Probe) CurrentProfile() (*Profile, error)                               { return nil, nil }
+func (p *Probe) CachedProfile() *Profile                                         { return nil }
+func (p *Probe) GetProfile(ctx context.Context, uuid string) (*Profile, error) {
+	return nil, errNoop
+}
+func (p *Probe) AssertProfile(ctx context.Context, uuid string, check func(Envelope) error) error {
+	return errNoop
+}
+func (p *Probe) ProfileWithCallback(duration time.Duration, callback func(ProfileResult)) error {
+	return nil
+}
+func (p *Probe) CurrentProbeOptions() bf_format.ProbeOptions             { return bf_format.ProbeOptions{} }
+func (p *Probe) SetCurrentTitle(title string)                            {}
+func (p *Probe) Stats() ProbeStats                                       { return ProbeStats{} }
+func (p *Probe) Sinks() []SinkStats                                      { return nil }
+func (p *Probe) GoroutineCPU() []pprof_reader.GoroutineCPU               { return nil }
+func (p *Probe) State() string                                           { return "off" }
+func (p *Probe) StopReason() string                                      { return "" }
+func (p *Probe) LintBlackfireYaml() error                                { return nil }
+func (p *Probe) CheckAgent(ctx context.Context) (*AgentDiagnosis, error) { return nil, errNoop }
+
+// HTTP: the mux has no endpoints, the handlers respond with 404 and the
+// middleware passes the requests through.
+
+func NewServeMux(prefix string) (*http.ServeMux, error) {
+	return http.NewServeMux(), nil
+}
+
+func NewServeMuxFor(p *Probe, opts ...MuxOption) (*http.ServeMux, error) {
+	return http.NewServeMux(), nil
+}
+
+func (p *Probe) NewServeMux(prefix string) (*http.ServeMux, error) {
+	return http.NewServeMux(), nil
+}
+
+func DashboardHandler(w http.ResponseWriter, r *http.Request)    { http.NotFound(w, r) }
+func DashboardApiHandler(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }
+func StatusHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func HealthHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func EnableHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func DisableHandler(w http.ResponseWriter, r *http.Request)      { http.NotFound(w, r) }
+func EndHandler(w http.ResponseWriter, r *http.Request)          { http.NotFound(w, r) }
+func ProfileHandler(w http.ResponseWriter, r *http.Request)      { http.NotFound(w, r) }
+func EventsHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+
+func (p *Probe) DashboardHandler(w http.ResponseWriter, r *http.Request)    { http.NotFound(w, r) }
+func (p *Probe) DashboardApiHandler(w http.ResponseWriter, r *http.Request) { http.NotFound(w, r) }
+func (p *Probe) StatusHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func (p *Probe) HealthHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func (p *Probe) EnableHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+func (p *Probe) DisableHandler(w http.ResponseWriter, r *http.Request)      { http.NotFound(w, r) }
+func (p *Probe) EndHandler(w http.ResponseWriter, r *http.Request)          { http.NotFound(w, r) }
+func (p *Probe) ProfileHandler(w http.ResponseWriter, r *http.Request)      { http.NotFound(w, r) }
+func (p *Probe) EventsHandler(w http.ResponseWriter, r *http.Request)       { http.NotFound(w, r) }
+
+// Counter is a custom metric that is never recorded.
+type Counter struct {
+	name string
+}
+
+func (c *Counter) Name() string { return c.name }
+func (c *Counter) Add(n uint64) {}
+
+func Metric(name string) *Counter            { return &Counter{name: name} }
+func (p *Probe) Metric(name string) *Counter { return &Counter{name: name} }
+
+func WrapConn(conn net.Conn) net.Conn            { return conn }
+func (p *Probe) WrapConn(conn net.Conn) net.Conn { return conn }
+
+func Middleware(next http.Handler) http.Handler            { return next }
+func (p *Probe) Middleware(next http.Handler) http.Handler { return next }
+
+// Server is never started: its methods are no-ops.
+type Server struct{}
+
+func StartServer(addr string, opts ...MuxOption) (*Server, error) { return nil, nil }
+func StartServerTLS(addr, certFile, keyFile string, opts ...MuxOption) (*Server, error) {
+	return nil, nil
+}
+func (p *Probe) StartServer(addr string, opts ...MuxOption) (*Server, error) { return nil, nil }
+func (p *Probe) StartServerTLS(addr, certFile, keyFile string, opts ...MuxOption) (*Server, error) {
+	return nil, nil
+}
+func (s *Server) Addr() net.Addr                     { return nil }
+func (s *Server) Close() error                       { return nil }
+func (s *Server) Shutdown(ctx context.Context) error { return nil }
+
+// HeapSnapshot is never taken: DiffHeap ignores it.
+type HeapSnapshot struct{}
+
+func SnapshotHeap() (*HeapSnapshot, error)                  { return nil, nil }
+func DiffHeap(before, after *HeapSnapshot) error            { return nil }
+func (p *Probe) SnapshotHeap() (*HeapSnapshot, error)       { return nil, nil }
+func (p *Probe) DiffHeap(before, after *HeapSnapshot) error { return nil }
+
+// Collector is never started: its methods are no-ops.
+type Collector struct{}
+
+func StartCollector(path string) (*Collector, error)            { return nil, nil }
+func (p *Probe) StartCollector(path string) (*Collector, error) { return nil, nil }
+func (c *Collector) Env() []string                              { return os.Environ() }
+func (c *Collector) Close() error                               { return nil }
+
+// Main and RunMain run the main function without profiling it.
+func Main(main func() int)                   { os.Exit(main()) }
+func RunMain(main func() int) int            { return main() }
+func (p *Probe) RunMain(main func() int) int { return main() }
+
+func CapturePanic()            {}
+func (p *Probe) CapturePanic() {}
+
+// SignalHandler is never installed: its methods are no-ops.
+type SignalHandler struct{}
+
+func (h *SignalHandler) Stop() {}
+
+func EnableOnSignal(sig os.Signal, duration time.Duration) error { return nil }
+func EnableOnSignalHandler(sig os.Signal, duration time.Duration) (*SignalHandler, error) {
+	return nil, nil
+}
+func DisableOnSignal(sig os.Signal) error                           { return nil }
+func DisableOnSignalHandler(sig os.Signal) (*SignalHandler, error)  { return nil, nil }
+func EndOnSignal(sig os.Signal) error                               { return nil }
+func EndOnSignalHandler(sig os.Signal) (*SignalHandler, error)      { return nil, nil }
+func ToggleOnSignal(sig os.Signal, maxDuration time.Duration) error { return nil }
+func ToggleOnSignalHandler(sig os.Signal, maxDuration time.Duration) (*SignalHandler, error) {
+	return nil, nil
+}
+func StopSignalHandlers() {}
+
+func (p *Probe) EnableOnSignal(sig os.Signal, duration time.Duration) error { return nil }
+func (p *Probe) EnableOnSignalHandler(sig os.Signal, duration time.Duration) (*SignalHandler, error) {
+	return nil, nil
+}
+func (p *Probe) DisableOnSignal(sig os.Signal) error                           { return nil }
+func (p *Probe) DisableOnSignalHandler(sig os.Signal) (*SignalHandler, error)  { return nil, nil }
+func (p *Probe) EndOnSignal(sig os.Signal) error                               { return nil }
+func (p *Probe) EndOnSignalHandler(sig os.Signal) (*SignalHandler, error)      { return nil, nil }
+func (p *Probe) ToggleOnSignal(sig os.Signal, maxDuration time.Duration) error { return nil }
+func (p *Probe) ToggleOnSignalHandler(sig os.Signal, maxDuration time.Duration) (*SignalHandler, error) {
+	return nil, nil
+}
+func (p *Probe) StopSignalHandlers() {}
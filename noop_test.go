@@ -0,0 +1,46 @@
+//go:build blackfire_noop
+// +build blackfire_noop
+
+package blackfire
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestNoop(t *testing.T) {
+	p := NewProbe(&Configuration{})
+	if err := p.EnableNowFor(time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if p.IsProfiling() {
+		t.Fatal("the no-op probe must never profile")
+	}
+	EnableNow().End()
+
+	handler, err := ToggleOnSignalHandler(os.Interrupt, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler.Stop()
+
+	server, err := StartServer("127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	called := false
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+	Middleware(next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	if !called {
+		t.Fatal("the middleware must pass the requests through")
+	}
+}
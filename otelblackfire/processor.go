@@ -0,0 +1,105 @@
+// Package otelblackfire links OpenTelemetry traces to Blackfire profiles. It
+// lives in its own module so that go-blackfire itself does not depend on
+// OpenTelemetry.
+package otelblackfire
+
+import (
+	"context"
+	"time"
+
+	"github.com/blackfireio/go-blackfire"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// Span attributes identifying the profile recorded while the span ran.
+	ProfileUUIDKey = attribute.Key("blackfire.profile.uuid")
+	ProfileURLKey  = attribute.Key("blackfire.profile.url")
+
+	// ProfileStartEvent is recorded on the spans which started a profile
+	// (see StartProfile). The profile ends with the span.
+	ProfileStartEvent = "blackfire.profile.start"
+)
+
+// SpanProcessor attaches the profile being recorded by a probe to the spans
+// starting while it is profiling, and ends the profiles started by spans.
+type SpanProcessor struct {
+	probe *blackfire.Probe
+}
+
+var _ sdktrace.SpanProcessor = (*SpanProcessor)(nil)
+
+// NewSpanProcessor returns a span processor for the specified probe (use
+// blackfire.GlobalProbe() for the global one).
+func NewSpanProcessor(probe *blackfire.Probe) *SpanProcessor {
+	// Start looking up the profiles as they start.
+	probe.CachedProfile()
+	return &SpanProcessor{
+		probe: probe,
+	}
+}
+
+// OnStart attaches the profile being recorded, if any, to the span. The
+// profile is the one looked up in the background when it started (see
+// blackfire.Probe.CachedProfile), so that starting spans never waits for
+// the probe.
+func (p *SpanProcessor) OnStart(parent context.Context, s sdktrace.ReadWriteSpan) {
+	if !p.probe.IsProfiling() {
+		return
+	}
+	profile := p.probe.CachedProfile()
+	if profile == nil {
+		return
+	}
+	s.SetAttributes(profileAttributes(profile)...)
+}
+
+func (p *SpanProcessor) OnEnd(s sdktrace.ReadOnlySpan) {
+	for _, event := range s.Events() {
+		if event.Name == ProfileStartEvent {
+			p.probe.EndNoWait()
+			return
+		}
+	}
+}
+
+func (p *SpanProcessor) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+func (p *SpanProcessor) ForceFlush(ctx context.Context) error {
+	return nil
+}
+
+// StartProfile profiles the rest of the span in ctx, for at most the specified
+// duration. The profile is titled after the span, and ends with it when the
+// tracer provider uses a SpanProcessor for the same probe.
+func StartProfile(ctx context.Context, probe *blackfire.Probe, duration time.Duration) error {
+	span := trace.SpanFromContext(ctx)
+	options := blackfire.ProfileOptions{}
+	if s, ok := span.(sdktrace.ReadOnlySpan); ok {
+		options.Title = s.Name()
+	}
+	if err := probe.EnableNowForWithOptions(duration, options); err != nil {
+		return err
+	}
+
+	span.AddEvent(ProfileStartEvent)
+	if profile, err := probe.CurrentProfile(); err == nil && profile != nil {
+		span.SetAttributes(profileAttributes(profile)...)
+	}
+	return nil
+}
+
+func profileAttributes(profile *blackfire.Profile) []attribute.KeyValue {
+	var attributes []attribute.KeyValue
+	if profile.UUID != "" {
+		attributes = append(attributes, ProfileUUIDKey.String(profile.UUID))
+	}
+	if profile.URL != "" {
+		attributes = append(attributes, ProfileURLKey.String(profile.URL))
+	}
+	return attributes
+}
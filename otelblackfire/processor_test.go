@@ -0,0 +1,72 @@
+package otelblackfire
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/blackfireio/go-blackfire"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProfileAttributes(t *testing.T) {
+	attributes := profileAttributes(&blackfire.Profile{
+		UUID: "1234",
+		URL:  "https://blackfire.io/profiles/1234/graph",
+	})
+	expected := []attribute.KeyValue{
+		ProfileUUIDKey.String("1234"),
+		ProfileURLKey.String("https://blackfire.io/profiles/1234/graph"),
+	}
+	if len(attributes) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, attributes)
+	}
+	for i := range expected {
+		if attributes[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], attributes[i])
+		}
+	}
+
+	if attributes := profileAttributes(&blackfire.Profile{}); len(attributes) != 0 {
+		t.Errorf("expected no attributes for an unsigned profile, got %v", attributes)
+	}
+}
+
+func TestStartProfileEndsWithSpan(t *testing.T) {
+	logger := blackfire.NewLogger(filepath.Join(os.TempDir(), "otelblackfire-test.log"), 4)
+	probe := blackfire.NewProbe(&blackfire.Configuration{
+		OutputFile: filepath.Join(os.TempDir(), "otelblackfire-test.bf"),
+		Logger:     &logger,
+	})
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(NewSpanProcessor(probe)),
+		sdktrace.WithSpanProcessor(recorder),
+	)
+	ctx, span := provider.Tracer("test").Start(context.Background(), "checkout")
+	if err := StartProfile(ctx, probe, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if state := probe.State(); state != "enabled" {
+		t.Fatalf("expected the probe to be enabled, got %s", state)
+	}
+	span.End()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for probe.State() != "off" && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if state := probe.State(); state != "off" {
+		t.Errorf("expected the profile to end with the span, got %s", state)
+	}
+
+	events := recorder.Ended()[0].Events()
+	if len(events) != 1 || events[0].Name != ProfileStartEvent {
+		t.Errorf("expected a %s event, got %v", ProfileStartEvent, events)
+	}
+}
@@ -0,0 +1,255 @@
+// Package otlpblackfire exports Blackfire profiles to an OpenTelemetry
+// collector, using the OTLP profiles signal over HTTP. It lives in its own
+// module so that go-blackfire itself does not depend on the OTLP protobufs.
+package otlpblackfire
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/blackfireio/go-blackfire"
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	profilespb "go.opentelemetry.io/proto/otlp/profiles/v1development"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultEndpoint is the OTLP/HTTP profiles endpoint of a local collector.
+const DefaultEndpoint = "http://localhost:4318/v1development/profiles"
+
+// exportTimeout bounds the time sending a profile may take. Exports run on
+// the probe's event loop, so a hanging collector must not block profiling.
+const exportTimeout = 30 * time.Second
+
+// Exporter sends profiles to an OTLP collector. Use it as
+// blackfire.Configuration.Exporter.
+type Exporter struct {
+	endpoint string
+	client   *http.Client
+	resource map[string]string
+}
+
+var _ blackfire.Exporter = (*Exporter)(nil)
+
+// Option customizes an Exporter.
+type Option func(*Exporter)
+
+// WithHTTPClient sets the HTTP client used to reach the collector. The client
+// should have a timeout, as exports run on the probe's event loop.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Exporter) {
+		e.client = client
+	}
+}
+
+// WithResourceAttributes sets the attributes describing the profiled service.
+// service.name defaults to the name of the executable.
+func WithResourceAttributes(attributes map[string]string) Option {
+	return func(e *Exporter) {
+		for k, v := range attributes {
+			e.resource[k] = v
+		}
+	}
+}
+
+// NewExporter returns an exporter sending profiles to the specified OTLP/HTTP
+// endpoint, or to DefaultEndpoint if empty.
+func NewExporter(endpoint string, options ...Option) *Exporter {
+	if endpoint == "" {
+		endpoint = DefaultEndpoint
+	}
+	e := &Exporter{
+		endpoint: endpoint,
+		client:   &http.Client{Timeout: exportTimeout},
+		resource: map[string]string{
+			"service.name": filepath.Base(os.Args[0]),
+		},
+	}
+	for _, option := range options {
+		option(e)
+	}
+	return e
+}
+
+// Export converts the profile to the OTLP profiles signal and sends it to
+// the collector.
+func (e *Exporter) Export(profile *pprof_reader.Profile, title string) (int, error) {
+	// ProfilesData and ExportProfilesServiceRequest share the same wire
+	// format, which spares a dependency on the gRPC service definitions.
+	payload, err := proto.Marshal(Convert(profile, title, e.resource))
+	if err != nil {
+		return 0, err
+	}
+
+	response, err := e.client.Post(e.endpoint, "application/x-protobuf", bytes.NewReader(payload))
+	if err != nil {
+		return 0, err
+	}
+	defer response.Body.Close()
+	if response.StatusCode/100 != 2 {
+		body, _ := ioutil.ReadAll(io.LimitReader(response.Body, 1024))
+		return 0, fmt.Errorf("OTLP export to %s failed: %s: %s", e.endpoint, response.Status, body)
+	}
+	return len(payload), nil
+}
+
+// Convert converts a profile to the OTLP profiles signal. Samples carry their
+// count, CPU time and the heap memory of their leaf frame, and their pprof
+// labels as attributes.
+func Convert(profile *pprof_reader.Profile, title string, resource map[string]string) *profilespb.ProfilesData {
+	c := newConverter()
+
+	p := &profilespb.Profile{
+		SampleType: []*profilespb.ValueType{
+			c.valueType("samples", "count"),
+			c.valueType("cpu", "nanoseconds"),
+			c.valueType("inuse_space", "bytes"),
+		},
+		DefaultSampleTypeIndex: 1,
+		PeriodType:             c.valueType("cpu", "nanoseconds"),
+		Period:                 int64(profile.USecPerSample) * 1000,
+		TimeNanos:              time.Now().Add(-profile.Duration).UnixNano(),
+		DurationNanos:          int64(profile.Duration),
+	}
+	if title != "" {
+		p.AttributeIndices = append(p.AttributeIndices, c.attribute("profile.title", title))
+	}
+
+	for _, sample := range profile.Samples {
+		if len(sample.Stack) == 0 {
+			continue
+		}
+		s := &profilespb.Sample{
+			LocationsStartIndex: int32(len(p.LocationIndices)),
+			LocationsLength:     int32(len(sample.Stack)),
+			Value: []int64{
+				int64(sample.Count),
+				int64(sample.CPUTime) * 1000,
+				int64(sample.FrameMemoryCost(len(sample.Stack) - 1)),
+			},
+		}
+		// OTLP stacks are leaf first.
+		for i := len(sample.Stack) - 1; i >= 0; i-- {
+			p.LocationIndices = append(p.LocationIndices, c.location(sample.Stack[i]))
+		}
+		for _, key := range sortedKeys(sample.Labels) {
+			s.AttributeIndices = append(s.AttributeIndices, c.attribute(key, sample.Labels[key]))
+		}
+		p.Sample = append(p.Sample, s)
+	}
+
+	var resourceAttributes []*commonpb.KeyValue
+	for _, key := range sortedKeys(resource) {
+		resourceAttributes = append(resourceAttributes, keyValue(key, resource[key]))
+	}
+
+	return &profilespb.ProfilesData{
+		ResourceProfiles: []*profilespb.ResourceProfiles{{
+			Resource: &resourcepb.Resource{
+				Attributes: resourceAttributes,
+			},
+			ScopeProfiles: []*profilespb.ScopeProfiles{{
+				Scope: &commonpb.InstrumentationScope{
+					Name: "github.com/blackfireio/go-blackfire",
+				},
+				Profiles: []*profilespb.Profile{p},
+			}},
+		}},
+		Dictionary: c.dictionary,
+	}
+}
+
+// converter builds the dictionary shared by the profiles.
+type converter struct {
+	dictionary *profilespb.ProfilesDictionary
+	strings    map[string]int32
+	locations  map[*pprof_reader.Function]int32
+	attributes map[string]int32
+}
+
+func newConverter() *converter {
+	c := &converter{
+		dictionary: &profilespb.ProfilesDictionary{},
+		strings:    make(map[string]int32),
+		locations:  make(map[*pprof_reader.Function]int32),
+		attributes: make(map[string]int32),
+	}
+	// The first string must be the empty string.
+	c.str("")
+	return c
+}
+
+func (c *converter) str(s string) int32 {
+	if index, ok := c.strings[s]; ok {
+		return index
+	}
+	index := int32(len(c.dictionary.StringTable))
+	c.dictionary.StringTable = append(c.dictionary.StringTable, s)
+	c.strings[s] = index
+	return index
+}
+
+func (c *converter) valueType(typ, unit string) *profilespb.ValueType {
+	return &profilespb.ValueType{
+		TypeStrindex: c.str(typ),
+		UnitStrindex: c.str(unit),
+	}
+}
+
+// location returns the index of the location of a function, as pprof_reader
+// functions have no address or line information.
+func (c *converter) location(f *pprof_reader.Function) int32 {
+	if index, ok := c.locations[f]; ok {
+		return index
+	}
+	functionIndex := int32(len(c.dictionary.FunctionTable))
+	c.dictionary.FunctionTable = append(c.dictionary.FunctionTable, &profilespb.Function{
+		NameStrindex:       c.str(f.Name),
+		SystemNameStrindex: c.str(f.Name),
+	})
+	index := int32(len(c.dictionary.LocationTable))
+	c.dictionary.LocationTable = append(c.dictionary.LocationTable, &profilespb.Location{
+		Line: []*profilespb.Line{{
+			FunctionIndex: functionIndex,
+		}},
+	})
+	c.locations[f] = index
+	return index
+}
+
+func (c *converter) attribute(key, value string) int32 {
+	id := key + "\x00" + value
+	if index, ok := c.attributes[id]; ok {
+		return index
+	}
+	index := int32(len(c.dictionary.AttributeTable))
+	c.dictionary.AttributeTable = append(c.dictionary.AttributeTable, keyValue(key, value))
+	c.attributes[id] = index
+	return index
+}
+
+func keyValue(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key: key,
+		Value: &commonpb.AnyValue{
+			Value: &commonpb.AnyValue_StringValue{StringValue: value},
+		},
+	}
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,117 @@
+package otlpblackfire
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	profilespb "go.opentelemetry.io/proto/otlp/profiles/v1development"
+	"google.golang.org/protobuf/proto"
+)
+
+func testProfile() *pprof_reader.Profile {
+	main := &pprof_reader.Function{Name: "main.main"}
+	work := &pprof_reader.Function{Name: "main.work", DistributedMemoryCost: 64}
+	profile := pprof_reader.NewProfile()
+	profile.USecPerSample = 10000
+	profile.Duration = time.Second
+	profile.Samples = []*pprof_reader.Sample{
+		{Count: 2, CPUTime: 20000, Stack: []*pprof_reader.Function{main, work}, Labels: map[string]string{"route": "/"}},
+		{Count: 1, CPUTime: 10000, Stack: []*pprof_reader.Function{main}},
+		{Count: 1, CPUTime: 10000},
+	}
+	return profile
+}
+
+func TestConvert(t *testing.T) {
+	data := Convert(testProfile(), "my profile", map[string]string{"service.name": "test"})
+	dictionary := data.Dictionary
+	str := func(index int32) string {
+		return dictionary.StringTable[index]
+	}
+	functionName := func(locationIndex int32) string {
+		line := dictionary.LocationTable[locationIndex].Line[0]
+		return str(dictionary.FunctionTable[line.FunctionIndex].NameStrindex)
+	}
+
+	if dictionary.StringTable[0] != "" {
+		t.Errorf("expected the first string to be empty, got %q", dictionary.StringTable[0])
+	}
+	if len(dictionary.FunctionTable) != 2 || len(dictionary.LocationTable) != 2 {
+		t.Errorf("expected functions and locations to be shared, got %d functions and %d locations",
+			len(dictionary.FunctionTable), len(dictionary.LocationTable))
+	}
+
+	resource := data.ResourceProfiles[0].Resource.Attributes
+	if len(resource) != 1 || resource[0].Key != "service.name" || resource[0].Value.GetStringValue() != "test" {
+		t.Errorf("unexpected resource attributes %v", resource)
+	}
+
+	p := data.ResourceProfiles[0].ScopeProfiles[0].Profiles[0]
+	if p.Period != 10000000 || p.DurationNanos != int64(time.Second) {
+		t.Errorf("unexpected period %d or duration %d", p.Period, p.DurationNanos)
+	}
+	title := dictionary.AttributeTable[p.AttributeIndices[0]]
+	if title.Key != "profile.title" || title.Value.GetStringValue() != "my profile" {
+		t.Errorf("unexpected title attribute %v", title)
+	}
+
+	// Samples without a stack are dropped.
+	if len(p.Sample) != 2 {
+		t.Fatalf("expected 2 samples, got %d", len(p.Sample))
+	}
+	sample := p.Sample[0]
+	var stack []string
+	for _, index := range p.LocationIndices[sample.LocationsStartIndex : sample.LocationsStartIndex+sample.LocationsLength] {
+		stack = append(stack, functionName(index))
+	}
+	if len(stack) != 2 || stack[0] != "main.work" || stack[1] != "main.main" {
+		t.Errorf("expected a leaf first stack, got %v", stack)
+	}
+	if sample.Value[0] != 2 || sample.Value[1] != 20000000 || sample.Value[2] != 128 {
+		t.Errorf("unexpected sample values %v", sample.Value)
+	}
+	label := dictionary.AttributeTable[sample.AttributeIndices[0]]
+	if label.Key != "route" || label.Value.GetStringValue() != "/" {
+		t.Errorf("unexpected sample attribute %v", label)
+	}
+}
+
+func TestExport(t *testing.T) {
+	var received profilespb.ProfilesData
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("unexpected content type %s", r.Header.Get("Content-Type"))
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if err := proto.Unmarshal(body, &received); err != nil {
+			t.Error(err)
+		}
+	}))
+	defer server.Close()
+
+	size, err := NewExporter(server.URL).Export(testProfile(), "my profile")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size == 0 {
+		t.Errorf("expected the payload size to be reported")
+	}
+	if len(received.ResourceProfiles[0].ScopeProfiles[0].Profiles[0].Sample) != 2 {
+		t.Errorf("expected the profile to be received, got %v", &received)
+	}
+}
+
+func TestExportFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unsupported", http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	if _, err := NewExporter(server.URL).Export(testProfile(), ""); err == nil {
+		t.Errorf("expected an error")
+	}
+}
@@ -0,0 +1,24 @@
+module github.com/blackfireio/go-blackfire/otlpblackfire
+
+go 1.23.0
+
+require (
+	github.com/blackfireio/go-blackfire v0.0.0-20261016115257-6179df72977e
+	go.opentelemetry.io/proto/otlp v1.7.0
+	google.golang.org/protobuf v1.36.6
+)
+
+require (
+	github.com/blackfireio/osinfo v1.0.2 // indirect
+	github.com/go-ini/ini v1.51.1 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/rakyll/statik v0.1.7 // indirect
+	github.com/rs/zerolog v1.17.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Builds against the go-blackfire sources of this repository. The replace
+// directive is ignored by the consumers of the module, who get the go-blackfire
+// version required above: the commit providing the API this module uses,
+// until a go-blackfire release does.
+replace github.com/blackfireio/go-blackfire => ../
@@ -0,0 +1,65 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"fmt"
+)
+
+// maxPanicTagSize caps the size of the panic message in the tags.
+const maxPanicTagSize = 256
+
+// CapturePanic ends the current profile of the global probe when the program
+// panics (see Probe.CapturePanic). It must be deferred directly:
+//
+//	func main() {
+//		defer blackfire.CapturePanic()
+//		...
+//	}
+func CapturePanic() {
+	if r := recover(); r != nil {
+		globalProbe.endPanicked(r)
+		panic(r)
+	}
+}
+
+// CapturePanic ends the current profile when the program panics, and uploads
+// it tagged with panicked=true and the panic message (panic=...), before the
+// panic goes on. It must be deferred directly, in main or at the top of a
+// goroutine, for post-mortem profiles of crashes.
+func (p *Probe) CapturePanic() {
+	if r := recover(); r != nil {
+		p.endPanicked(r)
+		panic(r)
+	}
+}
+
+// endPanicked ends the current profile, if any, tagging it with the panic.
+func (p *Probe) endPanicked(r interface{}) {
+	if !p.IsProfiling() {
+		return
+	}
+	p.tagPanic(r)
+	if err := p.End(); err != nil {
+		p.configuration.Logger.Error().Err(err).Msg("Blackfire: Unable to end the profile of the panic")
+	}
+}
+
+// tagPanic tags the current profile with the panic.
+func (p *Probe) tagPanic(r interface{}) {
+	message := fmt.Sprint(r)
+	if len(message) > maxPanicTagSize {
+		message = message[:maxPanicTagSize]
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	// The tags of the options may be shared with the caller.
+	tags := make(map[string]string, len(p.profileOptions.Tags)+2)
+	for name, value := range p.profileOptions.Tags {
+		tags[name] = value
+	}
+	tags["panicked"] = "true"
+	tags["panic"] = message
+	p.profileOptions.Tags = tags
+}
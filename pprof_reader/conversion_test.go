@@ -0,0 +1,71 @@
+package pprof_reader_test
+
+import (
+	"bufio"
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/blackfireio/go-blackfire/bf_format"
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+func toLineSet(data []byte) map[string]bool {
+	result := make(map[string]bool)
+	r := bytes.NewReader(data)
+	s := bufio.NewScanner(r)
+
+	// Skip past headers, which are OS and go version dependent.
+	for s.Scan() {
+		if s.Text() == "" {
+			break
+		}
+	}
+
+	// We compare only the payload.
+	for s.Scan() {
+		result[s.Text()] = true
+	}
+
+	return result
+}
+
+func TestConversion(t *testing.T) {
+	data, err := ioutil.ReadFile("fixtures/wt.pprof.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	profile, err := pprof_reader.ReadFromPProf([]*bytes.Buffer{bytes.NewBuffer(data)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The golden file of the Blackfire format writer.
+	expectedBytes, err := ioutil.ReadFile("../bf_format/fixtures/wt.bf")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := toLineSet(expectedBytes)
+
+	var b bytes.Buffer
+	if err := bf_format.WriteBFFormat(profile, &b, bf_format.ProbeOptions{}, ""); err != nil {
+		t.Fatal(err)
+	}
+	actual := toLineSet(b.Bytes())
+
+	if len(actual) != len(expected) {
+		t.Errorf("Expected lines (%v) != actual lines (%v)", len(expected), len(actual))
+	}
+
+	for k := range expected {
+		if !actual[k] {
+			t.Errorf("Expected line [%v] not found in actual output", k)
+		}
+	}
+
+	for k := range actual {
+		if !expected[k] {
+			t.Errorf("Unexpected line [%v] found in actual output", k)
+		}
+	}
+}
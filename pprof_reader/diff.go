@@ -0,0 +1,46 @@
+package pprof_reader
+
+// DiffMemory returns a profile of the memory growth between two profiles
+// read with the MemoryPerStack attribution, such as two heap snapshots of
+// the same program: the in-use memory of each call stack in before is
+// subtracted from the one in after. The stacks whose memory did not grow are
+// dropped, so that leaks are attributed to the code retaining more and more
+// memory, instead of being drowned in the memory in use all along. The
+// resulting profile has no CPU samples, and the functions and headers of
+// after.
+func DiffMemory(before, after *Profile) *Profile {
+	freed := make(map[string]uint64)
+	for _, sample := range before.Samples {
+		freed[stackKey(sample.Stack)] += sample.MemUsage
+	}
+
+	// The samples sharing a stack (with different labels) are merged.
+	var keys []string
+	grown := make(map[string]*Sample)
+	for _, sample := range after.Samples {
+		if sample.MemUsage == 0 {
+			continue
+		}
+		key := stackKey(sample.Stack)
+		if diff, ok := grown[key]; ok {
+			diff.MemUsage += sample.MemUsage
+			continue
+		}
+		keys = append(keys, key)
+		grown[key] = newSample(0, 0, sample.Stack, sample.Labels)
+		grown[key].MemUsage = sample.MemUsage
+	}
+
+	diff := after.CloneWithSamples(nil)
+	diff.MemoryAttribution = MemoryPerStack
+	for _, key := range keys {
+		sample := grown[key]
+		if sample.MemUsage <= freed[key] {
+			continue
+		}
+		sample.MemUsage -= freed[key]
+		sample.MemoryCosts = []uint64{sample.MemUsage}
+		diff.Samples = append(diff.Samples, sample)
+	}
+	return diff
+}
@@ -0,0 +1,67 @@
+package pprof_reader
+
+// ProcessProfile is the profile of one of the processes merged by
+// MergeProfiles.
+type ProcessProfile struct {
+	// Name of the node the samples of the process are moved under. If
+	// empty, the stacks are merged with the ones of the other processes.
+	Name    string
+	Profile *Profile
+}
+
+// MergeProfiles combines the profiles of several processes, such as a parent
+// and its workers or the shards of a job, into a single profile. The
+// profiles should be read with the same options; the CPU sample rate,
+// memory attribution and headers of the first one are kept. Since the
+// processes have their own clocks, the duration is the longest one, and the
+// GC pauses and gaps of the timeline are only kept for a single profile.
+func MergeProfiles(profiles []ProcessProfile) *Profile {
+	merged := NewProfile()
+	first := true
+	for _, process := range profiles {
+		p := process.Profile
+		if p == nil {
+			continue
+		}
+		if first {
+			first = false
+			merged.CpuSampleRateHz = p.CpuSampleRateHz
+			merged.USecPerSample = p.USecPerSample
+			merged.MemoryAttribution = p.MemoryAttribution
+			merged.Headers = p.Headers
+			merged.GraphRoot = p.GraphRoot
+			merged.Language = p.Language
+			merged.Runtime = p.Runtime
+		}
+		if p.Duration > merged.Duration {
+			merged.Duration = p.Duration
+		}
+		for name, f := range p.Functions {
+			if _, ok := merged.Functions[name]; !ok {
+				merged.Functions[name] = f
+			}
+		}
+
+		if process.Name == "" {
+			merged.Samples = append(merged.Samples, p.Samples...)
+			continue
+		}
+		node := &Function{
+			Name: process.Name,
+		}
+		merged.Functions[node.Name] = node
+		for _, sample := range p.Samples {
+			node.ReferenceCount += sample.Count
+			stack := make([]*Function, 0, len(sample.Stack)+1)
+			stack = append(stack, node)
+			stack = append(stack, sample.Stack...)
+			merged.Samples = append(merged.Samples, sample.CloneWithStack(stack))
+		}
+	}
+
+	if len(profiles) == 1 && profiles[0].Profile != nil {
+		merged.GCPauses = profiles[0].Profile.GCPauses
+		merged.Gaps = profiles[0].Profile.Gaps
+	}
+	return merged
+}
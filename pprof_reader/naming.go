@@ -0,0 +1,78 @@
+package pprof_reader
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+)
+
+// Closures are named after their enclosing function, followed by .funcN and
+// then .N for nested closures: pkg.Func.func1, pkg.Func.func1.2
+var closureSuffixRegex = regexp.MustCompile(`\.func\d+(\.\d+)*$`)
+
+func isClosure(name string) bool {
+	return closureSuffixRegex.MatchString(name)
+}
+
+// appendFileLine appends the file (along with its directory, as file names
+// are often shared across packages) and line to a function name.
+func appendFileLine(name string, filename string, line int64) string {
+	if filename == "" {
+		return name
+	}
+	file := path.Join(path.Base(path.Dir(filename)), path.Base(filename))
+	if line == 0 {
+		return fmt.Sprintf("%s (%s)", name, file)
+	}
+	return fmt.Sprintf("%s (%s:%d)", name, file, line)
+}
+
+// annotateClosure appends the enclosing function and the definition location
+// to the name of a closure. Other names are returned untouched.
+func annotateClosure(name string, filename string, line int64) string {
+	loc := closureSuffixRegex.FindStringIndex(name)
+	if loc == nil {
+		return name
+	}
+
+	parent := name[:loc[0]]
+	if strings.HasSuffix(parent, ".glob.") {
+		parent = strings.TrimSuffix(parent, ".glob.") + " package var"
+	}
+	if filename == "" || line == 0 {
+		return fmt.Sprintf("%s (closure in %s)", name, parent)
+	}
+	return fmt.Sprintf("%s (closure in %s at %s:%d)", name, parent, path.Base(filename), line)
+}
+
+// collapseGenerics replaces the type arguments of generic instantiations
+// with "...", so that pkg.Func[go.shape.int] and pkg.Func[go.shape.string]
+// both become pkg.Func[...].
+func collapseGenerics(name string) string {
+	if !strings.Contains(name, "[") {
+		return name
+	}
+
+	var builder strings.Builder
+	depth := 0
+	for _, r := range name {
+		switch r {
+		case '[':
+			if depth == 0 {
+				builder.WriteString("[...]")
+			}
+			depth++
+			continue
+		case ']':
+			if depth > 0 {
+				depth--
+				continue
+			}
+		}
+		if depth == 0 {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
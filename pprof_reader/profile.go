@@ -4,8 +4,15 @@ import (
 	"bytes"
 	"fmt"
 	"io/ioutil"
+	"net/url"
 	"os"
 	"path"
+	"regexp"
+	"runtime"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	pprof "github.com/blackfireio/go-blackfire/pprof_reader/internal/profile"
 )
@@ -37,25 +44,58 @@ type Sample struct {
 	CPUTime  uint64
 	MemUsage uint64
 	Stack    []*Function
+	// The memory cost of each frame of the stack, aligned on the leaf. Frames
+	// without a cost (such as synthetic frames prepended to the stack) may be
+	// omitted. When nil, costs are derived from DistributedMemoryCost.
+	MemoryCosts []uint64
+	// The pprof labels (set via pprof.Do or pprof.SetGoroutineLabels) of the
+	// goroutine this sample was taken in.
+	Labels map[string]string
+	// Bytes received and sent over the network by the stack (see
+	// AddNetworkSample).
+	NetworkIn  uint64
+	NetworkOut uint64
+	// The custom metrics accounted to the stack, by name (see
+	// AddMetricSample).
+	Metrics map[string]uint64
 }
 
-func newSample(count int, cpuTime uint64, stack []*Function) *Sample {
+func newSample(count int, cpuTime uint64, stack []*Function, labels map[string]string) *Sample {
 	return &Sample{
 		Count:   count,
 		CPUTime: cpuTime,
 		Stack:   stack,
+		Labels:  labels,
 	}
 }
 
 func (s *Sample) CloneWithStack(stack []*Function) *Sample {
 	return &Sample{
-		Count:    s.Count,
-		CPUTime:  s.CPUTime,
-		MemUsage: s.MemUsage,
-		Stack:    stack,
+		Count:       s.Count,
+		CPUTime:     s.CPUTime,
+		MemUsage:    s.MemUsage,
+		Stack:       stack,
+		MemoryCosts: s.MemoryCosts,
+		Labels:      s.Labels,
+		NetworkIn:   s.NetworkIn,
+		NetworkOut:  s.NetworkOut,
+		Metrics:     s.Metrics,
 	}
 }
 
+// FrameMemoryCost returns the memory cost of the frame at the specified index
+// of the stack, for all the occurrences (Count) of the sample.
+func (s *Sample) FrameMemoryCost(index int) uint64 {
+	if s.MemoryCosts == nil {
+		return s.Stack[index].DistributedMemoryCost * uint64(s.Count)
+	}
+	index -= len(s.Stack) - len(s.MemoryCosts)
+	if index < 0 {
+		return 0
+	}
+	return s.MemoryCosts[index]
+}
+
 // Profle contains a set of entry points, which collectively contain all sampled data
 type Profile struct {
 	CpuSampleRateHz int
@@ -63,31 +103,484 @@ type Profile struct {
 	Samples         []*Sample
 	// Note: Matching by ID didn't work since there seems to be some duplication
 	// in the pprof data. We match by name instead since it's guaranteed unique.
+	// The samples share these functions, and thus their names. The Blackfire
+	// format has no symbol table though: names are written in full for each
+	// edge.
 	Functions map[string]*Function
+	// Wall clock time spent profiling, and the GC pauses that happened in
+	// the meantime, relative to the beginning of the profile.
+	Duration time.Duration
+	GCPauses []GCPause
+	// Points where profiling was paused then resumed, relative to the
+	// beginning of the profile.
+	Gaps []Gap
+	// How heap memory was attributed to the samples.
+	MemoryAttribution MemoryAttribution
+	// Additional headers to write along with the profile.
+	Headers map[string]string
+	// Context of the profile, replacing the command line arguments in the
+	// Context header if not nil.
+	Context url.Values
+	// Patterns of the command line arguments masked in the Context header.
+	SensitiveArgs []*regexp.Regexp
+	// Root node of the graph, and language and runtime of the profiled
+	// program, replacing "go", "go" and the Go version when not empty, for
+	// mixed-runtime programs.
+	GraphRoot string
+	Language  string
+	Runtime   string
+
+	options ReadOptions
+	// Heap profile samples, used with MemoryPerStack attribution.
+	memorySamples []*Sample
+	// The functions of the lines of the pprof profile being read, so that
+	// their names are only built once (see getMatchingFunction).
+	lineFunctions map[lineKey]*Function
+	// The functions found in the pprof profiles, by name, whose definition
+	// lines name the frames of the samples added afterwards.
+	pprofFunctions map[string]*pprof.Function
+	// The copies of the functions made by decycleStack, by name, shared by
+	// all the samples.
+	decycled map[string]*Function
+}
+
+// lineKey identifies the lines of a pprof profile sharing a function name.
+type lineKey struct {
+	function *pprof.Function
+	line     int64
+}
+
+// GCPause is a stop-the-world garbage collection pause.
+type GCPause struct {
+	Start    time.Duration
+	Duration time.Duration
+}
+
+// Gap is a period during which profiling was paused. Start is relative to the
+// profiled time, which excludes the gap itself.
+type Gap struct {
+	Start    time.Duration
+	Duration time.Duration
 }
 
 func NewProfile() *Profile {
 	return &Profile{
 		Functions: make(map[string]*Function),
+		decycled:  make(map[string]*Function),
 	}
 }
 
 func (p *Profile) CloneWithSamples(samples []*Sample) *Profile {
 	return &Profile{
-		CpuSampleRateHz: p.CpuSampleRateHz,
-		USecPerSample:   p.USecPerSample,
-		Samples:         samples,
-		Functions:       p.Functions,
+		CpuSampleRateHz:   p.CpuSampleRateHz,
+		USecPerSample:     p.USecPerSample,
+		Samples:           samples,
+		Functions:         p.Functions,
+		Duration:          p.Duration,
+		GCPauses:          p.GCPauses,
+		Gaps:              p.Gaps,
+		MemoryAttribution: p.MemoryAttribution,
+		Headers:           p.Headers,
+		GraphRoot:         p.GraphRoot,
+		Language:          p.Language,
+		Runtime:           p.Runtime,
+	}
+}
+
+// AggregateSamples merges the samples sharing the same call stack, and divides
+// their count and CPU time by the number of iterations the profile covers, so
+// that the result represents a single iteration.
+func (p *Profile) AggregateSamples(iterations int) *Profile {
+	if iterations < 1 {
+		iterations = 1
+	}
+
+	samplesByStack := make(map[string]*Sample)
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		key := stackKey(sample.Stack)
+		if aggregated, ok := samplesByStack[key]; ok {
+			aggregated.Count += sample.Count
+			aggregated.CPUTime += sample.CPUTime
+			aggregated.NetworkIn += sample.NetworkIn
+			aggregated.NetworkOut += sample.NetworkOut
+			for name, value := range sample.Metrics {
+				aggregated.Metrics[name] += value
+			}
+			for i := range aggregated.MemoryCosts {
+				aggregated.MemoryCosts[i] += sample.FrameMemoryCost(i)
+			}
+			continue
+		}
+		aggregated := sample.CloneWithStack(sample.Stack)
+		aggregated.Metrics = make(map[string]uint64, len(sample.Metrics))
+		for name, value := range sample.Metrics {
+			aggregated.Metrics[name] = value
+		}
+		aggregated.MemoryCosts = make([]uint64, len(sample.Stack))
+		for i := range aggregated.MemoryCosts {
+			aggregated.MemoryCosts[i] = sample.FrameMemoryCost(i)
+		}
+		samplesByStack[key] = aggregated
+		samples = append(samples, aggregated)
+	}
+
+	for _, sample := range samples {
+		// Round up so that a sampled stack never disappears from the graph.
+		sample.Count = (sample.Count + iterations - 1) / iterations
+		sample.CPUTime /= uint64(iterations)
+		sample.NetworkIn /= uint64(iterations)
+		sample.NetworkOut /= uint64(iterations)
+		for name := range sample.Metrics {
+			sample.Metrics[name] /= uint64(iterations)
+		}
+		for i := range sample.MemoryCosts {
+			sample.MemoryCosts[i] /= uint64(iterations)
+		}
 	}
+
+	return p.CloneWithSamples(samples)
+}
+
+// StackFrame is a frame of a call stack recorded outside of pprof profiles,
+// as found in a runtime.Frame.
+type StackFrame struct {
+	Function string
+	File     string
+	Line     int
+}
+
+// AddNetworkSample adds a sample accounting network traffic to a call stack,
+// given root first. count is the number of I/O operations.
+func (p *Profile) AddNetworkSample(stack []StackFrame, count int, in, out uint64) {
+	sample := p.newStackSample(stack, count)
+	sample.NetworkIn = in
+	sample.NetworkOut = out
+	p.Samples = append(p.Samples, sample)
+}
+
+// AddMetricSample adds a sample accounting a custom metric to a call stack,
+// given root first. count is the number of times the metric was incremented.
+func (p *Profile) AddMetricSample(stack []StackFrame, name string, count int, value uint64) {
+	sample := p.newStackSample(stack, count)
+	sample.Metrics = map[string]uint64{name: value}
+	p.Samples = append(p.Samples, sample)
+}
+
+// MetricNames returns the sorted names of the custom metrics found in the
+// samples.
+func (p *Profile) MetricNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, sample := range p.Samples {
+		for name := range sample.Metrics {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
 }
 
-func (p *Profile) getMatchingFunction(pf *pprof.Function) *Function {
-	f, ok := p.Functions[pf.Name]
+// newStackSample creates a sample without CPU time nor memory for a call
+// stack given root first. The frames are named like the pprof lines, so that
+// their costs land on the same nodes as the CPU samples of their functions.
+func (p *Profile) newStackSample(stack []StackFrame, count int) *Sample {
+	functions := make([]*Function, 0, len(stack))
+	for _, frame := range stack {
+		pf, ok := p.pprofFunctions[frame.Function]
+		if !ok {
+			// The definition line of functions absent from the pprof
+			// profiles is unknown.
+			pf = &pprof.Function{Name: frame.Function, Filename: frame.File}
+		}
+		line := pprof.Line{Function: pf, Line: int64(frame.Line)}
+		functions = append(functions, p.getFunctionNamed(p.functionName(line)))
+	}
+	functions = p.decycle(functions)
+	sample := newSample(count, 0, functions, nil)
+	sample.MemoryCosts = []uint64{}
+	return sample
+}
+
+// SegmentByLabels moves the samples carrying any of the specified pprof label
+// keys under a synthetic node named after their label values, so that each
+// label combination shows up as its own sub-graph. Other samples are left
+// untouched.
+func (p *Profile) SegmentByLabels(keys []string) *Profile {
+	if len(keys) == 0 {
+		return p
+	}
+
+	segments := make(map[string]*Function)
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		values := url.Values{}
+		for _, key := range keys {
+			if value, ok := sample.Labels[key]; ok {
+				values.Set(key, value)
+			}
+		}
+		if len(values) == 0 {
+			samples = append(samples, sample)
+			continue
+		}
+
+		name := "label:" + values.Encode()
+		segment, ok := segments[name]
+		if !ok {
+			segment = &Function{
+				Name: name,
+			}
+			segments[name] = segment
+		}
+		segment.ReferenceCount += sample.Count
+
+		stack := make([]*Function, 0, len(sample.Stack)+1)
+		stack = append(stack, segment)
+		stack = append(stack, sample.Stack...)
+		samples = append(samples, sample.CloneWithStack(stack))
+	}
+
+	return p.CloneWithSamples(samples)
+}
+
+// FilterByLabel returns a profile containing only the samples carrying the
+// specified pprof label key.
+func (p *Profile) FilterByLabel(key string) *Profile {
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		if _, ok := sample.Labels[key]; ok {
+			samples = append(samples, sample)
+		}
+	}
+	return p.CloneWithSamples(samples)
+}
+
+// GoroutineCPU is the CPU time spent in the goroutines started with a
+// function.
+type GoroutineCPU struct {
+	Function string
+	// CPU time, in microseconds, and number of samples.
+	CPUTime uint64
+	Count   int
+}
+
+// CPUByGoroutine aggregates the CPU time of the samples by the function their
+// goroutine was started with: the root of their stack, past the frames of
+// the runtime starting the goroutine. The goroutines of the runtime itself
+// (GC workers...) are accounted to their runtime root. The result is sorted
+// by decreasing CPU time.
+func (p *Profile) CPUByGoroutine() []GoroutineCPU {
+	indexes := make(map[string]int)
+	var report []GoroutineCPU
+	for _, sample := range p.Samples {
+		if sample.CPUTime == 0 || len(sample.Stack) == 0 {
+			continue
+		}
+		root := goroutineRoot(sample.Stack)
+		i, ok := indexes[root]
+		if !ok {
+			i = len(report)
+			indexes[root] = i
+			report = append(report, GoroutineCPU{Function: root})
+		}
+		report[i].CPUTime += sample.CPUTime
+		report[i].Count += sample.Count
+	}
+	sort.SliceStable(report, func(i, j int) bool {
+		return report[i].CPUTime > report[j].CPUTime
+	})
+	return report
+}
+
+// goroutineRoot returns the name of the function a root-first stack was
+// started with.
+func goroutineRoot(stack []*Function) string {
+	for _, f := range stack {
+		if !strings.HasPrefix(f.Name, "runtime.") {
+			return f.Name
+		}
+	}
+	if len(stack) > 1 && stack[0].Name == "runtime.goexit" {
+		return stack[1].Name
+	}
+	return stack[0].Name
+}
+
+// TruncatedNode is the name of the node TruncateStacks replaces the deepest
+// frames of the stacks with.
+const TruncatedNode = "…truncated"
+
+// TruncateStacks returns a profile whose stacks are at most maxDepth frames
+// deep: the frames beyond the limit are replaced with a synthetic node, which
+// is accounted their memory. It also returns the number of truncated stacks.
+// Stacks are left untouched if maxDepth is not positive.
+func (p *Profile) TruncateStacks(maxDepth int) (*Profile, int) {
+	if maxDepth <= 0 {
+		return p, 0
+	}
+	if maxDepth < 2 {
+		// The synthetic node needs a parent.
+		maxDepth = 2
+	}
+
+	node := &Function{
+		Name: TruncatedNode,
+	}
+	truncated := 0
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		if len(sample.Stack) <= maxDepth {
+			samples = append(samples, sample)
+			continue
+		}
+		truncated++
+		node.ReferenceCount += sample.Count
+
+		stack := make([]*Function, 0, maxDepth)
+		stack = append(stack, sample.Stack[:maxDepth-1]...)
+		stack = append(stack, node)
+		costs := make([]uint64, maxDepth)
+		for i := range sample.Stack {
+			if i < maxDepth-1 {
+				costs[i] = sample.FrameMemoryCost(i)
+			} else {
+				costs[maxDepth-1] += sample.FrameMemoryCost(i)
+			}
+		}
+		clone := sample.CloneWithStack(stack)
+		clone.MemoryCosts = costs
+		samples = append(samples, clone)
+	}
+	return p.CloneWithSamples(samples), truncated
+}
+
+// ProbeNode is the name of the node GroupRootedIn moves the samples under.
+const ProbeNode = "blackfire-probe"
+
+// PruneRootedIn returns a profile without the samples of the goroutines
+// started in any of the specified packages or their sub-packages, such as
+// the serialization and upload goroutines of the probe.
+func (p *Profile) PruneRootedIn(packages []string) *Profile {
+	if len(packages) == 0 {
+		return p
+	}
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		if !isRootedIn(sample.Stack, packages) {
+			samples = append(samples, sample)
+		}
+	}
+	return p.CloneWithSamples(samples)
+}
+
+// GroupRootedIn moves the samples of the goroutines started in any of the
+// specified packages or their sub-packages under a synthetic node of the
+// specified name, so that they show up apart from the application.
+func (p *Profile) GroupRootedIn(packages []string, name string) *Profile {
+	if len(packages) == 0 {
+		return p
+	}
+	node := &Function{
+		Name: name,
+	}
+	samples := make([]*Sample, 0, len(p.Samples))
+	for _, sample := range p.Samples {
+		if !isRootedIn(sample.Stack, packages) {
+			samples = append(samples, sample)
+			continue
+		}
+		node.ReferenceCount += sample.Count
+		stack := make([]*Function, 0, len(sample.Stack)+1)
+		stack = append(stack, node)
+		stack = append(stack, sample.Stack...)
+		samples = append(samples, sample.CloneWithStack(stack))
+	}
+	return p.CloneWithSamples(samples)
+}
+
+// isRootedIn returns whether the first frame of a root-first stack, past the
+// frames of the runtime starting the goroutine, belongs to any of the
+// packages or their sub-packages.
+func isRootedIn(stack []*Function, packages []string) bool {
+	for _, f := range stack {
+		if strings.HasPrefix(f.Name, "runtime.") {
+			continue
+		}
+		for _, pkg := range packages {
+			if strings.HasPrefix(f.Name, pkg+".") || strings.HasPrefix(f.Name, pkg+"/") {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+func stackKey(stack []*Function) string {
+	var builder strings.Builder
+	for _, f := range stack {
+		builder.WriteString(f.Name)
+		builder.WriteByte(0)
+	}
+	return builder.String()
+}
+
+// getMatchingFunction returns the function of a pprof line. Large programs
+// have tens of thousands of functions, found again and again in the samples:
+// the functions are interned, by pprof line while a pprof profile is being
+// read, then by name, so that names are only built and stored once.
+func (p *Profile) getMatchingFunction(line pprof.Line) *Function {
+	if p.lineFunctions == nil {
+		return p.getFunctionNamed(p.functionName(line))
+	}
+	key := lineKey{function: line.Function}
+	if p.options.FileLine == FileLineCallSite {
+		key.line = line.Line
+	}
+	f, ok := p.lineFunctions[key]
+	if !ok {
+		f = p.getFunctionNamed(p.functionName(line))
+		p.lineFunctions[key] = f
+		if p.pprofFunctions == nil {
+			p.pprofFunctions = make(map[string]*pprof.Function)
+		}
+		p.pprofFunctions[line.Function.Name] = line.Function
+	}
+	return f
+}
+
+// functionName returns the name of the function of a pprof line, renamed
+// according to the read options.
+func (p *Profile) functionName(line pprof.Line) string {
+	pf := line.Function
+	name := pf.Name
+	if p.options.CollapseGenerics {
+		name = collapseGenerics(name)
+	}
+	if p.options.AnnotateClosures && isClosure(name) {
+		// The annotation already carries the file and line.
+		return annotateClosure(name, pf.Filename, pf.StartLine)
+	}
+	switch p.options.FileLine {
+	case FileLineDefinition:
+		name = appendFileLine(name, pf.Filename, pf.StartLine)
+	case FileLineCallSite:
+		name = appendFileLine(name, pf.Filename, line.Line)
+	}
+	return name
+}
+
+func (p *Profile) getFunctionNamed(name string) *Function {
+	f, ok := p.Functions[name]
 	if !ok {
 		f = &Function{
-			Name: pf.Name,
+			Name: name,
 		}
-		p.Functions[pf.Name] = f
+		p.Functions[name] = f
 	}
 
 	return f
@@ -102,40 +595,213 @@ func (p *Profile) HasData() bool {
 	return len(p.Samples) > 0
 }
 
+// ReadOptions customizes the conversion of pprof profiles.
+type ReadOptions struct {
+	// Name the C frames (from cgo code), which carry no symbol information in
+	// pprof data. Symbols are resolved with addr2line when it is available,
+	// otherwise frames are named after their binary and offset.
+	SymbolizeCFrames bool
+
+	// Append the parent function and the definition file:line to closure
+	// names such as pkg.Func.func1.
+	AnnotateClosures bool
+
+	// Merge the instantiations of generic functions (pkg.Func[go.shape.int])
+	// into a single pkg.Func[...] node.
+	CollapseGenerics bool
+
+	// Append the source file and line to function names, to disambiguate
+	// identically named functions.
+	FileLine FileLineMode
+
+	// How heap memory is attributed to call stacks.
+	MemoryAttribution MemoryAttribution
+
+	// How recursive call stacks are represented.
+	Recursion RecursionMode
+
+	// How the functions inlined into their callers are represented.
+	Inlining InliningPolicy
+}
+
+// InliningPolicy selects how inlined functions, which share a location with
+// their callers in pprof data, are represented.
+type InliningPolicy int
+
+const (
+	// Each inlined function is a frame of its own, as if it wasn't inlined.
+	InliningExpand InliningPolicy = iota
+	// Inlined functions are left out, their cost being accounted to the
+	// function they are inlined into.
+	InliningCollapse
+	// Only the innermost inlined function of each location is kept, the
+	// functions it is inlined into being left out.
+	InliningLeafOnly
+)
+
+// RecursionMode selects how the functions found several times in a call
+// stack are represented, as the Blackfire graph can't have cycles.
+type RecursionMode int
+
+const (
+	// Each occurrence after the first is a distinct node, named after the
+	// function with @1, @2, etc appended.
+	RecursionSuffix RecursionMode = iota
+	// The frames between the occurrences are removed, so that the recursion
+	// shows up as a single node with the aggregated cost.
+	RecursionCollapse
+)
+
+// MemoryAttribution selects how heap memory is attributed to call stacks.
+type MemoryAttribution int
+
+const (
+	// The memory allocated by a function is distributed evenly across every
+	// CPU sample the function appears in, and accounted to its callers.
+	MemoryDistributed MemoryAttribution = iota
+	// The memory allocated by a function is distributed evenly across the
+	// CPU samples the function is the leaf of, and not accounted to its
+	// callers. The memory of functions that are never the leaf of a CPU
+	// sample is left out.
+	MemoryLeafOnly
+	// The heap profile stacks are used as is, with the in-use memory of each
+	// stack accounted to its allocating function and callers. This matches
+	// what pprof shows.
+	MemoryPerStack
+)
+
+// FileLineMode selects the line appended to function names.
+type FileLineMode int
+
+const (
+	// Function names are left untouched.
+	FileLineNone FileLineMode = iota
+	// The line where the function is defined is appended, keeping one node
+	// per function.
+	FileLineDefinition
+	// The line being executed in the function is appended, splitting each
+	// function into one node per line.
+	FileLineCallSite
+)
+
 // Read a pprof format profile and convert to our internal format.
 func ReadFromPProf(cpuBuffers, memBuffers []*bytes.Buffer) (*Profile, error) {
+	return ReadFromPProfWithOptions(cpuBuffers, memBuffers, ReadOptions{})
+}
+
+// ReadFromPProfWithOptions is like ReadFromPProf, but customizes the
+// conversion with the specified options.
+func ReadFromPProfWithOptions(cpuBuffers, memBuffers []*bytes.Buffer, options ReadOptions) (*Profile, error) {
+	memProfiles, err := parseBuffers(memBuffers)
+	if err != nil {
+		return nil, err
+	}
+	cpuProfiles, err := parseBuffers(cpuBuffers)
+	if err != nil {
+		return nil, err
+	}
+	return newProfileFrom(cpuProfiles, memProfiles, options), nil
+}
+
+// newProfileFrom converts parsed pprof profiles to our internal format.
+func newProfileFrom(cpuProfiles, memProfiles []*pprof.Profile, options ReadOptions) *Profile {
 	profile := NewProfile()
+	profile.options = options
+	profile.MemoryAttribution = options.MemoryAttribution
 
-	for _, buffer := range memBuffers {
-		if p, err := pprof.Parse(buffer); err != nil {
-			return nil, err
-		} else {
-			profile.addMemorySamples(p)
-		}
+	// The samples are added in the order of the buffers, for the profile not
+	// to depend on the order they were parsed in.
+	for _, p := range memProfiles {
+		profile.addMemoryProfile(p)
+	}
+	for _, p := range cpuProfiles {
+		profile.addCPUProfile(p)
 	}
 
-	for _, buffer := range cpuBuffers {
-		if p, err := pprof.Parse(buffer); err != nil {
-			return nil, err
-		} else {
-			profile.USecPerSample = uint64(p.Period) / 1000
-			profile.CpuSampleRateHz = int(1000000 / profile.USecPerSample)
-			profile.addCPUSamples(p)
+	profile.postProcessSamples()
+	return profile
+}
+
+func (p *Profile) addMemoryProfile(pp *pprof.Profile) {
+	p.lineFunctions = make(map[lineKey]*Function)
+	p.addMemorySamples(pp)
+	p.lineFunctions = nil
+}
+
+func (p *Profile) addCPUProfile(pp *pprof.Profile) {
+	p.USecPerSample = uint64(pp.Period) / 1000
+	p.CpuSampleRateHz = int(1000000 / p.USecPerSample)
+	p.lineFunctions = make(map[lineKey]*Function)
+	p.addCPUSamples(pp)
+	p.lineFunctions = nil
+}
+
+// parseBuffers parses the pprof buffers concurrently, up to GOMAXPROCS at a
+// time, as continuous profiling produces many of them. The profiles are
+// returned in the order of the buffers, empty buffers being skipped as they
+// are left empty for the dimensions which were not collected. The error, if
+// any, is the one of the first buffer failing to parse.
+func parseBuffers(buffers []*bytes.Buffer) ([]*pprof.Profile, error) {
+	profiles := make([]*pprof.Profile, len(buffers))
+	errs := make([]error, len(buffers))
+	slots := make(chan struct{}, runtime.GOMAXPROCS(0))
+	var wg sync.WaitGroup
+	for i, buffer := range buffers {
+		if buffer.Len() == 0 {
+			continue
 		}
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, buffer *bytes.Buffer) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			profiles[i], errs[i] = pprof.Parse(buffer)
+		}(i, buffer)
 	}
+	wg.Wait()
 
-	profile.postProcessSamples()
-	return profile, nil
+	parsed := make([]*pprof.Profile, 0, len(profiles))
+	for i, p := range profiles {
+		if errs[i] != nil {
+			return nil, errs[i]
+		}
+		if p != nil {
+			parsed = append(parsed, p)
+		}
+	}
+	return parsed, nil
 }
 
 func (p *Profile) addMemorySamples(pp *pprof.Profile) {
 	const valueIndex = 3
+	var cFrameNames map[*pprof.Location]string
+	if p.options.SymbolizeCFrames {
+		cFrameNames = symbolizeCFrames(pp.Location)
+	}
+
 	for _, sample := range pp.Sample {
 		memUsage := sample.Value[valueIndex]
-		if memUsage > 0 {
+		if memUsage > 0 && p.options.MemoryAttribution == MemoryPerStack {
+			stack := p.stackOf(sample, cFrameNames)
+			if len(stack) == 0 {
+				continue
+			}
+			// Not a call: only the memory is accounted to the stack.
+			memSample := newSample(0, 0, stack, sampleLabels(sample))
+			memSample.MemoryCosts = []uint64{uint64(memUsage)}
+			p.memorySamples = append(p.memorySamples, memSample)
+		} else if memUsage > 0 {
 			loc := sample.Location[0]
-			line := loc.Line[0]
-			f := p.getMatchingFunction(line.Function)
+			var f *Function
+			if lines := p.locationLines(loc); len(lines) > 0 {
+				f = p.getMatchingFunction(lines[0])
+			} else if name, ok := cFrameNames[loc]; ok {
+				f = p.getFunctionNamed(name)
+			} else {
+				continue
+			}
 			f.MemoryCost += uint64(memUsage)
 		}
 	}
@@ -148,6 +814,11 @@ func (p *Profile) addCPUSamples(pp *pprof.Profile) {
 	const countIndex = 0
 	const valueIndex = 1
 
+	var cFrameNames map[*pprof.Location]string
+	if p.options.SymbolizeCFrames {
+		cFrameNames = symbolizeCFrames(pp.Location)
+	}
+
 	for _, sample := range pp.Sample {
 		callCount := sample.Value[countIndex]
 		if callCount < 1 {
@@ -155,51 +826,169 @@ func (p *Profile) addCPUSamples(pp *pprof.Profile) {
 		}
 		cpuTime := uint64(sample.Value[valueIndex]) / 1000 // Convert ns to us
 
-		// A sample contains a stack trace, which is made of locations.
-		// A location has one or more lines (>1 if functions are inlined).
-		// Each line points to a function.
-		stack := make([]*Function, 0, 10)
-
-		// PProf stack data is stored leaf-first. We need it to be root-first.
-		for i := len(sample.Location) - 1; i >= 0; i-- {
-			location := sample.Location[i]
-			for j := len(location.Line) - 1; j >= 0; j-- {
-				line := location.Line[j]
-				f := p.getMatchingFunction(line.Function)
+		stack := p.stackOf(sample, cFrameNames)
+		if p.options.MemoryAttribution == MemoryLeafOnly {
+			if len(stack) > 0 {
+				stack[len(stack)-1].AddReferences(int(callCount))
+			}
+		} else {
+			for _, f := range stack {
 				f.AddReferences(int(callCount))
-				stack = append(stack, f)
 			}
 		}
 
-		p.Samples = append(p.Samples, newSample(int(callCount), cpuTime, stack))
+		p.Samples = append(p.Samples, newSample(int(callCount), cpuTime, stack, sampleLabels(sample)))
 	}
 }
 
+// stackOf returns the root-first call stack of a pprof sample.
+func (p *Profile) stackOf(sample *pprof.Sample, cFrameNames map[*pprof.Location]string) []*Function {
+	// A sample contains a stack trace, which is made of locations.
+	// A location has one or more lines (>1 if functions are inlined).
+	// Each line points to a function.
+	stack := make([]*Function, 0, 10)
+
+	// PProf stack data is stored leaf-first. We need it to be root-first.
+	for i := len(sample.Location) - 1; i >= 0; i-- {
+		location := sample.Location[i]
+		if name, ok := cFrameNames[location]; ok {
+			stack = append(stack, p.getFunctionNamed(name))
+			continue
+		}
+		lines := p.locationLines(location)
+		for j := len(lines) - 1; j >= 0; j-- {
+			stack = append(stack, p.getMatchingFunction(lines[j]))
+		}
+	}
+	return stack
+}
+
+// locationLines returns the lines of a location to expand into frames,
+// leaf-first like in pprof data, according to the inlining policy.
+func (p *Profile) locationLines(location *pprof.Location) []pprof.Line {
+	lines := location.Line
+	if len(lines) < 2 {
+		return lines
+	}
+	switch p.options.Inlining {
+	case InliningCollapse:
+		// The last line is the function the others are inlined into.
+		return lines[len(lines)-1:]
+	case InliningLeafOnly:
+		return lines[:1]
+	}
+	return lines
+}
+
+func sampleLabels(sample *pprof.Sample) map[string]string {
+	if len(sample.Label) == 0 {
+		return nil
+	}
+	labels := make(map[string]string, len(sample.Label))
+	for key, values := range sample.Label {
+		if len(values) > 0 {
+			labels[key] = values[0]
+		}
+	}
+	return labels
+}
+
 func (p *Profile) postProcessSamples() {
+	// The memory of the heap profiles added after the CPU samples referencing
+	// a function is only distributed now.
+	for _, f := range p.Functions {
+		if f.ReferenceCount > 0 {
+			f.DistributedMemoryCost = f.MemoryCost / uint64(f.ReferenceCount)
+		}
+	}
+
 	for _, sample := range p.Samples {
-		decycleStack(sample.Stack)
-		memUsage := uint64(0)
-		for _, f := range sample.Stack {
-			memUsage += f.DistributedMemoryCost
+		sample.Stack = p.decycle(sample.Stack)
+		switch p.MemoryAttribution {
+		case MemoryPerStack:
+			// Memory comes from the heap samples only.
+			sample.MemoryCosts = []uint64{}
+		case MemoryLeafOnly:
+			sample.MemUsage = 0
+			sample.MemoryCosts = []uint64{}
+			if len(sample.Stack) > 0 {
+				leaf := sample.Stack[len(sample.Stack)-1]
+				sample.MemUsage = leaf.DistributedMemoryCost
+				sample.MemoryCosts = []uint64{leaf.DistributedMemoryCost * uint64(sample.Count)}
+			}
+		default:
+			memUsage := uint64(0)
+			sample.MemoryCosts = make([]uint64, len(sample.Stack))
+			for i, f := range sample.Stack {
+				memUsage += f.DistributedMemoryCost
+				sample.MemoryCosts[i] = f.DistributedMemoryCost * uint64(sample.Count)
+			}
+			sample.MemUsage = memUsage
+		}
+	}
+
+	for _, sample := range p.memorySamples {
+		sample.Stack = p.decycle(sample.Stack)
+		sample.MemUsage = sample.MemoryCosts[0]
+		p.Samples = append(p.Samples, sample)
+	}
+	p.memorySamples = nil
+}
+
+// decycle removes the cycles of a call stack according to the read options,
+// and returns the resulting stack, which may be shorter.
+func (p *Profile) decycle(stack []*Function) []*Function {
+	if p.options.Recursion == RecursionCollapse {
+		return collapseRecursion(stack)
+	}
+	decycleStack(stack, p.decycled)
+	return stack
+}
+
+// collapseRecursion collapses the cycles of a call stack: when a function is
+// encountered again, the frames since its first occurrence are removed, so
+// that the cost of the recursion is aggregated into a single node. The stack
+// is modified in place.
+func collapseRecursion(stack []*Function) []*Function {
+	positions := make(map[string]int)
+	collapsed := stack[:0]
+	for _, f := range stack {
+		if i, ok := positions[f.Name]; ok {
+			for _, removed := range collapsed[i+1:] {
+				delete(positions, removed.Name)
+			}
+			collapsed = collapsed[:i+1]
+			continue
 		}
-		sample.MemUsage = memUsage
+		positions[f.Name] = len(collapsed)
+		collapsed = append(collapsed, f)
 	}
+	return collapsed
 }
 
 // Decycle a sample's call stack.
 // If the same function is encountered multiple times in a goroutine stack,
 // create duplicates with @1, @2, etc appended to the name so that they show
-// up as different names in the BF visualizer.
-func decycleStack(stack []*Function) {
+// up as different names in the BF visualizer. The duplicates are interned in
+// the specified map, if not nil, so that recursive stacks share them.
+func decycleStack(stack []*Function, interned map[string]*Function) {
 	seen := make(map[string]int)
 	for i, f := range stack {
 		if dupCount, ok := seen[f.Name]; ok {
-			stack[i] = &Function{
-				Name:                  fmt.Sprintf("%s@%d", f.Name, dupCount),
-				MemoryCost:            f.MemoryCost,
-				DistributedMemoryCost: f.DistributedMemoryCost,
-				ReferenceCount:        f.ReferenceCount,
+			name := fmt.Sprintf("%s@%d", f.Name, dupCount)
+			duplicate, ok := interned[name]
+			if !ok {
+				duplicate = &Function{
+					Name:                  name,
+					MemoryCost:            f.MemoryCost,
+					DistributedMemoryCost: f.DistributedMemoryCost,
+					ReferenceCount:        f.ReferenceCount,
+				}
+				if interned != nil {
+					interned[name] = duplicate
+				}
 			}
+			stack[i] = duplicate
 			seen[f.Name] = dupCount + 1
 		} else {
 			seen[f.Name] = 1
@@ -216,7 +1005,9 @@ func getBasename(path string) string {
 	return path
 }
 
-func getExeName() string {
+// ExecutableName returns the name of the running executable, without its
+// directory nor extension.
+func ExecutableName() string {
 	name, err := os.Executable()
 	if err != nil {
 		return "go-unknown"
@@ -252,7 +1043,7 @@ func getDumpStartIndex(pathPrefix string) int {
 // It uses the naming scheme exename-type-index.pprof, starting at the next
 // index after the last one found in the specified directory.
 func DumpProfiles(cpuBuffers, memBuffers []*bytes.Buffer, dstDir string) (err error) {
-	pathPrefix := path.Join(dstDir, getExeName())
+	pathPrefix := path.Join(dstDir, ExecutableName())
 	startIndex := getDumpStartIndex(pathPrefix)
 
 	for i, buff := range cpuBuffers {
@@ -1,8 +1,14 @@
 package pprof_reader
 
 import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
 	"reflect"
 	"testing"
+	"time"
+
+	pprof "github.com/blackfireio/go-blackfire/pprof_reader/internal/profile"
 )
 
 func newTestStack(entries ...string) (stack []*Function) {
@@ -17,7 +23,7 @@ func newTestStack(entries ...string) (stack []*Function) {
 func TestDecycleStack(t *testing.T) {
 	expected := newTestStack("a", "b", "c", "b@1", "c@1", "d")
 	actual := newTestStack("a", "b", "c", "b", "c", "d")
-	decycleStack(actual)
+	decycleStack(actual, nil)
 	if !reflect.DeepEqual(expected, actual) {
 		t.Errorf("Expected %v but got %v", expected, actual)
 	}
@@ -26,8 +32,674 @@ func TestDecycleStack(t *testing.T) {
 func TestDecycleStackComplex(t *testing.T) {
 	expected := newTestStack("a", "b", "c", "b@1", "c@1", "d", "a@1", "b@2", "c@2", "f")
 	actual := newTestStack("a", "b", "c", "b", "c", "d", "a", "b", "c", "f")
-	decycleStack(actual)
+	decycleStack(actual, nil)
 	if !reflect.DeepEqual(expected, actual) {
 		t.Errorf("Expected %v but got %v", expected, actual)
 	}
 }
+
+func TestDecycleStackDeepCycles(t *testing.T) {
+	cases := []struct {
+		stack    []string
+		expected []string
+	}{
+		{[]string{"a", "a", "a", "a"}, []string{"a", "a@1", "a@2", "a@3"}},
+		{[]string{"a", "b", "a", "b", "a", "b"}, []string{"a", "b", "a@1", "b@1", "a@2", "b@2"}},
+		{[]string{"a", "b", "c", "a", "c", "b", "a"}, []string{"a", "b", "c", "a@1", "c@1", "b@1", "a@2"}},
+	}
+	for _, c := range cases {
+		actual := newTestStack(c.stack...)
+		decycleStack(actual, nil)
+		if expected := newTestStack(c.expected...); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestCollapseRecursion(t *testing.T) {
+	cases := []struct {
+		stack    []string
+		expected []string
+	}{
+		{[]string{"a", "b", "c"}, []string{"a", "b", "c"}},
+		{[]string{"a", "b", "b", "b", "c"}, []string{"a", "b", "c"}},
+		{[]string{"a", "b", "c", "b", "c", "d"}, []string{"a", "b", "c", "d"}},
+		{[]string{"a", "b", "c", "a", "d"}, []string{"a", "d"}},
+		{[]string{"a", "b", "c", "a", "c", "e"}, []string{"a", "c", "e"}},
+	}
+	for _, c := range cases {
+		actual := collapseRecursion(newTestStack(c.stack...))
+		if expected := newTestStack(c.expected...); !reflect.DeepEqual(expected, actual) {
+			t.Errorf("Expected %v but got %v", expected, actual)
+		}
+	}
+}
+
+func TestAggregateSamples(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 2, CPUTime: 200, Stack: newTestStack("a", "b")},
+		{Count: 1, CPUTime: 100, Stack: newTestStack("a", "c")},
+		{Count: 4, CPUTime: 400, Stack: newTestStack("a", "b")},
+	}
+
+	aggregated := profile.AggregateSamples(2)
+	if len(aggregated.Samples) != 2 {
+		t.Fatalf("Expected 2 samples but got %v", len(aggregated.Samples))
+	}
+	if s := aggregated.Samples[0]; s.Count != 3 || s.CPUTime != 300 {
+		t.Errorf("Expected count 3 and cpu 300 but got %v and %v", s.Count, s.CPUTime)
+	}
+	if s := aggregated.Samples[1]; s.Count != 1 || s.CPUTime != 50 {
+		t.Errorf("Expected count 1 and cpu 50 but got %v and %v", s.Count, s.CPUTime)
+	}
+	if profile.Samples[0].Count != 2 {
+		t.Errorf("Original profile was modified")
+	}
+}
+
+func TestSegmentByLabels(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, Stack: newTestStack("a", "b"), Labels: map[string]string{"tenant": "acme", "other": "x"}},
+		{Count: 1, Stack: newTestStack("a", "c")},
+		{Count: 1, Stack: newTestStack("a", "d"), Labels: map[string]string{"tenant": "acme"}},
+	}
+
+	segmented := profile.SegmentByLabels([]string{"tenant"})
+	expected := [][]string{
+		{"label:tenant=acme", "a", "b"},
+		{"a", "c"},
+		{"label:tenant=acme", "a", "d"},
+	}
+	for i, sample := range segmented.Samples {
+		var names []string
+		for _, f := range sample.Stack {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(expected[i], names) {
+			t.Errorf("Expected %v but got %v", expected[i], names)
+		}
+	}
+	if segmented.Samples[0].Stack[0] != segmented.Samples[2].Stack[0] {
+		t.Errorf("Expected samples with the same labels to share their segment node")
+	}
+}
+
+func TestFilterByLabel(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, Stack: newTestStack("a", "b"), Labels: map[string]string{"blackfire": "job"}},
+		{Count: 1, Stack: newTestStack("a", "c")},
+		{Count: 1, Stack: newTestStack("a", "d"), Labels: map[string]string{"other": "x"}},
+	}
+
+	filtered := profile.FilterByLabel("blackfire")
+	if len(filtered.Samples) != 1 || filtered.Samples[0] != profile.Samples[0] {
+		t.Errorf("Expected only the labeled sample but got %v", filtered.Samples)
+	}
+}
+
+func TestCPUByGoroutine(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, CPUTime: 10, Stack: newTestStack("runtime.main", "main.main", "main.run")},
+		{Count: 2, CPUTime: 50, Stack: newTestStack("runtime.goexit", "main.poll", "net.Read")},
+		{Count: 1, CPUTime: 20, Stack: newTestStack("runtime.goexit", "runtime.gcBgMarkWorker", "runtime.gcDrain")},
+		{Count: 3, CPUTime: 30, Stack: newTestStack("runtime.goexit", "main.poll", "time.Sleep")},
+		{Count: 1, Stack: newTestStack("runtime.goexit", "main.idle")},
+	}
+
+	expected := []GoroutineCPU{
+		{Function: "main.poll", CPUTime: 80, Count: 5},
+		{Function: "runtime.gcBgMarkWorker", CPUTime: 20, Count: 1},
+		{Function: "main.main", CPUTime: 10, Count: 1},
+	}
+	if report := profile.CPUByGoroutine(); !reflect.DeepEqual(expected, report) {
+		t.Errorf("Expected %v but got %v", expected, report)
+	}
+}
+
+func TestTruncateStacks(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, Stack: newTestStack("a", "b"), MemoryCosts: []uint64{1, 2}},
+		{Count: 2, Stack: newTestStack("a", "b", "c", "d"), MemoryCosts: []uint64{1, 2, 3, 4}},
+		{Count: 3, Stack: newTestStack("a", "b", "c", "d", "e"), MemoryCosts: []uint64{1, 2, 3, 4, 5}},
+	}
+
+	truncated, count := profile.TruncateStacks(3)
+	if count != 2 {
+		t.Errorf("Expected 2 truncated stacks but got %v", count)
+	}
+	expected := [][]string{
+		{"a", "b"},
+		{"a", "b", TruncatedNode},
+		{"a", "b", TruncatedNode},
+	}
+	expectedCosts := [][]uint64{{1, 2}, {1, 2, 7}, {1, 2, 12}}
+	for i, sample := range truncated.Samples {
+		var names []string
+		for _, f := range sample.Stack {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(expected[i], names) {
+			t.Errorf("Expected %v but got %v", expected[i], names)
+		}
+		if !reflect.DeepEqual(expectedCosts[i], sample.MemoryCosts) {
+			t.Errorf("Expected costs %v but got %v", expectedCosts[i], sample.MemoryCosts)
+		}
+	}
+	if node := truncated.Samples[1].Stack[2]; node != truncated.Samples[2].Stack[2] || node.ReferenceCount != 5 {
+		t.Errorf("Expected the truncated stacks to share the truncated node")
+	}
+
+	if same, count := profile.TruncateStacks(0); same != profile || count != 0 {
+		t.Errorf("Expected the profile to be left untouched without a limit")
+	}
+}
+
+func TestPruneRootedIn(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, Stack: newTestStack("runtime.goexit", "github.com/blackfireio/go-blackfire.(*Probe).onProfileDisableTriggered.func1")},
+		{Count: 1, Stack: newTestStack("runtime.goexit", "main.main", "github.com/blackfireio/go-blackfire.Enable")},
+		{Count: 1, Stack: newTestStack("github.com/blackfireio/go-blackfire/pprof_reader.parseBuffers.func1")},
+		{Count: 1, Stack: newTestStack("github.com/blackfireio/go-blackfire-extra.Run")},
+	}
+
+	pruned := profile.PruneRootedIn([]string{"github.com/blackfireio/go-blackfire"})
+	if len(pruned.Samples) != 2 || pruned.Samples[0] != profile.Samples[1] || pruned.Samples[1] != profile.Samples[3] {
+		t.Errorf("Expected only the samples rooted outside of the probe but got %v", pruned.Samples)
+	}
+}
+
+func TestGroupRootedIn(t *testing.T) {
+	profile := NewProfile()
+	profile.Samples = []*Sample{
+		{Count: 1, Stack: newTestStack("runtime.goexit", "github.com/blackfireio/go-blackfire.upload")},
+		{Count: 1, Stack: newTestStack("runtime.goexit", "main.main")},
+		{Count: 2, Stack: newTestStack("github.com/blackfireio/go-blackfire.convert")},
+	}
+
+	grouped := profile.GroupRootedIn([]string{"github.com/blackfireio/go-blackfire"}, ProbeNode)
+	expected := [][]string{
+		{"blackfire-probe", "runtime.goexit", "github.com/blackfireio/go-blackfire.upload"},
+		{"runtime.goexit", "main.main"},
+		{"blackfire-probe", "github.com/blackfireio/go-blackfire.convert"},
+	}
+	for i, sample := range grouped.Samples {
+		var names []string
+		for _, f := range sample.Stack {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(expected[i], names) {
+			t.Errorf("Expected %v but got %v", expected[i], names)
+		}
+	}
+	if node := grouped.Samples[0].Stack[0]; node != grouped.Samples[2].Stack[0] || node.ReferenceCount != 3 {
+		t.Errorf("Expected the samples to share the probe node")
+	}
+}
+
+func TestLocationLines(t *testing.T) {
+	location := &pprof.Location{Line: []pprof.Line{
+		{Function: &pprof.Function{Name: "pkg.leaf"}},
+		{Function: &pprof.Function{Name: "pkg.middle"}},
+		{Function: &pprof.Function{Name: "pkg.caller"}},
+	}}
+	cases := []struct {
+		policy   InliningPolicy
+		expected []string
+	}{
+		{InliningExpand, []string{"pkg.leaf", "pkg.middle", "pkg.caller"}},
+		{InliningCollapse, []string{"pkg.caller"}},
+		{InliningLeafOnly, []string{"pkg.leaf"}},
+	}
+	for _, c := range cases {
+		profile := NewProfile()
+		profile.options.Inlining = c.policy
+		var names []string
+		for _, line := range profile.locationLines(location) {
+			names = append(names, line.Function.Name)
+		}
+		if !reflect.DeepEqual(c.expected, names) {
+			t.Errorf("Expected %v with policy %v but got %v", c.expected, c.policy, names)
+		}
+	}
+}
+
+func TestMergeProfiles(t *testing.T) {
+	parent := NewProfile()
+	parent.CpuSampleRateHz = 100
+	parent.Duration = time.Second
+	parent.Samples = []*Sample{{Count: 1, Stack: newTestStack("main.main", "main.fork")}}
+	worker := NewProfile()
+	worker.CpuSampleRateHz = 200
+	worker.Duration = 2 * time.Second
+	worker.Samples = []*Sample{
+		{Count: 2, Stack: newTestStack("main.main", "main.work")},
+		{Count: 3, Stack: newTestStack("main.main", "main.work")},
+	}
+
+	merged := MergeProfiles([]ProcessProfile{{Profile: parent}, {Name: "worker-1", Profile: worker}})
+	expected := [][]string{
+		{"main.main", "main.fork"},
+		{"worker-1", "main.main", "main.work"},
+		{"worker-1", "main.main", "main.work"},
+	}
+	if len(merged.Samples) != len(expected) {
+		t.Fatalf("Expected %v samples but got %v", len(expected), len(merged.Samples))
+	}
+	for i, sample := range merged.Samples {
+		var names []string
+		for _, f := range sample.Stack {
+			names = append(names, f.Name)
+		}
+		if !reflect.DeepEqual(expected[i], names) {
+			t.Errorf("Expected %v but got %v", expected[i], names)
+		}
+	}
+	if node := merged.Samples[1].Stack[0]; node.ReferenceCount != 5 {
+		t.Errorf("Expected 5 references to the process node but got %v", node.ReferenceCount)
+	}
+	if merged.CpuSampleRateHz != 100 || merged.Duration != 2*time.Second {
+		t.Errorf("Expected the sample rate of the first profile and the longest duration, got %v and %v", merged.CpuSampleRateHz, merged.Duration)
+	}
+	if len(worker.Samples[0].Stack) != 2 {
+		t.Errorf("Original profile was modified")
+	}
+}
+
+func TestDiffMemory(t *testing.T) {
+	before := NewProfile()
+	before.Samples = []*Sample{
+		{Count: 1, MemUsage: 100, Stack: newTestStack("main.main", "main.cache")},
+		{Count: 1, MemUsage: 50, Stack: newTestStack("main.main", "main.buffer")},
+		{Count: 1, MemUsage: 30, Stack: newTestStack("main.main", "main.freed")},
+	}
+	after := NewProfile()
+	after.Samples = []*Sample{
+		{Count: 1, MemUsage: 80, Stack: newTestStack("main.main", "main.cache")},
+		{Count: 1, MemUsage: 70, Stack: newTestStack("main.main", "main.cache")},
+		{Count: 1, MemUsage: 40, Stack: newTestStack("main.main", "main.buffer")},
+		{Count: 1, MemUsage: 20, Stack: newTestStack("main.main", "main.leak")},
+		{Count: 1, CPUTime: 10, Stack: newTestStack("main.main", "main.run")},
+	}
+
+	diff := DiffMemory(before, after)
+	if diff.MemoryAttribution != MemoryPerStack {
+		t.Errorf("Expected the MemoryPerStack attribution but got %v", diff.MemoryAttribution)
+	}
+	expected := map[string]uint64{"main.cache": 50, "main.leak": 20}
+	if len(diff.Samples) != len(expected) {
+		t.Fatalf("Expected %v samples but got %v", len(expected), len(diff.Samples))
+	}
+	for _, sample := range diff.Samples {
+		leaf := sample.Stack[len(sample.Stack)-1].Name
+		if sample.MemUsage != expected[leaf] || !reflect.DeepEqual(sample.MemoryCosts, []uint64{expected[leaf]}) {
+			t.Errorf("Expected %v growth for %v but got %v (%v)", expected[leaf], leaf, sample.MemUsage, sample.MemoryCosts)
+		}
+	}
+	if after.Samples[0].MemUsage != 80 {
+		t.Errorf("Original profile was modified")
+	}
+}
+
+// newTestPProf returns a pprof buffer with a sample of the specified values
+// for each of the root-first stacks.
+func newTestPProf(t *testing.T, values [][]int64, stacks ...[]string) *bytes.Buffer {
+	pp := &pprof.Profile{Period: 10000000}
+	for range values[0] {
+		pp.SampleType = append(pp.SampleType, &pprof.ValueType{Type: "samples", Unit: "count"})
+	}
+	locations := make(map[string]*pprof.Location)
+	for i, stack := range stacks {
+		sample := &pprof.Sample{Value: values[i]}
+		for j := len(stack) - 1; j >= 0; j-- {
+			name := stack[j]
+			loc, ok := locations[name]
+			if !ok {
+				id := uint64(len(locations) + 1)
+				function := &pprof.Function{ID: id, Name: name, SystemName: name}
+				loc = &pprof.Location{ID: id, Line: []pprof.Line{{Function: function}}}
+				locations[name] = loc
+				pp.Function = append(pp.Function, function)
+				pp.Location = append(pp.Location, loc)
+			}
+			sample.Location = append(sample.Location, loc)
+		}
+		pp.Sample = append(pp.Sample, sample)
+	}
+	var buffer bytes.Buffer
+	if err := pp.Write(&buffer); err != nil {
+		t.Fatal(err)
+	}
+	return &buffer
+}
+
+func TestReadMemoryAttribution(t *testing.T) {
+	read := func(attribution MemoryAttribution) *Profile {
+		mem := newTestPProf(t, [][]int64{{0, 0, 0, 100}}, []string{"main.main", "main.alloc"})
+		cpu := newTestPProf(t, [][]int64{{1, 10000}, {1, 20000}},
+			[]string{"main.main", "main.alloc"},
+			[]string{"main.main", "main.alloc", "main.helper"})
+		profile, err := ReadFromPProfWithOptions([]*bytes.Buffer{cpu}, []*bytes.Buffer{mem}, ReadOptions{MemoryAttribution: attribution})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return profile
+	}
+
+	profile := read(MemoryLeafOnly)
+	if len(profile.Samples) != 2 {
+		t.Fatalf("Expected 2 samples but got %v", len(profile.Samples))
+	}
+	// main.alloc is the leaf of the first sample only, which gets all of its
+	// memory.
+	for i, expected := range []uint64{100, 0} {
+		sample := profile.Samples[i]
+		leaf := len(sample.Stack) - 1
+		if sample.MemUsage != expected || sample.FrameMemoryCost(leaf) != expected {
+			t.Errorf("Expected %v memory for sample %v but got %v", expected, i, sample.MemUsage)
+		}
+		for j := 0; j < leaf; j++ {
+			if cost := sample.FrameMemoryCost(j); cost != 0 {
+				t.Errorf("Expected no memory for %v but got %v", sample.Stack[j], cost)
+			}
+		}
+	}
+
+	profile = read(MemoryPerStack)
+	if len(profile.Samples) != 3 {
+		t.Fatalf("Expected 3 samples but got %v", len(profile.Samples))
+	}
+	if sample := profile.Samples[2]; sample.Count != 0 || sample.MemUsage != 100 || sample.CPUTime != 0 {
+		t.Errorf("Expected a memory-only sample, got count %v, memory %v and CPU %v", sample.Count, sample.MemUsage, sample.CPUTime)
+	}
+}
+
+func TestCFrameFallbackName(t *testing.T) {
+	location := &pprof.Location{Address: 0x1234}
+	if name := cFrameFallbackName(location); name != "0x1234" {
+		t.Errorf("Expected [0x1234] but got [%v]", name)
+	}
+
+	location.Mapping = &pprof.Mapping{File: "/usr/lib/libfoo.so.1", Start: 0x1000, Offset: 0x200}
+	if name := cFrameFallbackName(location); name != "libfoo.so.1+0x434" {
+		t.Errorf("Expected [libfoo.so.1+0x434] but got [%v]", name)
+	}
+}
+
+func TestAnnotateClosure(t *testing.T) {
+	cases := []struct {
+		name     string
+		filename string
+		line     int64
+		expected string
+	}{
+		{"main.main", "/src/main.go", 10, "main.main"},
+		{"main.main.func1", "", 0, "main.main.func1 (closure in main.main)"},
+		{"main.main.func1.2", "/src/main.go", 12, "main.main.func1.2 (closure in main.main at main.go:12)"},
+		{"pkg.(*T).Run.func3", "/src/t.go", 7, "pkg.(*T).Run.func3 (closure in pkg.(*T).Run at t.go:7)"},
+		{"pkg.glob..func1", "/src/vars.go", 3, "pkg.glob..func1 (closure in pkg package var at vars.go:3)"},
+	}
+	for _, c := range cases {
+		if actual := annotateClosure(c.name, c.filename, c.line); actual != c.expected {
+			t.Errorf("Expected [%v] but got [%v]", c.expected, actual)
+		}
+	}
+}
+
+func TestCollapseGenerics(t *testing.T) {
+	cases := map[string]string{
+		"main.main": "main.main",
+		"pkg.Map[go.shape.string,go.shape.[]int]": "pkg.Map[...]",
+		"pkg.Map[...]":                                 "pkg.Map[...]",
+		"pkg.(*List[go.shape.int]).Push":               "pkg.(*List[...]).Push",
+		"pkg.Sort[go.shape.struct { A [2]int }].func1": "pkg.Sort[...].func1",
+	}
+	for name, expected := range cases {
+		if actual := collapseGenerics(name); actual != expected {
+			t.Errorf("Expected [%v] but got [%v]", expected, actual)
+		}
+	}
+}
+
+func TestAppendFileLine(t *testing.T) {
+	cases := []struct {
+		filename string
+		line     int64
+		expected string
+	}{
+		{"", 12, "pkg.Func"},
+		{"/src/pkg/client.go", 0, "pkg.Func (pkg/client.go)"},
+		{"/src/pkg/client.go", 12, "pkg.Func (pkg/client.go:12)"},
+	}
+	for _, c := range cases {
+		if actual := appendFileLine("pkg.Func", c.filename, c.line); actual != c.expected {
+			t.Errorf("Expected [%v] but got [%v]", c.expected, actual)
+		}
+	}
+}
+
+func TestFunctionNameFileLine(t *testing.T) {
+	line := pprof.Line{
+		Function: &pprof.Function{Name: "pkg.Func", Filename: "/src/pkg/client.go", StartLine: 10},
+		Line:     14,
+	}
+
+	profile := NewProfile()
+	if name := profile.functionName(line); name != "pkg.Func" {
+		t.Errorf("Expected [pkg.Func] but got [%v]", name)
+	}
+	profile.options.FileLine = FileLineDefinition
+	if name := profile.functionName(line); name != "pkg.Func (pkg/client.go:10)" {
+		t.Errorf("Expected [pkg.Func (pkg/client.go:10)] but got [%v]", name)
+	}
+	profile.options.FileLine = FileLineCallSite
+	if name := profile.functionName(line); name != "pkg.Func (pkg/client.go:14)" {
+		t.Errorf("Expected [pkg.Func (pkg/client.go:14)] but got [%v]", name)
+	}
+}
+
+func readFixture(t testing.TB, name string) []*bytes.Buffer {
+	data, err := ioutil.ReadFile("fixtures/" + name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return []*bytes.Buffer{bytes.NewBuffer(data)}
+}
+
+func TestReadFromPProfInternsFunctions(t *testing.T) {
+	for _, options := range []ReadOptions{{}, {FileLine: FileLineCallSite}} {
+		profile, err := ReadFromPProfWithOptions(readFixture(t, "wt.pprof.gz"), nil, options)
+		if err != nil {
+			t.Fatal(err)
+		}
+		functions := make(map[string]*Function)
+		for _, sample := range profile.Samples {
+			for _, f := range sample.Stack {
+				if interned, ok := functions[f.Name]; ok && interned != f {
+					t.Fatalf("Expected a single %v function", f.Name)
+				}
+				functions[f.Name] = f
+			}
+		}
+		if len(functions) < len(profile.Functions) {
+			t.Errorf("Expected the stacks to use all the %v functions, got %v", len(profile.Functions), len(functions))
+		}
+		if profile.lineFunctions != nil {
+			t.Errorf("Expected the line cache to be released")
+		}
+	}
+}
+
+func TestDecycleStackInterned(t *testing.T) {
+	interned := make(map[string]*Function)
+	first := newTestStack("a", "b", "a")
+	second := newTestStack("a", "a")
+	decycleStack(first, interned)
+	decycleStack(second, interned)
+	if first[2] != second[1] || first[2].Name != "a@1" {
+		t.Errorf("Expected the a@1 duplicates to be shared, got %v and %v", first[2], second[1])
+	}
+}
+
+func BenchmarkReadFromPProf(b *testing.B) {
+	data := readFixture(b, "wt.pprof.gz")[0].Bytes()
+	for _, options := range []ReadOptions{{}, {FileLine: FileLineCallSite, AnnotateClosures: true}} {
+		b.Run(fmt.Sprintf("FileLine=%v", options.FileLine), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ReadFromPProfWithOptions([]*bytes.Buffer{bytes.NewBuffer(data)}, nil, options); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestReadFromPProfBufferSets(t *testing.T) {
+	single, err := ReadFromPProf(readFixture(t, "wt.pprof.gz"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := readFixture(t, "wt.pprof.gz")[0].Bytes()
+	buffers := func() []*bytes.Buffer {
+		var buffers []*bytes.Buffer
+		for i := 0; i < 8; i++ {
+			buffers = append(buffers, bytes.NewBuffer(data), &bytes.Buffer{})
+		}
+		return buffers
+	}
+	profile, err := ReadFromPProf(buffers(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.Samples) != 8*len(single.Samples) {
+		t.Fatalf("Expected %v samples but got %v", 8*len(single.Samples), len(profile.Samples))
+	}
+	// The samples come in the order of the buffers.
+	for i, sample := range profile.Samples {
+		expected := single.Samples[i%len(single.Samples)]
+		if stackKey(sample.Stack) != stackKey(expected.Stack) || sample.CPUTime != expected.CPUTime {
+			t.Fatalf("Unexpected sample %v: %v", i, sample.Stack)
+		}
+	}
+
+	corrupted := buffers()
+	corrupted[5] = bytes.NewBufferString("not a profile")
+	if _, err := ReadFromPProf(corrupted, nil); err == nil {
+		t.Errorf("Expected an error for the corrupted buffer")
+	}
+}
+
+func TestReader(t *testing.T) {
+	data := readFixture(t, "wt.pprof.gz")[0].Bytes()
+	expected, err := ReadFromPProf([]*bytes.Buffer{bytes.NewBuffer(data), bytes.NewBuffer(data)}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	reader := NewReader(ReadOptions{})
+	first := bytes.NewBuffer(data)
+	reader.Add(first, &bytes.Buffer{})
+	reader.Add(bytes.NewBuffer(data), nil)
+	profile, err := reader.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.Samples) != len(expected.Samples) {
+		t.Fatalf("Expected %v samples but got %v", len(expected.Samples), len(profile.Samples))
+	}
+	for i, sample := range profile.Samples {
+		if stackKey(sample.Stack) != stackKey(expected.Samples[i].Stack) {
+			t.Fatalf("Unexpected sample %v: %v", i, sample.Stack)
+		}
+	}
+	if first.Len() != len(data) {
+		t.Errorf("Expected the buffers to be left untouched")
+	}
+
+	reader = NewReader(ReadOptions{})
+	reader.Add(bytes.NewBuffer(data), nil)
+	reader.Add(bytes.NewBufferString("not a profile"), nil)
+	if _, err := reader.Profile(); err == nil {
+		t.Errorf("Expected an error for the corrupted buffer")
+	}
+}
+
+func TestReaderMemoryAfterCPU(t *testing.T) {
+	function := &pprof.Function{ID: 1, Name: "main.work", SystemName: "main.work", Filename: "/src/app/main.go"}
+	location := &pprof.Location{ID: 1, Line: []pprof.Line{{Function: function, Line: 14}}}
+	cpu := &pprof.Profile{
+		SampleType: []*pprof.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Period:     10000000,
+		Function:   []*pprof.Function{function},
+		Location:   []*pprof.Location{location},
+		Sample:     []*pprof.Sample{{Value: []int64{1, 10000}, Location: []*pprof.Location{location}}},
+	}
+	heap := &pprof.Profile{
+		SampleType: []*pprof.ValueType{{Type: "alloc_objects", Unit: "count"}, {Type: "alloc_space", Unit: "bytes"}, {Type: "inuse_objects", Unit: "count"}, {Type: "inuse_space", Unit: "bytes"}},
+		Period:     512 * 1024,
+		Function:   []*pprof.Function{function},
+		Location:   []*pprof.Location{location},
+		Sample:     []*pprof.Sample{{Value: []int64{1, 1000, 1, 1000}, Location: []*pprof.Location{location}}},
+	}
+	var cpuBuffer, heapBuffer bytes.Buffer
+	if err := cpu.Write(&cpuBuffer); err != nil {
+		t.Fatal(err)
+	}
+	if err := heap.Write(&heapBuffer); err != nil {
+		t.Fatal(err)
+	}
+
+	// The heap profile of a window is converted after the CPU samples of the
+	// previous one, which reference the same function.
+	reader := NewReader(ReadOptions{})
+	reader.Add(bytes.NewBuffer(cpuBuffer.Bytes()), nil)
+	reader.Add(nil, bytes.NewBuffer(heapBuffer.Bytes()))
+	profile, err := reader.Profile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(profile.Samples) != 1 {
+		t.Fatalf("Expected 1 sample but got %v", len(profile.Samples))
+	}
+	if profile.Samples[0].MemUsage != 1000 {
+		t.Errorf("Expected a memory usage of 1000 but got %v", profile.Samples[0].MemUsage)
+	}
+}
+
+func TestAddMetricSampleNaming(t *testing.T) {
+	function := &pprof.Function{ID: 1, Name: "main.work", SystemName: "main.work", Filename: "/src/app/main.go", StartLine: 10}
+	location := &pprof.Location{ID: 1, Line: []pprof.Line{{Function: function, Line: 14}}}
+	pp := &pprof.Profile{
+		SampleType: []*pprof.ValueType{{Type: "samples", Unit: "count"}, {Type: "cpu", Unit: "nanoseconds"}},
+		Period:     10000000,
+		Function:   []*pprof.Function{function},
+		Location:   []*pprof.Location{location},
+		Sample:     []*pprof.Sample{{Value: []int64{1, 10000}, Location: []*pprof.Location{location}}},
+	}
+	var cpu bytes.Buffer
+	if err := pp.Write(&cpu); err != nil {
+		t.Fatal(err)
+	}
+	profile, err := ReadFromPProfWithOptions([]*bytes.Buffer{&cpu}, nil, ReadOptions{FileLine: FileLineDefinition})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The metric is accounted to the node of the CPU samples of the
+	// function, named after its definition line rather than the call site.
+	profile.AddMetricSample([]StackFrame{{Function: "main.work", File: "/src/app/main.go", Line: 12}}, "rows", 1, 5)
+	cpuFunction := profile.Samples[0].Stack[0]
+	metricFunction := profile.Samples[1].Stack[0]
+	if cpuFunction != metricFunction {
+		t.Errorf("Expected the metric to be accounted to %v but got %v", cpuFunction.Name, metricFunction.Name)
+	}
+	if expected := "main.work (app/main.go:10)"; metricFunction.Name != expected {
+		t.Errorf("Expected %v but got %v", expected, metricFunction.Name)
+	}
+}
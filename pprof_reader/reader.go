@@ -0,0 +1,107 @@
+package pprof_reader
+
+import (
+	"bytes"
+	"sync"
+
+	pprof "github.com/blackfireio/go-blackfire/pprof_reader/internal/profile"
+)
+
+// Reader converts pprof profiles incrementally: each buffer set is parsed and
+// converted in the background as soon as it is complete, so that only the
+// post-processing of the profile is left when it is needed. It is safe for
+// concurrent use.
+type Reader struct {
+	// The profile the buffer sets are converted into, and the first error
+	// converting them. The conversions are serialized, and the last one to
+	// complete closes converted.
+	profile *Profile
+	err     error
+
+	mutex     sync.Mutex
+	converted chan struct{}
+}
+
+// NewReader returns a Reader converting the profiles with the specified
+// options.
+func NewReader(options ReadOptions) *Reader {
+	profile := NewProfile()
+	profile.options = options
+	profile.MemoryAttribution = options.MemoryAttribution
+	return &Reader{
+		profile: profile,
+	}
+}
+
+// Add starts converting a set of CPU and memory buffers. The buffers must not
+// be written to anymore; they are left untouched, so that they can still be
+// dumped. Empty buffers are skipped. The sets are converted in the order they
+// are added.
+func (r *Reader) Add(cpuBuffer, memBuffer *bytes.Buffer) {
+	cpuData, memData := bytesOf(cpuBuffer), bytesOf(memBuffer)
+	if cpuData == nil && memData == nil {
+		return
+	}
+
+	r.mutex.Lock()
+	previous := r.converted
+	converted := make(chan struct{})
+	r.converted = converted
+	r.mutex.Unlock()
+
+	go func() {
+		defer close(converted)
+		cpuProfile, cpuErr := parseData(cpuData)
+		memProfile, memErr := parseData(memData)
+		if previous != nil {
+			<-previous
+		}
+		if r.err != nil {
+			return
+		}
+		if r.err = memErr; r.err != nil {
+			return
+		}
+		if r.err = cpuErr; r.err != nil {
+			return
+		}
+		if memProfile != nil {
+			r.profile.addMemoryProfile(memProfile)
+		}
+		if cpuProfile != nil {
+			r.profile.addCPUProfile(cpuProfile)
+		}
+	}()
+}
+
+func bytesOf(buffer *bytes.Buffer) []byte {
+	if buffer == nil || buffer.Len() == 0 {
+		return nil
+	}
+	return buffer.Bytes()
+}
+
+func parseData(data []byte) (*pprof.Profile, error) {
+	if data == nil {
+		return nil, nil
+	}
+	return pprof.Parse(bytes.NewReader(data))
+}
+
+// Profile waits for the buffer sets added so far to be converted, and
+// returns the resulting profile, like ReadFromPProfWithOptions. It must only
+// be called once, after the last buffer set is added.
+func (r *Reader) Profile() (*Profile, error) {
+	r.mutex.Lock()
+	converted := r.converted
+	r.mutex.Unlock()
+
+	if converted != nil {
+		<-converted
+	}
+	if r.err != nil {
+		return nil, r.err
+	}
+	r.profile.postProcessSamples()
+	return r.profile, nil
+}
@@ -0,0 +1,95 @@
+package pprof_reader
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	pprof "github.com/blackfireio/go-blackfire/pprof_reader/internal/profile"
+)
+
+// symbolizeCFrames names the locations that have no line information, which
+// is the case of C frames in cgo programs. Names are resolved with addr2line
+// when possible, and fall back to the binary name and offset otherwise.
+func symbolizeCFrames(locations []*pprof.Location) map[*pprof.Location]string {
+	names := make(map[*pprof.Location]string)
+	byFile := make(map[string][]*pprof.Location)
+	for _, location := range locations {
+		if len(location.Line) > 0 {
+			continue
+		}
+		names[location] = cFrameFallbackName(location)
+		if location.Mapping != nil && location.Mapping.File != "" {
+			byFile[location.Mapping.File] = append(byFile[location.Mapping.File], location)
+		}
+	}
+
+	if len(byFile) == 0 {
+		return names
+	}
+	addr2line, err := exec.LookPath("addr2line")
+	if err != nil {
+		return names
+	}
+	for file, fileLocations := range byFile {
+		resolved, err := runAddr2line(addr2line, file, fileLocations)
+		if err != nil {
+			continue
+		}
+		for i, name := range resolved {
+			if name != "" {
+				names[fileLocations[i]] = name
+			}
+		}
+	}
+	return names
+}
+
+func cFrameFallbackName(location *pprof.Location) string {
+	if location.Mapping == nil || location.Mapping.File == "" {
+		return fmt.Sprintf("0x%x", location.Address)
+	}
+	return fmt.Sprintf("%s+0x%x", path.Base(location.Mapping.File), cFrameAddress(location))
+}
+
+// cFrameAddress returns the address of a location as expected by addr2line:
+// relative to the file for shared libraries, absolute otherwise.
+func cFrameAddress(location *pprof.Location) uint64 {
+	m := location.Mapping
+	if strings.HasSuffix(m.File, ".so") || strings.Contains(m.File, ".so.") {
+		return location.Address - m.Start + m.Offset
+	}
+	return location.Address
+}
+
+// runAddr2line resolves the function names of all locations in a single
+// addr2line invocation. Unresolved names are returned empty.
+func runAddr2line(addr2line string, file string, locations []*pprof.Location) ([]string, error) {
+	args := []string{"-f", "-C", "-e", file}
+	for _, location := range locations {
+		args = append(args, fmt.Sprintf("0x%x", cFrameAddress(location)))
+	}
+	output, err := exec.Command(addr2line, args...).Output()
+	if err != nil {
+		return nil, err
+	}
+
+	// addr2line outputs two lines per address: the function, then file:line.
+	names := make([]string, 0, len(locations))
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for len(names) < len(locations) && scanner.Scan() {
+		name := strings.TrimSpace(scanner.Text())
+		if name == "??" {
+			name = ""
+		}
+		names = append(names, name)
+		scanner.Scan()
+	}
+	for len(names) < len(locations) {
+		names = append(names, "")
+	}
+	return names, nil
+}
@@ -1,56 +1,168 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
 package blackfire
 
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/base64"
 	"fmt"
+	"io/ioutil"
 	"math/rand"
 	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
 	"runtime"
 	"runtime/debug"
 	"runtime/pprof"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/blackfireio/go-blackfire/bf_format"
 	"github.com/blackfireio/go-blackfire/pprof_reader"
 	"github.com/pkg/errors"
 )
 
-type profilerState int
+// Probe profiles the current process and sends the profiles to Blackfire.
+// The package-level API proxies to a global Probe; use NewProbe to get an
+// independent one with its own configuration. As the Go runtime only supports
+// one CPU profile at a time, only one probe can be profiling at any time:
+// enabling another one fails with ProfilerErrorCPUProfilerInUse.
+type Probe struct {
+	configuration       *Configuration
+	agentClientMutex    sync.Mutex
+	agentClient         *agentClient
+	sinksMutex          sync.Mutex
+	sinkAgentClients    map[string]*agentClient
+	sinkDeliveries      sync.WaitGroup
+	mutex               sync.Mutex
+	commands            chan *probeCommand
+	eventLoop           sync.Once
+	stateMutex          sync.Mutex
+	currentTitle        string
+	profileTitle        string
+	profileOptions      ProfileOptions
+	profileContext      string
+	profileTrigger      string
+	profileStartedAt    time.Time
+	profileMethod       string
+	profileRoute        string
+	currentState        profilerState
+	stopReason          string
+	profileEndedChan    chan struct{}
+	window              uint64
+	windowCancel        context.CancelFunc
+	cpuProfileBuffers   []*bytes.Buffer
+	memProfileBuffers   []*bytes.Buffer
+	profileReader       *pprof_reader.Reader
+	profileEndCallback  func(ProfileResult)
+	profileEndOnExpiry  bool
+	profileLimiters     map[string]*profileLimiter
+	cpuSampleRate       int
+	ender               Ender
+	disabledFromPanic   bool
+	clock               clock
+	savedMemProfileRate int
+	gcWindowStart       time.Time
+	profiledDuration    time.Duration
+	gcPauses            []pprof_reader.GCPause
+	requestedDuration   time.Duration
+	pausedAt            time.Time
+	gaps                []pprof_reader.Gap
+	statsMutex          sync.Mutex
+	stats               ProbeStats
+	sinkStats           []SinkStats
+	lastUploadError     error
+	failedUpload        *failedUpload
+	uploadErrors        []uploadError
+	eventsMutex         sync.Mutex
+	subscribers         map[chan lifecycleEvent]struct{}
+	signalMutex         sync.Mutex
+	signalHandlers      map[os.Signal]*SignalHandler
+	autoEnabler         sync.Once
+	skipCPU             bool
+	skipMemory          bool
+	profileNetwork      bool
+	recordingNetwork    int32
+	networkMutex        sync.Mutex
+	network             map[callStack]*networkRecord
+	networkSampler      *callSampler
+	lastNetworkStack    callStack
+	recordingMetrics    int32
+	metricsMutex        sync.Mutex
+	metrics             map[metricKey]*metricRecord
+	metricSamplers      sync.Map
+	lastMetricStacks    map[string]callStack
+	goroutineCPU        []pprof_reader.GoroutineCPU
+	subProfilesMutex    sync.Mutex
+	subProfiles         []pprof_reader.ProcessProfile
+	heapMutex           sync.Mutex
+	heapAgentClient     *agentClient
+	profileCacher       sync.Once
+	cachedProfile       atomic.Value
+
+	// The threads created before the profile started (see threads.go).
+	threadsCreatedAtStart  int
+	threadCreationsAtStart map[callStack]int
+}
 
-const (
-	profilerStateOff profilerState = iota
-	profilerStateEnabled
-	profilerStateDisabled
-	profilerStateSending
-)
+// maxUploadErrors is the number of upload errors reported on the dashboard.
+const maxUploadErrors = 10
 
-type probe struct {
-	configuration         *Configuration
-	agentClient           *agentClient
-	mutex                 sync.Mutex
-	profileDisableTrigger chan bool
-	currentTitle          string
-	currentState          profilerState
-	cpuProfileBuffers     []*bytes.Buffer
-	memProfileBuffers     []*bytes.Buffer
-	profileEndCallback    func()
-	cpuSampleRate         int
-	ender                 Ender
-	disabledFromPanic     bool
+type uploadError struct {
+	time time.Time
+	err  error
+}
+
+// failedUpload is a profile the agent failed to receive, kept to retry the
+// upload.
+type failedUpload struct {
+	profile    *pprof_reader.Profile
+	title      string
+	dimensions []string
 }
 
 var errDisabledFromPanic = errors.Errorf("Probe has been disabled due to a previous panic. Please check the logs for details.")
 
-type Ender interface {
-	End()
-	EndNoWait()
+var errDisabled = errors.Errorf("Probe has been disabled by configuration (BLACKFIRE_DISABLED).")
+
+// The probe running the CPU profiler of the process, if any. The Go runtime
+// only has one, which restarting from another probe would break.
+var (
+	cpuProfilerMutex sync.Mutex
+	cpuProfilerOwner *Probe
+)
+
+// acquireCPUProfiler reserves the CPU profiler for the probe, unless another
+// probe has it.
+func (p *Probe) acquireCPUProfiler() error {
+	cpuProfilerMutex.Lock()
+	defer cpuProfilerMutex.Unlock()
+	if cpuProfilerOwner != nil && cpuProfilerOwner != p {
+		return ProfilerErrorCPUProfilerInUse
+	}
+	cpuProfilerOwner = p
+	return nil
+}
+
+// releaseCPUProfiler lets other probes use the CPU profiler, if the probe
+// had it.
+func (p *Probe) releaseCPUProfiler() {
+	cpuProfilerMutex.Lock()
+	defer cpuProfilerMutex.Unlock()
+	if cpuProfilerOwner == p {
+		cpuProfilerOwner = nil
+	}
 }
 
 type ender struct {
-	probe *probe
+	probe *Probe
 }
 
 func (e *ender) End() {
@@ -61,36 +173,105 @@ func (e *ender) EndNoWait() {
 	e.probe.EndNoWait()
 }
 
-func newProbe() *probe {
-	p := &probe{
+func newProbe() *Probe {
+	p := &Probe{
 		configuration: &Configuration{},
+		clock:         realClock{},
+		// Allocated apart for its 64-bit atomic counters to be aligned.
+		networkSampler: &callSampler{},
 	}
 	p.ender = &ender{
 		probe: p,
 	}
-	p.currentTitle = "un-named profile"
-	p.startTriggerRearmLoop()
+	// Use a large queue for the rare edge case where many goroutines
+	// issue commands at the same time.
+	p.commands = make(chan *probeCommand, 100)
+	return p
+}
+
+// NewProbe returns a probe independent from the global one, which will use the
+// specified configuration. A nil configuration uses the defaults, the INI file
+// and the environment variables, like the global probe does.
+func NewProbe(config *Configuration) *Probe {
+	p := newProbe()
+	p.Configure(config)
 	return p
 }
 
-func (p *probe) Configure(config *Configuration) {
+// Configure explicitely configures the probe. This should be done before any
+// other call. config will be ignored if nil. Profiling starts right away if
+// auto-enabling is requested (see Configuration.AutoEnable).
+func (p *Probe) Configure(config *Configuration) {
+	if config == nil {
+		return
+	}
 	p.mutex.Lock()
-	defer p.mutex.Unlock()
 	p.configuration = config
-	return
+	p.mutex.Unlock()
+	// The configuration is only loaded now if auto-enabling may be requested.
+	if config.AutoEnable || isAutoEnabledFromEnv() || config.BlackfireQuery != "" || os.Getenv("BLACKFIRE_QUERY") != "" {
+		p.autoEnable()
+	}
 }
 
-func (p *probe) IsProfiling() bool {
+// autoEnable starts profiling for AutoEnableDuration if auto-enabling was
+// requested, either via the configuration or via the auto_enable option of
+// the Blackfire query. It only has effect once per probe.
+func (p *Probe) autoEnable() {
+	p.autoEnabler.Do(func() {
+		if err := p.configuration.load(); err != nil {
+			p.configuration.Logger.Error().Err(err).Msg("Blackfire: Unable to auto-enable profiling")
+			return
+		}
+		if !p.configuration.canProfile() {
+			return
+		}
+		if !p.configuration.AutoEnable && !p.offlineProbeOptions().IsAutoEnableSet() {
+			return
+		}
+		if err := p.enableUntilExpiry(p.configuration.AutoEnableDuration, ProfileOptions{}, triggerAuto, nil); err != nil {
+			p.configuration.Logger.Error().Err(err).Msg("Blackfire: Unable to auto-enable profiling")
+		}
+	})
+}
+
+func (p *Probe) IsProfiling() bool {
 	if err := p.configuration.load(); err != nil {
 		return false
 	}
 	if !p.configuration.canProfile() {
 		return false
 	}
-	return p.currentState == profilerStateEnabled || p.currentState == profilerStateSending
+	state := p.getState()
+	return state == profilerStateEnabled || state == profilerStateSending
+}
+
+func (p *Probe) EnableNowFor(duration time.Duration) (err error) {
+	return p.EnableNowForWithOptions(duration, ProfileOptions{})
+}
+
+// EnableNowForWithOptions profiles for the specified duration with options
+// specific to this profile. A non-zero duration takes precedence over
+// options.Duration.
+func (p *Probe) EnableNowForWithOptions(duration time.Duration, options ProfileOptions) error {
+	return p.enableNowFor(duration, options, "", nil)
+}
+
+// enableNowFor profiles like EnableNowForWithOptions, for the specified
+// trigger (see Configuration.MaxProfilesPerHour), calling back with the
+// outcome of the profile once it ended, if callback is not nil.
+func (p *Probe) enableNowFor(duration time.Duration, options ProfileOptions, trigger string, callback func(ProfileResult)) error {
+	return p.enable(&probeCommand{
+		event:    eventEnable,
+		duration: duration,
+		options:  options,
+		trigger:  trigger,
+		callback: callback,
+	})
 }
 
-func (p *probe) EnableNowFor(duration time.Duration) (err error) {
+// enable starts or resumes profiling with the specified enable command.
+func (p *Probe) enable(command *probeCommand) (err error) {
 	if p.disabledFromPanic {
 		return errDisabledFromPanic
 	}
@@ -100,60 +281,176 @@ func (p *probe) EnableNowFor(duration time.Duration) (err error) {
 		}
 	}()
 
+	if err = command.options.validate(); err != nil {
+		return
+	}
 	if err = p.configuration.load(); err != nil {
 		return
 	}
 	if !p.configuration.canProfile() {
 		return
 	}
-	logger := p.configuration.Logger
 
-	// Note: We do this once on each side of the mutex to be 100% sure that it's
-	// impossible for deferred/idempotent calls to deadlock, here and forever.
-	if !p.canEnableProfiling() {
-		err = errors.Errorf("unable to enable profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+	if command.duration == 0 {
+		command.duration = command.options.Duration
+	}
+	return p.execute(command)
+}
+
+// EnableWithOptions profiles with options specific to this profile, such as
+// its title, duration or CPU sample rate, so that profiles configured
+// differently don't need to change the probe-wide settings.
+func (p *Probe) EnableWithOptions(options ProfileOptions) error {
+	return p.EnableNowForWithOptions(0, options)
+}
+
+// ProfileStartup profiles the startup of the process, and is meant to be
+// called first thing in main(). Profiling stops after the specified
+// duration, then the profile, titled "startup", is uploaded in the
+// background. Ending the profile earlier uploads it right away.
+func (p *Probe) ProfileStartup(duration time.Duration) (err error) {
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+	return p.enableUntilExpiry(duration, ProfileOptions{Title: "startup"}, "", nil)
+}
+
+// ProfileWithCallback profiles for the specified duration, then uploads the
+// profile in the background and calls back with the outcome of the upload.
+// Ending the profile earlier uploads it right away. The callback is called in
+// its own goroutine, and only once the profile ended: not when profiling
+// could not start, which is reported by the returned error.
+func (p *Probe) ProfileWithCallback(duration time.Duration, callback func(ProfileResult)) error {
+	return p.enableUntilExpiry(duration, ProfileOptions{}, "", callback)
+}
 
-	if !p.canEnableProfiling() {
-		err = errors.Errorf("unable to enable profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+// enableUntilExpiry profiles for the specified duration like
+// EnableNowForWithOptions, then ends the profile from the event loop when its
+// profiling window expires, instead of waiting for an explicit End.
+func (p *Probe) enableUntilExpiry(duration time.Duration, options ProfileOptions, trigger string, callback func(ProfileResult)) error {
+	return p.enable(&probeCommand{
+		event:       eventEnable,
+		duration:    duration,
+		options:     options,
+		trigger:     trigger,
+		callback:    callback,
+		endOnExpiry: true,
+	})
+}
+
+// Pause stops profiling without ending the current profile, which can be
+// resumed later on with Resume. It fails if the probe is not profiling.
+func (p *Probe) Pause() (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
+
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	if duration == 0 || duration > p.configuration.MaxProfileDuration {
-		duration = p.configuration.MaxProfileDuration
+	return p.execute(&probeCommand{
+		event: eventDisable,
+		pause: true,
+	})
+}
+
+// Resume resumes profiling after Pause, for the rest of the duration the
+// profile was enabled for. The profiled segments are stitched together with a
+// gap marker in the timeline when the profile ends. It fails if the probe is
+// not paused.
+func (p *Probe) Resume() (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
 	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
 
-	if err = p.enableProfiling(); err != nil {
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	channel := p.profileDisableTrigger
-	shouldEndProfile := false
+	return p.execute(&probeCommand{
+		event: eventEnable,
+		pause: true,
+	})
+}
 
-	go func() {
-		<-time.After(duration)
-		channel <- shouldEndProfile
+// WaitAndEnable profiles for the specified duration like EnableNowFor, but
+// waits for the profile in progress to end instead of failing with
+// ProfilerErrorAlreadyProfiling. It gives up when the context is done.
+func (p *Probe) WaitAndEnable(ctx context.Context, duration time.Duration) error {
+	for {
+		ended := p.profileEnded()
+		err := p.EnableNowFor(duration)
+		if err != ProfilerErrorAlreadyProfiling {
+			return err
+		}
+		select {
+		case <-ended:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// enableForQuery profiles for the maximum duration using the specified
+// Blackfire query, as sent by the Blackfire CLI or browser extension to the
+// middleware.
+func (p *Probe) enableForQuery(query string, options ProfileOptions) (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
 	}()
 
-	return
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if p.configuration.Disabled {
+		return
+	}
+
+	return p.execute(&probeCommand{
+		event:    eventEnable,
+		duration: p.configuration.MaxProfileDuration,
+		query:    query,
+		options:  options,
+		trigger:  triggerHTTP,
+	})
 }
 
-func (p *probe) EnableNow() (err error) {
+func (p *Probe) EnableNow() (err error) {
 	return p.EnableNowFor(p.configuration.MaxProfileDuration)
 }
 
-func (p *probe) Enable() (err error) {
+func (p *Probe) Enable() (err error) {
 	p.configuration.onDemandOnly = true
 	return p.EnableNowFor(p.configuration.MaxProfileDuration)
 }
 
-func (p *probe) Disable() (err error) {
+func (p *Probe) Disable() (err error) {
 	if p.disabledFromPanic {
 		return errDisabledFromPanic
 	}
@@ -169,30 +466,36 @@ func (p *probe) Disable() (err error) {
 	if !p.configuration.canProfile() {
 		return
 	}
-	logger := p.configuration.Logger
 
-	// Note: We do this once on each side of the mutex to be 100% sure that it's
-	// impossible for deferred/idempotent calls to deadlock, here and forever.
-	if !p.canDisableProfiling() {
-		err = errors.Errorf("unable to disable profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
-		return
-	}
+	return p.execute(&probeCommand{
+		event: eventDisable,
+	})
+}
 
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
+func (p *Probe) EndNoWait() (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
 
-	if !p.canDisableProfiling() {
-		err = errors.Errorf("unable to disable profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	p.triggerStopProfiler(false)
-	return
+	return p.execute(&probeCommand{
+		event:      eventEnd,
+		outputPath: p.configuration.OutputFile,
+	})
 }
 
-func (p *probe) EndNoWait() (err error) {
+func (p *Probe) End() (err error) {
 	if p.disabledFromPanic {
 		return errDisabledFromPanic
 	}
@@ -210,28 +513,19 @@ func (p *probe) EndNoWait() (err error) {
 	}
 	logger := p.configuration.Logger
 
-	// Note: We do this once on each side of the mutex to be 100% sure that it's
-	// impossible for deferred/idempotent calls to deadlock, here and forever.
-	if !p.canEndProfiling() {
-		err = errors.Errorf("unable to end profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
-		return
-	}
-
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-
-	if !p.canEndProfiling() {
-		err = errors.Errorf("unable to end profiling as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+	logger.Debug().Msg("Blackfire: Ending the current profile and blocking until it's uploaded")
+	if err = p.execute(&probeCommand{
+		event:      eventEnd,
+		outputPath: p.configuration.OutputFile,
+		wait:       true,
+	}); err != nil {
 		return
 	}
-
-	p.triggerStopProfiler(true)
+	logger.Debug().Msg("Blackfire: Profile uploaded. Unblocking.")
 	return
 }
 
-func (p *probe) End() (err error) {
+func (p *Probe) EndToFile(path string) (err error) {
 	if p.disabledFromPanic {
 		return errDisabledFromPanic
 	}
@@ -249,33 +543,54 @@ func (p *probe) End() (err error) {
 	}
 	logger := p.configuration.Logger
 
-	// Note: We do this once on each side of the mutex to be 100% sure that it's
-	// impossible for deferred/idempotent calls to deadlock, here and forever.
-	if !p.canEndProfiling() {
-		err = errors.Errorf("unable to end profiling and wait as state is %v", p.currentState)
-		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+	logger.Debug().Msgf("Blackfire: Ending the current profile and writing it to %s", path)
+	if err = p.execute(&probeCommand{
+		event:      eventEnd,
+		outputPath: path,
+		wait:       true,
+	}); err != nil {
 		return
 	}
+	logger.Debug().Msg("Blackfire: Profile written. Unblocking.")
+	return
+}
+
+func (p *Probe) RetryUpload() (err error) {
+	if p.disabledFromPanic {
+		return errDisabledFromPanic
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
 
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
-
-	if !p.canEndProfiling() {
-		err = errors.Errorf("unable to end profiling and wait as state is %v", p.currentState)
-		logger.Error().Err(err).Msg("Blackfire: wrong profiler state")
+	upload := p.failedUpload
+	if upload == nil {
+		return nil
+	}
+	if err = p.prepareAgentClient(); err != nil {
 		return
 	}
 
-	logger.Debug().Msg("Blackfire: Ending the current profile and blocking until it's uploaded")
-	if err = p.endProfile(); err != nil {
-		logger.Error().Msgf("Blackfire (end profile): %v", err)
+	p.configuration.Logger.Debug().Msgf("Blackfire: Retrying the upload of %s", upload.title)
+	size, err := p.getAgentClient().SendProfile(context.Background(), upload.profile, upload.title, upload.dimensions)
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	p.lastUploadError = err
+	if err != nil {
+		p.stats.UploadsFailed++
 		return
 	}
-	logger.Debug().Msg("Blackfire: Profile uploaded. Unblocking.")
+	p.failedUpload = nil
+	p.stats.UploadsSucceeded++
+	p.stats.PayloadBytes += uint64(size)
 	return
 }
 
-func (p *probe) GenerateSubProfileQuery() (s string, err error) {
+func (p *Probe) GenerateSubProfileQuery() (s string, err error) {
 	if p.disabledFromPanic {
 		err = errDisabledFromPanic
 		return
@@ -286,10 +601,16 @@ func (p *probe) GenerateSubProfileQuery() (s string, err error) {
 		}
 	}()
 
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if p.configuration.Disabled {
+		return "", errDisabled
+	}
 	if err := p.prepareAgentClient(); err != nil {
 		return "", err
 	}
-	currentQuery, err := p.agentClient.CurrentBlackfireQuery()
+	currentQuery, err := p.getAgentClient().CurrentBlackfireQuery(context.Background())
 	if err != nil {
 		return "", err
 	}
@@ -307,10 +628,10 @@ func (p *probe) GenerateSubProfileQuery() (s string, err error) {
 			return "", errors.Wrapf(err, "Blackfire: Unable to generate a sub-profile query")
 		}
 	}
-	args.Del("aggreg_samples")
+	args.Del(bf_format.OptionAggregSamples)
 
 	parent := ""
-	parts = strings.Split(args.Get("sub_profile"), ":")
+	parts = strings.Split(args.Get(bf_format.OptionSubProfile), ":")
 	if len(parts) > 1 {
 		parent = parts[1]
 	}
@@ -320,46 +641,253 @@ func (p *probe) GenerateSubProfileQuery() (s string, err error) {
 	id = strings.TrimRight(id, "=")
 	id = strings.ReplaceAll(id, "+", "A")
 	id = strings.ReplaceAll(id, "/", "B")
-	args.Set("sub_profile", parent+":"+id[0:9])
+	args.Set(bf_format.OptionSubProfile, parent+":"+id[0:9])
 	return challenge + "&signature=" + signature + "&" + args.Encode(), nil
 }
 
-func (p *probe) SetCurrentTitle(title string) {
+// CurrentProfile returns the profile being recorded, or nil if the probe is
+// not profiling or writes profiles to a file. The first call for a profile
+// may request a profile slot from Blackfire, which the upload then uses.
+func (p *Probe) CurrentProfile() (profile *Profile, err error) {
+	if p.disabledFromPanic {
+		err = errDisabledFromPanic
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			err = p.handlePanic(r)
+		}
+	}()
+
+	if state := p.getState(); state != profilerStateEnabled && state != profilerStateDisabled {
+		return nil, nil
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	if state := p.getState(); state != profilerStateEnabled && state != profilerStateDisabled {
+		return nil, nil
+	}
+	if p.configuration.OutputFile != "" || p.configuration.Exporter != nil || p.configuration.CollectorSocket != "" {
+		return nil, nil
+	}
+	if err = p.prepareAgentClient(); err != nil {
+		return
+	}
+	return p.getAgentClient().currentProfile(context.Background())
+}
+
+// CachedProfile returns the profile being recorded, like CurrentProfile, but
+// without blocking: the profile is looked up in the background when it
+// starts, so CachedProfile returns nil until the lookup completes. The first
+// call starts watching the profiles.
+func (p *Probe) CachedProfile() *Profile {
+	p.profileCacher.Do(func() {
+		events, _ := p.subscribe()
+		go p.cacheProfiles(events)
+	})
+	profile, _ := p.cachedProfile.Load().(*Profile)
+	return profile
+}
+
+// cacheProfiles looks up the profiles returned by CachedProfile as they
+// start.
+func (p *Probe) cacheProfiles(events <-chan lifecycleEvent) {
+	lookup := func() {
+		profile, err := p.CurrentProfile()
+		if err != nil {
+			p.configuration.Logger.Debug().Err(err).Msg("Blackfire: Unable to look up the current profile")
+		}
+		p.cachedProfile.Store(profile)
+	}
+	if p.IsProfiling() {
+		lookup()
+	}
+	for event := range events {
+		// The cache is cleared when the profile ends.
+		if profile, _ := p.cachedProfile.Load().(*Profile); event.Type == lifecycleStarted && profile == nil {
+			lookup()
+		}
+	}
+}
+
+// profileUUIDRegexp matches the UUIDs of the profiles, which are part of the
+// path of the API requests.
+var profileUUIDRegexp = regexp.MustCompile(`^[0-9A-Za-z-]+$`)
+
+// GetProfile fetches a profile from the Blackfire API, with the configured
+// client credentials.
+func (p *Probe) GetProfile(ctx context.Context, uuid string) (profile *Profile, err error) {
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if p.configuration.ClientID == "" || p.configuration.ClientToken == "" {
+		return nil, errors.Errorf("The client ID and token are needed to fetch a profile")
+	}
+
+	if !profileUUIDRegexp.MatchString(uuid) {
+		return nil, errors.Errorf("Invalid profile UUID %q", uuid)
+	}
+
+	// Copy the endpoint so that the configuration is left untouched.
+	endpoint := *p.configuration.HTTPEndpoint
+	endpoint.Path = path.Join(endpoint.Path, "/api/v1/profiles", uuid)
+	profile = &Profile{
+		UUID:   uuid,
+		APIURL: endpoint.String(),
+		auth:   apiAuthorization(p.configuration),
+	}
+	// Server errors are retried when waiting for the profile.
+	if profile.loaded, err = profile.fetch(ctx, profile.auth); err != nil && !isTemporaryAPIError(err) {
+		return nil, err
+	}
+	profile.URL = profile.Links["graph_url"]["href"]
+	return profile, nil
+}
+
+// AssertProfile waits for a profile to be finalized, then checks its
+// envelope: check returns an error when the costs are not the expected ones
+// (see EnvelopeBudget.Check).
+func (p *Probe) AssertProfile(ctx context.Context, uuid string, check func(Envelope) error) error {
+	profile, err := p.GetProfile(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	status := profile.Status
+	if !profile.loaded {
+		if status, err = profile.Wait(ctx); err != nil {
+			return err
+		}
+	}
+	if status.Name != "finished" {
+		if status.FailureReason != "" {
+			return errors.Errorf("The profile %s is %s: %s", uuid, status.Name, status.FailureReason)
+		}
+		return errors.Errorf("The profile %s is %s", uuid, status.Name)
+	}
+	return check(profile.Envelope)
+}
+
+// SetCurrentTitle sets the title of the next profiles, unless they are
+// given one with ProfileOptions.Title.
+func (p *Probe) SetCurrentTitle(title string) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
 	p.currentTitle = title
 }
 
-func (p *probe) startTriggerRearmLoop() {
-	go func() {
-		for {
-			// Use a large queue for the rare edge case where many goroutines
-			// try to trigger the same channel before it gets rebuilt.
-			p.profileDisableTrigger = make(chan bool, 100)
-			shouldEndProfile := <-p.profileDisableTrigger
-			p.onProfileDisableTriggered(shouldEndProfile, p.profileEndCallback)
+// title returns the title of the profile being recorded. It must be called
+// with the probe mutex held.
+func (p *Probe) title() string {
+	if p.profileTitle != "" {
+		return p.profileTitle
+	}
+	if p.currentTitle != "" {
+		return p.currentTitle
+	}
+	return p.defaultTitle()
+}
 
+// defaultTitle returns the title of a profile which was given none, made of
+// the DefaultTitleTemplate.
+func (p *Probe) defaultTitle() string {
+	variables := p.titleVariables()
+	variables.Time = p.profileStartedAt.Round(0)
+	var title strings.Builder
+	if t := p.configuration.defaultTitleTemplate; t != nil {
+		if err := t.Execute(&title, variables); err == nil {
+			return title.String()
 		}
-	}()
+	}
+	return variables.Executable
 }
 
-func (p *probe) addNewProfileBufferSet() {
+// titleVariables returns the variables of the title templates, but the
+// title and the time.
+func (p *Probe) titleVariables() TitleVariables {
+	hostname, _ := os.Hostname()
+	return TitleVariables{
+		Hostname:   hostname,
+		Executable: pprof_reader.ExecutableName(),
+		Method:     p.profileMethod,
+		Route:      p.profileRoute,
+		Trigger:    p.profileTrigger,
+	}
+}
+
+// resolveTitle sets the title of the profile from the title template, if
+// any. The title is left as is if the template fails.
+func (p *Probe) resolveTitle() {
+	t := p.configuration.titleTemplate
+	if p.profileMethod != "" && p.configuration.middlewareTitleTemplate != nil {
+		t = p.configuration.middlewareTitleTemplate
+	}
+	if t == nil {
+		return
+	}
+	variables := p.titleVariables()
+	variables.Title = p.title()
+	variables.Time = p.clock.Now().Round(0)
+	var title strings.Builder
+	if err := t.Execute(&title, variables); err != nil {
+		p.configuration.Logger.Warn().Err(err).Msgf("Blackfire: Unable to resolve the title template")
+		return
+	}
+	p.profileTitle = title.String()
+}
+
+func (p *Probe) addNewProfileBufferSet() {
 	p.cpuProfileBuffers = append(p.cpuProfileBuffers, &bytes.Buffer{})
 	p.memProfileBuffers = append(p.memProfileBuffers, &bytes.Buffer{})
 }
 
-func (p *probe) resetProfileBufferSet() {
+func (p *Probe) resetProfileBufferSet() {
 	p.cpuProfileBuffers = p.cpuProfileBuffers[:0]
 	p.memProfileBuffers = p.memProfileBuffers[:0]
+	p.profileReader = nil
+	p.profiledDuration = 0
+	p.gcPauses = nil
+	p.pausedAt = time.Time{}
+	p.gaps = nil
 }
 
-func (p *probe) currentCPUBuffer() *bytes.Buffer {
+// recordGCPauses records the GC pauses that happened since profiling was
+// last enabled, so that they can be displayed in the timeline.
+func (p *Probe) recordGCPauses() {
+	// GC stats are timestamped with the wall clock, not the probe clock.
+	now := time.Now()
+	stats := &debug.GCStats{}
+	debug.ReadGCStats(stats)
+
+	// Pauses are listed from the most recent to the oldest.
+	var pauses []pprof_reader.GCPause
+	for i := 0; i < len(stats.Pause) && i < len(stats.PauseEnd); i++ {
+		start := stats.PauseEnd[i].Add(-stats.Pause[i])
+		if start.Before(p.gcWindowStart) {
+			break
+		}
+		pauses = append(pauses, pprof_reader.GCPause{
+			Start:    p.profiledDuration + start.Sub(p.gcWindowStart),
+			Duration: stats.Pause[i],
+		})
+	}
+	for i := len(pauses) - 1; i >= 0; i-- {
+		p.gcPauses = append(p.gcPauses, pauses[i])
+	}
+	p.profiledDuration += now.Sub(p.gcWindowStart)
+}
+
+func (p *Probe) currentCPUBuffer() *bytes.Buffer {
 	return p.cpuProfileBuffers[len(p.cpuProfileBuffers)-1]
 }
 
-func (p *probe) currentMemBuffer() *bytes.Buffer {
+func (p *Probe) currentMemBuffer() *bytes.Buffer {
 	return p.memProfileBuffers[len(p.memProfileBuffers)-1]
 }
 
-func (p *probe) prepareAgentClient() (err error) {
+func (p *Probe) prepareAgentClient() (err error) {
+	p.agentClientMutex.Lock()
+	defer p.agentClientMutex.Unlock()
 	if p.agentClient != nil {
 		return nil
 	}
@@ -367,49 +895,63 @@ func (p *probe) prepareAgentClient() (err error) {
 	return err
 }
 
-func (p *probe) canEnableProfiling() bool {
-	switch p.currentState {
-	case profilerStateOff, profilerStateDisabled:
-		return true
-	case profilerStateEnabled, profilerStateSending:
-		return false
-	default:
-		panic(fmt.Errorf("Blackfire: Unhandled state: %v", p.currentState))
-	}
+// getAgentClient returns the agent client, or nil if it was not prepared
+// yet. The client may be prepared outside of the probe mutex.
+func (p *Probe) getAgentClient() *agentClient {
+	p.agentClientMutex.Lock()
+	defer p.agentClientMutex.Unlock()
+	return p.agentClient
 }
 
-func (p *probe) canDisableProfiling() bool {
-	switch p.currentState {
-	case profilerStateEnabled:
-		return true
-	case profilerStateOff, profilerStateDisabled, profilerStateSending:
-		return false
-	default:
-		panic(fmt.Errorf("Blackfire: Unhandled state: %v", p.currentState))
-	}
-}
-
-func (p *probe) canEndProfiling() bool {
-	switch p.currentState {
-	case profilerStateEnabled, profilerStateDisabled:
-		return true
-	case profilerStateOff, profilerStateSending:
-		return false
-	default:
-		panic(fmt.Errorf("Blackfire: Unhandled state: %v", p.currentState))
+// useBlackfireQuery makes the next profile use the specified Blackfire query
+// instead of requesting a new one from the signing endpoint.
+func (p *Probe) useBlackfireQuery(query string) error {
+	if err := p.prepareAgentClient(); err != nil {
+		return err
 	}
+	return p.getAgentClient().setBlackfireQuery(query)
 }
 
-func (p *probe) enableProfiling() error {
+func (p *Probe) enableProfiling(duration time.Duration) error {
 	logger := p.configuration.Logger
 	logger.Debug().Msgf("Blackfire: Start profiling")
 
-	p.addNewProfileBufferSet()
+	if duration == 0 || duration > p.configuration.MaxProfileDuration {
+		duration = p.configuration.MaxProfileDuration
+	}
+
+	if p.getState() == profilerStateOff {
+		// Only collect the dimensions requested by the server, when known,
+		// or by the profile options.
+		options := p.pendingProbeOptions()
+		p.skipCPU = !options.GetBool(bf_format.OptionFlagCPU, true)
+		p.skipMemory = !options.GetBool(bf_format.OptionFlagMemory, true)
+		p.profileNetwork = options.GetBool(bf_format.OptionFlagNW, false)
+	}
+	if !p.skipCPU {
+		if err := p.acquireCPUProfiler(); err != nil {
+			return err
+		}
+	}
+
+	if p.getState() == profilerStateOff {
+		p.requestedDuration = duration
+		p.statsMutex.Lock()
+		p.stats.ProfilesStarted++
+		p.statsMutex.Unlock()
 
-	if p.cpuSampleRate == 0 {
-		p.cpuSampleRate = p.configuration.DefaultCPUSampleRateHz
+		p.takeNetworkRecords()
+		p.takeMetricRecords()
+		p.startThreadStats()
+
+		p.cpuSampleRate = p.profileOptions.CPUSampleRateHz
+		if p.cpuSampleRate == 0 {
+			p.cpuSampleRate = p.configuration.DefaultCPUSampleRateHz
+		}
 	}
 
+	p.addNewProfileBufferSet()
+
 	// We call SetCPUProfileRate before StartCPUProfile in order to lock in our
 	// desired sample rate. When SetCPUProfileRate is called with a non-zero
 	// value, profiling is considered "ON". Any attempt to change the sample
@@ -419,48 +961,177 @@ func (p *probe) enableProfiling() error {
 	// previous profile has finished" to stderr). Since StartCPUProfile can't
 	// know if its call to SetCPUProfileRate failed, it will just carry on with
 	// the profiling (at our selected rate).
-	runtime.SetCPUProfileRate(0)
-	if p.cpuSampleRate != golangDefaultCPUSampleRate {
-		// Only pre-set if it's different from what StartCPUProfile would set.
-		// This avoids the unsightly error message whenever possible.
-		runtime.SetCPUProfileRate(p.cpuSampleRate)
+	if !p.skipCPU {
+		runtime.SetCPUProfileRate(0)
+		if p.cpuSampleRate != golangDefaultCPUSampleRate {
+			// Only pre-set if it's different from what StartCPUProfile would set.
+			// This avoids the unsightly error message whenever possible.
+			runtime.SetCPUProfileRate(p.cpuSampleRate)
+		}
+		if err := pprof.StartCPUProfile(p.currentCPUBuffer()); err != nil {
+			p.releaseCPUProfiler()
+			return err
+		}
 	}
-	if err := pprof.StartCPUProfile(p.currentCPUBuffer()); err != nil {
-		return err
+	p.gcWindowStart = time.Now()
+	if !p.pausedAt.IsZero() {
+		p.gaps = append(p.gaps, pprof_reader.Gap{
+			Start:    p.profiledDuration,
+			Duration: p.gcWindowStart.Sub(p.pausedAt),
+		})
+		p.pausedAt = time.Time{}
+	}
+	if !p.skipMemory {
+		p.setMemProfileRate()
 	}
+	p.setRecordingNetwork(p.profileNetwork)
+	p.setRecordingMetrics(true)
+
+	p.startWindowTimer(duration)
 
-	p.currentState = profilerStateEnabled
+	p.setState(profilerStateEnabled)
+	p.publish(lifecycleEvent{Type: lifecycleStarted, Title: p.title()})
 	return nil
 }
 
-func (p *probe) disableProfiling() error {
+// startWindowTimer starts a new profiling window, which expires after the
+// specified duration unless it is cancelled before.
+func (p *Probe) startWindowTimer(duration time.Duration) {
+	p.cancelWindowTimer()
+
+	p.window++
+	window := p.window
+	ctx, cancel := context.WithCancel(context.Background())
+	p.windowCancel = cancel
+	timer := p.clock.NewTimer(duration)
+	go func() {
+		select {
+		case <-timer.C():
+			p.post(&probeCommand{
+				event:  eventExpire,
+				window: window,
+			})
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}()
+}
+
+// cancelWindowTimer cancels the expiry of the current profiling window, if
+// any.
+func (p *Probe) cancelWindowTimer() {
+	if p.windowCancel != nil {
+		p.windowCancel()
+		p.windowCancel = nil
+	}
+}
+
+// expireProfiling records that the profiling window elapsed, before profiling
+// is stopped, and lets the application know.
+func (p *Probe) expireProfiling() {
+	reason := StopReasonDuration
+	if p.requestedDuration >= p.configuration.MaxProfileDuration {
+		reason = StopReasonMaxDuration
+	}
+	p.setStopReason(reason)
+	p.configuration.Logger.Info().Msgf("Blackfire: Profiling stopped after %v (%s)", p.requestedDuration, reason)
+	if hook := p.configuration.OnAutoStop; hook != nil {
+		go hook(reason)
+	}
+}
+
+func (p *Probe) disableProfiling() error {
 	logger := p.configuration.Logger
 	logger.Debug().Msgf("Blackfire: Stop profiling")
-	if !p.canDisableProfiling() {
+	if p.getState() != profilerStateEnabled {
 		return nil
 	}
+	p.cancelWindowTimer()
 
-	defer func() {
-		p.currentState = profilerStateDisabled
-	}()
+	defer p.publish(lifecycleEvent{Type: lifecycleStopped, Title: p.title(), Reason: p.StopReason()})
+	defer p.setState(profilerStateDisabled)
+	// The heap profile must be written before restoring the rate, since it
+	// is used to scale heap samples.
+	defer p.restoreMemProfileRate()
 
-	pprof.StopCPUProfile()
+	if !p.skipCPU {
+		pprof.StopCPUProfile()
+		p.releaseCPUProfiler()
+	}
+	p.setRecordingNetwork(false)
+	p.setRecordingMetrics(false)
+	p.recordGCPauses()
+	p.pausedAt = time.Now()
+
+	// The buffer set is complete: it is converted in the background, so
+	// that ending the profile only has to post-process it. The CPU samples
+	// of the window are kept even if the heap profile can't be written.
+	var err error
+	memBuffer := p.currentMemBuffer()
+	if !p.skipMemory {
+		if err = p.writeHeapProfile(); err != nil {
+			memBuffer = nil
+		}
+	}
+	p.reader().Add(p.currentCPUBuffer(), memBuffer)
+	return err
+}
 
+func (p *Probe) writeHeapProfile() error {
+	defer p.measure(&p.stats.HeapSnapshotTime)()
 	memWriter := bufio.NewWriter(p.currentMemBuffer())
 	if err := pprof.WriteHeapProfile(memWriter); err != nil {
 		return err
 	}
-	if err := memWriter.Flush(); err != nil {
-		return err
+	return memWriter.Flush()
+}
+
+// reader returns the reader converting the buffer sets of the current
+// profile.
+func (p *Probe) reader() *pprof_reader.Reader {
+	if p.profileReader == nil {
+		p.profileReader = pprof_reader.NewReader(p.readOptions())
 	}
+	return p.profileReader
+}
 
-	return nil
+// setMemProfileRate sets the configured heap sampling rate for the profiling
+// window, unless the application manages the rate itself.
+func (p *Probe) setMemProfileRate() {
+	rate := p.configuration.MemProfileRate
+	if rate <= 0 || p.savedMemProfileRate != 0 {
+		return
+	}
+	if runtime.MemProfileRate != golangDefaultMemProfileRate {
+		p.configuration.Logger.Warn().Msgf("Blackfire: MemProfileRate has been changed by the application to %d, not setting it to %d", runtime.MemProfileRate, rate)
+		return
+	}
+	p.configuration.Logger.Debug().Msgf("Blackfire: Set MemProfileRate to %d", rate)
+	p.savedMemProfileRate = runtime.MemProfileRate
+	runtime.MemProfileRate = rate
+}
+
+// restoreMemProfileRate restores the heap sampling rate in effect before
+// setMemProfileRate was called.
+func (p *Probe) restoreMemProfileRate() {
+	if p.savedMemProfileRate == 0 {
+		return
+	}
+	p.configuration.Logger.Debug().Msgf("Blackfire: Restore MemProfileRate to %d", p.savedMemProfileRate)
+	runtime.MemProfileRate = p.savedMemProfileRate
+	p.savedMemProfileRate = 0
+}
+
+func (p *Probe) endProfile() error {
+	return p.endProfileTo(p.configuration.OutputFile)
 }
 
-func (p *probe) endProfile() error {
+// endProfileTo ends the current profile and uploads it to the agent, or
+// writes it to outputPath instead if it is not empty.
+func (p *Probe) endProfileTo(outputPath string) (err error) {
 	logger := p.configuration.Logger
 	logger.Debug().Msgf("Blackfire: End profile")
-	if !p.canEndProfiling() {
+	if state := p.getState(); state != profilerStateEnabled && state != profilerStateDisabled {
 		return nil
 	}
 
@@ -468,13 +1139,44 @@ func (p *probe) endProfile() error {
 		return err
 	}
 
-	if err := p.prepareAgentClient(); err != nil {
-		return err
+	exporter := p.configuration.Exporter
+	collector := ""
+	if outputPath == "" && exporter == nil {
+		collector = p.configuration.CollectorSocket
+	}
+	if outputPath == "" && exporter == nil && collector == "" {
+		if err := p.prepareAgentClient(); err != nil {
+			return err
+		}
+	}
+
+	p.resolveTitle()
+	result := ProfileResult{Title: p.title()}
+	if callback := p.profileEndCallback; callback != nil {
+		p.profileEndCallback = nil
+		defer func() {
+			result.Err = err
+			go callback(result)
+		}()
 	}
 
-	p.currentState = profilerStateSending
+	p.setState(profilerStateSending)
+	defer p.setState(profilerStateOff)
+	defer func() {
+		p.cachedProfile.Store((*Profile)(nil))
+		p.profileTitle = ""
+		p.profileOptions = ProfileOptions{}
+		p.profileContext = ""
+		p.profileTrigger = ""
+		p.profileEndOnExpiry = false
+		p.profileStartedAt = time.Time{}
+		p.profileMethod = ""
+		p.profileRoute = ""
+	}()
 	defer func() {
-		p.currentState = profilerStateOff
+		if err != nil {
+			p.publish(lifecycleEvent{Type: lifecycleErrored, Title: p.title(), Error: err.Error()})
+		}
 	}()
 
 	if p.configuration.PProfDumpDir != "" {
@@ -482,54 +1184,289 @@ func (p *probe) endProfile() error {
 		pprof_reader.DumpProfiles(p.cpuProfileBuffers, p.memProfileBuffers, p.configuration.PProfDumpDir)
 	}
 
-	profile, err := pprof_reader.ReadFromPProf(p.cpuProfileBuffers, p.memProfileBuffers)
-	if err != nil {
+	if collector != "" {
+		logger.Debug().Msgf("Blackfire: Push the profile to the collector at %s", collector)
+		err = p.pushToCollector(collector)
+		p.recordUpload(0, err)
 		return err
 	}
-	p.resetProfileBufferSet()
 
-	if profile == nil {
-		return fmt.Errorf("Profile was not created")
+	profile, err := p.convertProfile()
+	if err != nil {
+		return err
 	}
-
 	if !profile.HasData() {
 		logger.Debug().Msgf("Blackfire: No samples recorded")
 		return nil
 	}
 
-	if err := p.agentClient.SendProfile(profile, p.currentTitle); err != nil {
-		return err
+	profile.Headers = make(map[string]string)
+	if p.configuration.ReportOverhead {
+		profile.Headers["probe-overhead"] = p.Stats().overheadHeader()
+	}
+	profile.Headers["threads"] = p.threadsHeader()
+	profile.SensitiveArgs = p.configuration.sensitiveArgs
+	profile.GraphRoot = p.configuration.GraphRoot
+	profile.Language = p.configuration.ProbedLanguage
+	profile.Runtime = p.configuration.ProbedRuntime
+	if provider := p.configuration.ContextProvider; provider != nil {
+		profile.Context = provider()
+	}
+	contextHeader := p.profileContext
+	if len(p.profileOptions.Tags) > 0 {
+		if contextHeader != "" {
+			contextHeader += "&"
+		}
+		contextHeader += p.profileOptions.tagsContext()
+	}
+	if contextHeader != "" {
+		profile.Headers["Context"] = contextHeader
 	}
 
+	result.UUID, result.URL, err = p.uploadProfile(profile, outputPath, p.title(), p.profileOptions.Dimensions)
 	return err
 }
 
-func (p *probe) triggerStopProfiler(shouldEndProfile bool) {
-	p.profileDisableTrigger <- shouldEndProfile
+// uploadProfile writes the profile to outputPath if it is not empty, or else
+// sends it to the exporter or to the agent, then delivers it to the sinks. The
+// UUID and URL of the profile are returned when the agent is involved.
+func (p *Probe) uploadProfile(profile *pprof_reader.Profile, outputPath, title string, dimensions []string) (uuid, url string, err error) {
+	p.publish(lifecycleEvent{Type: lifecycleUploading, Title: title})
+	stopMeasure := p.measure(&p.stats.UploadTime)
+	var size int
+	uploaded := lifecycleEvent{Type: lifecycleUploaded, Title: title}
+	if outputPath != "" {
+		size, err = p.writeProfileToFile(profile, outputPath, title, dimensions)
+	} else if exporter := p.configuration.Exporter; exporter != nil {
+		size, err = exporter.Export(profile, title)
+	} else {
+		client := p.getAgentClient()
+		size, err = client.SendProfile(context.Background(), profile, title, dimensions)
+		if sent := client.lastSentProfile(); sent != nil {
+			uploaded.UUID = sent.UUID
+			uploaded.URL = sent.URL
+			uuid = sent.UUID
+			url = sent.URL
+			if err == nil {
+				p.notifyWebhook(sent, title)
+			}
+		}
+		if err != nil {
+			p.keepFailedUpload(profile, title, dimensions)
+		}
+	}
+	stopMeasure()
+	if err == nil {
+		p.publish(uploaded)
+	}
+	p.deliverToSinks(profile, title, dimensions)
+	p.recordUpload(size, err)
+	return
 }
 
-func (p *probe) onProfileDisableTriggered(shouldEndProfile bool, callback func()) {
-	logger := p.configuration.Logger
-	logger.Debug().Msgf("Blackfire: Received profile disable trigger. shouldEndProfile = %t, callback = %p", shouldEndProfile, callback)
-	p.mutex.Lock()
-	defer p.mutex.Unlock()
-
-	if shouldEndProfile {
-		if err := p.endProfile(); err != nil {
-			logger.Error().Msgf("Blackfire (end profile): %v", err)
+// recordUpload records the outcome of an upload in the stats.
+func (p *Probe) recordUpload(size int, err error) {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	p.lastUploadError = err
+	if err != nil {
+		p.stats.UploadsFailed++
+		p.uploadErrors = append(p.uploadErrors, uploadError{time: p.clock.Now(), err: err})
+		if len(p.uploadErrors) > maxUploadErrors {
+			p.uploadErrors = p.uploadErrors[len(p.uploadErrors)-maxUploadErrors:]
 		}
 	} else {
-		if err := p.disableProfiling(); err != nil {
-			logger.Error().Msgf("Blackfire (stop profiling): %v", err)
+		p.stats.UploadsSucceeded++
+		p.stats.PayloadBytes += uint64(size)
+	}
+}
+
+// keepFailedUpload keeps a profile the agent failed to receive for
+// RetryUpload, and writes it to the spool directory if there is one.
+func (p *Probe) keepFailedUpload(profile *pprof_reader.Profile, title string, dimensions []string) {
+	p.failedUpload = &failedUpload{
+		profile:    profile,
+		title:      title,
+		dimensions: dimensions,
+	}
+	if p.configuration.SpoolDir == "" {
+		return
+	}
+	name := fmt.Sprintf("profile-%s.bf", p.clock.Now().UTC().Format("20060102-150405.000000000"))
+	if _, err := p.writeProfileToFile(profile, filepath.Join(p.configuration.SpoolDir, name), title, dimensions); err != nil {
+		p.configuration.Logger.Error().Err(err).Msgf("Blackfire: Unable to spool the profile")
+	}
+}
+
+// convertProfile converts the recorded pprof profiles to a Blackfire profile.
+func (p *Probe) convertProfile() (*pprof_reader.Profile, error) {
+	defer p.measure(&p.stats.ConversionTime)()
+
+	profile, err := p.reader().Profile()
+	if err != nil {
+		return nil, err
+	}
+	if subProfiles := p.takeSubProfiles(); profile != nil && len(subProfiles) > 0 {
+		profile = pprof_reader.MergeProfiles(append([]pprof_reader.ProcessProfile{{Profile: profile}}, subProfiles...))
+	}
+	if profile != nil {
+		p.addNetworkSamples(profile)
+		p.addMetricSamples(profile)
+		p.addThreadSamples(profile)
+		profile.Duration = p.profiledDuration
+		profile.GCPauses = p.gcPauses
+		profile.Gaps = p.gaps
+	}
+	p.resetProfileBufferSet()
+
+	if profile == nil {
+		return nil, fmt.Errorf("Profile was not created")
+	}
+
+	p.statsMutex.Lock()
+	p.stats.Profiles++
+	p.stats.DroppedSamples += countSamplesWithoutStack(profile)
+	p.statsMutex.Unlock()
+
+	if p.configuration.OnlyProfiledGoroutines {
+		sampleCount := len(profile.Samples)
+		profile = profile.FilterByLabel(GoroutineLabel)
+		p.statsMutex.Lock()
+		p.stats.DroppedSamples += uint64(sampleCount - len(profile.Samples))
+		p.statsMutex.Unlock()
+	}
+	packages := p.configuration.ProbePackages
+	if len(packages) == 0 {
+		packages = defaultProbePackages
+	}
+	switch p.configuration.ProbeFrames {
+	case ProbeFramesPrune:
+		profile = profile.PruneRootedIn(packages)
+	case ProbeFramesGroup:
+		profile = profile.GroupRootedIn(packages, pprof_reader.ProbeNode)
+	}
+	if p.configuration.ReportGoroutineCPU {
+		report := profile.CPUByGoroutine()
+		p.statsMutex.Lock()
+		p.goroutineCPU = report
+		p.statsMutex.Unlock()
+	}
+	profile, truncated := profile.TruncateStacks(p.configuration.MaxStackDepth)
+	p.statsMutex.Lock()
+	p.stats.TruncatedStacks += uint64(truncated)
+	p.statsMutex.Unlock()
+	return profile.SegmentByLabels(p.configuration.SegmentByLabels), nil
+}
+
+func (p *Probe) readOptions() pprof_reader.ReadOptions {
+	options := pprof_reader.ReadOptions{
+		SymbolizeCFrames:  p.configuration.SymbolizeCFrames,
+		AnnotateClosures:  p.configuration.AnnotateClosures,
+		CollapseGenerics:  p.configuration.CollapseGenerics,
+		FileLine:          p.configuration.FileLine,
+		MemoryAttribution: p.configuration.MemoryAttribution,
+		Recursion:         p.configuration.Recursion,
+		Inlining:          p.configuration.Inlining,
+	}
+	if p.skipCPU {
+		// Memory can't be distributed across CPU samples without them.
+		options.MemoryAttribution = pprof_reader.MemoryPerStack
+	}
+	return options
+}
+
+// writeProfileToFile writes the profile to a file, and returns its size.
+func (p *Probe) writeProfileToFile(profile *pprof_reader.Profile, outputPath, title string, dimensions []string) (size int, err error) {
+	return writeProfileToFile(p.configuration, profile, outputPath, title, withDimensions(p.pendingProbeOptions(), dimensions))
+}
+
+// writeProfileToFile writes the profile with the specified options to a file,
+// and returns its size.
+func writeProfileToFile(config *Configuration, profile *pprof_reader.Profile, outputPath, title string, options bf_format.ProbeOptions) (size int, err error) {
+	logger := config.Logger
+	logger.Debug().Msgf("Blackfire: Write profile to %s", outputPath)
+
+	buffer := new(bytes.Buffer)
+	if err = config.Encoder.Encode(buffer, profile, options, ProfileMetadata{Title: title}); err != nil {
+		return
+	}
+	size = buffer.Len()
+	err = ioutil.WriteFile(outputPath, buffer.Bytes(), 0644)
+	return
+}
+
+// CurrentProbeOptions returns the options requested by the server for the
+// current profile, so that integrations can adapt to them. They come from the
+// Blackfire query, or from the last signing response. The returned options
+// are a copy.
+func (p *Probe) CurrentProbeOptions() bf_format.ProbeOptions {
+	options := make(bf_format.ProbeOptions)
+	if err := p.configuration.load(); err != nil || p.configuration.Disabled {
+		return options
+	}
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	current := p.offlineProbeOptions()
+	if client := p.getAgentClient(); client != nil {
+		if signed, _ := client.ProbeOptions(); signed != nil {
+			current = signed
 		}
 	}
+	for name, value := range current {
+		options[name] = value
+	}
+	return options
+}
+
+// pendingProbeOptions returns the options of the profile about to start,
+// when they are already known: they come from a Blackfire query, while those
+// of signed profiles are only known when the profile is sent. It must be
+// called with the probe mutex held.
+func (p *Probe) pendingProbeOptions() bf_format.ProbeOptions {
+	client := p.getAgentClient()
+	if client == nil {
+		return withDimensions(p.offlineProbeOptions(), p.profileOptions.Dimensions)
+	}
+	if signed, consumed := client.ProbeOptions(); !consumed && signed != nil {
+		return withDimensions(signed, p.profileOptions.Dimensions)
+	}
+	return withDimensions(make(bf_format.ProbeOptions), p.profileOptions.Dimensions)
+}
+
+// withDimensions returns a copy of the probe options requesting the specified
+// cost dimensions, or the options themselves if no dimension is specified.
+func withDimensions(options bf_format.ProbeOptions, dimensions []string) bf_format.ProbeOptions {
+	if len(dimensions) == 0 {
+		return options
+	}
+	flags := map[string]string{
+		DimensionCPU:     bf_format.OptionFlagCPU,
+		DimensionMemory:  bf_format.OptionFlagMemory,
+		DimensionNetwork: bf_format.OptionFlagNW,
+	}
+	result := make(bf_format.ProbeOptions, len(options)+len(flags))
+	for name, value := range options {
+		result[name] = value
+	}
+	for _, flag := range flags {
+		result[flag] = "0"
+	}
+	for _, dimension := range dimensions {
+		result[flags[dimension]] = "1"
+	}
+	return result
+}
 
-	if callback != nil {
-		go callback()
+// offlineProbeOptions returns the probe options to use when no agent is
+// involved. They come from the Blackfire query if there is one.
+func (p *Probe) offlineProbeOptions() bf_format.ProbeOptions {
+	if response, err := signingResponseFromBFQuery(p.configuration.BlackfireQuery); err == nil && response != nil {
+		return response.Options
 	}
+	return make(bf_format.ProbeOptions)
 }
 
-func (p *probe) handlePanic(r interface{}) error {
+func (p *Probe) handlePanic(r interface{}) error {
 	p.disabledFromPanic = true
 	p.configuration.Logger.Error().Msgf("Unexpected panic %v. Probe has been disabled.", r)
 	p.configuration.Logger.Error().Msg(string(debug.Stack()))
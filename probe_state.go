@@ -0,0 +1,340 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+type profilerState int
+
+const (
+	profilerStateOff profilerState = iota
+	profilerStateEnabled
+	profilerStateDisabled
+	profilerStateSending
+)
+
+func (s profilerState) String() string {
+	switch s {
+	case profilerStateOff:
+		return "off"
+	case profilerStateEnabled:
+		return "enabled"
+	case profilerStateDisabled:
+		return "disabled"
+	case profilerStateSending:
+		return "sending"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(s))
+	}
+}
+
+// ProfilerStates returns the names of the states a probe can be in, as
+// reported by State.
+func ProfilerStates() []string {
+	return []string{
+		profilerStateOff.String(),
+		profilerStateEnabled.String(),
+		profilerStateDisabled.String(),
+		profilerStateSending.String(),
+	}
+}
+
+// State returns the name of the current state of the probe: off, enabled
+// (profiling), disabled (paused until enabled again or ended) or sending.
+func (p *Probe) State() string {
+	return p.getState().String()
+}
+
+// StopReason tells why the probe stopped profiling on its own, or returns an
+// empty string if it did not. It is reset when profiling is enabled again, so
+// that it still explains the state of the probe after an automatic end.
+func (p *Probe) StopReason() string {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	return p.stopReason
+}
+
+func (p *Probe) setStopReason(reason string) {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	p.stopReason = reason
+}
+
+type probeEvent int
+
+const (
+	// Start (or restart) collecting samples.
+	eventEnable probeEvent = iota
+	// Stop collecting samples, keeping them for the next End.
+	eventDisable
+	// The profiling window of an Enable elapsed.
+	eventExpire
+	// Stop collecting samples, then upload the profile.
+	eventEnd
+)
+
+func (e probeEvent) String() string {
+	switch e {
+	case eventEnable:
+		return "enable"
+	case eventDisable:
+		return "disable"
+	case eventExpire:
+		return "expire"
+	case eventEnd:
+		return "end"
+	default:
+		return fmt.Sprintf("unknown (%d)", int(e))
+	}
+}
+
+// transitions lists, for each state, the events it accepts and the state they
+// lead to. Any other event is refused. The sending state is only transient,
+// while an end event is being handled.
+var transitions = map[profilerState]map[probeEvent]profilerState{
+	profilerStateOff: {
+		eventEnable: profilerStateEnabled,
+	},
+	profilerStateEnabled: {
+		eventDisable: profilerStateDisabled,
+		eventExpire:  profilerStateDisabled,
+		eventEnd:     profilerStateOff,
+	},
+	profilerStateDisabled: {
+		eventEnable: profilerStateEnabled,
+		eventEnd:    profilerStateOff,
+	},
+	profilerStateSending: {},
+}
+
+// probeCommand is an event queued to the probe event loop.
+type probeCommand struct {
+	event probeEvent
+	// Profiling duration, for enable events.
+	duration time.Duration
+	// The profiling window that elapsed, for expire events.
+	window uint64
+	// Blackfire query and options to use for the profile, for enable events.
+	query   string
+	options ProfileOptions
+	// The trigger starting the profile, whose rate limit applies, for enable
+	// events.
+	trigger string
+	// Called with the outcome of the profile once it ended, for enable
+	// events starting a profile.
+	callback func(ProfileResult)
+	// Whether the profile ends when its profiling window expires, instead of
+	// waiting for an explicit end, for enable events starting a profile.
+	endOnExpiry bool
+	// Whether the enable or disable event resumes or pauses a profile, which
+	// only applies to a paused or running profile respectively.
+	pause bool
+	// For end events: write the profile to this file instead of uploading,
+	// and whether the result is only replied once the upload is done.
+	outputPath string
+	wait       bool
+
+	result chan error
+}
+
+// pauseAction names the action of a pause or resume command.
+func (c *probeCommand) pauseAction() string {
+	if c.event == eventEnable {
+		return "resume"
+	}
+	return "pause"
+}
+
+// reply sends the outcome of the command to its issuer, at most once.
+func (c *probeCommand) reply(err error) {
+	if c.result != nil {
+		c.result <- err
+		c.result = nil
+	}
+}
+
+func (p *Probe) getState() profilerState {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	return p.currentState
+}
+
+func (p *Probe) setState(state profilerState) {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	p.currentState = state
+}
+
+// profileEnded returns a channel closed when the current profile ends.
+func (p *Probe) profileEnded() <-chan struct{} {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	if p.profileEndedChan == nil {
+		p.profileEndedChan = make(chan struct{})
+	}
+	return p.profileEndedChan
+}
+
+func (p *Probe) notifyProfileEnded() {
+	p.stateMutex.Lock()
+	defer p.stateMutex.Unlock()
+	if p.profileEndedChan != nil {
+		close(p.profileEndedChan)
+		p.profileEndedChan = nil
+	}
+}
+
+// startEventLoop starts handling the queued commands, on first use so that
+// no goroutine is started for a disabled probe.
+func (p *Probe) startEventLoop() {
+	p.eventLoop.Do(func() {
+		go func() {
+			for command := range p.commands {
+				p.handleCommand(command)
+			}
+		}()
+	})
+}
+
+// execute queues a command and waits for its result.
+func (p *Probe) execute(command *probeCommand) error {
+	command.result = make(chan error, 1)
+	result := command.result
+	p.startEventLoop()
+	p.commands <- command
+	return <-result
+}
+
+// post queues a command without waiting for its result.
+func (p *Probe) post(command *probeCommand) {
+	p.startEventLoop()
+	p.commands <- command
+}
+
+func (p *Probe) handleCommand(command *probeCommand) {
+	defer func() {
+		if r := recover(); r != nil {
+			command.reply(p.handlePanic(r))
+		}
+	}()
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	logger := p.configuration.Logger
+	state := p.getState()
+
+	if command.event == eventExpire && (state != profilerStateEnabled || command.window != p.window) {
+		logger.Debug().Msgf("Blackfire: Ignoring the expiry of profiling window %d", command.window)
+		command.reply(nil)
+		return
+	}
+
+	if command.pause && !(command.event == eventEnable && state == profilerStateDisabled) && !(command.event == eventDisable && state == profilerStateEnabled) {
+		err := errors.Errorf("unable to %v profiling as state is %v", command.pauseAction(), state)
+		logger.Debug().Err(err).Msgf("Blackfire: wrong profiler state")
+		command.reply(err)
+		return
+	}
+
+	if command.event == eventEnable && state == profilerStateEnabled {
+		logger.Debug().Msgf("Blackfire: A profile is already in progress")
+		command.reply(ProfilerErrorAlreadyProfiling)
+		return
+	}
+
+	if command.event == eventEnd && state == profilerStateOff && p.StopReason() != "" {
+		// The profile was already ended when its duration elapsed.
+		logger.Debug().Msgf("Blackfire: The profile already ended (%s)", p.StopReason())
+		command.reply(nil)
+		return
+	}
+
+	if _, ok := transitions[state][command.event]; !ok {
+		err := errors.Errorf("unable to %v profiling as state is %v", command.event, state)
+		logger.Error().Err(err).Msgf("Blackfire: wrong profiler state")
+		command.reply(err)
+		return
+	}
+
+	switch command.event {
+	case eventEnable:
+		if state == profilerStateOff {
+			if err := p.checkProfileLimits(command.trigger); err != nil {
+				logger.Warn().Msgf("Blackfire: Not profiling: %v", err)
+				command.reply(err)
+				return
+			}
+		}
+		p.setStopReason("")
+		if command.query != "" {
+			if err := p.useBlackfireQuery(command.query); err != nil {
+				command.reply(err)
+				return
+			}
+		}
+		previousTitle := p.profileTitle
+		if command.options.Title != "" {
+			p.profileTitle = command.options.Title
+		}
+		if state == profilerStateOff {
+			p.profileOptions = command.options
+			p.profileTrigger = command.trigger
+			p.profileStartedAt = p.clock.Now()
+			p.profileEndCallback = command.callback
+			p.profileEndOnExpiry = command.endOnExpiry
+		}
+		duration := command.duration
+		if command.pause {
+			duration = p.requestedDuration - p.profiledDuration
+			if duration <= 0 {
+				// Let the window expire right away.
+				duration = time.Nanosecond
+			}
+		}
+		err := p.enableProfiling(duration)
+		if err != nil {
+			// The title must not leak into the next profile.
+			p.profileTitle = previousTitle
+			if state == profilerStateOff {
+				p.profileEndCallback = nil
+				p.profileEndOnExpiry = false
+			}
+		}
+		command.reply(err)
+		return
+	case eventDisable, eventExpire:
+		if command.event == eventExpire {
+			p.expireProfiling()
+		}
+		err := p.disableProfiling()
+		if err != nil {
+			logger.Error().Msgf("Blackfire (stop profiling): %v", err)
+		}
+		command.reply(err)
+		if command.event == eventExpire && err == nil && p.profileEndOnExpiry {
+			// Ending the profile right away, from the event loop, leaves no
+			// chance to a later profile to be ended instead.
+			if err := p.endProfileTo(p.configuration.OutputFile); err != nil {
+				logger.Error().Msgf("Blackfire (end profile): %v", err)
+			}
+			p.notifyProfileEnded()
+		}
+	case eventEnd:
+		if !command.wait {
+			command.reply(nil)
+		}
+		err := p.endProfileTo(command.outputPath)
+		if err != nil {
+			logger.Error().Msgf("Blackfire (end profile): %v", err)
+		}
+		p.notifyProfileEnded()
+		command.reply(err)
+	}
+}
@@ -0,0 +1,86 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+// enterState brings a test probe to the specified state using real commands,
+// except for the transient sending state which is forced.
+func enterState(c *C, p *Probe, state profilerState) {
+	switch state {
+	case profilerStateEnabled:
+		c.Assert(p.execute(&probeCommand{event: eventEnable}), IsNil)
+	case profilerStateDisabled:
+		c.Assert(p.execute(&probeCommand{event: eventEnable}), IsNil)
+		c.Assert(p.execute(&probeCommand{event: eventDisable}), IsNil)
+	case profilerStateSending:
+		p.setState(profilerStateSending)
+	}
+	c.Assert(p.getState(), Equals, state)
+}
+
+func leaveState(c *C, p *Probe) {
+	switch p.getState() {
+	case profilerStateEnabled, profilerStateDisabled:
+		c.Assert(p.execute(&probeCommand{event: eventEnd, outputPath: p.configuration.OutputFile, wait: true}), IsNil)
+	case profilerStateSending:
+		p.setState(profilerStateOff)
+	}
+}
+
+func (s *BlackfireSuite) TestProbeEveryTransition(c *C) {
+	states := []profilerState{profilerStateOff, profilerStateEnabled, profilerStateDisabled, profilerStateSending}
+	events := []probeEvent{eventEnable, eventDisable, eventExpire, eventEnd}
+
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.configuration.load(), IsNil)
+
+	for _, state := range states {
+		for _, event := range events {
+			comment := Commentf("%v + %v", state, event)
+			enterState(c, p, state)
+
+			err := p.execute(&probeCommand{
+				event:      event,
+				window:     p.window,
+				outputPath: p.configuration.OutputFile,
+				wait:       true,
+			})
+			expected, accepted := transitions[state][event]
+			switch {
+			case accepted:
+				c.Assert(err, IsNil, comment)
+				c.Assert(p.getState(), Equals, expected, comment)
+			case event == eventExpire:
+				// Expiries of a window that isn't running are ignored.
+				c.Assert(err, IsNil, comment)
+				c.Assert(p.getState(), Equals, state, comment)
+			default:
+				c.Assert(err, NotNil, comment)
+				c.Assert(p.getState(), Equals, state, comment)
+			}
+
+			leaveState(c, p)
+		}
+	}
+}
+
+func (s *BlackfireSuite) TestProbeStaleWindowExpiry(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.End(), IsNil)
+
+	c.Assert(p.EnableNowFor(time.Hour), IsNil)
+	staleWindow := p.window - 1
+	c.Assert(p.execute(&probeCommand{event: eventExpire, window: staleWindow}), IsNil)
+	c.Assert(p.getState(), Equals, profilerStateEnabled)
+
+	c.Assert(p.End(), IsNil)
+}
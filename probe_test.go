@@ -0,0 +1,1067 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sync"
+	"time"
+
+	"github.com/blackfireio/go-blackfire/bf_format"
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	. "gopkg.in/check.v1"
+)
+
+type fakeClock struct {
+	mutex  sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock    *fakeClock
+	deadline time.Time
+	c        chan time.Time
+	stopped  bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{
+		now: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) NewTimer(d time.Duration) timer {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	t := &fakeTimer{
+		clock:    c,
+		deadline: c.now.Add(d),
+		c:        make(chan time.Time, 1),
+	}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves the clock forward, firing the timers that expire.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.now = c.now.Add(d)
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.stopped {
+			continue
+		}
+		if t.deadline.After(c.now) {
+			pending = append(pending, t)
+			continue
+		}
+		t.c <- c.now
+	}
+	c.timers = pending
+}
+
+func (t *fakeTimer) C() <-chan time.Time {
+	return t.c
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mutex.Lock()
+	defer t.clock.mutex.Unlock()
+	wasActive := !t.stopped
+	t.stopped = true
+	return wasActive
+}
+
+// activeTimers returns the number of timers that are neither stopped nor
+// expired.
+func (c *fakeClock) activeTimers() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	count := 0
+	for _, t := range c.timers {
+		if !t.stopped {
+			count++
+		}
+	}
+	return count
+}
+
+func newTestProbe(clock clock) *Probe {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := newProbe()
+	p.clock = clock
+	p.Configure(&Configuration{
+		OutputFile: filepath.Join(os.TempDir(), "blackfire-probe-test.bf"),
+		Logger:     &logger,
+	})
+	return p
+}
+
+func (p *Probe) stateForTest() profilerState {
+	return p.getState()
+}
+
+// waitForState waits for the asynchronous triggers to bring the probe to the
+// expected state.
+func waitForState(p *Probe, expected profilerState) profilerState {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if state := p.stateForTest(); state == expected {
+			return state
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return p.stateForTest()
+}
+
+func (s *BlackfireSuite) TestProbeWindowExpiry(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+
+	clock.Advance(30 * time.Second)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+
+	clock.Advance(30 * time.Second)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestProbeProfileStartup(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	events, unsubscribe := p.subscribe()
+	defer unsubscribe()
+
+	c.Assert(p.ProfileStartup(time.Minute), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	event := <-events
+	c.Assert(event.Type, Equals, lifecycleStarted)
+	c.Assert(event.Title, Equals, "startup")
+
+	// The profile ends on its own once the duration elapsed.
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestProbeProfileStartupPause(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	events, unsubscribe := p.subscribe()
+	defer unsubscribe()
+
+	c.Assert(p.ProfileStartup(time.Minute), IsNil)
+	c.Assert((<-events).Type, Equals, lifecycleStarted)
+	p.Metric("ops").Add(1)
+
+	// Pausing doesn't end the profile.
+	c.Assert(p.Pause(), IsNil)
+	c.Assert((<-events).Type, Equals, lifecycleStopped)
+	clock.Advance(time.Minute)
+	c.Assert(p.stateForTest(), Equals, profilerStateDisabled)
+
+	c.Assert(p.Resume(), IsNil)
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestProbeProfileStartupEndedEarly(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	c.Assert(p.ProfileStartup(time.Minute), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+
+	// The expiry of a later profile doesn't end it.
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.Resume(), IsNil)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeProfileWithCallback(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	results := make(chan ProfileResult, 1)
+	callback := func(result ProfileResult) {
+		results <- result
+	}
+
+	c.Assert(p.ProfileWithCallback(time.Minute, callback), IsNil)
+	c.Assert(p.ProfileWithCallback(time.Minute, callback), Equals, ProfilerErrorAlreadyProfiling)
+	p.SetCurrentTitle("callback")
+	p.Metric("ops").Add(1)
+	clock.Advance(time.Minute)
+	result := <-results
+	c.Assert(result.Title, Equals, "callback")
+	c.Assert(result.Err, IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	// The upload errors are reported too.
+	p.configuration.OutputFile = ""
+	p.configuration.Exporter = &testExporter{err: errors.New("unavailable")}
+	c.Assert(p.ProfileWithCallback(time.Minute, callback), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), ErrorMatches, "unavailable")
+	c.Assert((<-results).Err, ErrorMatches, "unavailable")
+	select {
+	case result := <-results:
+		c.Fatalf("Unexpected result %+v", result)
+	default:
+	}
+
+	// Disabling profiling doesn't end the profile, nor calls back.
+	p.configuration.Exporter = nil
+	p.configuration.OutputFile = filepath.Join(os.TempDir(), "blackfire-probe-test.bf")
+	c.Assert(p.ProfileWithCallback(time.Minute, callback), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.Disable(), IsNil)
+	clock.Advance(time.Minute)
+	c.Assert(p.stateForTest(), Equals, profilerStateDisabled)
+	select {
+	case result := <-results:
+		c.Fatalf("Unexpected result %+v", result)
+	default:
+	}
+	c.Assert(p.End(), IsNil)
+	c.Assert((<-results).Err, IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeMaxDurationCap(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.MaxProfileDuration = time.Minute
+
+	c.Assert(p.EnableNowFor(time.Hour), IsNil)
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeAutoStop(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.MaxProfileDuration = time.Minute
+	reasons := make(chan string, 1)
+	p.configuration.OnAutoStop = func(reason string) {
+		reasons <- reason
+	}
+	os.Remove(p.configuration.OutputFile)
+	defer os.Remove(p.configuration.OutputFile)
+
+	c.Assert(p.EnableNowFor(time.Hour), IsNil)
+	c.Assert(p.StopReason(), Equals, "")
+	events, unsubscribe := p.subscribe()
+	defer unsubscribe()
+	p.Metric("ops").Add(1)
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(<-reasons, Equals, StopReasonMaxDuration)
+	c.Assert(p.StopReason(), Equals, StopReasonMaxDuration)
+	event := <-events
+	c.Assert(event.Type, Equals, lifecycleStopped)
+	c.Assert(event.Reason, Equals, StopReasonMaxDuration)
+
+	// End uploads the samples collected until then.
+	c.Assert(p.End(), IsNil)
+	_, err := os.Stat(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+
+	// Ending a profile which already ended on its own is not an error.
+	c.Assert(p.ProfileStartup(time.Second), IsNil)
+	c.Assert(p.StopReason(), Equals, "")
+	clock.Advance(time.Second)
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+	c.Assert(<-reasons, Equals, StopReasonDuration)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeStateTransitions(c *C) {
+	p := newTestProbe(newFakeClock())
+
+	c.Assert(p.Disable(), NotNil)
+	c.Assert(p.End(), NotNil)
+
+	c.Assert(p.EnableNow(), IsNil)
+	c.Assert(p.EnableNow(), NotNil)
+
+	c.Assert(p.Disable(), IsNil)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.Disable(), NotNil)
+
+	c.Assert(p.EnableNow(), IsNil)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+}
+
+// waitForActiveTimers waits for the timer goroutines to settle.
+func waitForActiveTimers(clock *fakeClock, expected int) int {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		if count := clock.activeTimers(); count == expected {
+			return count
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return clock.activeTimers()
+}
+
+func (s *BlackfireSuite) TestProbeEndCancelsWindowTimer(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(clock.activeTimers(), Equals, 1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(waitForActiveTimers(clock, 0), Equals, 0)
+}
+
+func (s *BlackfireSuite) TestProbeOverlappingWindows(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	// The first window must not truncate the second one.
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.EnableNowFor(2*time.Minute), IsNil)
+	c.Assert(waitForActiveTimers(clock, 1), Equals, 1)
+
+	clock.Advance(time.Minute)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+
+	// Disabling then re-enabling starts a fresh window.
+	c.Assert(p.Disable(), IsNil)
+	c.Assert(p.EnableNowFor(2*time.Minute), IsNil)
+	c.Assert(waitForActiveTimers(clock, 1), Equals, 1)
+
+	clock.Advance(time.Minute)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestIndependentProbes(c *C) {
+	first := newTestProbe(newFakeClock())
+	second := newTestProbe(newFakeClock())
+
+	c.Assert(first.EnableNowFor(time.Minute), IsNil)
+	c.Assert(first.stateForTest(), Equals, profilerStateEnabled)
+	c.Assert(second.stateForTest(), Equals, profilerStateOff)
+	c.Assert(first.Disable(), IsNil)
+	c.Assert(second.stateForTest(), Equals, profilerStateOff)
+	c.Assert(first.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeMiddleware(c *C) {
+	p := newTestProbe(newFakeClock())
+
+	var statesSeen []profilerState
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		statesSeen = append(statesSeen, p.stateForTest())
+	}))
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "/", nil))
+	c.Assert(p.agentClient, IsNil)
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set(blackfireQueryHeader, "expires=2000000000&signature=sig&agentIds=request-id")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+	c.Assert(p.agentClient.signingResponse.Agents, DeepEquals, []string{"request-id"})
+
+	c.Assert(statesSeen, DeepEquals, []profilerState{profilerStateOff, profilerStateEnabled})
+}
+
+func (s *BlackfireSuite) TestProbeMiddlewareTags(c *C) {
+	p := newTestProbe(newFakeClock())
+	os.Remove(p.configuration.OutputFile)
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.Metric("ops").Add(1)
+	}))
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set(blackfireQueryHeader, "expires=2000000000&signature=sig")
+	request.Header.Set("X-Request-ID", "abc")
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*\nContext: [^\n]*&trace_id=4bf92f3577b34da6a3ce929d0e0e4736&traceparent=00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01&x-request-id=abc\n.*")
+}
+
+func (s *BlackfireSuite) TestProbeMiddlewareCapture(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.MiddlewareCapture = &RequestCapture{
+		Method:     true,
+		Path:       true,
+		StatusCode: true,
+		Duration:   true,
+		Redact: func(name, value string) string {
+			if name == "path" {
+				return "/users/{id}"
+			}
+			return value
+		},
+	}
+	os.Remove(p.configuration.OutputFile)
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.Metric("ops").Add(1)
+		clock.Advance(12 * time.Millisecond)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	request := httptest.NewRequest("POST", "/users/1234", nil)
+	request.Header.Set(blackfireQueryHeader, "expires=2000000000&signature=sig")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*\nContext: script=[^\n]*&request_method=POST&request_path=%2Fusers%2F%7Bid%7D&request_status_code=201&request_duration=12ms\n.*")
+
+	// The attributes that don't fit are dropped.
+	p.configuration.MiddlewareCapture.MaxSize = 45
+	c.Assert(p.configuration.MiddlewareCapture.context(request, &statusRecorder{status: 404}, time.Second), Equals, "request_method=POST&request_status_code=404")
+}
+
+func (s *BlackfireSuite) TestProbeMiddlewareCapturePanics(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.configuration.MiddlewareCapturePanics = true
+	os.Remove(p.configuration.OutputFile)
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.Metric("ops").Add(1)
+		panic("boom")
+	}))
+
+	request := httptest.NewRequest("GET", "/", nil)
+	request.Header.Set(blackfireQueryHeader, "expires=2000000000&signature=sig")
+	c.Assert(func() {
+		handler.ServeHTTP(httptest.NewRecorder(), request)
+	}, PanicMatches, "boom")
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*\nContext: [^\n]*&panic=boom&panicked=true\n.*")
+}
+
+func (s *BlackfireSuite) TestProbeTitleTemplate(c *C) {
+	p := newTestProbe(newFakeClock())
+	exporter := &testExporter{}
+	p.configuration.OutputFile = ""
+	p.configuration.Exporter = exporter
+	p.configuration.TitleTemplate = `{{.Title}} {{.Trigger}}{{.Route}} {{.Time.Format "2006-01-02"}}`
+	p.configuration.MiddlewareTitleTemplate = `{{.Method}} {{.Route}}@{{.Hostname}}`
+	hostname, err := os.Hostname()
+	c.Assert(err, IsNil)
+
+	p.SetCurrentTitle("job")
+	c.Assert(p.EnableNow(), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.enableNowFor(0, ProfileOptions{Title: "http"}, triggerHTTP, nil), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+
+	handler := p.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p.Metric("ops").Add(1)
+	}))
+	request := httptest.NewRequest("POST", "/users/1234", nil)
+	request.Header.Set(blackfireQueryHeader, "expires=2000000000&signature=sig")
+	handler.ServeHTTP(httptest.NewRecorder(), request)
+
+	c.Assert(exporter.titles, DeepEquals, []string{
+		"job  2020-01-01",
+		"http http 2020-01-01",
+		"POST /users/1234@" + hostname,
+	})
+
+	// The templates are checked with the configuration.
+	p = newTestProbe(newFakeClock())
+	p.configuration.TitleTemplate = "{{.Route"
+	c.Assert(p.EnableNow(), ErrorMatches, "Invalid title template .*")
+}
+
+func (s *BlackfireSuite) TestProbeDefaultTitle(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	exporter := &testExporter{}
+	p.configuration.OutputFile = ""
+	p.configuration.Exporter = exporter
+	hostname, err := os.Hostname()
+	c.Assert(err, IsNil)
+
+	c.Assert(p.EnableNow(), IsNil)
+	p.Metric("ops").Add(1)
+	clock.Advance(time.Minute)
+	c.Assert(p.End(), IsNil)
+	c.Assert(exporter.titles, DeepEquals, []string{pprof_reader.ExecutableName() + "@" + hostname + " 2020-01-01 00:00:00"})
+
+	p = newTestProbe(clock)
+	p.configuration.OutputFile = ""
+	p.configuration.Exporter = exporter
+	p.configuration.DefaultTitleTemplate = `{{.Executable}} {{.Trigger}}`
+	c.Assert(p.enableNowFor(0, ProfileOptions{}, triggerSignal, nil), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(exporter.titles[1], Equals, pprof_reader.ExecutableName()+" signal")
+}
+
+func (s *BlackfireSuite) TestProbeContextProvider(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.configuration.ContextProvider = func() url.Values {
+		return url.Values{"job": {"42"}, "queue": {"emails"}}
+	}
+	os.Remove(p.configuration.OutputFile)
+
+	c.Assert(p.EnableNow(), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*\nContext: job=42&queue=emails\n.*")
+}
+
+func (p *Probe) titleForTest() string {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.title()
+}
+
+func (s *BlackfireSuite) TestProbeProfileTitle(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.SetCurrentTitle("current")
+
+	c.Assert(p.EnableNowForWithOptions(time.Minute, ProfileOptions{Title: "own"}), IsNil)
+	c.Assert(p.titleForTest(), Equals, "own")
+	p.SetCurrentTitle("changed")
+	c.Assert(p.titleForTest(), Equals, "own")
+	c.Assert(p.End(), IsNil)
+
+	// The title of a profile does not leak into the following ones.
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.titleForTest(), Equals, "changed")
+	c.Assert(p.End(), IsNil)
+
+	// Nor does the title of a profile that failed to start.
+	other := newTestProbe(newFakeClock())
+	c.Assert(other.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.EnableNowForWithOptions(time.Minute, ProfileOptions{Title: "failed"}), Equals, ProfilerErrorCPUProfilerInUse)
+	c.Assert(other.End(), IsNil)
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.titleForTest(), Equals, "changed")
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeAlreadyProfiling(c *C) {
+	p := newTestProbe(newFakeClock())
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.EnableNowFor(time.Minute), Equals, ProfilerErrorAlreadyProfiling)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeCPUProfilerInUse(c *C) {
+	first := newTestProbe(newFakeClock())
+	second := newTestProbe(newFakeClock())
+
+	c.Assert(first.EnableNowFor(time.Minute), IsNil)
+	c.Assert(second.EnableNowFor(time.Minute), Equals, ProfilerErrorCPUProfilerInUse)
+	c.Assert(second.stateForTest(), Equals, profilerStateOff)
+
+	// The CPU profiler is released when the profile is paused or ended.
+	c.Assert(first.Pause(), IsNil)
+	c.Assert(second.EnableNowFor(time.Minute), IsNil)
+	c.Assert(first.Resume(), Equals, ProfilerErrorCPUProfilerInUse)
+	c.Assert(second.End(), IsNil)
+	c.Assert(first.Resume(), IsNil)
+	c.Assert(first.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeWaitAndEnable(c *C) {
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+
+	// Give up when the context is done.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	c.Assert(p.WaitAndEnable(ctx, time.Minute), Equals, context.DeadlineExceeded)
+
+	// Enable as soon as the current profile ends.
+	result := make(chan error, 1)
+	go func() {
+		result <- p.WaitAndEnable(context.Background(), time.Minute)
+	}()
+	c.Assert(p.End(), IsNil)
+	select {
+	case err := <-result:
+		c.Assert(err, IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("WaitAndEnable did not return after the profile ended")
+	}
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbePauseResume(c *C) {
+	p := newTestProbe(newFakeClock())
+
+	c.Assert(p.Pause(), ErrorMatches, "unable to pause profiling as state is off")
+	c.Assert(p.Resume(), ErrorMatches, "unable to resume profiling as state is off")
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.Resume(), ErrorMatches, "unable to resume profiling as state is enabled")
+	c.Assert(p.Pause(), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateDisabled)
+	c.Assert(p.Pause(), ErrorMatches, "unable to pause profiling as state is disabled")
+	c.Assert(p.Resume(), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	c.Assert(p.Pause(), IsNil)
+	c.Assert(p.Resume(), IsNil)
+
+	p.mutex.Lock()
+	c.Assert(len(p.gaps), Equals, 2)
+	p.mutex.Unlock()
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeStats(c *C) {
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.Stats(), Equals, ProbeStats{})
+
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.Disable(), IsNil)
+	stats := p.Stats()
+	c.Assert(stats.ProfilesStarted, Equals, 1)
+	c.Assert(stats.Profiles, Equals, 0)
+	c.Assert(stats.HeapSnapshotTime > 0, Equals, true)
+
+	c.Assert(p.End(), IsNil)
+	stats = p.Stats()
+	c.Assert(stats.Profiles, Equals, 1)
+	c.Assert(stats.ConversionTime > 0, Equals, true)
+	c.Assert(p.State(), Equals, "off")
+}
+
+func (s *BlackfireSuite) TestProbeStatsOverheadHeader(c *C) {
+	stats := ProbeStats{
+		Profiles:         2,
+		HeapSnapshotTime: 1500 * time.Microsecond,
+		ConversionTime:   3 * time.Millisecond,
+		UploadTime:       time.Second,
+		DroppedSamples:   7,
+	}
+	c.Assert(stats.overheadHeader(), Equals, "conversion_us=3000&dropped_samples=7&heap_snapshot_us=1500&profiles=2&upload_us=1000000")
+}
+
+func (s *BlackfireSuite) TestProbeCurrentProfile(c *C) {
+	p := newTestProbe(newFakeClock())
+
+	profile, err := p.CurrentProfile()
+	c.Assert(err, IsNil)
+	c.Assert(profile, IsNil)
+
+	// Profiles written to a file are never uploaded.
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	profile, err = p.CurrentProfile()
+	c.Assert(err, IsNil)
+	c.Assert(profile, IsNil)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeDisabled(c *C) {
+	os.Setenv("BLACKFIRE_DISABLED", "true")
+	defer os.Unsetenv("BLACKFIRE_DISABLED")
+
+	p := NewProbe(&Configuration{
+		ConfigFile: "fixtures/test_blackfire.ini",
+	})
+	c.Assert(p.EnableNowFor(time.Minute), IsNil)
+	c.Assert(p.IsProfiling(), Equals, false)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.enableForQuery("signature=x", ProfileOptions{}), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	// The INI file is not even loaded.
+	c.Assert(p.configuration.Disabled, Equals, true)
+	c.Assert(p.configuration.ClientID, Equals, "")
+
+	handler, err := p.ToggleOnSignalHandler(os.Interrupt, time.Minute)
+	c.Assert(err, IsNil)
+	c.Assert(handler, IsNil)
+	server, err := p.StartServer("127.0.0.1:0")
+	c.Assert(err, IsNil)
+	c.Assert(server, IsNil)
+	_, err = p.CheckAgent(context.Background())
+	c.Assert(err, Equals, errDisabled)
+}
+
+func (s *BlackfireSuite) TestProbeAutoEnable(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := NewProbe(&Configuration{
+		OutputFile:         filepath.Join(os.TempDir(), "blackfire-probe-test.bf"),
+		Logger:             &logger,
+		AutoEnable:         true,
+		AutoEnableDuration: time.Minute,
+	})
+	c.Assert(waitForState(p, profilerStateEnabled), Equals, profilerStateEnabled)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	// Auto-enabling only happens once.
+	p.autoEnable()
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	// The auto_enable option of the Blackfire query also enables profiling.
+	p = NewProbe(&Configuration{
+		OutputFile:     filepath.Join(os.TempDir(), "blackfire-probe-test.bf"),
+		Logger:         &logger,
+		BlackfireQuery: "expires=9999999999&signature=abc&auto_enable=1",
+	})
+	c.Assert(waitForState(p, profilerStateEnabled), Equals, profilerStateEnabled)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeCurrentProbeOptions(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := NewProbe(&Configuration{
+		OutputFile:     filepath.Join(os.TempDir(), "blackfire-probe-test.bf"),
+		Logger:         &logger,
+		BlackfireQuery: "expires=9999999999&signature=abc&flag_timespan=1&aggreg_samples=10",
+	})
+	options := p.CurrentProbeOptions()
+	c.Assert(options.GetBool(bf_format.OptionFlagTimespan, false), Equals, true)
+	c.Assert(options.GetInt(bf_format.OptionAggregSamples, 1), Equals, 10)
+	c.Assert(options.GetString(bf_format.OptionSignature, ""), Equals, "")
+
+	// The options are a copy.
+	options[bf_format.OptionFlagTimespan] = "0"
+	c.Assert(p.CurrentProbeOptions().GetBool(bf_format.OptionFlagTimespan, false), Equals, true)
+}
+
+func cpuProfilerInUse() bool {
+	if err := pprof.StartCPUProfile(ioutil.Discard); err != nil {
+		return true
+	}
+	pprof.StopCPUProfile()
+	return false
+}
+
+func (s *BlackfireSuite) TestProbeSkipsDimensions(c *C) {
+	rate := runtime.MemProfileRate
+
+	p := newTestProbe(newFakeClock())
+	p.configuration.MemProfileRate = 1
+	c.Assert(p.enableForQuery("expires=9999999999&signature=abc&flag_cpu=0", ProfileOptions{}), IsNil)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	c.Assert(cpuProfilerInUse(), Equals, false)
+	c.Assert(runtime.MemProfileRate, Equals, 1)
+	c.Assert(p.End(), IsNil)
+	c.Assert(runtime.MemProfileRate, Equals, rate)
+
+	p = newTestProbe(newFakeClock())
+	p.configuration.MemProfileRate = 1
+	c.Assert(p.enableForQuery("expires=9999999999&signature=abc&flag_memory=0", ProfileOptions{}), IsNil)
+	c.Assert(cpuProfilerInUse(), Equals, true)
+	c.Assert(runtime.MemProfileRate, Equals, rate)
+	c.Assert(p.End(), IsNil)
+
+	// Both dimensions are collected by default.
+	p = newTestProbe(newFakeClock())
+	p.configuration.MemProfileRate = 1
+	c.Assert(p.EnableNow(), IsNil)
+	c.Assert(cpuProfilerInUse(), Equals, true)
+	c.Assert(runtime.MemProfileRate, Equals, 1)
+	c.Assert(p.End(), IsNil)
+}
+
+func (s *BlackfireSuite) TestProbeNetwork(c *C) {
+	p := newTestProbe(newFakeClock())
+	client, server := net.Pipe()
+	defer server.Close()
+	conn := p.WrapConn(client)
+	defer conn.Close()
+	exchange := func() {
+		go server.Write([]byte("hello"))
+		buffer := make([]byte, 5)
+		_, err := io.ReadFull(conn, buffer)
+		c.Assert(err, IsNil)
+	}
+
+	// Nothing is recorded unless the server requests it.
+	c.Assert(p.EnableNow(), IsNil)
+	exchange()
+	c.Assert(p.network, HasLen, 0)
+	c.Assert(p.End(), IsNil)
+
+	c.Assert(p.enableForQuery("expires=9999999999&signature=abc&flag_nw=1", ProfileOptions{}), IsNil)
+	exchange()
+	c.Assert(p.network, HasLen, 1)
+	c.Assert(p.End(), IsNil)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*Cost-Dimensions: cpu pmu nw_in nw_out\n.*")
+	c.Assert(string(contents), Matches, `(?s).*TestProbeNetwork.func1//1 0 0 5 0\n.*`)
+}
+
+func (s *BlackfireSuite) TestProbeMetrics(c *C) {
+	p := newTestProbe(newFakeClock())
+	hits := p.Metric("cache hits")
+	c.Assert(hits.Name(), Equals, "cache_hits")
+
+	// Nothing is recorded when not profiling.
+	hits.Add(1)
+	c.Assert(p.metrics, HasLen, 0)
+
+	// Only some of the updates take a call stack, but all of them are
+	// accounted.
+	c.Assert(p.EnableNow(), IsNil)
+	for i := 0; i < 2*callSampleInterval+3; i++ {
+		hits.Add(2)
+	}
+	c.Assert(p.metrics, HasLen, 1)
+	c.Assert(p.End(), IsNil)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*Cost-Dimensions: cpu pmu metric_cache_hits\n.*")
+	c.Assert(string(contents), Matches, `(?s).*TestProbeMetrics//35 0 0 70\n.*`)
+}
+
+func (s *BlackfireSuite) TestProbeEnableWithOptions(c *C) {
+	p := newTestProbe(newFakeClock())
+	os.Remove(p.configuration.OutputFile)
+	c.Assert(p.EnableWithOptions(ProfileOptions{Dimensions: []string{"disk"}}), ErrorMatches, `unknown cost dimension "disk"`)
+	c.Assert(p.stateForTest(), Equals, profilerStateOff)
+
+	c.Assert(p.EnableWithOptions(ProfileOptions{
+		Title:           "with options",
+		Duration:        time.Second,
+		CPUSampleRateHz: 200,
+		Tags:            map[string]string{"env": "test"},
+		Dimensions:      []string{DimensionCPU},
+	}), IsNil)
+	c.Assert(p.cpuSampleRate, Equals, 200)
+	c.Assert(p.skipMemory, Equals, true)
+	p.Metric("ops").Add(1)
+	p.clock.(*fakeClock).Advance(time.Second)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	c.Assert(p.End(), IsNil)
+
+	contents, err := ioutil.ReadFile(p.configuration.OutputFile)
+	c.Assert(err, IsNil)
+	c.Assert(string(contents), Matches, "(?s).*Cost-Dimensions: cpu metric_ops\n.*")
+	c.Assert(string(contents), Matches, "(?s).*\nContext: [^\n]*&env=test\n.*")
+	c.Assert(string(contents), Matches, `(?s).*"title":"with options".*`)
+
+	// The options only apply to a single profile.
+	c.Assert(p.EnableNow(), IsNil)
+	c.Assert(p.cpuSampleRate, Equals, p.configuration.DefaultCPUSampleRateHz)
+	c.Assert(p.skipMemory, Equals, false)
+	c.Assert(p.End(), IsNil)
+}
+
+type testExporter struct {
+	titles []string
+	err    error
+}
+
+func (e *testExporter) Export(profile *pprof_reader.Profile, title string) (int, error) {
+	e.titles = append(e.titles, title)
+	return len(title), e.err
+}
+
+func (s *BlackfireSuite) TestProbeSinks(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-sinks")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	exporter := &testExporter{}
+	failing := &testExporter{err: errors.New("unavailable")}
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := newProbe()
+	p.clock = newFakeClock()
+	p.Configure(&Configuration{
+		OutputFile: filepath.Join(os.TempDir(), "blackfire-probe-test.bf"),
+		Logger:     &logger,
+		Sinks: []Sink{
+			{OutputDir: dir},
+			{Name: "exporter", Exporter: exporter},
+			{Exporter: failing},
+		},
+	})
+
+	c.Assert(p.EnableWithOptions(ProfileOptions{Title: "sinks"}), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	p.sinkDeliveries.Wait()
+
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+	c.Assert(exporter.titles, DeepEquals, []string{"sinks"})
+	c.Assert(failing.titles, DeepEquals, []string{"sinks"})
+
+	c.Assert(p.Stats().UploadsSucceeded, Equals, 1)
+	sinks := p.Sinks()
+	c.Assert(sinks, HasLen, 3)
+	c.Assert(sinks[0].Name, Equals, "directory "+dir)
+	c.Assert(sinks[0].UploadsSucceeded, Equals, 1)
+	c.Assert(sinks[0].PayloadBytes, Equals, uint64(files[0].Size()))
+	c.Assert(sinks[1], DeepEquals, SinkStats{Name: "exporter", UploadsSucceeded: 1, PayloadBytes: 5})
+	c.Assert(sinks[2], DeepEquals, SinkStats{Name: "exporter *blackfire.testExporter", UploadsFailed: 1, LastError: failing.err})
+}
+
+func (s *BlackfireSuite) TestProbeSinkCredentials(c *C) {
+	p := newTestProbe(newFakeClock())
+	c.Assert(p.configuration.load(), IsNil)
+	endpoint, err := url.Parse("https://other.blackfire.example")
+	c.Assert(err, IsNil)
+
+	client, err := p.sinkAgentClient(p.configuration, Sink{AgentSocket: "tcp://127.0.0.1:8307"})
+	c.Assert(err, IsNil)
+	c.Assert(client.signingAuth, Equals, apiAuthorization(p.configuration))
+
+	client, err = p.sinkAgentClient(p.configuration, Sink{AgentSocket: "tcp://127.0.0.1:8307", ClientID: "id", ClientToken: "token", HTTPEndpoint: endpoint})
+	c.Assert(err, IsNil)
+	c.Assert(client.signingAuth, Equals, basicAuthorization("id", "token"))
+	c.Assert(client.signingEndpoint.String(), Equals, "https://other.blackfire.example/api/v1/signing")
+	c.Assert(client.agentAddress, Equals, "127.0.0.1:8307")
+}
+
+// jsonEncoder writes the title and the sample count of the profiles as JSON.
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(w io.Writer, profile *pprof_reader.Profile, options bf_format.ProbeOptions, metadata ProfileMetadata) error {
+	return json.NewEncoder(w).Encode(map[string]interface{}{
+		"title":   metadata.Title,
+		"samples": len(profile.Samples),
+	})
+}
+
+func (s *BlackfireSuite) TestProbeEncoder(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	output := filepath.Join(os.TempDir(), "blackfire-encoder-test.json")
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := newProbe()
+	p.clock = newFakeClock()
+	p.Configure(&Configuration{
+		OutputFile: output,
+		Encoder:    jsonEncoder{},
+		Logger:     &logger,
+	})
+
+	c.Assert(p.EnableWithOptions(ProfileOptions{Title: "encoded"}), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	data, err := ioutil.ReadFile(output)
+	c.Assert(err, IsNil)
+	c.Assert(string(data), Equals, `{"samples":1,"title":"encoded"}`+"\n")
+
+	// The Blackfire format is the default.
+	c.Assert(newConfiguration(&Configuration{OutputFile: output}).Encoder, Equals, BFEncoder{})
+}
+
+func (s *BlackfireSuite) TestProbeRetryUpload(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-spool")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer listener.Close()
+	// The agent refuses the first profile, and accepts the second one.
+	responses := []string{"Blackfire-Error: Invalid signature\n\n", "Blackfire-Response: continue=true\n\n"}
+	go func() {
+		for _, response := range responses {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			reader := bufio.NewReader(conn)
+			for {
+				line, err := reader.ReadString('\n')
+				if err != nil || line == "\n" {
+					break
+				}
+			}
+			conn.Write([]byte(response))
+			ioutil.ReadAll(reader)
+			conn.Close()
+		}
+	}()
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := newProbe()
+	p.clock = newFakeClock()
+	p.Configure(&Configuration{
+		AgentSocket:    "tcp://" + listener.Addr().String(),
+		BlackfireQuery: "expires=9999999999&signature=abc",
+		SpoolDir:       dir,
+		Logger:         &logger,
+	})
+
+	c.Assert(p.EnableNow(), IsNil)
+	p.Metric("ops").Add(1)
+	err = p.End()
+	c.Assert(err, FitsTypeOf, &AgentError{})
+	c.Assert(err.(*AgentError).Err, Equals, ErrAgentInvalidSignature)
+	c.Assert(err, ErrorMatches, "Blackfire-Error: Invalid signature")
+	files, err := ioutil.ReadDir(dir)
+	c.Assert(err, IsNil)
+	c.Assert(files, HasLen, 1)
+
+	p.mutex.Lock()
+	c.Assert(p.useBlackfireQuery("expires=9999999999&signature=def"), IsNil)
+	p.mutex.Unlock()
+	c.Assert(p.RetryUpload(), IsNil)
+	c.Assert(p.Stats().UploadsFailed, Equals, 1)
+	c.Assert(p.Stats().UploadsSucceeded, Equals, 1)
+	c.Assert(p.RetryUpload(), IsNil)
+	c.Assert(p.Stats().UploadsSucceeded, Equals, 1)
+}
@@ -1,13 +1,18 @@
 package blackfire
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"strings"
 	"time"
 )
 
+type linksMap map[string]map[string]string
+
 type Profile struct {
 	UUID      string
 	URL       string
@@ -18,6 +23,8 @@ type Profile struct {
 	Envelope  Envelope `json:"envelope"`
 	Links     linksMap `json:"_links"`
 
+	// auth is the Authorization header of the API requests.
+	auth    string
 	retries int
 	loaded  bool
 }
@@ -29,6 +36,34 @@ type Envelope struct {
 	PMU int `json:"pmu"`
 }
 
+// EnvelopeBudget is a budget for the main costs of a profile, to be checked
+// with AssertProfile. Zero limits are not checked.
+type EnvelopeBudget struct {
+	CPU time.Duration
+	// Memory and PeakMemory are in bytes.
+	Memory     int
+	PeakMemory int
+}
+
+// Check returns an error listing the costs of the envelope which are over
+// budget, if any.
+func (b EnvelopeBudget) Check(e Envelope) error {
+	var overruns []string
+	if cpu := time.Duration(e.CPU) * time.Microsecond; b.CPU > 0 && cpu > b.CPU {
+		overruns = append(overruns, fmt.Sprintf("CPU %v > %v", cpu, b.CPU))
+	}
+	if b.Memory > 0 && e.MU > b.Memory {
+		overruns = append(overruns, fmt.Sprintf("memory %d > %d bytes", e.MU, b.Memory))
+	}
+	if b.PeakMemory > 0 && e.PMU > b.PeakMemory {
+		overruns = append(overruns, fmt.Sprintf("peak memory %d > %d bytes", e.PMU, b.PeakMemory))
+	}
+	if len(overruns) > 0 {
+		return fmt.Errorf("The profile is over budget: %s", strings.Join(overruns, ", "))
+	}
+	return nil
+}
+
 type Status struct {
 	Name          string `json:"name"`
 	Code          int    `json:"code"`
@@ -51,7 +86,62 @@ func (m *BFTime) UnmarshalJSON(b []byte) (err error) {
 	return
 }
 
-func (p *Profile) load(auth string) error {
+// apiError is returned when the Blackfire API answers with an error status.
+type apiError struct {
+	status string
+	code   int
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("The Blackfire API returned %s", e.status)
+}
+
+// isTemporaryAPIError tells whether the request failed because of a server
+// error, and may succeed when retried.
+func isTemporaryAPIError(err error) bool {
+	e, ok := err.(*apiError)
+	return ok && e.code >= 500
+}
+
+// The delays between two requests polling the status of a profile.
+const (
+	profilePollMinDelay = 500 * time.Millisecond
+	profilePollMaxDelay = 10 * time.Second
+)
+
+// Wait polls the Blackfire API, backing off exponentially, until the profile
+// is finalized, and returns its final status. Server errors are retried.
+func (p *Profile) Wait(ctx context.Context) (Status, error) {
+	if p.APIURL == "" {
+		return Status{}, errors.New("The API URL of the profile is unknown")
+	}
+	delay := profilePollMinDelay
+	for {
+		done, err := p.fetch(ctx, p.auth)
+		if err != nil && !isTemporaryAPIError(err) {
+			return Status{}, err
+		}
+		if done {
+			p.loaded = true
+			return p.Status, nil
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return Status{}, ctx.Err()
+		case <-timer.C:
+		}
+		if delay *= 2; delay > profilePollMaxDelay {
+			delay = profilePollMaxDelay
+		}
+	}
+}
+
+// load fetches the details of the profile from the API, until it is
+// finalized or the API was polled too many times.
+func (p *Profile) load(ctx context.Context, auth string) error {
 	if p.loaded {
 		return nil
 	}
@@ -61,35 +151,40 @@ func (p *Profile) load(auth string) error {
 		p.loaded = true
 		return nil
 	}
-	request, err := http.NewRequest("GET", p.APIURL, nil)
+	done, err := p.fetch(ctx, auth)
 	if err != nil {
 		return err
 	}
+	p.loaded = done
+	return nil
+}
+
+// fetch updates the profile from the API, and tells whether it is finalized.
+func (p *Profile) fetch(ctx context.Context, auth string) (done bool, err error) {
+	request, err := http.NewRequest("GET", p.APIURL, nil)
+	if err != nil {
+		return false, err
+	}
 	request.Header.Add("Authorization", auth)
 	client := http.DefaultClient
-	response, err := client.Do(request)
+	response, err := client.Do(request.WithContext(ctx))
 	if err != nil {
-		return err
+		return false, err
 	}
+	defer response.Body.Close()
 	if response.StatusCode == 404 {
 		p.Status = Status{Name: "queued"}
-		return nil
+		return false, nil
 	}
 	if response.StatusCode >= 400 {
-		p.Status = Status{Name: "errored"}
-		p.loaded = true
-		return nil
+		return false, &apiError{status: response.Status, code: response.StatusCode}
 	}
 	responseData, err := ioutil.ReadAll(response.Body)
 	if err != nil {
-		return err
+		return false, err
 	}
-	if err := json.Unmarshal(responseData, &p); err != nil {
-		return fmt.Errorf("JSON error: %v", err)
+	if err := json.Unmarshal(responseData, p); err != nil {
+		return false, fmt.Errorf("JSON error: %v", err)
 	}
-
-	if p.Status.Code > 0 {
-		p.loaded = true
-	}
-	return nil
+	return p.Status.Code > 0, nil
 }
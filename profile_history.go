@@ -0,0 +1,131 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sync"
+)
+
+// profileHistory keeps the most recent profiles in a ring buffer, and
+// optionally persists them to a file so that they survive a restart. It is
+// safe for concurrent use: the profiles are copied in and out, so that they
+// are never shared.
+type profileHistory struct {
+	mutex   sync.Mutex
+	entries []*Profile
+	// next is the index where the next profile is stored.
+	next  int
+	count int
+	path  string
+}
+
+// profileHistoryEntry is the persisted form of a profile: its details are
+// fetched again from the API.
+type profileHistoryEntry struct {
+	UUID   string `json:"uuid"`
+	URL    string `json:"url"`
+	APIURL string `json:"api_url"`
+}
+
+func newProfileHistory(size int, path string) *profileHistory {
+	return &profileHistory{
+		entries: make([]*Profile, size),
+		path:    path,
+	}
+}
+
+// add records a profile, dropping the oldest one if the history is full.
+func (h *profileHistory) add(profile *Profile) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.addLocked(profile)
+}
+
+func (h *profileHistory) addLocked(profile *Profile) {
+	copied := *profile
+	h.entries[h.next] = &copied
+	h.next = (h.next + 1) % len(h.entries)
+	if h.count < len(h.entries) {
+		h.count++
+	}
+}
+
+// list returns copies of the profiles, the most recent first.
+func (h *profileHistory) list() []*Profile {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	profiles := make([]*Profile, 0, h.count)
+	for i := 1; i <= h.count; i++ {
+		copied := *h.entries[(h.next-i+len(h.entries))%len(h.entries)]
+		profiles = append(profiles, &copied)
+	}
+	return profiles
+}
+
+// update replaces the profile with the same UUID, if it is still in the
+// history, typically once its details were fetched.
+func (h *profileHistory) update(profile *Profile) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for i, entry := range h.entries {
+		if entry != nil && entry.UUID == profile.UUID {
+			copied := *profile
+			h.entries[i] = &copied
+			return
+		}
+	}
+}
+
+// save writes the history to its file, if any.
+func (h *profileHistory) save() error {
+	if h.path == "" {
+		return nil
+	}
+	profiles := h.list()
+	entries := make([]profileHistoryEntry, len(profiles))
+	for i, profile := range profiles {
+		entries[i] = profileHistoryEntry{
+			UUID:   profile.UUID,
+			URL:    profile.URL,
+			APIURL: profile.APIURL,
+		}
+	}
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(h.path, data, 0600)
+}
+
+// load reads the history from its file. A missing file is an empty history.
+func (h *profileHistory) load() error {
+	if h.path == "" {
+		return nil
+	}
+	data, err := ioutil.ReadFile(h.path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	var entries []profileHistoryEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return err
+	}
+	// The file lists the most recent profiles first.
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	for i := len(entries) - 1; i >= 0; i-- {
+		h.addLocked(&Profile{
+			UUID:   entries[i].UUID,
+			URL:    entries[i].URL,
+			APIURL: entries[i].APIURL,
+		})
+	}
+	return nil
+}
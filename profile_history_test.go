@@ -0,0 +1,34 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"os"
+	"path/filepath"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProfileHistory(c *C) {
+	path := filepath.Join(os.TempDir(), "blackfire-history-test.json")
+	os.Remove(path)
+	defer os.Remove(path)
+
+	history := newProfileHistory(2, path)
+	c.Assert(history.load(), IsNil)
+	c.Assert(history.list(), HasLen, 0)
+	for _, uuid := range []string{"1", "2", "3"} {
+		history.add(&Profile{UUID: uuid, APIURL: "/profiles/" + uuid})
+	}
+	c.Assert(history.list(), DeepEquals, []*Profile{{UUID: "3", APIURL: "/profiles/3"}, {UUID: "2", APIURL: "/profiles/2"}})
+	c.Assert(history.save(), IsNil)
+
+	// The details of the persisted profiles are fetched again.
+	history = newProfileHistory(5, path)
+	c.Assert(history.load(), IsNil)
+	c.Assert(history.list(), DeepEquals, []*Profile{{UUID: "3", APIURL: "/profiles/3"}, {UUID: "2", APIURL: "/profiles/2"}})
+	history.add(&Profile{UUID: "4"})
+	c.Assert(history.list()[0].UUID, Equals, "4")
+	c.Assert(history.list(), HasLen, 3)
+}
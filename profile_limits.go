@@ -0,0 +1,88 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"time"
+)
+
+// The triggers of the probe having their own rate limit (see
+// Configuration.MaxProfilesPerHour).
+const (
+	triggerSignal = "signal"
+	triggerHTTP   = "http"
+	triggerAuto   = "auto"
+)
+
+// profileLimiter keeps the start times of the profiles started in the last
+// hour, the oldest first.
+type profileLimiter struct {
+	starts []time.Time
+}
+
+// retryAfter returns how long to wait until a profile can be started under
+// the limit, or zero if one can be started now.
+func (l *profileLimiter) retryAfter(now time.Time, limit int) time.Duration {
+	for len(l.starts) > 0 && now.Sub(l.starts[0]) >= time.Hour {
+		l.starts = l.starts[1:]
+	}
+	if len(l.starts) < limit {
+		return 0
+	}
+	return l.starts[len(l.starts)-limit].Add(time.Hour).Sub(now)
+}
+
+// profileLimits returns the limits applying to the profiles started by the
+// trigger, by trigger name, the overall limit being named after the empty
+// trigger.
+func (p *Probe) profileLimits(trigger string) map[string]int {
+	limits := make(map[string]int)
+	if limit := p.configuration.MaxProfilesPerHour; limit > 0 {
+		limits[""] = limit
+	}
+	limit := 0
+	switch trigger {
+	case triggerSignal:
+		limit = p.configuration.MaxSignalProfilesPerHour
+	case triggerHTTP:
+		limit = p.configuration.MaxHTTPProfilesPerHour
+	case triggerAuto:
+		limit = p.configuration.MaxAutoProfilesPerHour
+	}
+	if limit > 0 {
+		limits[trigger] = limit
+	}
+	return limits
+}
+
+// checkProfileLimits returns a RateLimitError if a profile started by the
+// trigger would go over a limit, and records the profile start otherwise.
+func (p *Probe) checkProfileLimits(trigger string) error {
+	limits := p.profileLimits(trigger)
+	if len(limits) == 0 {
+		return nil
+	}
+	if p.profileLimiters == nil {
+		p.profileLimiters = make(map[string]*profileLimiter)
+	}
+	now := p.clock.Now()
+	for name, limit := range limits {
+		limiter := p.profileLimiters[name]
+		if limiter == nil {
+			limiter = &profileLimiter{}
+			p.profileLimiters[name] = limiter
+		}
+		if retryAfter := limiter.retryAfter(now, limit); retryAfter > 0 {
+			p.statsMutex.Lock()
+			p.stats.ProfilesRateLimited++
+			p.statsMutex.Unlock()
+			return &RateLimitError{Trigger: name, RetryAfter: retryAfter}
+		}
+	}
+	for name := range limits {
+		limiter := p.profileLimiters[name]
+		limiter.starts = append(limiter.starts, now)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"net/http/httptest"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProbeProfileLimits(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+	p.configuration.MaxProfilesPerHour = 3
+	p.configuration.MaxHTTPProfilesPerHour = 1
+
+	c.Assert(p.EnableNow(), IsNil)
+	c.Assert(p.End(), IsNil)
+	c.Assert(p.enableNowFor(0, ProfileOptions{}, triggerHTTP, nil), IsNil)
+	c.Assert(p.End(), IsNil)
+
+	// The HTTP trigger reached its own limit.
+	clock.Advance(time.Minute)
+	err := p.enableNowFor(0, ProfileOptions{}, triggerHTTP, nil)
+	c.Assert(err, DeepEquals, &RateLimitError{Trigger: triggerHTTP, RetryAfter: 59 * time.Minute})
+	recorder := httptest.NewRecorder()
+	p.EnableHandler(recorder, httptest.NewRequest("POST", "/enable", nil))
+	c.Assert(recorder.Code, Equals, 429)
+	c.Assert(recorder.Header().Get("Retry-After"), Equals, "3540")
+
+	// The refused profiles don't count.
+	c.Assert(p.enableNowFor(0, ProfileOptions{}, triggerSignal, nil), IsNil)
+	c.Assert(p.End(), IsNil)
+	err = p.EnableNow()
+	c.Assert(err, DeepEquals, &RateLimitError{RetryAfter: 59 * time.Minute})
+	c.Assert(err.(*RateLimitError).Unwrap(), Equals, ErrRateLimited)
+	c.Assert(p.Stats().ProfilesRateLimited, Equals, 3)
+
+	clock.Advance(59 * time.Minute)
+	c.Assert(p.enableNowFor(0, ProfileOptions{}, triggerHTTP, nil), IsNil)
+	c.Assert(p.End(), IsNil)
+}
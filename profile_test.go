@@ -0,0 +1,123 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProfileWait(c *C) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		c.Check(r.Header.Get("Authorization"), Equals, "Basic abc")
+		switch {
+		case r.URL.Path == "/forbidden":
+			w.WriteHeader(http.StatusForbidden)
+		case r.URL.Path == "/queued" || requests == 1:
+			w.WriteHeader(http.StatusNotFound)
+		case requests == 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case requests == 3:
+			fmt.Fprint(w, `{"label":"title","status":{"name":"pending","code":0}}`)
+		default:
+			fmt.Fprint(w, `{"label":"title","status":{"name":"finished","code":64},"envelope":{"ct":1,"cpu":2,"mu":3,"pmu":4}}`)
+		}
+	}))
+	defer server.Close()
+
+	profile := &Profile{APIURL: server.URL + "/profile", auth: "Basic abc"}
+	status, err := profile.Wait(context.Background())
+	c.Assert(err, IsNil)
+	c.Assert(status, Equals, Status{Name: "finished", Code: 64})
+	c.Assert(profile.Envelope, Equals, Envelope{Ct: 1, CPU: 2, MU: 3, PMU: 4})
+	c.Assert(requests, Equals, 4)
+
+	profile = &Profile{APIURL: server.URL + "/forbidden", auth: "Basic abc"}
+	_, err = profile.Wait(context.Background())
+	c.Assert(err, ErrorMatches, "The Blackfire API returned 403 Forbidden")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	profile = &Profile{APIURL: server.URL + "/queued", auth: "Basic abc"}
+	_, err = profile.Wait(ctx)
+	c.Assert(err, Equals, context.DeadlineExceeded)
+
+	_, err = (&Profile{}).Wait(context.Background())
+	c.Assert(err, ErrorMatches, "The API URL of the profile is unknown")
+}
+
+func (s *BlackfireSuite) TestGetProfile(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/profiles/1234" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		c.Check(r.Header.Get("Authorization"), Equals, "Basic aWQ6dG9rZW4=")
+		fmt.Fprint(w, `{"label":"title","status":{"name":"finished","code":64},"_links":{"graph_url":{"href":"https://blackfire.io/profiles/1234/graph"}}}`)
+	}))
+	defer server.Close()
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	endpoint, err := url.Parse(server.URL)
+	c.Assert(err, IsNil)
+	p := NewProbe(&Configuration{
+		ClientID:     "id",
+		ClientToken:  "token",
+		HTTPEndpoint: endpoint,
+	})
+	profile, err := p.GetProfile(context.Background(), "1234")
+	c.Assert(err, IsNil)
+	c.Assert(profile.Title, Equals, "title")
+	c.Assert(profile.Status, Equals, Status{Name: "finished", Code: 64})
+	c.Assert(profile.URL, Equals, "https://blackfire.io/profiles/1234/graph")
+
+	profile, err = p.GetProfile(context.Background(), "5678")
+	c.Assert(err, IsNil)
+	c.Assert(profile.Status.Name, Equals, "queued")
+
+	_, err = p.GetProfile(context.Background(), "../1234")
+	c.Assert(err, ErrorMatches, `Invalid profile UUID "../1234"`)
+
+	_, err = NewProbe(&Configuration{BlackfireQuery: "signature=abc", HTTPEndpoint: endpoint}).GetProfile(context.Background(), "1234")
+	c.Assert(err, ErrorMatches, "The client ID and token are needed to fetch a profile")
+}
+
+func (s *BlackfireSuite) TestAssertProfile(c *C) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/profiles/1234":
+			fmt.Fprint(w, `{"status":{"name":"finished","code":64},"envelope":{"ct":10,"cpu":2000,"mu":1024,"pmu":4096}}`)
+		case "/api/v1/profiles/5678":
+			fmt.Fprint(w, `{"status":{"name":"failure","code":32,"failure_reason":"Invalid profile"}}`)
+		default:
+			w.WriteHeader(http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	endpoint, err := url.Parse(server.URL)
+	c.Assert(err, IsNil)
+	p := NewProbe(&Configuration{
+		ClientID:     "id",
+		ClientToken:  "token",
+		HTTPEndpoint: endpoint,
+	})
+	ctx := context.Background()
+	c.Assert(p.AssertProfile(ctx, "1234", EnvelopeBudget{CPU: 2 * time.Millisecond, PeakMemory: 4096}.Check), IsNil)
+	c.Assert(p.AssertProfile(ctx, "1234", EnvelopeBudget{CPU: time.Millisecond, Memory: 1000}.Check), ErrorMatches,
+		"The profile is over budget: CPU 2ms > 1ms, memory 1024 > 1000 bytes")
+	c.Assert(p.AssertProfile(ctx, "5678", EnvelopeBudget{}.Check), ErrorMatches, "The profile 5678 is failure: Invalid profile")
+	c.Assert(p.AssertProfile(ctx, "0000", EnvelopeBudget{}.Check), ErrorMatches, "The Blackfire API returned 403 Forbidden")
+}
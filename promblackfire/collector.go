@@ -0,0 +1,103 @@
+// Package promblackfire exports the internal metrics of Blackfire probes to
+// Prometheus. It lives in its own module so that go-blackfire itself does not
+// depend on the Prometheus client.
+package promblackfire
+
+import (
+	"github.com/blackfireio/go-blackfire"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+const namespace = "blackfire_probe"
+
+// source is the part of a probe the collector reads its metrics from.
+type source interface {
+	Stats() blackfire.ProbeStats
+	State() string
+}
+
+// globalProbe reads the metrics of the global probe.
+type globalProbe struct{}
+
+func (globalProbe) Stats() blackfire.ProbeStats {
+	return blackfire.Stats()
+}
+
+func (globalProbe) State() string {
+	return blackfire.State()
+}
+
+type collector struct {
+	source source
+
+	profilesStarted  *prometheus.Desc
+	profilesEnded    *prometheus.Desc
+	uploads          *prometheus.Desc
+	uploadDuration   *prometheus.Desc
+	payloadBytes     *prometheus.Desc
+	droppedSamples   *prometheus.Desc
+	heapSnapshotTime *prometheus.Desc
+	conversionTime   *prometheus.Desc
+	state            *prometheus.Desc
+}
+
+// NewCollector returns a collector exporting the metrics of the specified
+// probe, or of the global probe if nil. Use constLabels to tell apart the
+// collectors of several probes registered in the same registry.
+func NewCollector(probe *blackfire.Probe, constLabels prometheus.Labels) prometheus.Collector {
+	var s source = globalProbe{}
+	if probe != nil {
+		s = probe
+	}
+	desc := func(name, help string, variableLabels ...string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "", name), help, variableLabels, constLabels)
+	}
+	return &collector{
+		source:           s,
+		profilesStarted:  desc("profiles_started_total", "Number of profiles started."),
+		profilesEnded:    desc("profiles_ended_total", "Number of profiles ended."),
+		uploads:          desc("uploads_total", "Number of profile uploads, by result.", "result"),
+		uploadDuration:   desc("upload_duration_seconds", "Time spent encoding and uploading profiles."),
+		payloadBytes:     desc("payload_bytes_total", "Size of the uploaded profiles."),
+		droppedSamples:   desc("dropped_samples_total", "Number of samples left out of the profiles."),
+		heapSnapshotTime: desc("heap_snapshot_seconds_total", "Time spent writing heap snapshots."),
+		conversionTime:   desc("conversion_seconds_total", "Time spent converting pprof profiles."),
+		state:            desc("state", "Current state of the probe (1 for the current state, 0 otherwise).", "state"),
+	}
+}
+
+func (c *collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.profilesStarted
+	ch <- c.profilesEnded
+	ch <- c.uploads
+	ch <- c.uploadDuration
+	ch <- c.payloadBytes
+	ch <- c.droppedSamples
+	ch <- c.heapSnapshotTime
+	ch <- c.conversionTime
+	ch <- c.state
+}
+
+func (c *collector) Collect(ch chan<- prometheus.Metric) {
+	stats := c.source.Stats()
+	uploads := uint64(stats.UploadsSucceeded + stats.UploadsFailed)
+
+	ch <- prometheus.MustNewConstMetric(c.profilesStarted, prometheus.CounterValue, float64(stats.ProfilesStarted))
+	ch <- prometheus.MustNewConstMetric(c.profilesEnded, prometheus.CounterValue, float64(stats.Profiles))
+	ch <- prometheus.MustNewConstMetric(c.uploads, prometheus.CounterValue, float64(stats.UploadsSucceeded), "success")
+	ch <- prometheus.MustNewConstMetric(c.uploads, prometheus.CounterValue, float64(stats.UploadsFailed), "failure")
+	ch <- prometheus.MustNewConstSummary(c.uploadDuration, uploads, stats.UploadTime.Seconds(), nil)
+	ch <- prometheus.MustNewConstMetric(c.payloadBytes, prometheus.CounterValue, float64(stats.PayloadBytes))
+	ch <- prometheus.MustNewConstMetric(c.droppedSamples, prometheus.CounterValue, float64(stats.DroppedSamples))
+	ch <- prometheus.MustNewConstMetric(c.heapSnapshotTime, prometheus.CounterValue, stats.HeapSnapshotTime.Seconds())
+	ch <- prometheus.MustNewConstMetric(c.conversionTime, prometheus.CounterValue, stats.ConversionTime.Seconds())
+
+	current := c.source.State()
+	for _, state := range blackfire.ProfilerStates() {
+		value := 0.0
+		if state == current {
+			value = 1
+		}
+		ch <- prometheus.MustNewConstMetric(c.state, prometheus.GaugeValue, value, state)
+	}
+}
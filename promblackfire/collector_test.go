@@ -0,0 +1,72 @@
+package promblackfire
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blackfireio/go-blackfire"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+type fakeSource struct {
+	stats blackfire.ProbeStats
+	state string
+}
+
+func (s *fakeSource) Stats() blackfire.ProbeStats {
+	return s.stats
+}
+
+func (s *fakeSource) State() string {
+	return s.state
+}
+
+func TestCollector(t *testing.T) {
+	c := NewCollector(nil, prometheus.Labels{"probe": "test"}).(*collector)
+	c.source = &fakeSource{
+		stats: blackfire.ProbeStats{
+			ProfilesStarted:  3,
+			Profiles:         2,
+			UploadsSucceeded: 1,
+			UploadsFailed:    1,
+			UploadTime:       3 * time.Second,
+			PayloadBytes:     1024,
+		},
+		state: "enabled",
+	}
+
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(c); err != nil {
+		t.Fatal(err)
+	}
+	families, err := registry.Gather()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	metrics := make(map[string][]*dto.Metric)
+	for _, family := range families {
+		metrics[family.GetName()] = family.GetMetric()
+	}
+
+	if value := metrics["blackfire_probe_profiles_started_total"][0].GetCounter().GetValue(); value != 3 {
+		t.Errorf("expected 3 profiles started, got %v", value)
+	}
+	if value := metrics["blackfire_probe_payload_bytes_total"][0].GetCounter().GetValue(); value != 1024 {
+		t.Errorf("expected 1024 payload bytes, got %v", value)
+	}
+	summary := metrics["blackfire_probe_upload_duration_seconds"][0].GetSummary()
+	if summary.GetSampleCount() != 2 || summary.GetSampleSum() != 3 {
+		t.Errorf("expected 2 uploads in 3s, got %d in %vs", summary.GetSampleCount(), summary.GetSampleSum())
+	}
+	for _, metric := range metrics["blackfire_probe_state"] {
+		expected := 0.0
+		if metric.GetLabel()[1].GetValue() == "enabled" {
+			expected = 1
+		}
+		if value := metric.GetGauge().GetValue(); value != expected {
+			t.Errorf("expected state %v to be %v, got %v", metric.GetLabel()[1].GetValue(), expected, value)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+module github.com/blackfireio/go-blackfire/promblackfire
+
+go 1.20
+
+require (
+	github.com/blackfireio/go-blackfire v0.0.0-20261016115257-6179df72977e
+	github.com/prometheus/client_golang v1.20.5
+	github.com/prometheus/client_model v0.6.1
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/blackfireio/osinfo v1.0.2 // indirect
+	github.com/cespare/xxhash/v2 v2.3.0 // indirect
+	github.com/go-ini/ini v1.51.1 // indirect
+	github.com/munnerz/goautoneg v0.0.0-20191010083416-a7dc8b61c822 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/prometheus/common v0.55.0 // indirect
+	github.com/prometheus/procfs v0.15.1 // indirect
+	github.com/rakyll/statik v0.1.7 // indirect
+	github.com/rs/zerolog v1.17.2 // indirect
+	golang.org/x/sys v0.22.0 // indirect
+	google.golang.org/protobuf v1.34.2 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Builds against the go-blackfire sources of this repository. The replace
+// directive is ignored by the consumers of the module, who get the go-blackfire
+// version required above: the commit providing the API this module uses,
+// until a go-blackfire release does.
+replace github.com/blackfireio/go-blackfire => ../
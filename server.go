@@ -0,0 +1,133 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Server serves the HTTP endpoints of a probe (see NewServeMuxFor) on a
+// dedicated listener, for applications which don't run an HTTP server of
+// their own. No server is started when the probe is disabled by
+// configuration: a nil Server is returned, whose methods are no-ops.
+type Server struct {
+	server   *http.Server
+	listener net.Listener
+}
+
+// StartServer serves the HTTP endpoints of the global probe on the specified
+// address (see Probe.StartServer).
+func StartServer(addr string, opts ...MuxOption) (*Server, error) {
+	return globalProbe.StartServer(addr, opts...)
+}
+
+// StartServerTLS serves the HTTP endpoints of the global probe over HTTPS
+// (see Probe.StartServerTLS).
+func StartServerTLS(addr, certFile, keyFile string, opts ...MuxOption) (*Server, error) {
+	return globalProbe.StartServerTLS(addr, certFile, keyFile, opts...)
+}
+
+// StartServer serves the HTTP endpoints of this probe on the specified
+// address, in the background. The address is either a TCP address
+// (host:port), or a URL like the agent socket (tcp://host:port or
+// unix:///path/to/socket). The endpoints are served at the root unless the
+// MuxPrefix option is set.
+func (p *Probe) StartServer(addr string, opts ...MuxOption) (*Server, error) {
+	return p.startServer(addr, "", "", opts)
+}
+
+// StartServerTLS serves the HTTP endpoints of this probe over HTTPS like
+// StartServer, using the specified certificate and key files.
+func (p *Probe) StartServerTLS(addr, certFile, keyFile string, opts ...MuxOption) (*Server, error) {
+	return p.startServer(addr, certFile, keyFile, opts)
+}
+
+func (p *Probe) startServer(addr, certFile, keyFile string, opts []MuxOption) (*Server, error) {
+	mux, err := NewServeMuxFor(p, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if p.configuration.Disabled {
+		return nil, nil
+	}
+	server := &http.Server{Handler: mux}
+	if certFile != "" {
+		// Load the certificate right away to report errors to the caller.
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		server.TLSConfig = &tls.Config{Certificates: []tls.Certificate{cert}}
+	}
+	listener, err := listen(addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Server{
+		server:   server,
+		listener: listener,
+	}
+	logger := p.configuration.Logger
+	logger.Info().Msgf("Blackfire (HTTP): Serving the profiling endpoints on %s", listener.Addr())
+	go func() {
+		var err error
+		if server.TLSConfig != nil {
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+		if err != nil && err != http.ErrServerClosed {
+			logger.Error().Msgf("Blackfire (HTTP): %v", err)
+		}
+	}()
+	return s, nil
+}
+
+func listen(addr string) (net.Listener, error) {
+	network, address := "tcp", addr
+	if strings.Contains(addr, "://") {
+		var err error
+		if network, address, err = parseNetworkAddressString(addr); err != nil {
+			return nil, err
+		}
+	}
+	if network == "unix" {
+		// Remove the socket left over by a previous run.
+		if info, err := os.Stat(address); err == nil && info.Mode()&os.ModeSocket != 0 {
+			os.Remove(address)
+		}
+	}
+	return net.Listen(network, address)
+}
+
+// Addr returns the address the server listens on.
+func (s *Server) Addr() net.Addr {
+	if s == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Close stops the server right away.
+func (s *Server) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.server.Close()
+}
+
+// Shutdown stops the server gracefully, waiting for the requests in progress
+// until the context is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return s.server.Shutdown(ctx)
+}
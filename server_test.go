@@ -0,0 +1,109 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestStartServer(c *C) {
+	p := newTestProbe(newFakeClock())
+	server, err := p.StartServer("127.0.0.1:0", MuxPrefix("_blackfire"))
+	c.Assert(err, IsNil)
+
+	response, err := http.Get("http://" + server.Addr().String() + "/_blackfire/dashboard_api")
+	c.Assert(err, IsNil)
+	response.Body.Close()
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+
+	c.Assert(server.Close(), IsNil)
+	_, err = http.Get("http://" + server.Addr().String() + "/_blackfire/dashboard_api")
+	c.Assert(err, NotNil)
+}
+
+func (s *BlackfireSuite) TestStartServerUnixSocket(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-server-test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	socket := filepath.Join(dir, "blackfire.sock")
+
+	p := newTestProbe(newFakeClock())
+	server, err := p.StartServer("unix://" + socket)
+	c.Assert(err, IsNil)
+
+	client := &http.Client{Transport: &http.Transport{
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			var dialer net.Dialer
+			return dialer.DialContext(ctx, "unix", socket)
+		},
+	}}
+	response, err := client.Get("http://blackfire/status")
+	c.Assert(err, IsNil)
+	response.Body.Close()
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+
+	c.Assert(server.Shutdown(context.Background()), IsNil)
+}
+
+func (s *BlackfireSuite) TestStartServerTLS(c *C) {
+	dir, err := ioutil.TempDir("", "blackfire-server-test")
+	c.Assert(err, IsNil)
+	defer os.RemoveAll(dir)
+	certFile, keyFile := writeTestCertificate(c, dir)
+
+	p := newTestProbe(newFakeClock())
+	_, err = p.StartServerTLS("127.0.0.1:0", certFile, filepath.Join(dir, "missing.pem"))
+	c.Assert(err, NotNil)
+
+	server, err := p.StartServerTLS("127.0.0.1:0", certFile, keyFile)
+	c.Assert(err, IsNil)
+	defer server.Close()
+
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+	}}
+	response, err := client.Get("https://" + server.Addr().String() + "/dashboard_api")
+	c.Assert(err, IsNil)
+	response.Body.Close()
+	c.Assert(response.StatusCode, Equals, http.StatusOK)
+}
+
+// writeTestCertificate writes a self-signed certificate and its key to dir.
+func writeTestCertificate(c *C, dir string) (certFile, keyFile string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c.Assert(err, IsNil)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	c.Assert(err, IsNil)
+	keyBytes, err := x509.MarshalECPrivateKey(key)
+	c.Assert(err, IsNil)
+
+	certFile = filepath.Join(dir, "cert.pem")
+	keyFile = filepath.Join(dir, "key.pem")
+	c.Assert(ioutil.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: cert}), 0600), IsNil)
+	c.Assert(ioutil.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyBytes}), 0600), IsNil)
+	return certFile, keyFile
+}
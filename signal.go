@@ -1,27 +1,52 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
 package blackfire
 
 import (
 	"os"
 	"os/signal"
+	"sync"
 	"time"
 )
 
 // EnableOnSignal sets up a trigger to enable profiling when the specified signal is received.
 // The profiler will profile for the specified duration.
-func EnableOnSignal(sig os.Signal, duration time.Duration) (err error) {
-	if err = globalProbe.configuration.load(); err != nil {
+func EnableOnSignal(sig os.Signal, duration time.Duration) error {
+	return globalProbe.EnableOnSignal(sig, duration)
+}
+
+// EnableOnSignalHandler is EnableOnSignal, returning the trigger to stop it.
+func EnableOnSignalHandler(sig os.Signal, duration time.Duration) (*SignalHandler, error) {
+	return globalProbe.EnableOnSignalHandler(sig, duration)
+}
+
+// EnableOnSignal sets up a trigger to enable profiling of this probe when the
+// specified signal is received. A zero duration stands for
+// Configuration.DefaultProfileDuration.
+func (p *Probe) EnableOnSignal(sig os.Signal, duration time.Duration) error {
+	_, err := p.EnableOnSignalHandler(sig, duration)
+	return err
+}
+
+// EnableOnSignalHandler is EnableOnSignal, returning the trigger to stop it.
+func (p *Probe) EnableOnSignalHandler(sig os.Signal, duration time.Duration) (handler *SignalHandler, err error) {
+	if err = p.configuration.load(); err != nil {
 		return
 	}
-	if !globalProbe.configuration.canProfile() {
+	if !p.configuration.canProfile() {
 		return
 	}
+	if duration <= 0 {
+		duration = p.configuration.DefaultProfileDuration
+	}
 
-	logger := globalProbe.configuration.Logger
+	logger := p.configuration.Logger
 	logger.Info().Msgf("Blackfire (signal): Signal [%s] triggers profiling for %.0f seconds", sig, float64(duration)/1000000000)
 
-	callFuncOnSignal(sig, func() {
+	handler = p.callFuncOnSignal(sig, func() {
 		logger.Info().Msgf("Blackfire (%s): Profiling for %.0f seconds", sig, float64(duration)/1000000000)
-		if err := globalProbe.EnableNowFor(duration); err != nil {
+		if err := p.enableNowFor(duration, ProfileOptions{}, triggerSignal, nil); err != nil {
 			logger.Error().Msgf("Blackfire (EnableOnSignal): %v", err)
 		}
 	})
@@ -30,20 +55,37 @@ func EnableOnSignal(sig os.Signal, duration time.Duration) (err error) {
 }
 
 // DisableOnSignal sets up a trigger to disable profiling when the specified signal is received.
-func DisableOnSignal(sig os.Signal) (err error) {
-	if err = globalProbe.configuration.load(); err != nil {
+func DisableOnSignal(sig os.Signal) error {
+	return globalProbe.DisableOnSignal(sig)
+}
+
+// DisableOnSignalHandler is DisableOnSignal, returning the trigger to stop it.
+func DisableOnSignalHandler(sig os.Signal) (*SignalHandler, error) {
+	return globalProbe.DisableOnSignalHandler(sig)
+}
+
+// DisableOnSignal sets up a trigger to disable profiling of this probe when
+// the specified signal is received.
+func (p *Probe) DisableOnSignal(sig os.Signal) error {
+	_, err := p.DisableOnSignalHandler(sig)
+	return err
+}
+
+// DisableOnSignalHandler is DisableOnSignal, returning the trigger to stop it.
+func (p *Probe) DisableOnSignalHandler(sig os.Signal) (handler *SignalHandler, err error) {
+	if err = p.configuration.load(); err != nil {
 		return
 	}
-	if !globalProbe.configuration.canProfile() {
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	logger := globalProbe.configuration.Logger
+	logger := p.configuration.Logger
 	logger.Info().Msgf("Blackfire (signal): Signal [%s] stops profiling", sig)
 
-	callFuncOnSignal(sig, func() {
+	handler = p.callFuncOnSignal(sig, func() {
 		logger.Info().Msgf("Blackfire (%s): Disable profiling", sig)
-		if err := globalProbe.Disable(); err != nil {
+		if err := p.Disable(); err != nil {
 			logger.Error().Msgf("Blackfire (DisableOnSignal): %v", err)
 		}
 	})
@@ -52,33 +94,180 @@ func DisableOnSignal(sig os.Signal) (err error) {
 
 // EndOnSignal sets up a trigger to end the current profile and upload to Blackfire when the
 // specified signal is received.
-func EndOnSignal(sig os.Signal) (err error) {
-	if err = globalProbe.configuration.load(); err != nil {
+func EndOnSignal(sig os.Signal) error {
+	return globalProbe.EndOnSignal(sig)
+}
+
+// EndOnSignalHandler is EndOnSignal, returning the trigger to stop it.
+func EndOnSignalHandler(sig os.Signal) (*SignalHandler, error) {
+	return globalProbe.EndOnSignalHandler(sig)
+}
+
+// EndOnSignal sets up a trigger to end the current profile of this probe when
+// the specified signal is received.
+func (p *Probe) EndOnSignal(sig os.Signal) error {
+	_, err := p.EndOnSignalHandler(sig)
+	return err
+}
+
+// EndOnSignalHandler is EndOnSignal, returning the trigger to stop it.
+func (p *Probe) EndOnSignalHandler(sig os.Signal) (handler *SignalHandler, err error) {
+	if err = p.configuration.load(); err != nil {
 		return
 	}
-	if !globalProbe.configuration.canProfile() {
+	if !p.configuration.canProfile() {
 		return
 	}
 
-	logger := globalProbe.configuration.Logger
+	logger := p.configuration.Logger
 	logger.Info().Msgf("Blackfire (signal): Signal [%s] ends the current profile", sig)
 
-	callFuncOnSignal(sig, func() {
+	handler = p.callFuncOnSignal(sig, func() {
 		logger.Info().Msgf("Blackfire (%s): End profile", sig)
-		if err := globalProbe.EndNoWait(); err != nil {
+		if err := p.EndNoWait(); err != nil {
 			logger.Error().Msgf("Blackfire (EndOnSignal): %v", err)
 		}
 	})
 	return
 }
 
-func callFuncOnSignal(sig os.Signal, function func()) {
-	sigs := make(chan os.Signal, 1)
-	signal.Notify(sigs, sig)
+// ToggleOnSignal sets up a trigger to start profiling when the specified
+// signal is received, and to end the profile and upload it to Blackfire when
+// it is received again.
+func ToggleOnSignal(sig os.Signal, maxDuration time.Duration) error {
+	return globalProbe.ToggleOnSignal(sig, maxDuration)
+}
+
+// ToggleOnSignalHandler is ToggleOnSignal, returning the trigger to stop it.
+func ToggleOnSignalHandler(sig os.Signal, maxDuration time.Duration) (*SignalHandler, error) {
+	return globalProbe.ToggleOnSignalHandler(sig, maxDuration)
+}
+
+// ToggleOnSignal sets up a trigger to start profiling this probe for at most
+// maxDuration when the specified signal is received, and to end the profile
+// when it is received again.
+func (p *Probe) ToggleOnSignal(sig os.Signal, maxDuration time.Duration) error {
+	_, err := p.ToggleOnSignalHandler(sig, maxDuration)
+	return err
+}
+
+// ToggleOnSignalHandler is ToggleOnSignal, returning the trigger to stop it.
+func (p *Probe) ToggleOnSignalHandler(sig os.Signal, maxDuration time.Duration) (handler *SignalHandler, err error) {
+	if err = p.configuration.load(); err != nil {
+		return
+	}
+	if !p.configuration.canProfile() {
+		return
+	}
+
+	logger := p.configuration.Logger
+	logger.Info().Msgf("Blackfire (signal): Signal [%s] toggles profiling for up to %.0f seconds", sig, float64(maxDuration)/1000000000)
+
+	handler = p.callFuncOnSignal(sig, func() {
+		p.toggle(sig, maxDuration)
+	})
+	return
+}
+
+// toggle ends the current profile if there is one, or starts a new one.
+func (p *Probe) toggle(sig os.Signal, maxDuration time.Duration) {
+	logger := p.configuration.Logger
+	switch p.getState() {
+	case profilerStateEnabled, profilerStateDisabled:
+		logger.Info().Msgf("Blackfire (%s): End profile", sig)
+		if err := p.EndNoWait(); err != nil {
+			logger.Error().Msgf("Blackfire (ToggleOnSignal): %v", err)
+		}
+	default:
+		logger.Info().Msgf("Blackfire (%s): Profiling for up to %.0f seconds", sig, float64(maxDuration)/1000000000)
+		if err := p.enableNowFor(maxDuration, ProfileOptions{}, triggerSignal, nil); err != nil {
+			logger.Error().Msgf("Blackfire (ToggleOnSignal): %v", err)
+		}
+	}
+}
+
+// SignalHandler is a signal trigger set up by EnableOnSignalHandler,
+// DisableOnSignalHandler, EndOnSignalHandler or ToggleOnSignalHandler. They
+// return a nil handler when no trigger is needed (when the probe only profiles
+// on demand); Stop is then a no-op.
+type SignalHandler struct {
+	probe *Probe
+	sig   os.Signal
+	sigs  chan os.Signal
+	done  chan struct{}
+	once  sync.Once
+}
+
+// Stop removes the signal trigger. The signal gets its default behavior back
+// if nothing else handles it.
+func (h *SignalHandler) Stop() {
+	if h == nil {
+		return
+	}
+	h.once.Do(func() {
+		signal.Stop(h.sigs)
+		close(h.done)
+		h.probe.removeSignalHandler(h)
+	})
+}
+
+// StopSignalHandlers removes all the signal triggers set up for the global
+// probe.
+func StopSignalHandlers() {
+	globalProbe.StopSignalHandlers()
+}
+
+// StopSignalHandlers removes all the signal triggers set up for this probe.
+func (p *Probe) StopSignalHandlers() {
+	p.signalMutex.Lock()
+	handlers := make([]*SignalHandler, 0, len(p.signalHandlers))
+	for _, handler := range p.signalHandlers {
+		handlers = append(handlers, handler)
+	}
+	p.signalMutex.Unlock()
+	for _, handler := range handlers {
+		handler.Stop()
+	}
+}
+
+// callFuncOnSignal calls the function whenever the signal is received. A
+// signal has a single trigger per probe: the previous one is removed.
+func (p *Probe) callFuncOnSignal(sig os.Signal, function func()) *SignalHandler {
+	handler := &SignalHandler{
+		probe: p,
+		sig:   sig,
+		sigs:  make(chan os.Signal, 1),
+		done:  make(chan struct{}),
+	}
+	p.signalMutex.Lock()
+	previous := p.signalHandlers[sig]
+	if p.signalHandlers == nil {
+		p.signalHandlers = make(map[os.Signal]*SignalHandler)
+	}
+	p.signalHandlers[sig] = handler
+	p.signalMutex.Unlock()
+	if previous != nil {
+		previous.Stop()
+	}
+
+	signal.Notify(handler.sigs, sig)
 	go func() {
 		for {
-			<-sigs
-			function()
+			select {
+			case <-handler.sigs:
+				function()
+			case <-handler.done:
+				return
+			}
 		}
 	}()
+	return handler
+}
+
+func (p *Probe) removeSignalHandler(handler *SignalHandler) {
+	p.signalMutex.Lock()
+	defer p.signalMutex.Unlock()
+	if p.signalHandlers[handler.sig] == handler {
+		delete(p.signalHandlers, handler.sig)
+	}
 }
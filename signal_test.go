@@ -0,0 +1,65 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"os"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProbeToggle(c *C) {
+	clock := newFakeClock()
+	p := newTestProbe(clock)
+
+	p.toggle(os.Interrupt, time.Minute)
+	c.Assert(p.stateForTest(), Equals, profilerStateEnabled)
+	p.toggle(os.Interrupt, time.Minute)
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+
+	// The profile still ends on the second signal after the maximum
+	// duration expired.
+	p.toggle(os.Interrupt, time.Minute)
+	clock.Advance(time.Minute)
+	c.Assert(waitForState(p, profilerStateDisabled), Equals, profilerStateDisabled)
+	p.toggle(os.Interrupt, time.Minute)
+	c.Assert(waitForState(p, profilerStateOff), Equals, profilerStateOff)
+}
+
+func (s *BlackfireSuite) TestSignalHandlerStop(c *C) {
+	p := newTestProbe(newFakeClock())
+	calls := make(chan int, 10)
+
+	first := p.callFuncOnSignal(os.Interrupt, func() { calls <- 1 })
+	first.sigs <- os.Interrupt
+	c.Assert(<-calls, Equals, 1)
+
+	// A new trigger for the same signal replaces the previous one.
+	second := p.callFuncOnSignal(os.Interrupt, func() { calls <- 2 })
+	<-first.done
+	second.sigs <- os.Interrupt
+	c.Assert(<-calls, Equals, 2)
+
+	p.StopSignalHandlers()
+	<-second.done
+	c.Assert(p.signalHandlers, HasLen, 0)
+
+	var handler *SignalHandler
+	handler.Stop()
+}
+
+func (s *BlackfireSuite) TestSignalHandlerVariants(c *C) {
+	p := newTestProbe(newFakeClock())
+	defer p.StopSignalHandlers()
+
+	c.Assert(p.EndOnSignal(os.Interrupt), IsNil)
+	c.Assert(p.signalHandlers[os.Interrupt], NotNil)
+
+	handler, err := p.EndOnSignalHandler(os.Interrupt)
+	c.Assert(err, IsNil)
+	c.Assert(p.signalHandlers[os.Interrupt], Equals, handler)
+	handler.Stop()
+	c.Assert(p.signalHandlers, HasLen, 0)
+}
@@ -0,0 +1,111 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// deliverToSinks delivers the profile to the additional sinks in the
+// background, not to hold the event loop, and records the outcome of each
+// delivery in the stats. It must be called with the probe mutex held. The
+// deliveries of successive profiles happen one at a time.
+func (p *Probe) deliverToSinks(profile *pprof_reader.Profile, title string, dimensions []string) {
+	config := p.configuration
+	if len(config.Sinks) == 0 {
+		return
+	}
+	// The options and the file names depend on the probe state, which is
+	// only available with the probe mutex held.
+	options := withDimensions(p.pendingProbeOptions(), dimensions)
+	name := fmt.Sprintf("profile-%s.bf", p.clock.Now().UTC().Format("20060102-150405.000000000"))
+
+	p.sinkDeliveries.Add(1)
+	go func() {
+		defer p.sinkDeliveries.Done()
+		p.sinksMutex.Lock()
+		defer p.sinksMutex.Unlock()
+		for i, sink := range config.Sinks {
+			var size int
+			var err error
+			switch {
+			case sink.Exporter != nil:
+				size, err = sink.Exporter.Export(profile, title)
+			case sink.OutputDir != "":
+				size, err = writeProfileToFile(config, profile, filepath.Join(sink.OutputDir, name), title, options)
+			default:
+				size, err = p.sendToSinkAgent(config, sink, profile, title, dimensions)
+			}
+			if err != nil {
+				config.Logger.Error().Err(err).Msgf("Blackfire: Unable to deliver the profile to %s", sink.name())
+			}
+			p.recordSinkDelivery(config, i, sink, size, err)
+		}
+	}()
+}
+
+// recordSinkDelivery records the outcome of a delivery to a sink in the stats.
+func (p *Probe) recordSinkDelivery(config *Configuration, i int, sink Sink, size int, err error) {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	if len(p.sinkStats) != len(config.Sinks) {
+		p.sinkStats = make([]SinkStats, len(config.Sinks))
+	}
+	stats := &p.sinkStats[i]
+	stats.Name = sink.name()
+	stats.LastError = err
+	if err != nil {
+		stats.UploadsFailed++
+	} else {
+		stats.UploadsSucceeded++
+		stats.PayloadBytes += uint64(size)
+	}
+}
+
+func (p *Probe) sendToSinkAgent(config *Configuration, sink Sink, profile *pprof_reader.Profile, title string, dimensions []string) (int, error) {
+	client, err := p.sinkAgentClient(config, sink)
+	if err != nil {
+		return 0, err
+	}
+	return client.SendProfile(context.Background(), profile, title, dimensions)
+}
+
+// sinkAgentClient returns the client uploading profiles to the agent of a
+// sink. Profiles are signed separately for each agent and credentials. It
+// must be called with the sinks mutex held.
+func (p *Probe) sinkAgentClient(config *Configuration, sink Sink) (*agentClient, error) {
+	key := sink.AgentSocket + "\x00" + sink.ClientID
+	if sink.HTTPEndpoint != nil {
+		key += "\x00" + sink.HTTPEndpoint.String()
+	}
+	if client, ok := p.sinkAgentClients[key]; ok {
+		return client, nil
+	}
+	client, err := NewAgentClient(config)
+	if err != nil {
+		return nil, err
+	}
+	if client.agentNetwork, client.agentAddress, err = parseNetworkAddressString(sink.AgentSocket); err != nil {
+		return nil, err
+	}
+	if sink.ClientID != "" {
+		client.signingAuth = basicAuthorization(sink.ClientID, sink.ClientToken)
+	}
+	if sink.HTTPEndpoint != nil {
+		client.signingEndpoint = signingEndpointOf(sink.HTTPEndpoint)
+	}
+	client.signingResponse = nil
+	// Only the profiles of the main agent are persisted.
+	client.history = newProfileHistory(config.ProfileHistorySize, "")
+	client.signingResponseIsConsumed = true
+	if p.sinkAgentClients == nil {
+		p.sinkAgentClients = make(map[string]*agentClient)
+	}
+	p.sinkAgentClients[key] = client
+	return client, nil
+}
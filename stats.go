@@ -0,0 +1,85 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"time"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// Stats returns the overhead of the global probe.
+func Stats() ProbeStats {
+	return globalProbe.Stats()
+}
+
+// State returns the name of the current state of the global probe (see
+// Probe.State).
+func State() string {
+	return globalProbe.State()
+}
+
+// StopReason tells why the global probe stopped profiling on its own (see
+// Probe.StopReason).
+func StopReason() string {
+	return globalProbe.StopReason()
+}
+
+// Stats returns the overhead of the probe.
+func (p *Probe) Stats() ProbeStats {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	return p.stats
+}
+
+// Sinks reports the deliveries of profiles to the additional sinks of the
+// global probe.
+func Sinks() []SinkStats {
+	return globalProbe.Sinks()
+}
+
+// Sinks reports the deliveries of profiles to the additional sinks, in the
+// order of Configuration.Sinks.
+func (p *Probe) Sinks() []SinkStats {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	return append([]SinkStats(nil), p.sinkStats...)
+}
+
+// GoroutineCPU reports the CPU time of the last profile of the global probe
+// by goroutine creation site (see Probe.GoroutineCPU).
+func GoroutineCPU() []pprof_reader.GoroutineCPU {
+	return globalProbe.GoroutineCPU()
+}
+
+// GoroutineCPU reports the CPU time of the last profile by goroutine creation
+// site, sorted by decreasing CPU time. It is only recorded when
+// Configuration.ReportGoroutineCPU is set.
+func (p *Probe) GoroutineCPU() []pprof_reader.GoroutineCPU {
+	p.statsMutex.Lock()
+	defer p.statsMutex.Unlock()
+	return append([]pprof_reader.GoroutineCPU(nil), p.goroutineCPU...)
+}
+
+// measure adds the time elapsed until the returned function is called to the
+// specified stat.
+func (p *Probe) measure(stat *time.Duration) func() {
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		p.statsMutex.Lock()
+		*stat += elapsed
+		p.statsMutex.Unlock()
+	}
+}
+
+func countSamplesWithoutStack(profile *pprof_reader.Profile) uint64 {
+	count := uint64(0)
+	for _, sample := range profile.Samples {
+		if len(sample.Stack) == 0 {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,93 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"net/url"
+	"runtime"
+	"strconv"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+)
+
+// threadsCreatedMetric is the cost dimension of the OS threads created while
+// profiling (see Configuration.ProfileThreadCreation).
+const threadsCreatedMetric = "threads_created"
+
+// threadCreations returns the call stacks which created the OS threads of the
+// program so far, from the threadcreate profile, with the number of threads
+// each one created.
+func threadCreations() map[callStack]int {
+	n, _ := runtime.ThreadCreateProfile(nil)
+	var records []runtime.StackRecord
+	for {
+		// Leave room for the threads created in the meantime.
+		records = make([]runtime.StackRecord, n+8)
+		var ok bool
+		if n, ok = runtime.ThreadCreateProfile(records); ok {
+			records = records[:n]
+			break
+		}
+	}
+
+	creations := make(map[callStack]int, len(records))
+	for _, record := range records {
+		var stack callStack
+		copy(stack[:], record.Stack())
+		creations[stack]++
+	}
+	return creations
+}
+
+// threadsCreated returns the number of OS threads created by the program so
+// far.
+func threadsCreated() int {
+	n, _ := runtime.ThreadCreateProfile(nil)
+	return n
+}
+
+// startThreadStats records the threads created before the profile starts, for
+// the ones created while profiling to be told apart.
+func (p *Probe) startThreadStats() {
+	p.threadsCreatedAtStart = threadsCreated()
+	p.threadCreationsAtStart = nil
+	if p.configuration.ProfileThreadCreation {
+		p.threadCreationsAtStart = threadCreations()
+	}
+}
+
+// addThreadSamples adds the OS threads created since the profile started to
+// the profile, accounted to the call stacks creating them.
+func (p *Probe) addThreadSamples(profile *pprof_reader.Profile) {
+	if !p.configuration.ProfileThreadCreation {
+		return
+	}
+	for stack, count := range threadCreations() {
+		created := count - p.threadCreationsAtStart[stack]
+		if created <= 0 {
+			continue
+		}
+		if frames := stack.frames(); len(frames) > 0 {
+			profile.AddMetricSample(frames, threadsCreatedMetric, created, uint64(created))
+		}
+	}
+	p.threadCreationsAtStart = nil
+}
+
+// threadsHeader formats the thread counts of the program for the threads
+// profile header: the OS threads alive (when the platform tells), the ones
+// created since the program started and while profiling, GOMAXPROCS and the
+// number of cgo calls.
+func (p *Probe) threadsHeader() string {
+	created := threadsCreated()
+	values := url.Values{}
+	if n, ok := osThreadCount(); ok {
+		values.Set("os", strconv.Itoa(n))
+	}
+	values.Set("created", strconv.Itoa(created))
+	values.Set("created_while_profiling", strconv.Itoa(created-p.threadsCreatedAtStart))
+	values.Set("gomaxprocs", strconv.Itoa(runtime.GOMAXPROCS(0)))
+	values.Set("cgo_calls", strconv.FormatInt(runtime.NumCgoCall(), 10))
+	return values.Encode()
+}
@@ -0,0 +1,30 @@
+//go:build linux && !blackfire_noop
+// +build linux,!blackfire_noop
+
+package blackfire
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// osThreadCount returns the number of OS threads of the process, from
+// /proc/self/status.
+func osThreadCount() (int, bool) {
+	f, err := os.Open("/proc/self/status")
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if value := strings.TrimPrefix(scanner.Text(), "Threads:"); value != scanner.Text() {
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			return n, err == nil
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,10 @@
+//go:build !linux && !blackfire_noop
+// +build !linux,!blackfire_noop
+
+package blackfire
+
+// osThreadCount returns the number of OS threads of the process, which is
+// only known on Linux.
+func osThreadCount() (int, bool) {
+	return 0, false
+}
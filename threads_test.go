@@ -0,0 +1,44 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"net/url"
+	"runtime"
+	"strconv"
+
+	"github.com/blackfireio/go-blackfire/pprof_reader"
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestThreadsHeader(c *C) {
+	p := newTestProbe(newFakeClock())
+	p.startThreadStats()
+	values, err := url.ParseQuery(p.threadsHeader())
+	c.Assert(err, IsNil)
+	created, err := strconv.Atoi(values.Get("created"))
+	c.Assert(err, IsNil)
+	c.Assert(created > 0, Equals, true)
+	c.Assert(values.Get("gomaxprocs"), Equals, strconv.Itoa(runtime.GOMAXPROCS(0)))
+	if runtime.GOOS == "linux" {
+		c.Assert(values.Get("os"), Not(Equals), "")
+	}
+}
+
+func (s *BlackfireSuite) TestAddThreadSamples(c *C) {
+	p := newTestProbe(newFakeClock())
+	profile := pprof_reader.NewProfile()
+	p.startThreadStats()
+	p.addThreadSamples(profile)
+	c.Assert(profile.Samples, HasLen, 0)
+
+	// Without a snapshot at the start, all the threads created so far are
+	// accounted, but the ones whose creation stack is unknown.
+	p.configuration.ProfileThreadCreation = true
+	p.addThreadSamples(profile)
+	for _, sample := range profile.Samples {
+		c.Assert(sample.Metrics[threadsCreatedMetric] > 0, Equals, true)
+		c.Assert(sample.Stack, Not(HasLen), 0)
+	}
+}
@@ -0,0 +1,82 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	// webhookTimeout bounds the time posting to the webhook may take.
+	webhookTimeout = 10 * time.Second
+	// webhookGraphTimeout bounds the time waiting for the graph of a profile
+	// to be finalized.
+	webhookGraphTimeout = 5 * time.Minute
+)
+
+// webhookPayload is posted to Configuration.WebhookURL for each uploaded
+// profile.
+type webhookPayload struct {
+	UUID     string    `json:"uuid"`
+	URL      string    `json:"url"`
+	Title    string    `json:"title"`
+	Envelope *Envelope `json:"envelope,omitempty"`
+}
+
+// notifyWebhook posts the uploaded profile to the webhook, if any. It
+// doesn't block the upload.
+func (p *Probe) notifyWebhook(profile *Profile, title string) {
+	configuration := p.configuration
+	if configuration.WebhookURL == "" {
+		return
+	}
+	go func() {
+		logger := configuration.Logger
+		payload := webhookPayload{
+			UUID:  profile.UUID,
+			URL:   profile.URL,
+			Title: title,
+		}
+		if configuration.WebhookWaitForGraph {
+			ctx, cancel := context.WithTimeout(context.Background(), webhookGraphTimeout)
+			if _, err := profile.Wait(ctx); err != nil {
+				logger.Warn().Err(err).Msgf("Blackfire: Unable to wait for the graph of profile %s", profile.UUID)
+			} else {
+				payload.Envelope = &profile.Envelope
+			}
+			cancel()
+		}
+		if err := postWebhook(configuration.WebhookURL, payload); err != nil {
+			logger.Error().Err(err).Msgf("Blackfire: Unable to notify the webhook of profile %s", profile.UUID)
+		}
+	}()
+}
+
+// postWebhook posts the payload to the webhook. The URL of the webhook is
+// often a secret, so it is left out of the errors.
+func postWebhook(webhookURL string, payload webhookPayload) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	client := &http.Client{Timeout: webhookTimeout}
+	response, err := client.Post(webhookURL, "application/json", bytes.NewReader(data))
+	if err != nil {
+		if urlErr, ok := err.(*url.Error); ok {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("Webhook request failed: %v", err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode >= 300 {
+		return fmt.Errorf("Webhook answered %s", response.Status)
+	}
+	return nil
+}
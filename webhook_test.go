@@ -0,0 +1,60 @@
+//go:build !blackfire_noop
+// +build !blackfire_noop
+
+package blackfire
+
+import (
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "gopkg.in/check.v1"
+)
+
+func (s *BlackfireSuite) TestProbeWebhook(c *C) {
+	payloads := make(chan webhookPayload, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var payload webhookPayload
+		c.Check(json.NewDecoder(r.Body).Decode(&payload), IsNil)
+		payloads <- payload
+	}))
+	defer webhook.Close()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	c.Assert(err, IsNil)
+	defer listener.Close()
+	go serveFakeAgent(listener, "Blackfire-Response: continue=true\n", make(chan net.Conn, 10), make(chan int, 10))
+
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	logger := NewLogger(filepath.Join(os.TempDir(), "blackfire-probe-test.log"), 4)
+	p := newProbe()
+	p.clock = newFakeClock()
+	p.Configure(&Configuration{
+		AgentSocket:    "tcp://" + listener.Addr().String(),
+		BlackfireQuery: "expires=9999999999&signature=abc",
+		WebhookURL:     webhook.URL,
+		Logger:         &logger,
+	})
+
+	c.Assert(p.EnableWithOptions(ProfileOptions{Title: "webhook"}), IsNil)
+	p.Metric("ops").Add(1)
+	c.Assert(p.End(), IsNil)
+	select {
+	case payload := <-payloads:
+		c.Assert(payload.Title, Equals, "webhook")
+		c.Assert(payload.Envelope, IsNil)
+	case <-time.After(5 * time.Second):
+		c.Fatal("The webhook was not notified")
+	}
+}
+
+func (s *BlackfireSuite) TestConfigurationWebhook(c *C) {
+	setIgnoreIni()
+	defer unsetIgnoreIni()
+	config := newConfiguration(&Configuration{BlackfireQuery: "signature=abc", WebhookURL: "ftp://example.com"})
+	c.Assert(config.load(), ErrorMatches, "Invalid webhook URL ftp://example.com")
+}